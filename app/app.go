@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rshelekhov/golib/config"
+	"github.com/rshelekhov/golib/observability"
+	"github.com/rshelekhov/golib/server"
+	"golang.org/x/sync/errgroup"
+)
+
+// App wires observability, components, workers and the server of a service
+// and manages their startup and shutdown
+type App struct {
+	logger           *slog.Logger
+	observabilityCfg *observability.Config
+	components       []*component
+	workers          []worker
+	newService       func(ctx context.Context) (server.GRPCProvider, error)
+	serverOpts       []server.Option
+	shutdownTimeout  time.Duration
+}
+
+// New creates an application with the given options
+func New(opts ...Option) *App {
+	a := &App{
+		logger:          slog.Default(),
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Main loads the config of type C, validates it if C has a Validate() error method,
+// builds the application with setup and runs it. It exits with status 1 on failure.
+//
+//	func main() {
+//		app.Main(func(cfg *Config) []app.Option {
+//			return []app.Option{...}
+//		})
+//	}
+//
+// With the -check flag, Main loads and validates the config, starts and checks the components,
+// prints a JSON report to stdout and exits with status 0 if everything is up, 1 otherwise.
+func Main[C any](setup func(cfg *C) []Option, opts ...config.Option) {
+	checkFlag := defineCheckFlag()
+	cfg, err := loadConfig[C](opts...)
+
+	// Flags are parsed after loading, so the config loader can define its own
+	if checkRequested(checkFlag) {
+		os.Exit(printReport(selfCheck(context.Background(), cfg, err, setup)))
+	}
+
+	if err == nil {
+		err = New(setup(cfg)...).Run(context.Background())
+	}
+	if err != nil {
+		slog.Error("application failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func loadConfig[C any](opts ...config.Option) (*C, error) {
+	cfg, err := config.Load[C](opts...)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := any(cfg).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// Run initializes observability, starts the components in dependency order, then the server and workers.
+// It blocks until ctx is done, SIGINT or SIGTERM is received, or the server or a worker returns,
+// then stops the components in reverse order and shuts observability down.
+func (a *App) Run(ctx context.Context) (err error) {
+	order, err := startOrder(a.components)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var obs *observability.Observability
+	if a.observabilityCfg != nil {
+		obs, err = observability.Init(ctx, *a.observabilityCfg)
+		if err != nil {
+			return fmt.Errorf("failed to init observability: %w", err)
+		}
+		a.logger = obs.Logger
+		ctx = observability.Inject(ctx, obs)
+
+		defer func() {
+			shutdownCtx, cancel := a.shutdownContext(ctx)
+			defer cancel()
+			if shutdownErr := obs.Shutdown(shutdownCtx); shutdownErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to shut down observability: %w", shutdownErr))
+			}
+		}()
+	}
+
+	started, err := a.start(ctx, order)
+	defer func() {
+		err = errors.Join(err, a.stop(ctx, started))
+	}()
+	if err != nil {
+		return err
+	}
+
+	return a.serve(ctx, obs, started)
+}
+
+// start starts the components in order, returning the started ones
+func (a *App) start(ctx context.Context, order []*component) ([]*component, error) {
+	started := make([]*component, 0, len(order))
+	for _, c := range order {
+		a.logger.Info("starting component", "component", c.name)
+		if err := c.start(ctx); err != nil {
+			return started, fmt.Errorf("failed to start %s: %w", c.name, err)
+		}
+		started = append(started, c)
+	}
+	return started, nil
+}
+
+// stop stops the started components in reverse order
+func (a *App) stop(ctx context.Context, started []*component) error {
+	ctx, cancel := a.shutdownContext(ctx)
+	defer cancel()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+		if c.stop == nil {
+			continue
+		}
+		if err := c.stop(ctx); err != nil {
+			a.logger.Error("failed to stop component", "component", c.name, "error", err)
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.name, err))
+			continue
+		}
+		a.logger.Info("component stopped", "component", c.name)
+	}
+	return errors.Join(errs...)
+}
+
+// serve runs the server and workers until ctx is done or one of them returns
+func (a *App) serve(ctx context.Context, obs *observability.Observability, components []*component) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	if a.newService != nil {
+		service, err := a.newService(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create service: %w", err)
+		}
+		srv, err := server.NewApp(ctx, a.serverOptions(obs, components)...)
+		if err != nil {
+			return fmt.Errorf("failed to create server: %w", err)
+		}
+		g.Go(func() error {
+			defer cancel()
+			return srv.Run(ctx, service)
+		})
+	}
+
+	for _, w := range a.workers {
+		g.Go(func() error {
+			defer cancel()
+			a.logger.Info("starting worker", "worker", w.name)
+			if err := w.run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("worker %s: %w", w.name, err)
+			}
+			a.logger.Info("worker stopped", "worker", w.name)
+			return nil
+		})
+	}
+
+	if a.newService == nil && len(a.workers) == 0 {
+		<-ctx.Done()
+	}
+
+	err := g.Wait()
+	a.logger.Info("shutting down")
+	return err
+}
+
+// serverOptions returns the options passing the logger, observability and component checks to the server
+func (a *App) serverOptions(obs *observability.Observability, components []*component) []server.Option {
+	opts := []server.Option{server.WithLogger(a.logger)}
+	if obs != nil {
+		opts = append(opts, server.WithObservability(obs))
+	}
+	for _, c := range components {
+		if c.check != nil {
+			opts = append(opts, server.WithDependencies(server.NewDependencyCheck(c.name, c.kind, checkFunc(c.check))))
+		}
+	}
+	return append(opts, a.serverOpts...)
+}
+
+// shutdownContext returns a context for shutdown that keeps the values of ctx but not its cancellation
+func (a *App) shutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), a.shutdownTimeout)
+}
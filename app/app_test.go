@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rshelekhov/golib/server"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) component(name string, deps ...string) Option {
+	return WithComponent(name,
+		func(ctx context.Context) error {
+			r.add("start " + name)
+			return nil
+		},
+		DependsOn(deps...),
+		WithStop(func(ctx context.Context) error {
+			r.add("stop " + name)
+			return nil
+		}),
+	)
+}
+
+func discardLogger() Option {
+	return WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestRun(t *testing.T) {
+	var rec recorder
+	errWorker := errors.New("worker failed")
+
+	a := New(
+		discardLogger(),
+		rec.component("service-cache", "postgres", "redis"),
+		rec.component("postgres"),
+		rec.component("redis"),
+		WithWorker("consumer", func(ctx context.Context) error {
+			rec.add("run consumer")
+			return errWorker
+		}),
+	)
+
+	err := a.Run(context.Background())
+	if !errors.Is(err, errWorker) {
+		t.Fatalf("Run() error = %v, want %v", err, errWorker)
+	}
+
+	want := []string{
+		"start postgres", "start redis", "start service-cache",
+		"run consumer",
+		"stop service-cache", "stop redis", "stop postgres",
+	}
+	if !slices.Equal(rec.events, want) {
+		t.Errorf("events = %v, want %v", rec.events, want)
+	}
+}
+
+func TestRunStartFailure(t *testing.T) {
+	var rec recorder
+	errStart := errors.New("connection refused")
+
+	a := New(
+		discardLogger(),
+		rec.component("postgres"),
+		WithComponent("kafka", func(ctx context.Context) error { return errStart }, DependsOn("postgres")),
+		WithWorker("consumer", func(ctx context.Context) error {
+			rec.add("run consumer")
+			return nil
+		}),
+	)
+
+	err := a.Run(context.Background())
+	if !errors.Is(err, errStart) {
+		t.Fatalf("Run() error = %v, want %v", err, errStart)
+	}
+	if want := []string{"start postgres", "stop postgres"}; !slices.Equal(rec.events, want) {
+		t.Errorf("events = %v, want %v", rec.events, want)
+	}
+}
+
+func TestStartOrderErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []*component
+		want       string
+	}{
+		{
+			name:       "unknown dependency",
+			components: []*component{{name: "cache", dependsOn: []string{"redis"}}},
+			want:       `component "cache" depends on unknown component "redis"`,
+		},
+		{
+			name:       "duplicate",
+			components: []*component{{name: "redis"}, {name: "redis"}},
+			want:       `duplicate component "redis"`,
+		},
+		{
+			name: "cycle",
+			components: []*component{
+				{name: "a", dependsOn: []string{"b"}},
+				{name: "b", dependsOn: []string{"a"}},
+			},
+			want: "dependency cycle: a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := startOrder(tt.components)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("startOrder() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var rec recorder
+	errUnreachable := errors.New("connection refused")
+
+	a := New(
+		discardLogger(),
+		rec.component("postgres"),
+		WithComponent("kafka",
+			func(ctx context.Context) error { return errUnreachable },
+		),
+		WithComponent("outbox",
+			func(ctx context.Context) error {
+				rec.add("start outbox")
+				return nil
+			},
+			DependsOn("postgres", "kafka"),
+		),
+		WithComponent("redis",
+			func(ctx context.Context) error { return nil },
+			WithCheck("redis", func(ctx context.Context) error { return errUnreachable }),
+		),
+		WithWorker("consumer", func(ctx context.Context) error {
+			rec.add("run consumer")
+			return nil
+		}),
+	)
+
+	report := a.Check(context.Background())
+	if report.State != server.DependencyDown {
+		t.Errorf("report state = %s, want down", report.State)
+	}
+
+	want := map[string]string{
+		"postgres": "",
+		"kafka":    "failed to start: connection refused",
+		"outbox":   "not started: dependency kafka failed",
+		"redis":    "connection refused",
+	}
+	if len(report.Dependencies) != len(want) {
+		t.Fatalf("got %d statuses, want %d", len(report.Dependencies), len(want))
+	}
+	for _, s := range report.Dependencies {
+		if s.LastError != want[s.Name] {
+			t.Errorf("%s error = %q, want %q", s.Name, s.LastError, want[s.Name])
+		}
+		if wantUp := want[s.Name] == ""; (s.State == server.DependencyUp) != wantUp {
+			t.Errorf("%s state = %s", s.Name, s.State)
+		}
+	}
+
+	if want := []string{"start postgres", "stop postgres"}; !slices.Equal(rec.events, want) {
+		t.Errorf("events = %v, want %v", rec.events, want)
+	}
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rshelekhov/golib/server"
+)
+
+// CheckFlag is the command line flag running Main in self-check mode
+const CheckFlag = "check"
+
+// Check starts the components in dependency order, runs their checks and stops them again,
+// without initializing observability or running the server and workers. Components whose
+// dependencies failed are not started. The report has the format of the server dependencies endpoint.
+func (a *App) Check(ctx context.Context) server.DependencyReport {
+	report := server.DependencyReport{State: server.DependencyUp}
+
+	order, err := startOrder(a.components)
+	if err != nil {
+		report.State = server.DependencyDown
+		report.Dependencies = append(report.Dependencies, server.DependencyStatus{
+			Name:      "components",
+			State:     server.DependencyDown,
+			LastError: err.Error(),
+			CheckedAt: time.Now(),
+		})
+		return report
+	}
+
+	var started []*component
+	defer func() {
+		_ = a.stop(ctx, started)
+	}()
+
+	failed := make(map[string]bool)
+	for _, c := range order {
+		status := a.checkComponent(ctx, c, failed)
+		if status.State == server.DependencyDown {
+			failed[c.name] = true
+			report.State = server.DependencyDown
+		} else {
+			started = append(started, c)
+		}
+		report.Dependencies = append(report.Dependencies, status)
+	}
+	return report
+}
+
+// checkComponent starts c unless one of its dependencies failed, then runs its check
+func (a *App) checkComponent(ctx context.Context, c *component, failed map[string]bool) server.DependencyStatus {
+	status := server.DependencyStatus{
+		Name:      c.name,
+		Kind:      c.kind,
+		State:     server.DependencyDown,
+		CheckedAt: time.Now(),
+	}
+
+	for _, dep := range c.dependsOn {
+		if failed[dep] {
+			status.LastError = fmt.Sprintf("not started: dependency %s failed", dep)
+			return status
+		}
+	}
+
+	a.logger.Info("starting component", "component", c.name)
+	if err := c.start(ctx); err != nil {
+		status.LastError = fmt.Sprintf("failed to start: %v", err)
+		status.Latency = time.Since(status.CheckedAt)
+		return status
+	}
+
+	if c.check == nil {
+		status.State = server.DependencyUp
+		status.Latency = time.Since(status.CheckedAt)
+		return status
+	}
+	return server.NewDependencyCheck(c.name, c.kind, checkFunc(c.check)).DependencyStatus(ctx)
+}
+
+// defineCheckFlag defines CheckFlag unless it is already defined. It must be defined
+// before the config loader parses the command line.
+func defineCheckFlag() *flag.Flag {
+	if f := flag.Lookup(CheckFlag); f != nil {
+		return f
+	}
+	flag.Bool(CheckFlag, false, "load and validate config, check dependencies, print a report and exit")
+	return flag.Lookup(CheckFlag)
+}
+
+// checkRequested reports whether the check flag is set, parsing flags if nobody parsed them yet
+func checkRequested(f *flag.Flag) bool {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	check, _ := strconv.ParseBool(f.Value.String())
+	return check
+}
+
+// selfCheck reports the config status followed by the component checks
+func selfCheck[C any](ctx context.Context, cfg *C, cfgErr error, setup func(cfg *C) []Option) server.DependencyReport {
+	configStatus := server.DependencyStatus{
+		Name:      "config",
+		Kind:      "config",
+		State:     server.DependencyUp,
+		CheckedAt: time.Now(),
+	}
+	if cfgErr != nil {
+		configStatus.State = server.DependencyDown
+		configStatus.LastError = cfgErr.Error()
+		return server.DependencyReport{
+			State:        server.DependencyDown,
+			Dependencies: []server.DependencyStatus{configStatus},
+		}
+	}
+
+	report := New(setup(cfg)...).Check(ctx)
+	report.Dependencies = append([]server.DependencyStatus{configStatus}, report.Dependencies...)
+	return report
+}
+
+// printReport writes the report as JSON to stdout and returns the exit code
+func printReport(report server.DependencyReport) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return 1
+	}
+	if report.State != server.DependencyUp {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type component struct {
+	name      string
+	kind      string
+	dependsOn []string
+	start     func(ctx context.Context) error
+	stop      func(ctx context.Context) error
+	check     func(ctx context.Context) error
+}
+
+// checkFunc adapts a function to server.ReadinessCheck
+type checkFunc func(ctx context.Context) error
+
+func (f checkFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// startOrder sorts components so that each one follows its dependencies,
+// keeping the declaration order otherwise
+func startOrder(components []*component) ([]*component, error) {
+	byName := make(map[string]*component, len(components))
+	for _, c := range components {
+		if _, ok := byName[c.name]; ok {
+			return nil, fmt.Errorf("duplicate component %q", c.name)
+		}
+		byName[c.name] = c
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(components))
+	order := make([]*component, 0, len(components))
+
+	var visit func(c *component, path []string) error
+	visit = func(c *component, path []string) error {
+		switch state[c.name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, c.name), " -> "))
+		}
+		state[c.name] = visiting
+		for _, name := range c.dependsOn {
+			dep, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("component %q depends on unknown component %q", c.name, name)
+			}
+			if err := visit(dep, append(path, c.name)); err != nil {
+				return err
+			}
+		}
+		state[c.name] = visited
+		order = append(order, c)
+		return nil
+	}
+
+	for _, c := range components {
+		if err := visit(c, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
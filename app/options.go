@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rshelekhov/golib/observability"
+	"github.com/rshelekhov/golib/server"
+)
+
+// DefaultShutdownTimeout limits stopping of components and flushing of observability
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Option configures an App
+type Option func(*App)
+
+// WithLogger sets the logger used when observability is not configured (default: slog.Default())
+func WithLogger(logger *slog.Logger) Option {
+	return func(a *App) {
+		a.logger = logger
+	}
+}
+
+// WithObservability initializes observability before all components and shuts it down after them.
+// Its logger is used by the App and the server, and it is injected into the contexts
+// of components, workers and requests.
+func WithObservability(cfg observability.Config) Option {
+	return func(a *App) {
+		a.observabilityCfg = &cfg
+	}
+}
+
+// WithComponent adds a component, e.g. a database connection, started by start.
+// Components are started in dependency order before the server and workers, and stopped in reverse order.
+func WithComponent(name string, start func(ctx context.Context) error, opts ...ComponentOption) Option {
+	return func(a *App) {
+		c := &component{name: name, start: start}
+		for _, opt := range opts {
+			opt(c)
+		}
+		a.components = append(a.components, c)
+	}
+}
+
+// WithWorker adds a background worker, e.g. a queue consumer, started after all components.
+// run must return when ctx is done. When a worker returns, the application shuts down.
+func WithWorker(name string, run func(ctx context.Context) error) Option {
+	return func(a *App) {
+		a.workers = append(a.workers, worker{name: name, run: run})
+	}
+}
+
+// WithServer runs a server.App with the service created by newService after all components are started.
+// The logger, observability and component checks are passed to the server before opts.
+func WithServer(newService func(ctx context.Context) (server.GRPCProvider, error), opts ...server.Option) Option {
+	return func(a *App) {
+		a.newService = newService
+		a.serverOpts = append(a.serverOpts, opts...)
+	}
+}
+
+// WithShutdownTimeout sets the timeout for stopping components and flushing observability
+// (default: DefaultShutdownTimeout). The server has its own timeout, see server.WithShutdownTimeout.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		a.shutdownTimeout = timeout
+	}
+}
+
+// ComponentOption configures a component
+type ComponentOption func(*component)
+
+// DependsOn makes the component start after the named components and stop before them
+func DependsOn(names ...string) ComponentOption {
+	return func(c *component) {
+		c.dependsOn = append(c.dependsOn, names...)
+	}
+}
+
+// WithStop sets the function stopping the component, e.g. closing a connection pool
+func WithStop(stop func(ctx context.Context) error) ComponentOption {
+	return func(c *component) {
+		c.stop = stop
+	}
+}
+
+// WithCheck reports the component at the server dependencies endpoint, checked with check, e.g. a ping.
+// kind describes the component, e.g. "postgres".
+func WithCheck(kind string, check func(ctx context.Context) error) ComponentOption {
+	return func(c *component) {
+		c.kind = kind
+		c.check = check
+	}
+}
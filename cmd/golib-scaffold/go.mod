@@ -0,0 +1,3 @@
+module github.com/rshelekhov/golib/cmd/golib-scaffold
+
+go 1.24.2
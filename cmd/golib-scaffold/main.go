@@ -0,0 +1,46 @@
+// Command golib-scaffold generates the skeleton of a service built on golib: config, observability,
+// server.App with the standard middleware stack, a pgx repository with TransactionManager
+// and integration tests running against a Postgres container.
+//
+//	go run github.com/rshelekhov/golib/cmd/golib-scaffold@latest -module github.com/acme/orders -name orders
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var p Params
+	flag.StringVar(&p.Module, "module", "", "Go module path of the service, e.g. github.com/acme/orders (required)")
+	flag.StringVar(&p.Name, "name", "", "service name (default: last element of the module path)")
+	flag.IntVar(&p.GRPCPort, "grpc-port", 9000, "default gRPC port")
+	flag.IntVar(&p.HTTPPort, "http-port", 8080, "default HTTP port")
+	dir := flag.String("dir", "", "output directory (default: ./<name>)")
+	force := flag.Bool("force", false, "overwrite existing files")
+	flag.Parse()
+
+	if p.Module == "" {
+		fmt.Fprintln(os.Stderr, "golib-scaffold: -module is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if p.Name == "" {
+		p.Name = filepath.Base(p.Module)
+	}
+	if *dir == "" {
+		*dir = p.Name
+	}
+
+	written, err := Generate(*dir, p, *force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "golib-scaffold: %v\n", err)
+		os.Exit(1)
+	}
+	for _, file := range written {
+		fmt.Println(filepath.Join(*dir, file))
+	}
+	fmt.Printf("\nNext steps:\n  cd %s\n  go mod tidy\n  go test ./...\n", *dir)
+}
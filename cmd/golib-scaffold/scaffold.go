@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed all:templates
+var templates embed.FS
+
+// Params are the values substituted into the templates
+type Params struct {
+	// Module is the Go module path of the service, e.g. "github.com/acme/orders"
+	Module string
+	// Name is the service name used for the binary, observability and the database, e.g. "orders"
+	Name string
+	// GRPCPort and HTTPPort are the default ports written to config.yaml
+	GRPCPort int
+	HTTPPort int
+}
+
+var (
+	namePattern   = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	modulePattern = regexp.MustCompile(`^[A-Za-z0-9.\-_~/]+$`)
+)
+
+// Validate checks that the params produce a buildable service
+func (p Params) Validate() error {
+	var errMessages []string
+	if !modulePattern.MatchString(p.Module) {
+		errMessages = append(errMessages, fmt.Sprintf("invalid module path %q", p.Module))
+	}
+	if !namePattern.MatchString(p.Name) {
+		errMessages = append(errMessages, fmt.Sprintf("invalid service name %q (lowercase letters, digits and dashes)", p.Name))
+	}
+	if p.GRPCPort <= 0 || p.HTTPPort <= 0 || p.GRPCPort == p.HTTPPort {
+		errMessages = append(errMessages, fmt.Sprintf("invalid ports %d and %d", p.GRPCPort, p.HTTPPort))
+	}
+	if len(errMessages) > 0 {
+		return errors.New(strings.Join(errMessages, "; "))
+	}
+	return nil
+}
+
+// Package is the name as a Go package name, e.g. "ordersapi" for "orders-api"
+func (p Params) Package() string {
+	return strings.ReplaceAll(p.Name, "-", "")
+}
+
+// Export is the name as an exported Go identifier, e.g. "OrdersApi" for "orders-api"
+func (p Params) Export() string {
+	var b strings.Builder
+	for _, part := range strings.Split(p.Name, "-") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// Generate renders the templates into dir. Existing files are kept unless force is set,
+// so that a half-written service is never overwritten by accident.
+func Generate(dir string, p Params, force bool) ([]string, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	err := fs.WalkDir(templates, "templates", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		target := outputPath(strings.TrimPrefix(name, "templates/"), p)
+		content, err := render(name, p)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(dir, filepath.FromSlash(target))
+		if _, err := os.Stat(dst); err == nil && !force {
+			return fmt.Errorf("%s already exists, use -force to overwrite", dst)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", target, err)
+		}
+		if err := os.WriteFile(dst, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		written = append(written, target)
+		return nil
+	})
+	return written, err
+}
+
+// outputPath maps a template name to the generated file: the .tmpl suffix is dropped,
+// "__name__" is replaced by the service name and "dot-" prefixes become dots, e.g. dot-gitignore
+func outputPath(name string, p Params) string {
+	name = strings.TrimSuffix(name, ".tmpl")
+	name = strings.ReplaceAll(name, "__name__", p.Name)
+	dir, file := path.Split(name)
+	if rest, ok := strings.CutPrefix(file, "dot-"); ok {
+		file = "." + rest
+	}
+	return dir + file
+}
+
+// render executes a template and formats Go sources
+func render(name string, p Params) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", name, err)
+	}
+	return formatted, nil
+}
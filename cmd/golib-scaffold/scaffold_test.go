@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	p := Params{Module: "github.com/acme/orders-api", Name: "orders-api", GRPCPort: 9000, HTTPPort: 8080}
+
+	written, err := Generate(dir, p, false)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"go.mod",
+		".gitignore",
+		"cmd/orders-api/main.go",
+		"internal/config/config.go",
+		"internal/storage/postgres/repository_test.go",
+		"internal/storage/postgres/migrations/001_create_items.sql",
+	} {
+		if !contains(written, want) {
+			t.Errorf("%s was not generated", want)
+		}
+	}
+
+	main, err := os.ReadFile(filepath.Join(dir, "cmd", "orders-api", "main.go"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(main), `"github.com/acme/orders-api/internal/service"`) {
+		t.Error("main.go doesn't import the service package of the module")
+	}
+	service, err := os.ReadFile(filepath.Join(dir, "internal", "service", "service.go"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(service), "ordersapiv1.RegisterOrdersApiServiceServer") {
+		t.Error("service.go doesn't reference the generated API by the service name")
+	}
+
+	// Existing files are kept unless forced
+	if _, err := Generate(dir, p, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Generate() over existing files error = %v, want already exists", err)
+	}
+	if _, err := Generate(dir, p, true); err != nil {
+		t.Errorf("Generate() with force error = %v", err)
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	err := Params{Module: "github.com/acme/orders api", Name: "Orders", GRPCPort: 8080, HTTPPort: 8080}.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil")
+	}
+	for _, want := range []string{"module path", "service name", "ports"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func contains(files []string, name string) bool {
+	for _, f := range files {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+// Package env provides typed access to environment variables.
+//
+// Every variable read through Get or Required is recorded, so that missing
+// and malformed values can be reported together by Validate at startup and
+// all consumed variables can be documented with WriteDocs.
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value lists the types supported by Get and Required.
+// Slices are read as comma-separated lists.
+type Value interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 |
+		*url.URL | []string
+}
+
+// Option configures how a variable is recorded
+type Option func(*Var)
+
+// WithDescription sets the description shown in generated documentation
+func WithDescription(description string) Option {
+	return func(v *Var) {
+		v.Description = description
+	}
+}
+
+// Get returns the value of the variable parsed as T, or def if it is unset or empty.
+// A malformed value is recorded as an error reported by Validate, and def is returned.
+func Get[T Value](name string, def T, opts ...Option) T {
+	v := Var{
+		Name:    name,
+		Type:    typeName[T](),
+		Default: formatValue(def),
+	}
+	for _, opt := range opts {
+		opt(&v)
+	}
+
+	value, err := lookup[T](name)
+	defaultRegistry.record(v, err)
+	if err != nil || !isSet(name) {
+		return def
+	}
+	return value
+}
+
+// Required returns the value of the variable parsed as T.
+// A missing or malformed value is recorded as an error reported by Validate,
+// and the zero value is returned.
+func Required[T Value](name string, opts ...Option) T {
+	v := Var{
+		Name:     name,
+		Type:     typeName[T](),
+		Required: true,
+	}
+	for _, opt := range opts {
+		opt(&v)
+	}
+
+	var zero T
+	if !isSet(name) {
+		defaultRegistry.record(v, ErrMissing)
+		return zero
+	}
+
+	value, err := lookup[T](name)
+	defaultRegistry.record(v, err)
+	if err != nil {
+		return zero
+	}
+	return value
+}
+
+// Lookup parses the variable as T without recording it.
+// It reports false if the variable is unset or empty.
+func Lookup[T Value](name string) (T, bool, error) {
+	value, err := lookup[T](name)
+	return value, err == nil && isSet(name), err
+}
+
+func isSet(name string) bool {
+	return os.Getenv(name) != ""
+}
+
+func lookup[T Value](name string) (T, error) {
+	var value T
+
+	raw := os.Getenv(name)
+	if raw == "" {
+		return value, nil
+	}
+
+	if err := parse(raw, &value); err != nil {
+		return value, &ParseError{Name: name, Value: raw, Err: err}
+	}
+	return value, nil
+}
+
+// parse converts raw into the value pointed to by dst
+func parse[T Value](raw string, dst *T) error {
+	switch p := any(dst).(type) {
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		*p = d
+		return nil
+	case **url.URL:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return errors.New("absolute URL expected")
+		}
+		*p = u
+		return nil
+	case *[]string:
+		parts := strings.Split(raw, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		*p = parts
+		return nil
+	}
+
+	// Remaining types, including named ones like `type Mode string`, are parsed by kind
+	rv := reflect.ValueOf(dst).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+	return nil
+}
+
+// typeName returns the name of T used in documentation
+func typeName[T Value]() string {
+	var zero T
+	switch any(zero).(type) {
+	case time.Duration:
+		return "duration"
+	case *url.URL:
+		return "url"
+	case []string:
+		return "list"
+	}
+	return reflect.TypeOf(zero).Kind().String()
+}
+
+// formatValue renders a default value for documentation
+func formatValue[T Value](v T) string {
+	switch val := any(v).(type) {
+	case *url.URL:
+		if val == nil {
+			return ""
+		}
+		return val.String()
+	case []string:
+		return strings.Join(val, ",")
+	}
+	return fmt.Sprint(v)
+}
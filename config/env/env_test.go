@@ -0,0 +1,104 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mode string
+
+func resetRegistry(t *testing.T) {
+	t.Helper()
+	defaultRegistry = newRegistry()
+	t.Cleanup(func() { defaultRegistry = newRegistry() })
+}
+
+func TestGet(t *testing.T) {
+	resetRegistry(t)
+	t.Setenv("TEST_PORT", "9090")
+	t.Setenv("TEST_DEBUG", "true")
+	t.Setenv("TEST_TIMEOUT", "1m30s")
+	t.Setenv("TEST_ENDPOINT", "https://api.example.com/v1")
+	t.Setenv("TEST_HOSTS", "a, b,c")
+	t.Setenv("TEST_MODE", "strict")
+
+	if got := Get("TEST_PORT", 8080); got != 9090 {
+		t.Errorf("expected 9090, got %d", got)
+	}
+	if got := Get("TEST_MISSING", 8080); got != 8080 {
+		t.Errorf("expected default 8080, got %d", got)
+	}
+	if got := Get("TEST_DEBUG", false); !got {
+		t.Error("expected true")
+	}
+	if got := Get("TEST_TIMEOUT", time.Second); got != 90*time.Second {
+		t.Errorf("expected 1m30s, got %s", got)
+	}
+	if got := Required[*url.URL]("TEST_ENDPOINT"); got == nil || got.Host != "api.example.com" {
+		t.Errorf("unexpected URL %v", got)
+	}
+	if got := Get[[]string]("TEST_HOSTS", nil); strings.Join(got, "|") != "a|b|c" {
+		t.Errorf("unexpected list %v", got)
+	}
+	if got := Get[mode]("TEST_MODE", "lenient"); got != "strict" {
+		t.Errorf("expected strict, got %s", got)
+	}
+
+	if err := Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func TestValidateAggregatesErrors(t *testing.T) {
+	resetRegistry(t)
+	t.Setenv("TEST_PORT", "http")
+	t.Setenv("TEST_TIMEOUT", "soon")
+
+	if got := Get("TEST_PORT", 8080); got != 8080 {
+		t.Errorf("expected default on malformed value, got %d", got)
+	}
+	Required[time.Duration]("TEST_TIMEOUT")
+	Required[string]("TEST_DATABASE_URL")
+
+	err := Validate()
+	if !errors.Is(err, ErrMissing) {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+
+	for _, name := range []string{"TEST_PORT", "TEST_TIMEOUT", "TEST_DATABASE_URL"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("expected %s in %q", name, err)
+		}
+	}
+}
+
+func TestWriteDocs(t *testing.T) {
+	resetRegistry(t)
+
+	Get("TEST_PORT", 8080, WithDescription("HTTP listen port"))
+	Required[string]("TEST_DATABASE_URL")
+
+	var buf bytes.Buffer
+	if err := WriteDocs(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"| `TEST_DATABASE_URL` | string | yes |  |  |",
+		"| `TEST_PORT` | int | no | `8080` | HTTP listen port |",
+	}
+	for _, line := range expected {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("expected line %q in\n%s", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrMissing is reported by Validate for required variables that are unset or empty
+var ErrMissing = errors.New("required variable is not set")
+
+// ParseError is reported by Validate for variables with malformed values
+type ParseError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s: %v", e.Value, e.Name, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Var describes an environment variable consumed by the service
+type Var struct {
+	Name        string
+	Type        string
+	Default     string
+	Description string
+	Required    bool
+}
+
+type registry struct {
+	mu   sync.Mutex
+	vars map[string]Var
+	errs map[string]error
+}
+
+var defaultRegistry = newRegistry()
+
+func newRegistry() *registry {
+	return &registry{
+		vars: make(map[string]Var),
+		errs: make(map[string]error),
+	}
+}
+
+func (r *registry) record(v Var, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.vars[v.Name] = v
+	if err != nil {
+		if errors.Is(err, ErrMissing) {
+			err = fmt.Errorf("%s: %w", v.Name, err)
+		}
+		r.errs[v.Name] = err
+	} else {
+		delete(r.errs, v.Name)
+	}
+}
+
+// Validate returns all errors of variables read so far, joined and sorted by name.
+// Call it once after configuration has been read:
+//
+//	port := env.Get("PORT", 8080)
+//	dsn := env.Required[string]("DATABASE_URL")
+//	if err := env.Validate(); err != nil {
+//		log.Fatal(err)
+//	}
+func Validate() error {
+	r := defaultRegistry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.errs))
+	for name := range r.errs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = r.errs[name]
+	}
+	return errors.Join(errs...)
+}
+
+// Vars returns all variables read so far, sorted by name
+func Vars() []Var {
+	r := defaultRegistry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vars := make([]Var, 0, len(r.vars))
+	for _, v := range r.vars {
+		vars = append(vars, v)
+	}
+	slices.SortFunc(vars, func(a, b Var) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return vars
+}
+
+// WriteDocs writes a Markdown table of all variables read so far.
+// It is meant to be called from a docs generator or a `-print-env` flag.
+func WriteDocs(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Variable | Type | Required | Default | Description |\n")
+	b.WriteString("|----------|------|----------|---------|-------------|\n")
+
+	for _, v := range Vars() {
+		required := "no"
+		if v.Required {
+			required = "yes"
+		}
+		def := ""
+		if v.Default != "" {
+			def = "`" + v.Default + "`"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", v.Name, v.Type, required, def, v.Description)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
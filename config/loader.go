@@ -2,8 +2,10 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/cristalhq/aconfig"
 	"github.com/cristalhq/aconfig/aconfigdotenv"
@@ -52,7 +54,19 @@ func WithSearchPaths(paths []string) Option {
 	}
 }
 
+// MustLoad is like Load but exits the process if the config can't be loaded
 func MustLoad[T any](opts ...Option) *T {
+	cfg, err := Load[T](opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return cfg
+}
+
+// Load loads the config from the files found by the options, the -config flag or CONFIG_PATH.
+// Loads are reported as metrics and traces, see the README.
+func Load[T any](opts ...Option) (_ *T, err error) {
+	start := time.Now()
 	cfg := new(T)
 
 	// Default loader config
@@ -68,9 +82,12 @@ func MustLoad[T any](opts ...Option) *T {
 		opt(loaderCfg)
 	}
 
-	configPath := fetchConfigPath(loaderCfg.SkipFlags)
+	configPath, source := fetchConfigPath(loaderCfg.SkipFlags)
 
 	var files []string
+	defer func() {
+		recordLoad(source, files, start, err)
+	}()
 
 	// If a path to the config is specified, use it
 	if configPath != "" {
@@ -78,11 +95,13 @@ func MustLoad[T any](opts ...Option) *T {
 	} else if len(loaderCfg.Files) > 0 {
 		// Use explicitly provided files
 		files = loaderCfg.Files
+		source = SourceFiles
 	} else {
 		// Auto-discover config files
 		files = discoverConfigFiles(loaderCfg.SearchPaths)
+		source = SourceSearchPaths
 		if len(files) == 0 {
-			log.Fatalf("no config files found in search paths: %v", loaderCfg.SearchPaths)
+			return nil, fmt.Errorf("no config files found in search paths: %v", loaderCfg.SearchPaths)
 		}
 	}
 
@@ -99,13 +118,14 @@ func MustLoad[T any](opts ...Option) *T {
 	})
 
 	if err := loader.Load(); err != nil {
-		log.Fatalf("failed to load config from files %v: %v", files, err)
+		return nil, fmt.Errorf("failed to load config from files %v: %w", files, err)
 	}
 
-	return cfg
+	return cfg, nil
 }
 
-func fetchConfigPath(skipFlags bool) string {
+// fetchConfigPath returns the config path from the -config flag or CONFIG_PATH and its source
+func fetchConfigPath(skipFlags bool) (string, string) {
 	var v string
 
 	if !skipFlags {
@@ -122,13 +142,12 @@ func fetchConfigPath(skipFlags bool) string {
 	if configFlag := flag.Lookup("config"); configFlag != nil {
 		v = configFlag.Value.String()
 	}
-
-	// Fallback to environment variable
-	if v == "" {
-		v = os.Getenv(CONFIG_PATH)
+	if v != "" {
+		return v, SourceFlag
 	}
 
-	return v
+	// Fallback to environment variable
+	return os.Getenv(CONFIG_PATH), SourceEnv
 }
 
 func getDefaultSearchPaths() []string {
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type testConfig struct {
+	Port int `yaml:"port"`
+}
+
+func TestLoadMetrics(t *testing.T) {
+	// Loading happens before the provider is set, as in services initializing observability from the config
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load[testConfig](WithFiles([]string{path}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("port = %d, want 8080", cfg.Port)
+	}
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("port: [\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load[testConfig](WithFiles([]string{invalid})); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	loads := make(map[string]int64)
+	var lastDuration bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case "config_loads_total":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					source, _ := dp.Attributes.Value("source")
+					status, _ := dp.Attributes.Value("status")
+					loads[source.AsString()+"/"+status.AsString()] = dp.Value
+				}
+			case "config_load_duration_seconds":
+				lastDuration = len(m.Data.(metricdata.Gauge[float64]).DataPoints) == 1
+			}
+		}
+	}
+	if loads["files/success"] != 1 || loads["files/error"] != 1 {
+		t.Errorf("loads = %v, want one success and one error from files", loads)
+	}
+	if !lastDuration {
+		t.Error("expected the duration of the last load")
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the instrumentation scope of config metrics and traces
+const instrumentationName = "github.com/rshelekhov/golib/config"
+
+// Sources of config files, reported as the source attribute
+const (
+	SourceFlag        = "flag"         // -config flag
+	SourceEnv         = "env"          // CONFIG_PATH environment variable
+	SourceFiles       = "files"        // WithFiles option
+	SourceSearchPaths = "search_paths" // auto-discovered in search paths
+)
+
+type loadKey struct {
+	source string
+	status string
+}
+
+// loadStats keeps the results of config loads. The config is usually loaded before
+// observability is initialized, so the stats are reported by observable instruments
+// on collection instead of being recorded at load time.
+type loadStats struct {
+	mu           sync.Mutex
+	counts       map[loadKey]int64
+	lastSource   string
+	lastDuration time.Duration
+	lastLoadedAt time.Time
+}
+
+var (
+	stats             = &loadStats{counts: make(map[loadKey]int64)}
+	registerStatsOnce sync.Once
+)
+
+// recordLoad records a config load and traces it
+func recordLoad(source string, files []string, start time.Time, err error) {
+	registerStatsOnce.Do(registerLoadMetrics)
+
+	duration := time.Since(start)
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+
+	stats.mu.Lock()
+	stats.counts[loadKey{source: source, status: status}]++
+	if err == nil {
+		stats.lastSource = source
+		stats.lastDuration = duration
+		stats.lastLoadedAt = start.Add(duration)
+	}
+	stats.mu.Unlock()
+
+	// The span is only exported if a tracer provider is set, e.g. when the config is loaded again
+	_, span := otel.Tracer(instrumentationName).Start(context.Background(), "config.Load",
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("config.source", source),
+			attribute.StringSlice("config.files", files),
+		),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(start.Add(duration)))
+}
+
+// registerLoadMetrics reports config loads through the global meter provider on every collection:
+//   - config_loads_total{source,status} - loads by source and status (success or error)
+//   - config_load_duration_seconds{source} - duration of the last successful load
+//   - config_last_load_timestamp_seconds{source} - time of the last successful load
+func registerLoadMetrics() {
+	meter := otel.GetMeterProvider().Meter(instrumentationName)
+
+	loads, err1 := meter.Int64ObservableCounter("config_loads_total",
+		metric.WithDescription("Total number of config loads by source and status."))
+	duration, err2 := meter.Float64ObservableGauge("config_load_duration_seconds",
+		metric.WithDescription("Duration of the last successful config load in seconds."), metric.WithUnit("s"))
+	loadedAt, err3 := meter.Float64ObservableGauge("config_last_load_timestamp_seconds",
+		metric.WithDescription("Unix time of the last successful config load."), metric.WithUnit("s"))
+	for _, err := range []error{err1, err2, err3} {
+		if err != nil {
+			otel.Handle(err)
+			return
+		}
+	}
+
+	_, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+
+		for k, n := range stats.counts {
+			o.ObserveInt64(loads, n, metric.WithAttributes(
+				attribute.String("source", k.source),
+				attribute.String("status", k.status),
+			))
+		}
+		if !stats.lastLoadedAt.IsZero() {
+			attrs := metric.WithAttributes(attribute.String("source", stats.lastSource))
+			o.ObserveFloat64(duration, stats.lastDuration.Seconds(), attrs)
+			o.ObserveFloat64(loadedAt, float64(stats.lastLoadedAt.UnixNano())/1e9, attrs)
+		}
+		return nil
+	}, loads, duration, loadedAt)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
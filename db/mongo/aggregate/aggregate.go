@@ -0,0 +1,213 @@
+// Package aggregate builds MongoDB aggregation pipelines from typed stages instead of nested bson.M literals.
+// Pipelines are immutable: every stage method returns a new pipeline, so a common prefix can be shared
+// by several queries.
+package aggregate
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Pipeline is a sequence of aggregation stages
+type Pipeline struct {
+	stages mongo.Pipeline
+}
+
+// New creates an empty pipeline
+func New() Pipeline {
+	return Pipeline{}
+}
+
+// Build returns the stages to pass to Aggregate
+func (p Pipeline) Build() mongo.Pipeline {
+	return p.stages
+}
+
+// Stage appends a raw stage, for stages without a dedicated method, e.g. {"$sample": {"size": 10}}
+func (p Pipeline) Stage(stage bson.D) Pipeline {
+	stages := make(mongo.Pipeline, len(p.stages), len(p.stages)+1)
+	copy(stages, p.stages)
+	return Pipeline{stages: append(stages, stage)}
+}
+
+// Append appends the stages of other pipelines
+func (p Pipeline) Append(others ...Pipeline) Pipeline {
+	for _, other := range others {
+		for _, stage := range other.stages {
+			p = p.Stage(stage)
+		}
+	}
+	return p
+}
+
+func (p Pipeline) stage(operator string, value any) Pipeline {
+	return p.Stage(bson.D{{Key: operator, Value: value}})
+}
+
+// Match filters documents with a query filter, e.g. bson.M{"status": "active"}
+func (p Pipeline) Match(filter any) Pipeline {
+	return p.stage("$match", filter)
+}
+
+// Field is a named expression of Group, Project and AddFields
+type Field struct {
+	Name  string
+	Value any
+}
+
+func fieldsDoc(fields []Field) bson.D {
+	d := make(bson.D, 0, len(fields))
+	for _, f := range fields {
+		d = append(d, bson.E{Key: f.Name, Value: f.Value})
+	}
+	return d
+}
+
+// Group groups documents by id, an expression such as "$user_id" or bson.D of several fields
+// (nil groups all documents), computing accumulators such as Sum and Count
+func (p Pipeline) Group(id any, accumulators ...Field) Pipeline {
+	return p.stage("$group", append(bson.D{{Key: "_id", Value: id}}, fieldsDoc(accumulators)...))
+}
+
+func accumulator(operator, name string, expr any) Field {
+	return Field{Name: name, Value: bson.D{{Key: operator, Value: expr}}}
+}
+
+// Sum computes the sum of expr, e.g. Sum("total", "$amount")
+func Sum(name string, expr any) Field { return accumulator("$sum", name, expr) }
+
+// Count counts the documents of a group
+func Count(name string) Field { return accumulator("$sum", name, 1) }
+
+// Avg computes the average of expr
+func Avg(name string, expr any) Field { return accumulator("$avg", name, expr) }
+
+// Min computes the minimum of expr
+func Min(name string, expr any) Field { return accumulator("$min", name, expr) }
+
+// Max computes the maximum of expr
+func Max(name string, expr any) Field { return accumulator("$max", name, expr) }
+
+// First takes expr of the first document of a group
+func First(name string, expr any) Field { return accumulator("$first", name, expr) }
+
+// Last takes expr of the last document of a group
+func Last(name string, expr any) Field { return accumulator("$last", name, expr) }
+
+// Push collects expr of all documents of a group into an array
+func Push(name string, expr any) Field { return accumulator("$push", name, expr) }
+
+// AddToSet collects the distinct values of expr of a group into an array
+func AddToSet(name string, expr any) Field { return accumulator("$addToSet", name, expr) }
+
+// SortKey is a field and direction of Sort
+type SortKey struct {
+	Field     string
+	Direction int
+}
+
+// Asc sorts by field in ascending order
+func Asc(field string) SortKey { return SortKey{Field: field, Direction: 1} }
+
+// Desc sorts by field in descending order
+func Desc(field string) SortKey { return SortKey{Field: field, Direction: -1} }
+
+// Sort orders documents by the keys, in the given order of precedence
+func (p Pipeline) Sort(keys ...SortKey) Pipeline {
+	d := make(bson.D, 0, len(keys))
+	for _, k := range keys {
+		d = append(d, bson.E{Key: k.Field, Value: k.Direction})
+	}
+	return p.stage("$sort", d)
+}
+
+// Skip skips n documents
+func (p Pipeline) Skip(n int64) Pipeline {
+	return p.stage("$skip", n)
+}
+
+// Limit passes at most n documents
+func (p Pipeline) Limit(n int64) Pipeline {
+	return p.stage("$limit", n)
+}
+
+// Lookup joins documents of another collection, either by equality of LocalField and ForeignField
+// or with a Pipeline using the Let variables, or both
+type Lookup struct {
+	From         string
+	LocalField   string
+	ForeignField string
+	Let          bson.D
+	Pipeline     Pipeline
+	As           string
+}
+
+// Lookup joins documents of another collection into the As array field
+func (p Pipeline) Lookup(l Lookup) Pipeline {
+	d := bson.D{{Key: "from", Value: l.From}}
+	if l.LocalField != "" || l.ForeignField != "" {
+		d = append(d, bson.E{Key: "localField", Value: l.LocalField}, bson.E{Key: "foreignField", Value: l.ForeignField})
+	}
+	if len(l.Let) > 0 {
+		d = append(d, bson.E{Key: "let", Value: l.Let})
+	}
+	if len(l.Pipeline.stages) > 0 {
+		d = append(d, bson.E{Key: "pipeline", Value: l.Pipeline.stages})
+	}
+	return p.stage("$lookup", append(d, bson.E{Key: "as", Value: l.As}))
+}
+
+// Unwind outputs a document for each element of the array at path, e.g. "$items".
+// With preserveEmpty, documents without elements are kept.
+func (p Pipeline) Unwind(path string, preserveEmpty bool) Pipeline {
+	if !preserveEmpty {
+		return p.stage("$unwind", path)
+	}
+	return p.stage("$unwind", bson.D{{Key: "path", Value: path}, {Key: "preserveNullAndEmptyArrays", Value: true}})
+}
+
+// Include keeps a field in Project
+func Include(name string) Field { return Field{Name: name, Value: 1} }
+
+// Exclude removes a field in Project, e.g. Exclude("_id")
+func Exclude(name string) Field { return Field{Name: name, Value: 0} }
+
+// Computed sets a field to an expression in Project or AddFields, e.g. Computed("year", bson.D{{"$year", "$created_at"}})
+func Computed(name string, expr any) Field { return Field{Name: name, Value: expr} }
+
+// Project reshapes documents with Include, Exclude and Computed fields
+func (p Pipeline) Project(fields ...Field) Pipeline {
+	return p.stage("$project", fieldsDoc(fields))
+}
+
+// AddFields adds or replaces fields, keeping the others
+func (p Pipeline) AddFields(fields ...Field) Pipeline {
+	return p.stage("$addFields", fieldsDoc(fields))
+}
+
+// Facet runs several pipelines on the same input documents, outputting a single document
+// with the result of each pipeline in the field of its name
+func (p Pipeline) Facet(facets map[string]Pipeline) Pipeline {
+	names := make([]string, 0, len(facets))
+	for name := range facets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	d := make(bson.D, 0, len(facets))
+	for _, name := range names {
+		stages := facets[name].stages
+		if stages == nil {
+			stages = mongo.Pipeline{}
+		}
+		d = append(d, bson.E{Key: name, Value: stages})
+	}
+	return p.stage("$facet", d)
+}
+
+// CountAs outputs a single document with the number of input documents in field
+func (p Pipeline) CountAs(field string) Pipeline {
+	return p.stage("$count", field)
+}
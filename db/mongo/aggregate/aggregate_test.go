@@ -0,0 +1,180 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPipeline(t *testing.T) {
+	active := New().Match(bson.M{"status": "active"})
+
+	got := active.
+		Lookup(Lookup{From: "users", LocalField: "user_id", ForeignField: "_id", As: "user"}).
+		Unwind("$user", true).
+		Group("$user.country", Count("orders"), Sum("revenue", "$amount")).
+		Sort(Desc("revenue"), Asc("_id")).
+		Project(Exclude("_id"), Include("orders"), Computed("country", "$_id")).
+		Build()
+
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "active"}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"},
+			{Key: "localField", Value: "user_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "user"},
+		}}},
+		{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$user"}, {Key: "preserveNullAndEmptyArrays", Value: true}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$user.country"},
+			{Key: "orders", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "revenue", Value: bson.D{{Key: "$sum", Value: "$amount"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "revenue", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}, {Key: "orders", Value: 1}, {Key: "country", Value: "$_id"}}}},
+	}
+	assert.Equal(t, want, got)
+
+	// Stages do not modify the pipeline they extend
+	assert.Len(t, active.Build(), 1)
+}
+
+func TestGroupCount(t *testing.T) {
+	// The builder form of the raw pipeline of TestAggregate in the mongo package
+	got := New().
+		Match(bson.M{"value": 42}).
+		Group("$value", Count("count")).
+		Build()
+
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"value": 42}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$value"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestAccumulators(t *testing.T) {
+	got := New().Group(nil,
+		Avg("avg", "$price"),
+		Min("min", "$price"),
+		Max("max", "$price"),
+		First("first", "$name"),
+		Last("last", "$name"),
+		Push("names", "$name"),
+		AddToSet("tags", "$tag"),
+	).Build()
+
+	want := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "avg", Value: bson.D{{Key: "$avg", Value: "$price"}}},
+			{Key: "min", Value: bson.D{{Key: "$min", Value: "$price"}}},
+			{Key: "max", Value: bson.D{{Key: "$max", Value: "$price"}}},
+			{Key: "first", Value: bson.D{{Key: "$first", Value: "$name"}}},
+			{Key: "last", Value: bson.D{{Key: "$last", Value: "$name"}}},
+			{Key: "names", Value: bson.D{{Key: "$push", Value: "$name"}}},
+			{Key: "tags", Value: bson.D{{Key: "$addToSet", Value: "$tag"}}},
+		}}},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestStages(t *testing.T) {
+	orders := New().Match(bson.M{"user_id": "$$user"})
+
+	got := New().
+		Lookup(Lookup{From: "orders", Let: bson.D{{Key: "user", Value: "$_id"}}, Pipeline: orders, As: "orders"}).
+		Unwind("$orders", false).
+		AddFields(Computed("total", "$orders.amount")).
+		Skip(10).
+		Limit(5).
+		Stage(bson.D{{Key: "$sample", Value: bson.D{{Key: "size", Value: 3}}}}).
+		Append(New().CountAs("n")).
+		Build()
+
+	want := mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "orders"},
+			{Key: "let", Value: bson.D{{Key: "user", Value: "$_id"}}},
+			{Key: "pipeline", Value: mongo.Pipeline{{{Key: "$match", Value: bson.M{"user_id": "$$user"}}}}},
+			{Key: "as", Value: "orders"},
+		}}},
+		{{Key: "$unwind", Value: "$orders"}},
+		{{Key: "$addFields", Value: bson.D{{Key: "total", Value: "$orders.amount"}}}},
+		{{Key: "$skip", Value: int64(10)}},
+		{{Key: "$limit", Value: int64(5)}},
+		{{Key: "$sample", Value: bson.D{{Key: "size", Value: 3}}}},
+		{{Key: "$count", Value: "n"}},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestFacet(t *testing.T) {
+	got := New().Facet(map[string]Pipeline{
+		"total": New().CountAs("count"),
+		"all":   New(),
+		"top":   New().Sort(Desc("score")).Limit(3),
+	}).Build()
+
+	// Facets are sorted by name, so the stage is deterministic
+	want := mongo.Pipeline{
+		{{Key: "$facet", Value: bson.D{
+			{Key: "all", Value: mongo.Pipeline{}},
+			{Key: "top", Value: mongo.Pipeline{
+				{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}}}},
+				{{Key: "$limit", Value: int64(3)}},
+			}},
+			{Key: "total", Value: mongo.Pipeline{{{Key: "$count", Value: "count"}}}},
+		}}},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestPaginate(t *testing.T) {
+	got := New().Sort(Asc("name")).Paginate(3, 20).Build()
+
+	want := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "items", Value: mongo.Pipeline{
+				{{Key: "$skip", Value: int64(40)}},
+				{{Key: "$limit", Value: int64(20)}},
+			}},
+			{Key: "total", Value: mongo.Pipeline{
+				{{Key: "$count", Value: "count"}},
+			}},
+		}}},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDecodePage(t *testing.T) {
+	type item struct {
+		Name string `bson:"name"`
+	}
+	doc := bson.M{
+		"items": bson.A{bson.M{"name": "a"}, bson.M{"name": "b"}},
+		"total": bson.A{bson.M{"count": int64(42)}},
+	}
+	cursor, err := mongo.NewCursorFromDocuments([]any{doc}, nil, nil)
+	assert.NoError(t, err)
+
+	page, err := DecodePage[item](t.Context(), cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, Page[item]{Items: []item{{Name: "a"}, {Name: "b"}}, Total: 42}, page)
+
+	// No matched documents
+	cursor, err = mongo.NewCursorFromDocuments([]any{bson.M{"items": bson.A{}, "total": bson.A{}}}, nil, nil)
+	assert.NoError(t, err)
+
+	page, err = DecodePage[item](t.Context(), cursor)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Zero(t, page.Total)
+}
@@ -0,0 +1,63 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Field names of the document produced by Paginate
+const (
+	PageItemsField = "items"
+	PageTotalField = "total"
+)
+
+// Paginate appends a facet returning a single document with the requested page in "items"
+// and the number of all matched documents in "total", so both come from one query.
+// Pages are numbered from 1; sort before paginating for a stable order.
+func (p Pipeline) Paginate(page, pageSize int64) Pipeline {
+	if page < 1 {
+		page = 1
+	}
+	return p.Facet(map[string]Pipeline{
+		PageItemsField: New().Skip((page - 1) * pageSize).Limit(pageSize),
+		PageTotalField: New().CountAs("count"),
+	})
+}
+
+// Page is the decoded result of a paginated pipeline
+type Page[T any] struct {
+	Items []T
+	Total int64
+}
+
+type pageResult[T any] struct {
+	Items []T `bson:"items"`
+	Total []struct {
+		Count int64 `bson:"count"`
+	} `bson:"total"`
+}
+
+// DecodePage decodes the cursor of a pipeline built with Paginate and closes it
+func DecodePage[T any](ctx context.Context, cursor *mongo.Cursor) (Page[T], error) {
+	defer cursor.Close(ctx)
+
+	var page Page[T]
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return page, fmt.Errorf("failed to read page: %w", err)
+		}
+		return page, nil
+	}
+
+	var result pageResult[T]
+	if err := cursor.Decode(&result); err != nil {
+		return page, fmt.Errorf("failed to decode page: %w", err)
+	}
+	page.Items = result.Items
+	if len(result.Total) > 0 {
+		page.Total = result.Total[0].Count
+	}
+	return page, nil
+}
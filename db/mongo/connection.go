@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // Connection represents a connection to MongoDB.
@@ -15,11 +16,14 @@ type Connection struct {
 	client   *mongo.Client
 	database *mongo.Database
 	timeout  time.Duration
+	metrics  metric.Registration
 }
 
 // connectionOptions holds configuration for MongoDB connection
 type connectionOptions struct {
 	enableTracing bool
+	enableMetrics bool
+	poolName      string
 	timeout       *time.Duration
 	serverAPI     *string
 }
@@ -48,6 +52,20 @@ func WithTracing(enable bool) ConnectionOption {
 	}
 }
 
+// WithMetrics turns on/off connection pool metrics (connections, waits, timeouts) exported through OpenTelemetry
+func WithMetrics(enable bool) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.enableMetrics = enable
+	}
+}
+
+// WithPoolName sets the pool attribute of pool metrics (default: database name).
+func WithPoolName(name string) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.poolName = name
+	}
+}
+
 // NewConnection creates a new connection to MongoDB.
 func NewConnection(ctx context.Context, uri string, dbName string, opts ...ConnectionOption) (ConnectionManager, error) {
 	clientOpts := options.Client().ApplyURI(uri)
@@ -55,6 +73,8 @@ func NewConnection(ctx context.Context, uri string, dbName string, opts ...Conne
 	// Apply default options
 	connOpts := &connectionOptions{
 		enableTracing: true, // default is true
+		enableMetrics: true, // default is true
+		poolName:      dbName,
 	}
 
 	for _, opt := range opts {
@@ -68,6 +88,13 @@ func NewConnection(ctx context.Context, uri string, dbName string, opts ...Conne
 		clientOpts.SetMonitor(otelmongo.NewMonitor())
 	}
 
+	// Apply pool metrics if enabled
+	var poolStats *poolMetrics
+	if connOpts.enableMetrics {
+		poolStats = newPoolMetrics(connOpts.poolName)
+		clientOpts.SetPoolMonitor(poolStats.monitor())
+	}
+
 	// Apply timeout
 	if connOpts.timeout != nil {
 		clientOpts.SetConnectTimeout(*connOpts.timeout)
@@ -95,11 +122,22 @@ func NewConnection(ctx context.Context, uri string, dbName string, opts ...Conne
 		timeout:  DefaultConnectionTimeout,
 	}
 
+	if poolStats != nil {
+		conn.metrics, err = poolStats.register()
+		if err != nil {
+			_ = client.Disconnect(ctx)
+			return nil, fmt.Errorf("failed to register pool metrics: %w", err)
+		}
+	}
+
 	return conn, nil
 }
 
 // Close closes the connection to MongoDB.
 func (c *Connection) Close(ctx context.Context) error {
+	if c.metrics != nil {
+		_ = c.metrics.Unregister()
+	}
 	return c.client.Disconnect(ctx)
 }
 
@@ -0,0 +1,128 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of pool metrics
+const meterName = "github.com/rshelekhov/golib/db/mongo"
+
+// poolMetrics tracks connection pool events of every server the client connects to
+type poolMetrics struct {
+	name string
+
+	mu      sync.Mutex
+	servers map[string]*serverPoolStats
+}
+
+type serverPoolStats struct {
+	maxConns     int64
+	totalConns   int64
+	inUseConns   int64
+	checkouts    int64
+	waitDuration time.Duration
+	timeouts     int64
+}
+
+func newPoolMetrics(name string) *poolMetrics {
+	return &poolMetrics{name: name, servers: make(map[string]*serverPoolStats)}
+}
+
+// monitor returns the pool monitor feeding the metrics
+func (m *poolMetrics) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: m.handle}
+}
+
+func (m *poolMetrics) handle(e *event.PoolEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e.Type == event.PoolClosedEvent {
+		delete(m.servers, e.Address)
+		return
+	}
+
+	s, ok := m.servers[e.Address]
+	if !ok {
+		s = &serverPoolStats{}
+		m.servers[e.Address] = s
+	}
+
+	switch e.Type {
+	case event.PoolCreated:
+		if e.PoolOptions != nil {
+			s.maxConns = int64(e.PoolOptions.MaxPoolSize)
+		}
+	case event.ConnectionCreated:
+		s.totalConns++
+	case event.ConnectionClosed:
+		s.totalConns--
+	case event.GetSucceeded:
+		s.inUseConns++
+		s.checkouts++
+		s.waitDuration += e.Duration
+	case event.GetFailed:
+		s.checkouts++
+		s.waitDuration += e.Duration
+		if e.Reason == event.ReasonTimedOut {
+			s.timeouts++
+		}
+	case event.ConnectionReturned:
+		s.inUseConns--
+	}
+}
+
+// register reports the tracked stats through the global meter provider on every collection:
+//   - db_pool_connections{state} - idle and in_use connections
+//   - db_pool_max_connections
+//   - db_pool_wait_count_total - connection checkouts
+//   - db_pool_wait_duration_seconds_total - time spent checking out connections
+//   - db_pool_timeouts_total - checkouts that timed out
+//
+// All metrics have db_system, pool and address attributes, one address per server.
+func (m *poolMetrics) register() (metric.Registration, error) {
+	meter := otel.GetMeterProvider().Meter(meterName)
+
+	connections, err1 := meter.Int64ObservableGauge("db_pool_connections",
+		metric.WithDescription("Number of connections in the pool by state."))
+	maxConnections, err2 := meter.Int64ObservableGauge("db_pool_max_connections",
+		metric.WithDescription("Maximum number of connections in the pool."))
+	waitCount, err3 := meter.Int64ObservableCounter("db_pool_wait_count_total",
+		metric.WithDescription("Total number of times a connection was waited for."))
+	waitDuration, err4 := meter.Float64ObservableCounter("db_pool_wait_duration_seconds_total",
+		metric.WithDescription("Total time spent waiting for a connection in seconds."), metric.WithUnit("s"))
+	timeouts, err5 := meter.Int64ObservableCounter("db_pool_timeouts_total",
+		metric.WithDescription("Total number of connection waits that timed out."))
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for address, s := range m.servers {
+			base := metric.WithAttributes(
+				attribute.String("db_system", "mongodb"),
+				attribute.String("pool", m.name),
+				attribute.String("address", address),
+			)
+
+			o.ObserveInt64(connections, s.totalConns-s.inUseConns, base, metric.WithAttributes(attribute.String("state", "idle")))
+			o.ObserveInt64(connections, s.inUseConns, base, metric.WithAttributes(attribute.String("state", "in_use")))
+			o.ObserveInt64(maxConnections, s.maxConns, base)
+			o.ObserveInt64(waitCount, s.checkouts, base)
+			o.ObserveFloat64(waitDuration, s.waitDuration.Seconds(), base)
+			o.ObserveInt64(timeouts, s.timeouts, base)
+		}
+		return nil
+	}, connections, maxConnections, waitCount, waitDuration, timeouts)
+}
@@ -0,0 +1,65 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestPoolMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	m := newPoolMetrics("orders")
+	reg, err := m.register()
+	require.NoError(t, err)
+	defer reg.Unregister()
+
+	monitor := m.monitor()
+	const addr = "mongo-0:27017"
+	for _, e := range []*event.PoolEvent{
+		{Type: event.PoolCreated, Address: addr, PoolOptions: &event.MonitorPoolOptions{MaxPoolSize: 100}},
+		{Type: event.ConnectionCreated, Address: addr},
+		{Type: event.ConnectionCreated, Address: addr},
+		{Type: event.GetSucceeded, Address: addr, Duration: time.Second},
+		{Type: event.GetSucceeded, Address: addr, Duration: time.Second},
+		{Type: event.ConnectionReturned, Address: addr},
+		{Type: event.GetFailed, Address: addr, Reason: event.ReasonTimedOut, Duration: time.Second},
+	} {
+		monitor.Event(e)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	values := map[string]float64{}
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		switch data := metric.Data.(type) {
+		case metricdata.Gauge[int64]:
+			for _, dp := range data.DataPoints {
+				state, _ := dp.Attributes.Value("state")
+				values[metric.Name+state.AsString()] = float64(dp.Value)
+			}
+		case metricdata.Sum[int64]:
+			values[metric.Name] = float64(data.DataPoints[0].Value)
+		case metricdata.Sum[float64]:
+			values[metric.Name] = data.DataPoints[0].Value
+		}
+	}
+
+	assert.Equal(t, float64(1), values["db_pool_connectionsidle"])
+	assert.Equal(t, float64(1), values["db_pool_connectionsin_use"])
+	assert.Equal(t, float64(100), values["db_pool_max_connections"])
+	assert.Equal(t, float64(3), values["db_pool_wait_count_total"])
+	assert.Equal(t, float64(3), values["db_pool_wait_duration_seconds_total"])
+	assert.Equal(t, float64(1), values["db_pool_timeouts_total"])
+}
@@ -0,0 +1,309 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/rshelekhov/golib/db/s3"
+)
+
+// ErrChecksumMismatch is returned when a dump does not match its manifest
+var ErrChecksumMismatch = errors.New("backup checksum mismatch")
+
+// Backuper backs up a PostgreSQL database to S3 with pg_dump and restores it with pg_restore.
+// Dumps are streamed without temporary files.
+type Backuper struct {
+	storage s3.HelperAPI
+	bucket  string
+	dsn     string
+	aead    cipher.AEAD
+	opts    *options
+}
+
+// NewBackuper creates a Backuper for the database at dsn storing backups in bucket.
+// pg_dump and pg_restore must be installed with a version compatible with the server.
+func NewBackuper(storage s3.HelperAPI, bucket, dsn string, opts ...Option) (*Backuper, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	b := &Backuper{
+		storage: storage,
+		bucket:  bucket,
+		dsn:     dsn,
+		opts:    o,
+	}
+
+	if o.encryptionKey != nil {
+		aead, err := newAEAD(o.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+		b.aead = aead
+	}
+
+	return b, nil
+}
+
+// Backup dumps the database to a new object under the prefix and uploads its manifest.
+// The manifest is written last, so a backup without a manifest is incomplete.
+func (b *Backuper) Backup(ctx context.Context) (Manifest, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now().UTC()
+	key := path.Join(b.opts.prefix, start.Format(keyTimeFormat)+dumpSuffix)
+
+	args := append([]string{"--format=" + manifestFormat, "--dbname=" + b.dsn}, b.opts.dumpArgs...)
+	cmd := b.command(ctx, b.opts.pgDump, args)
+	stderr := &limitedBuffer{limit: maxStderr}
+	cmd.Stderr = stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to create pg_dump pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return Manifest{}, fmt.Errorf("failed to start pg_dump: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	checksum := sha256.New()
+	dumpErr := make(chan error, 1)
+
+	go func() {
+		err := b.dump(io.MultiWriter(pw, checksum), stdout)
+		if waitErr := cmd.Wait(); waitErr != nil && err == nil {
+			err = fmt.Errorf("pg_dump failed: %w: %s", waitErr, bytes.TrimSpace(stderr.Bytes()))
+		}
+		pw.CloseWithError(err)
+		dumpErr <- err
+	}()
+
+	size, uploadErr := b.storage.UploadLargeObject(ctx, b.bucket, key, pr, b.opts.partSize)
+	if uploadErr != nil {
+		// Stop pg_dump blocked on the pipe
+		pr.CloseWithError(uploadErr)
+		cancel()
+	}
+	if err := <-dumpErr; err != nil {
+		return Manifest{}, err
+	}
+	if uploadErr != nil {
+		return Manifest{}, fmt.Errorf("failed to upload dump: %w", uploadErr)
+	}
+
+	m := Manifest{
+		Key:       key,
+		Format:    manifestFormat,
+		Encrypted: b.aead != nil,
+		CreatedAt: start,
+		Duration:  time.Since(start).String(),
+		Size:      size,
+		SHA256:    hex.EncodeToString(checksum.Sum(nil)),
+	}
+	if err := b.putManifest(ctx, m); err != nil {
+		return Manifest{}, err
+	}
+	return m, nil
+}
+
+// dump copies the pg_dump output to w, encrypting it if configured
+func (b *Backuper) dump(w io.Writer, r io.Reader) error {
+	if b.aead == nil {
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	enc, err := newEncryptWriter(w, b.aead)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt dump: %w", err)
+	}
+	if _, err := io.Copy(enc, r); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Restore restores the dump with the given key into the database.
+// Encrypted dumps are authenticated chunk by chunk while streaming. Plain dumps are checked against
+// the manifest checksum only after pg_restore finishes; call Verify first to check them beforehand.
+func (b *Backuper) Restore(ctx context.Context, key string) error {
+	m, body, sum, err := b.open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	reader, err := b.reader(m, body)
+	if err != nil {
+		return err
+	}
+
+	args := append(append([]string{}, b.opts.restoreArgs...), "--dbname="+b.dsn)
+	cmd := b.command(ctx, b.opts.pgRestore, args)
+	stderr := &limitedBuffer{limit: maxStderr}
+	cmd.Stderr = stderr
+	input := &errReader{r: reader}
+	cmd.Stdin = input
+
+	runErr := cmd.Run()
+	// A decryption or download error explains a pg_restore failure better than its output
+	if input.err != nil {
+		return fmt.Errorf("failed to read dump: %w", input.err)
+	}
+	if runErr != nil {
+		return fmt.Errorf("pg_restore failed: %w: %s", runErr, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return fmt.Errorf("failed to read dump: %w", err)
+	}
+	return sum.check(m)
+}
+
+// Verify downloads the dump with the given key and checks it against its manifest
+func (b *Backuper) Verify(ctx context.Context, key string) error {
+	m, body, sum, err := b.open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	reader, err := b.reader(m, body)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return fmt.Errorf("failed to read dump: %w", err)
+	}
+	return sum.check(m)
+}
+
+// open downloads the manifest and the dump, computing the checksum of the dump as it is read
+func (b *Backuper) open(ctx context.Context, key string) (Manifest, io.ReadCloser, *checksumReader, error) {
+	m, err := b.Manifest(ctx, key)
+	if err != nil {
+		return Manifest{}, nil, nil, err
+	}
+	if m.Encrypted && b.aead == nil {
+		return Manifest{}, nil, nil, errors.New("backup is encrypted, but no encryption key is configured")
+	}
+
+	body, err := b.storage.GetObjectSimple(ctx, b.bucket, key)
+	if err != nil {
+		return Manifest{}, nil, nil, fmt.Errorf("failed to download dump: %w", err)
+	}
+
+	sum := &checksumReader{r: body, hash: sha256.New()}
+	return m, readCloser{Reader: sum, Closer: body}, sum, nil
+}
+
+// reader returns the plain dump read from body
+func (b *Backuper) reader(m Manifest, body io.Reader) (io.Reader, error) {
+	if !m.Encrypted {
+		return body, nil
+	}
+	return newDecryptReader(body, b.aead)
+}
+
+func (b *Backuper) command(ctx context.Context, name string, args []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if len(b.opts.env) > 0 {
+		cmd.Env = append(os.Environ(), b.opts.env...)
+	}
+	return cmd
+}
+
+// Schedule runs Backup every interval until ctx is canceled, logging the results.
+// A failed backup is retried after a minute, or after interval if it is shorter.
+func (b *Backuper) Schedule(ctx context.Context, interval time.Duration) {
+	delay := interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		m, err := b.Backup(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.opts.logger.ErrorContext(ctx, "postgres backup failed", "error", err)
+			delay = min(retryDelay, interval)
+			continue
+		}
+
+		b.opts.logger.InfoContext(ctx, "postgres backup completed", "key", m.Key, "size", m.Size, "duration", m.Duration)
+		delay = interval
+	}
+}
+
+// checksumReader hashes and counts the bytes read
+type checksumReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+func (c *checksumReader) check(m Manifest) error {
+	if c.size != m.Size || hex.EncodeToString(c.hash.Sum(nil)) != m.SHA256 {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader remembers the first read error other than io.EOF
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil && err != io.EOF && e.err == nil {
+		e.err = err
+	}
+	return n, err
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if room := l.limit - l.Len(); room > 0 {
+		l.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
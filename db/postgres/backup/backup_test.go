@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rshelekhov/golib/db/s3"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage keeps objects in memory
+type memoryStorage struct {
+	s3.HelperAPI
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (m *memoryStorage) put(key string, data io.Reader) (int64, error) {
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = b
+	return int64(len(b)), nil
+}
+
+func (m *memoryStorage) PutObjectSimple(_ context.Context, _, key string, data io.Reader, _ string) error {
+	_, err := m.put(key, data)
+	return err
+}
+
+func (m *memoryStorage) UploadLargeObject(_ context.Context, _, key string, data io.Reader, _ int64) (int64, error) {
+	return m.put(key, data)
+}
+
+func (m *memoryStorage) GetObjectSimple(_ context.Context, _, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return io.NopCloser(bytes.NewReader(m.objects[key])), nil
+}
+
+// script writes an executable shell script standing in for pg_dump or pg_restore
+func script(t *testing.T, name, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+	return path
+}
+
+func TestBackupRestore(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+
+	dump := strings.Repeat("PGDMP custom format ", 10000)
+	restored := filepath.Join(t.TempDir(), "restored")
+
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "plain"},
+		{name: "encrypted", opts: []Option{WithEncryptionKey(bytes.Repeat([]byte{7}, KeySize))}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &memoryStorage{objects: map[string][]byte{}}
+			opts := append([]Option{
+				WithPrefix("db"),
+				WithPgDump(script(t, "pg_dump", "printf '%s' '"+dump+"'")),
+				WithPgRestore(script(t, "pg_restore", "cat > "+restored)),
+			}, tt.opts...)
+
+			b, err := NewBackuper(storage, "bucket", "postgres://localhost/app", opts...)
+			require.NoError(t, err)
+
+			m, err := b.Backup(context.Background())
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(m.Key, "db/"))
+			require.Equal(t, len(tt.opts) > 0, m.Encrypted)
+			require.Equal(t, int64(len(storage.objects[m.Key])), m.Size)
+			require.Contains(t, storage.objects, ManifestKey(m.Key))
+
+			require.NoError(t, b.Verify(context.Background(), m.Key))
+			require.NoError(t, b.Restore(context.Background(), m.Key))
+
+			data, err := os.ReadFile(restored)
+			require.NoError(t, err)
+			require.Equal(t, dump, string(data))
+
+			storage.objects[m.Key][len(storage.objects[m.Key])-1] ^= 1
+			require.Error(t, b.Verify(context.Background(), m.Key))
+		})
+	}
+}
+
+func TestBackupFailure(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+
+	storage := &memoryStorage{objects: map[string][]byte{}}
+	b, err := NewBackuper(storage, "bucket", "postgres://localhost/app",
+		WithPgDump(script(t, "pg_dump", "echo 'connection refused' >&2; exit 1")))
+	require.NoError(t, err)
+
+	_, err = b.Backup(context.Background())
+	require.ErrorContains(t, err, "connection refused")
+	for key := range storage.objects {
+		require.False(t, strings.HasSuffix(key, manifestSuffix), "manifest written for failed backup")
+	}
+}
+
+func TestNewBackuperInvalidKey(t *testing.T) {
+	_, err := NewBackuper(&memoryStorage{}, "bucket", "", WithEncryptionKey([]byte("short")))
+	require.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package backup
+
+import "time"
+
+const (
+	// DefaultPrefix is the default key prefix of backups in the bucket
+	DefaultPrefix = "postgres"
+	// DefaultPgDump is the default pg_dump executable
+	DefaultPgDump = "pg_dump"
+	// DefaultPgRestore is the default pg_restore executable
+	DefaultPgRestore = "pg_restore"
+	// KeySize is the size of the AES-256 encryption key
+	KeySize = 32
+)
+
+const (
+	dumpSuffix     = ".dump"
+	manifestSuffix = ".manifest.json"
+	keyTimeFormat  = "20060102T150405Z"
+	manifestFormat = "custom"
+	// maxStderr limits the pg_dump/pg_restore output kept for error messages
+	maxStderr = 4 * 1024
+	// retryDelay is the delay before Schedule retries a failed backup
+	retryDelay = time.Minute
+)
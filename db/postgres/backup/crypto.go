@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encrypted dumps are split into chunks sealed with AES-256-GCM:
+//
+//	magic | nonce prefix (7 bytes) | { ciphertext length (4 bytes) | ciphertext }...
+//
+// The nonce of a chunk is the prefix, the chunk counter (4 bytes) and a last-chunk flag (1 byte),
+// so reordered, duplicated or truncated chunks fail authentication.
+const (
+	chunkSize   = 64 * 1024
+	prefixSize  = 7
+	counterSize = 4
+)
+
+var magic = []byte("GLPGB\x01")
+
+// ErrCorrupted is returned when an encrypted dump fails authentication
+var ErrCorrupted = errors.New("backup is corrupted or the encryption key is wrong")
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, prefixSize+counterSize+1)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptWriter encrypts the written data. Close must be called to write the last chunk.
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	out     []byte
+}
+
+func newEncryptWriter(w io.Writer, aead cipher.AEAD) (*encryptWriter, error) {
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(append([]byte{}, magic...), prefix...)); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: prefix,
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// A full chunk is sealed only when more data arrives, so the last one is known at Close
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	if e.counter == ^uint32(0) {
+		return errors.New("backup is too large to encrypt")
+	}
+
+	e.out = binary.BigEndian.AppendUint32(e.out[:0], uint32(len(e.buf)+e.aead.Overhead()))
+	e.out = e.aead.Seal(e.out, chunkNonce(e.prefix, e.counter, last), e.buf, nil)
+	e.counter++
+	e.buf = e.buf[:0]
+
+	_, err := e.w.Write(e.out)
+	return err
+}
+
+// decryptReader decrypts data written by encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	in      []byte
+	plain   []byte
+	done    bool
+}
+
+func newDecryptReader(r io.Reader, aead cipher.AEAD) (*decryptReader, error) {
+	header := make([]byte, len(magic)+prefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCorrupted, err)
+	}
+	if string(header[:len(magic)]) != string(magic) {
+		return nil, fmt.Errorf("%w: unknown format", ErrCorrupted)
+	}
+	return &decryptReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		prefix: header[len(magic):],
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	var size [4]byte
+	if _, err := io.ReadFull(d.r, size[:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrCorrupted, err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < uint32(d.aead.Overhead()) || n > chunkSize+uint32(d.aead.Overhead()) {
+		return fmt.Errorf("%w: invalid chunk size", ErrCorrupted)
+	}
+
+	if cap(d.in) < int(n) {
+		d.in = make([]byte, n)
+	}
+	d.in = d.in[:n]
+	if _, err := io.ReadFull(d.r, d.in); err != nil {
+		return fmt.Errorf("%w: %w", ErrCorrupted, err)
+	}
+
+	// The chunk is the last one if no data follows it
+	_, err := d.r.Peek(1)
+	last := err == io.EOF
+	if err != nil && !last {
+		return err
+	}
+
+	plain, err := d.aead.Open(d.in[:0], chunkNonce(d.prefix, d.counter, last), d.in, nil)
+	if err != nil {
+		return ErrCorrupted
+	}
+	d.counter++
+	d.plain = plain
+	d.done = last
+	return nil
+}
@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryption(t *testing.T) {
+	key := make([]byte, KeySize)
+	_, _ = rand.Read(key)
+	aead, err := newAEAD(key)
+	require.NoError(t, err)
+
+	encrypt := func(t *testing.T, data []byte) []byte {
+		var out bytes.Buffer
+		enc, err := newEncryptWriter(&out, aead)
+		require.NoError(t, err)
+		_, err = enc.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, enc.Close())
+		return out.Bytes()
+	}
+	decrypt := func(data []byte) ([]byte, error) {
+		dec, err := newDecryptReader(bytes.NewReader(data), aead)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(dec)
+	}
+
+	for _, size := range []int{0, 1, chunkSize, 3*chunkSize + 17} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+
+		plain, err := decrypt(encrypt(t, data))
+		require.NoError(t, err, "size %d", size)
+		require.Equal(t, data, plain, "size %d", size)
+	}
+
+	data := make([]byte, 2*chunkSize+1)
+	encrypted := encrypt(t, data)
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Clone(encrypted)
+		tampered[len(magic)+prefixSize+10] ^= 1
+		_, err := decrypt(tampered)
+		require.ErrorIs(t, err, ErrCorrupted)
+	})
+
+	t.Run("truncated at chunk boundary", func(t *testing.T) {
+		// Header and the first sealed chunk only
+		boundary := len(magic) + prefixSize + 4 + chunkSize + aead.Overhead()
+		_, err := decrypt(encrypted[:boundary])
+		require.ErrorIs(t, err, ErrCorrupted)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other := make([]byte, KeySize)
+		otherAEAD, err := newAEAD(other)
+		require.NoError(t, err)
+		dec, err := newDecryptReader(bytes.NewReader(encrypted), otherAEAD)
+		require.NoError(t, err)
+		_, err = io.ReadAll(dec)
+		require.ErrorIs(t, err, ErrCorrupted)
+	})
+}
@@ -0,0 +1,54 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Manifest describes a backup. It is stored next to the dump with the ".manifest.json" suffix.
+type Manifest struct {
+	// Key is the key of the dump in the bucket
+	Key string `json:"key"`
+	// Format is the pg_dump output format
+	Format    string    `json:"format"`
+	Encrypted bool      `json:"encrypted"`
+	CreatedAt time.Time `json:"created_at"`
+	Duration  string    `json:"duration"`
+	// Size and SHA256 describe the stored (possibly encrypted) object
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestKey returns the key of the manifest of the dump with the given key
+func ManifestKey(key string) string {
+	return key + manifestSuffix
+}
+
+func (b *Backuper) putManifest(ctx context.Context, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := b.storage.PutObjectSimple(ctx, b.bucket, ManifestKey(m.Key), bytes.NewReader(data), ""); err != nil {
+		return fmt.Errorf("failed to upload manifest: %w", err)
+	}
+	return nil
+}
+
+// Manifest downloads the manifest of the dump with the given key
+func (b *Backuper) Manifest(ctx context.Context, key string) (Manifest, error) {
+	body, err := b.storage.GetObjectSimple(ctx, b.bucket, ManifestKey(key))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to download manifest: %w", err)
+	}
+	defer body.Close()
+
+	var m Manifest
+	if err := json.NewDecoder(body).Decode(&m); err != nil {
+		return Manifest{}, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"log/slog"
+
+	"github.com/rshelekhov/golib/db/s3"
+)
+
+// options holds configuration for Backuper
+type options struct {
+	prefix        string
+	encryptionKey []byte
+	pgDump        string
+	pgRestore     string
+	dumpArgs      []string
+	restoreArgs   []string
+	env           []string
+	partSize      int64
+	logger        *slog.Logger
+}
+
+// Option is a function that configures Backuper options.
+type Option func(opts *options)
+
+// WithPrefix sets the key prefix of backups in the bucket (default: DefaultPrefix).
+func WithPrefix(prefix string) Option {
+	return func(opts *options) {
+		opts.prefix = prefix
+	}
+}
+
+// WithEncryptionKey encrypts backups with AES-256-GCM. The key must be KeySize bytes long.
+func WithEncryptionKey(key []byte) Option {
+	return func(opts *options) {
+		opts.encryptionKey = key
+	}
+}
+
+// WithPgDump sets the path to the pg_dump executable.
+func WithPgDump(path string) Option {
+	return func(opts *options) {
+		opts.pgDump = path
+	}
+}
+
+// WithPgRestore sets the path to the pg_restore executable.
+func WithPgRestore(path string) Option {
+	return func(opts *options) {
+		opts.pgRestore = path
+	}
+}
+
+// WithDumpArgs adds pg_dump arguments, e.g. "--exclude-table=audit_log".
+func WithDumpArgs(args ...string) Option {
+	return func(opts *options) {
+		opts.dumpArgs = append(opts.dumpArgs, args...)
+	}
+}
+
+// WithRestoreArgs replaces the default pg_restore arguments ("--clean", "--if-exists", "--no-owner").
+func WithRestoreArgs(args ...string) Option {
+	return func(opts *options) {
+		opts.restoreArgs = args
+	}
+}
+
+// WithEnv adds environment variables for pg_dump and pg_restore, e.g. "PGPASSWORD=secret",
+// to keep credentials out of the process arguments.
+func WithEnv(env ...string) Option {
+	return func(opts *options) {
+		opts.env = append(opts.env, env...)
+	}
+}
+
+// WithPartSize sets the multipart upload part size (default: s3.DefaultPartSize).
+func WithPartSize(size int64) Option {
+	return func(opts *options) {
+		opts.partSize = size
+	}
+}
+
+// WithLogger sets the logger used by Schedule.
+func WithLogger(logger *slog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
+func defaultOptions() *options {
+	return &options{
+		prefix:      DefaultPrefix,
+		pgDump:      DefaultPgDump,
+		pgRestore:   DefaultPgRestore,
+		restoreArgs: []string{"--clean", "--if-exists", "--no-owner"},
+		partSize:    s3.DefaultPartSize,
+		logger:      slog.Default(),
+	}
+}
@@ -0,0 +1,14 @@
+package migrate
+
+const (
+	// DefaultTable is the default table recording the migration version. Its layout is the one
+	// of golang-migrate, so either tool can continue the migrations of the other.
+	DefaultTable = "schema_migrations"
+)
+
+const (
+	// nilVersion is the version of a database without applied migrations
+	nilVersion int64 = -1
+	// lockKeyPrefix is hashed with the table into the default advisory lock key
+	lockKeyPrefix = "golib/migrate:"
+)
@@ -0,0 +1,296 @@
+// Package migrate applies versioned SQL migrations, usually embedded into the binary, to a
+// PostgreSQL database. Migration files and the version table follow golang-migrate conventions.
+// An advisory lock serializes migrators, so every instance of a service can migrate on startup:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	app, _ := server.NewApp(ctx,
+//		server.WithWarmup("migrations", 5*time.Minute, func(ctx context.Context) error {
+//			return migrate.Run(ctx, conn.Pool(), migrations, migrate.WithDir("migrations"))
+//		}),
+//	)
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNoVersion is returned by Version when no migration has been applied
+var ErrNoVersion = errors.New("no migration applied")
+
+// DirtyError is returned when a previous migration failed midway. Fix the database manually,
+// then call Force with the version it is in.
+type DirtyError struct {
+	Version int64
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("database is dirty at version %d: fix it and force the version", e.Version)
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	table      string
+	lockKey    int64
+	opts       *options
+}
+
+// New creates a Migrator for the migrations in fsys, e.g. an embed.FS.
+func New(pool *pgxpool.Pool, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	migrations, err := ReadMigrations(fsys, o.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	lockKey := o.lockKey
+	if lockKey == 0 {
+		lockKey = int64(crc32.ChecksumIEEE([]byte(lockKeyPrefix + o.table)))
+	}
+
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+		table:      pgx.Identifier(strings.Split(o.table, ".")).Sanitize(),
+		lockKey:    lockKey,
+		opts:       o,
+	}, nil
+}
+
+// Run applies all pending migrations in fsys, e.g. on startup before serving.
+func Run(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, opts ...Option) error {
+	m, err := New(pool, fsys, opts...)
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx)
+}
+
+// Migrations returns the migrations of the Migrator, sorted by version
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Steps(ctx, len(m.migrations))
+}
+
+// Down reverts all applied migrations.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.Steps(ctx, -len(m.migrations))
+}
+
+// Steps applies the next n pending migrations if n is positive, or reverts the last -n applied
+// migrations if n is negative. Fewer migrations are applied or reverted if there aren't enough.
+func (m *Migrator) Steps(ctx context.Context, n int) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return &DirtyError{Version: version}
+		}
+
+		steps, err := plan(m.migrations, version, n)
+		if err != nil {
+			return err
+		}
+		for _, s := range steps {
+			if err := m.apply(ctx, conn, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Version returns the version of the database and whether the migration to it failed midway.
+// It returns ErrNoVersion if no migration has been applied.
+func (m *Migrator) Version(ctx context.Context) (version int64, dirty bool, err error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return 0, false, err
+	}
+	version, dirty, err = m.version(ctx, conn)
+	if err != nil {
+		return 0, false, err
+	}
+	if version == nilVersion && !dirty {
+		return 0, false, ErrNoVersion
+	}
+	return version, dirty, nil
+}
+
+// Force sets the version of the database and clears the dirty flag without running migrations,
+// after a failed migration was fixed manually. A negative version marks no migration as applied.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version < 0 {
+		version = nilVersion
+	}
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+// step applies or reverts a migration, leaving the database at version
+type step struct {
+	migration Migration
+	up        bool
+	version   int64
+}
+
+// plan returns the steps applying the next n migrations after version, or reverting the last -n
+func plan(migrations []Migration, version int64, n int) ([]step, error) {
+	current := -1
+	for i, migration := range migrations {
+		if migration.Version == version {
+			current = i
+		}
+	}
+	if version != nilVersion && current < 0 {
+		return nil, fmt.Errorf("database version %d has no migration", version)
+	}
+
+	var steps []step
+	if n >= 0 {
+		for i := current + 1; i < len(migrations) && len(steps) < n; i++ {
+			steps = append(steps, step{migration: migrations[i], up: true, version: migrations[i].Version})
+		}
+		return steps, nil
+	}
+
+	for i := current; i >= 0 && len(steps) < -n; i-- {
+		previous := nilVersion
+		if i > 0 {
+			previous = migrations[i-1].Version
+		}
+		steps = append(steps, step{migration: migrations[i], up: false, version: previous})
+	}
+	return steps, nil
+}
+
+// apply runs the step, marking the database dirty at the target version until it succeeds
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, s step) error {
+	direction, sql := "up", s.migration.Up
+	if !s.up {
+		direction, sql = "down", s.migration.Down
+		if strings.TrimSpace(sql) == "" {
+			return fmt.Errorf("migration %d has no down migration", s.migration.Version)
+		}
+	}
+
+	if err := m.setVersion(ctx, conn, s.version, true); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	// Without arguments pgx uses the simple protocol, which runs files with several statements
+	if _, err := conn.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("migration %d_%s %s failed: %w", s.migration.Version, s.migration.Name, direction, err)
+	}
+
+	if err := m.setVersion(ctx, conn, s.version, false); err != nil {
+		return err
+	}
+
+	m.opts.logger.InfoContext(ctx, "migration applied",
+		"version", s.migration.Version,
+		"name", s.migration.Name,
+		"direction", direction,
+		"duration", time.Since(start),
+	)
+	return nil
+}
+
+// withLock runs fn on a connection holding the advisory lock of the Migrator
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) (err error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// Waits for other migrators to finish
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", m.lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", m.lockKey); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release migration lock: %w", unlockErr))
+		}
+	}()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureTable creates the version table in the golang-migrate layout
+func (m *Migrator) ensureTable(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(ctx, "CREATE TABLE IF NOT EXISTS "+m.table+" (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+// version returns the version recorded in the table, or nilVersion
+func (m *Migrator) version(ctx context.Context, conn *pgxpool.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRow(ctx, "SELECT version, dirty FROM "+m.table+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nilVersion, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// setVersion replaces the version recorded in the table. Like golang-migrate, no migration
+// applied is recorded as an empty table, or as nilVersion while dirty.
+func (m *Migrator) setVersion(ctx context.Context, conn *pgxpool.Conn, version int64, dirty bool) error {
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "TRUNCATE "+m.table); err != nil {
+			return err
+		}
+		if version == nilVersion && !dirty {
+			return nil
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO "+m.table+" (version, dirty) VALUES ($1, $2)", version, dirty)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set migration version %d: %w", version, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/000002_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD email text;")},
+		"migrations/000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id bigint);")},
+		"migrations/000001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations/README.md":                    {Data: []byte("ignored")},
+	}
+
+	migrations, err := ReadMigrations(fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id bigint);", Down: "DROP TABLE users;"}, migrations[0])
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Empty(t, migrations[1].Down)
+
+	_, err = ReadMigrations(fstest.MapFS{"3_orphan.down.sql": {}}, ".")
+	assert.ErrorContains(t, err, "no up file")
+
+	_, err = ReadMigrations(fstest.MapFS{"1_a.up.sql": {}, "01_a.up.sql": {}}, ".")
+	assert.ErrorContains(t, err, "duplicate up migration 1")
+}
+
+func TestPlan(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 5}}
+	versions := func(steps []step) (applied, after []int64) {
+		for _, s := range steps {
+			applied = append(applied, s.migration.Version)
+			after = append(after, s.version)
+		}
+		return applied, after
+	}
+
+	steps, err := plan(migrations, nilVersion, 3)
+	require.NoError(t, err)
+	applied, _ := versions(steps)
+	assert.Equal(t, []int64{1, 2, 5}, applied)
+
+	steps, err = plan(migrations, 1, 1)
+	require.NoError(t, err)
+	applied, _ = versions(steps)
+	assert.Equal(t, []int64{2}, applied)
+
+	steps, err = plan(migrations, 5, -3)
+	require.NoError(t, err)
+	applied, after := versions(steps)
+	assert.Equal(t, []int64{5, 2, 1}, applied)
+	assert.Equal(t, []int64{2, 1, nilVersion}, after)
+
+	steps, err = plan(migrations, 5, 1)
+	require.NoError(t, err)
+	assert.Empty(t, steps)
+
+	_, err = plan(migrations, 3, 1)
+	assert.ErrorContains(t, err, "database version 3 has no migration")
+}
@@ -0,0 +1,52 @@
+package migrate
+
+import "log/slog"
+
+// options holds configuration for Migrator
+type options struct {
+	dir     string
+	table   string
+	lockKey int64
+	logger  *slog.Logger
+}
+
+// Option is a function that configures Migrator options.
+type Option func(opts *options)
+
+// WithDir sets the directory of the migration files in the file system (default: its root).
+func WithDir(dir string) Option {
+	return func(opts *options) {
+		opts.dir = dir
+	}
+}
+
+// WithTable sets the table recording the migration version, optionally schema-qualified
+// (default: DefaultTable).
+func WithTable(table string) Option {
+	return func(opts *options) {
+		opts.table = table
+	}
+}
+
+// WithLockKey sets the key of the advisory lock held while migrating (default: derived from the table).
+// Advisory locks are scoped to the database, so migrators of other databases never wait for it.
+func WithLockKey(key int64) Option {
+	return func(opts *options) {
+		opts.lockKey = key
+	}
+}
+
+// WithLogger sets the logger of applied migrations (default: slog.Default()).
+func WithLogger(logger *slog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
+func defaultOptions() *options {
+	return &options{
+		dir:    ".",
+		table:  DefaultTable,
+		logger: slog.Default(),
+	}
+}
@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// fileNamePattern matches migration files like 000001_create_users.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	// Up applies the migration, Down reverts it. Down is empty if there is no down file.
+	Up   string
+	Down string
+}
+
+// ReadMigrations reads the migrations of dir in fsys, sorted by version. Files are named
+// {version}_{name}.up.sql and {version}_{name}.down.sql like for golang-migrate; other files
+// are ignored. Every version needs an up file; down files are optional.
+func ReadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	hasDown := make(map[int64]bool)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files with different names: %q and %q", version, m.Name, match[2])
+		}
+
+		target, seen := &m.Up, hasUp
+		if match[3] == "down" {
+			target, seen = &m.Down, hasDown
+		}
+		if seen[version] {
+			return nil, fmt.Errorf("duplicate %s migration %d", match[3], version)
+		}
+		seen[version] = true
+		*target = string(data)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if !hasUp[m.Version] {
+			return nil, fmt.Errorf("migration %d has no up file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
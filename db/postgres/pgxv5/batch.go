@@ -0,0 +1,114 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrStatementSkipped is the error of statements following a failed statement of a batch,
+// which the server doesn't execute
+var ErrStatementSkipped = errors.New("statement skipped after a failed batch statement")
+
+// Batch queues statements to execute in a single round trip
+type Batch struct {
+	batch pgx.Batch
+}
+
+// NewBatch creates an empty Batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Queue adds a statement to the batch.
+func (b *Batch) Queue(sql string, args ...any) *Batch {
+	b.batch.Queue(sql, args...)
+	return b
+}
+
+// Len returns the number of queued statements.
+func (b *Batch) Len() int {
+	return b.batch.Len()
+}
+
+// StatementResult is the outcome of a statement of a batch
+type StatementResult struct {
+	SQL        string
+	CommandTag pgconn.CommandTag
+	Err        error
+}
+
+// BatchResult holds the results of the statements of a batch in queue order
+type BatchResult struct {
+	Statements []StatementResult
+}
+
+// RowsAffected returns the number of rows affected by all statements.
+func (r *BatchResult) RowsAffected() int64 {
+	var n int64
+	for _, s := range r.Statements {
+		n += s.CommandTag.RowsAffected()
+	}
+	return n
+}
+
+// BatchError is returned when a statement of a batch fails
+type BatchError struct {
+	// Index is the position of the failed statement in the batch
+	Index int
+	SQL   string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch statement %d failed: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ExecBatch sends the batch to q and reads the result of every statement. If a statement fails,
+// the following ones are skipped by the server and the returned *BatchError wraps the error,
+// while the BatchResult is returned regardless. Outside of a transaction the batch runs in
+// an implicit transaction, so a failure rolls back all statements.
+func ExecBatch(ctx context.Context, q QueryEngine, b *Batch) (*BatchResult, error) {
+	result := &BatchResult{Statements: make([]StatementResult, len(b.batch.QueuedQueries))}
+	for i, query := range b.batch.QueuedQueries {
+		result.Statements[i].SQL = query.SQL
+	}
+	if b.Len() == 0 {
+		return result, nil
+	}
+
+	br := q.SendBatch(ctx, &b.batch)
+	var batchErr *BatchError
+	for i := range result.Statements {
+		s := &result.Statements[i]
+		if batchErr != nil {
+			s.Err = ErrStatementSkipped
+			continue
+		}
+		s.CommandTag, s.Err = br.Exec()
+		if s.Err != nil {
+			batchErr = &BatchError{Index: i, SQL: s.SQL, Err: s.Err}
+		}
+	}
+
+	if err := br.Close(); err != nil && batchErr == nil {
+		return result, fmt.Errorf("failed to close batch: %w", err)
+	}
+	if batchErr != nil {
+		return result, batchErr
+	}
+	return result, nil
+}
+
+// ExecBatch sends the batch with the query engine of ctx, so it joins the transaction of ctx if any.
+// A failed statement aborts that transaction, so the error should be returned to roll it back.
+func (m *TransactionManager) ExecBatch(ctx context.Context, b *Batch) (*BatchResult, error) {
+	return ExecBatch(ctx, m.GetQueryEngine(ctx), b)
+}
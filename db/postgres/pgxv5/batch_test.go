@@ -0,0 +1,93 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchResults returns the given results of Exec in order
+type fakeBatchResults struct {
+	pgx.BatchResults
+	tags   []pgconn.CommandTag
+	errs   []error
+	execs  int
+	closed bool
+}
+
+func (r *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	i := r.execs
+	r.execs++
+	return r.tags[i], r.errs[i]
+}
+
+func (r *fakeBatchResults) Close() error {
+	r.closed = true
+	return nil
+}
+
+type fakeBatchEngine struct {
+	QueryEngine
+	results *fakeBatchResults
+	sent    int
+}
+
+func (e *fakeBatchEngine) SendBatch(_ context.Context, _ *pgx.Batch) pgx.BatchResults {
+	e.sent++
+	return e.results
+}
+
+func TestExecBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		engine := &fakeBatchEngine{results: &fakeBatchResults{
+			tags: []pgconn.CommandTag{pgconn.NewCommandTag("INSERT 0 1"), pgconn.NewCommandTag("UPDATE 2")},
+			errs: []error{nil, nil},
+		}}
+		b := NewBatch().
+			Queue("INSERT INTO users (name) VALUES ($1)", "John").
+			Queue("UPDATE users SET active = true")
+
+		result, err := ExecBatch(ctx, engine, b)
+		require.NoError(t, err)
+		require.Len(t, result.Statements, 2)
+		assert.Equal(t, "UPDATE users SET active = true", result.Statements[1].SQL)
+		assert.Equal(t, int64(3), result.RowsAffected())
+		assert.True(t, engine.results.closed)
+	})
+
+	t.Run("failed statement", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: "23505"}
+		engine := &fakeBatchEngine{results: &fakeBatchResults{
+			tags: []pgconn.CommandTag{pgconn.NewCommandTag("INSERT 0 1"), {}, {}},
+			errs: []error{nil, pgErr, pgErr},
+		}}
+		b := NewBatch().Queue("INSERT 1").Queue("INSERT 2").Queue("INSERT 3")
+
+		result, err := ExecBatch(ctx, engine, b)
+		var batchErr *BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, 1, batchErr.Index)
+		assert.Equal(t, "INSERT 2", batchErr.SQL)
+		assert.True(t, errors.Is(err, pgErr))
+
+		assert.NoError(t, result.Statements[0].Err)
+		assert.ErrorIs(t, result.Statements[2].Err, ErrStatementSkipped)
+		assert.Equal(t, 2, engine.results.execs)
+		assert.True(t, engine.results.closed)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		engine := &fakeBatchEngine{}
+		result, err := ExecBatch(ctx, engine, NewBatch())
+		require.NoError(t, err)
+		assert.Empty(t, result.Statements)
+		assert.Zero(t, engine.sent)
+	})
+}
@@ -0,0 +1,95 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// bulkUpsertTable is the temporary table rows are copied into by BulkUpsert
+const bulkUpsertTable = "bulk_upsert_rows"
+
+// BulkUpsert inserts rows into table, updating the rows conflicting on conflictTarget, e.g. the primary key
+// columns, and returns the number of rows inserted or updated. Rows are copied into a temporary table with
+// COPY and merged with INSERT ... ON CONFLICT, which is much faster than upserting rows one by one.
+//
+// Every row has a value for each of columns, which must include conflictTarget. Columns not in the conflict
+// target are updated on conflict; if there are none, conflicting rows are skipped. If several rows have the
+// same key, the last one wins. The upsert runs in the transaction of ctx, or in its own transaction.
+func (m *TransactionManager) BulkUpsert(ctx context.Context, table string, columns []string, rows [][]any, conflictTarget []string) (int64, error) {
+	if len(columns) == 0 || len(conflictTarget) == 0 {
+		return 0, errors.New("bulk upsert needs columns and a conflict target")
+	}
+	for _, column := range conflictTarget {
+		if !slices.Contains(columns, column) {
+			return 0, fmt.Errorf("conflict target column %q is not among the upserted columns", column)
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	target := pgx.Identifier(strings.Split(table, ".")).Sanitize()
+	temp := pgx.Identifier{bulkUpsertTable}.Sanitize()
+	columnList := identifierList(columns)
+	keyList := identifierList(conflictTarget)
+
+	var set []string
+	for _, column := range columns {
+		if !slices.Contains(conflictTarget, column) {
+			name := pgx.Identifier{column}.Sanitize()
+			set = append(set, name+" = EXCLUDED."+name)
+		}
+	}
+	onConflict := "DO NOTHING"
+	if len(set) > 0 {
+		onConflict = "DO UPDATE SET " + strings.Join(set, ", ")
+	}
+
+	var upserted int64
+	err := m.RunReadCommitted(ctx, func(txCtx context.Context) error {
+		q := m.GetQueryEngine(txCtx)
+
+		// Only the column types are copied, so columns left out may be NOT NULL in the target table
+		createSQL := fmt.Sprintf("CREATE TEMPORARY TABLE %s ON COMMIT DROP AS SELECT %s FROM %s WITH NO DATA",
+			temp, columnList, target)
+		if _, err := q.Exec(txCtx, createSQL); err != nil {
+			return fmt.Errorf("failed to create temporary table: %w", err)
+		}
+
+		if _, err := q.CopyFrom(txCtx, pgx.Identifier{bulkUpsertTable}, columns, pgx.CopyFromRows(rows)); err != nil {
+			return fmt.Errorf("failed to copy rows: %w", err)
+		}
+
+		// ON CONFLICT can't update a row twice, so only the last row of a key is merged
+		mergeSQL := fmt.Sprintf("INSERT INTO %s (%s) SELECT DISTINCT ON (%s) %s FROM %s ORDER BY %s, ctid DESC ON CONFLICT (%s) %s",
+			target, columnList, keyList, columnList, temp, keyList, keyList, onConflict)
+		tag, err := q.Exec(txCtx, mergeSQL)
+		if err != nil {
+			return fmt.Errorf("failed to merge rows into %s: %w", table, err)
+		}
+		upserted = tag.RowsAffected()
+
+		// Dropped now rather than on commit, so the transaction can upsert again
+		if _, err := q.Exec(txCtx, "DROP TABLE "+temp); err != nil {
+			return fmt.Errorf("failed to drop temporary table: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return upserted, nil
+}
+
+func identifierList(columns []string) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = pgx.Identifier{column}.Sanitize()
+	}
+	return strings.Join(names, ", ")
+}
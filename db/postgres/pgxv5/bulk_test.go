@@ -0,0 +1,24 @@
+package pgxv5
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkUpsertValidation(t *testing.T) {
+	ctx := context.Background()
+	txManager := NewTransactionManager(nil)
+
+	_, err := txManager.BulkUpsert(ctx, "users", []string{"id", "name"}, [][]any{{1, "Ann"}}, nil)
+	assert.Error(t, err)
+
+	_, err = txManager.BulkUpsert(ctx, "users", []string{"name"}, [][]any{{"Ann"}}, []string{"id"})
+	assert.ErrorContains(t, err, `"id" is not among the upserted columns`)
+
+	n, err := txManager.BulkUpsert(ctx, "users", []string{"id", "name"}, nil, []string{"id"})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
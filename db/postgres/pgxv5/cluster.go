@@ -0,0 +1,286 @@
+package pgxv5
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// replicaLagQuery returns the replication lag of a replica in seconds: zero if it has replayed
+// all WAL it received or if it has been promoted, so idle primaries don't make replicas look stale
+const replicaLagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END::float8`
+
+// ReplicaBalancing selects the replica serving a read-only transaction
+type ReplicaBalancing int
+
+const (
+	// RoundRobin cycles through the healthy replicas
+	RoundRobin ReplicaBalancing = iota
+	// LeastLoaded picks the healthy replica with the fewest acquired connections
+	LeastLoaded
+)
+
+type primaryReadKey struct{}
+
+// ReadFromPrimary returns a context whose read-only transactions go to the primary,
+// e.g. to read a row right after writing it.
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+type clusterOptions struct {
+	poolOptions   []ConnectionPoolOption
+	balancing     ReplicaBalancing
+	maxLag        time.Duration
+	checkInterval time.Duration
+}
+
+// ClusterOption is a function that configures cluster connection options.
+type ClusterOption func(opts *clusterOptions)
+
+// WithClusterPoolOptions sets the options of the primary and replica pools.
+func WithClusterPoolOptions(opts ...ConnectionPoolOption) ClusterOption {
+	return func(o *clusterOptions) {
+		o.poolOptions = append(o.poolOptions, opts...)
+	}
+}
+
+// WithReplicaBalancing sets how read-only transactions are spread over replicas (default: RoundRobin).
+func WithReplicaBalancing(balancing ReplicaBalancing) ClusterOption {
+	return func(o *clusterOptions) {
+		o.balancing = balancing
+	}
+}
+
+// WithMaxReplicaLag sets how stale replica data may be (default: 10s, zero disables the check).
+// Replicas lagging behind the primary by more than lag don't serve reads until they catch up.
+func WithMaxReplicaLag(lag time.Duration) ClusterOption {
+	return func(o *clusterOptions) {
+		o.maxLag = lag
+	}
+}
+
+// WithReplicaCheckInterval sets how often the health and lag of replicas are checked (default: 5s).
+func WithReplicaCheckInterval(interval time.Duration) ClusterOption {
+	return func(o *clusterOptions) {
+		o.checkInterval = interval
+	}
+}
+
+// ClusterConnection manages a primary pool and replica pools. Queries and read-write transactions
+// go to the primary; read-only transactions, e.g. of TransactionManager with ReadOnly access mode,
+// and Reader go to a healthy replica, or to the primary if there is none.
+type ClusterConnection struct {
+	primary   *Connection
+	replicas  []*Connection
+	balancing ReplicaBalancing
+	maxLag    time.Duration
+	interval  time.Duration
+
+	healthy atomic.Pointer[[]*Connection]
+	next    atomic.Uint64
+
+	stop      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var (
+	_ CommonAPI      = (*ClusterConnection)(nil)
+	_ ExtendedAPI    = (*ClusterConnection)(nil)
+	_ TransactionAPI = (*ClusterConnection)(nil)
+)
+
+// NewClusterConnection creates pools for the primary and every replica connection string.
+// All of them must be reachable; replicas failing health checks later are taken out of rotation
+// until they recover.
+func NewClusterConnection(ctx context.Context, primaryConnString string, replicaConnStrings []string, opts ...ClusterOption) (*ClusterConnection, error) {
+	options := &clusterOptions{
+		maxLag:        maxReplicaLagDefault,
+		checkInterval: replicaCheckIntervalDefault,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	primary, err := NewConnectionPool(ctx, primaryConnString, options.poolOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("can't connect to primary: %w", err)
+	}
+
+	c := &ClusterConnection{
+		primary:   primary,
+		balancing: options.balancing,
+		maxLag:    options.maxLag,
+		interval:  options.checkInterval,
+		stop:      make(chan struct{}),
+	}
+	for i, connString := range replicaConnStrings {
+		replica, err := NewConnectionPool(ctx, connString, options.poolOptions...)
+		if err != nil {
+			c.closePools()
+			return nil, fmt.Errorf("can't connect to replica %d: %w", i, err)
+		}
+		c.replicas = append(c.replicas, replica)
+	}
+
+	c.check(ctx)
+
+	if len(c.replicas) > 0 && c.interval > 0 {
+		c.wg.Add(1)
+		go c.run()
+	}
+
+	return c, nil
+}
+
+// Close stops the health checks and closes all pools. Calls after the first one do nothing.
+func (c *ClusterConnection) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.wg.Wait()
+		c.closePools()
+	})
+}
+
+func (c *ClusterConnection) closePools() {
+	c.primary.Close()
+	for _, replica := range c.replicas {
+		replica.Close()
+	}
+}
+
+// Primary returns the connection of the primary.
+func (c *ClusterConnection) Primary() *Connection {
+	return c.primary
+}
+
+// Reader returns the connection serving reads: a healthy replica, or the primary if there is none
+// or ctx was returned by ReadFromPrimary. Replica reads may not see the latest writes.
+func (c *ClusterConnection) Reader(ctx context.Context) *Connection {
+	if primary, _ := ctx.Value(primaryReadKey{}).(bool); primary {
+		return c.primary
+	}
+	if replica := c.replica(); replica != nil {
+		return replica
+	}
+	return c.primary
+}
+
+// replica returns a healthy replica chosen by the balancing, or nil if there is none
+func (c *ClusterConnection) replica() *Connection {
+	healthy := c.healthy.Load()
+	if healthy == nil || len(*healthy) == 0 {
+		return nil
+	}
+
+	replicas := *healthy
+	start := c.next.Add(1)
+	if c.balancing != LeastLoaded {
+		return replicas[start%uint64(len(replicas))]
+	}
+
+	// Start at the next replica, so replicas with the same load take turns
+	var best *Connection
+	bestLoad := int32(-1)
+	for i := range replicas {
+		replica := replicas[(start+uint64(i))%uint64(len(replicas))]
+		load := replica.pool.Stat().AcquiredConns()
+		if bestLoad < 0 || load < bestLoad {
+			best, bestLoad = replica, load
+		}
+	}
+	return best
+}
+
+func (c *ClusterConnection) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), c.interval)
+			c.check(ctx)
+			cancel()
+		}
+	}
+}
+
+// check refreshes the list of healthy replicas
+func (c *ClusterConnection) check(ctx context.Context) {
+	healthy := make([]*Connection, 0, len(c.replicas))
+	for _, replica := range c.replicas {
+		if c.isHealthy(ctx, replica) {
+			healthy = append(healthy, replica)
+		}
+	}
+	c.healthy.Store(&healthy)
+}
+
+// isHealthy reports whether the replica answers and isn't lagging behind the primary by more than maxLag
+func (c *ClusterConnection) isHealthy(ctx context.Context, replica *Connection) bool {
+	var lag float64
+	if err := replica.QueryRow(ctx, replicaLagQuery).Scan(&lag); err != nil {
+		return false
+	}
+	return c.maxLag <= 0 || time.Duration(lag*float64(time.Second)) <= c.maxLag
+}
+
+// Query executes a query that returns multiple rows on the primary.
+func (c *ClusterConnection) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return c.primary.Query(ctx, sql, args...)
+}
+
+// QueryRow executes a query that returns a single row on the primary.
+func (c *ClusterConnection) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return c.primary.QueryRow(ctx, sql, args...)
+}
+
+// Exec executes a query that doesn't return rows on the primary.
+func (c *ClusterConnection) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return c.primary.Exec(ctx, sql, args...)
+}
+
+// Begin starts a new read-write transaction on the primary.
+func (c *ClusterConnection) Begin(ctx context.Context) (pgx.Tx, error) {
+	return c.primary.Begin(ctx)
+}
+
+// BeginTx starts a new transaction with the given options. Read-only transactions start on a replica
+// chosen like by Reader, falling back to the primary if the replica fails to begin the transaction.
+// Serializable transactions always start on the primary, since hot standbys reject them.
+func (c *ClusterConnection) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	if txOptions.AccessMode != pgx.ReadOnly || txOptions.IsoLevel == pgx.Serializable {
+		return c.primary.BeginTx(ctx, txOptions)
+	}
+
+	reader := c.Reader(ctx)
+	tx, err := reader.BeginTx(ctx, txOptions)
+	if err != nil && reader != c.primary && ctx.Err() == nil {
+		return c.primary.BeginTx(ctx, txOptions)
+	}
+	return tx, err
+}
+
+// SendBatch sends a batch of queries to the primary.
+func (c *ClusterConnection) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return c.primary.SendBatch(ctx, b)
+}
+
+// CopyFrom performs a bulk copy operation on the primary.
+func (c *ClusterConnection) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return c.primary.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
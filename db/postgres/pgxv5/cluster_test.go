@@ -0,0 +1,82 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLazyConnection creates a connection whose pool never connects
+func newLazyConnection(t *testing.T, database string) *Connection {
+	cfg, err := pgxpool.ParseConfig("postgres://localhost:1/" + database)
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return &Connection{pool: pool}
+}
+
+func TestClusterConnectionReader(t *testing.T) {
+	ctx := context.Background()
+	primary := newLazyConnection(t, "primary")
+	replica1 := newLazyConnection(t, "replica1")
+	replica2 := newLazyConnection(t, "replica2")
+
+	for _, balancing := range []ReplicaBalancing{RoundRobin, LeastLoaded} {
+		c := &ClusterConnection{primary: primary, balancing: balancing}
+
+		c.healthy.Store(&[]*Connection{})
+		assert.Same(t, primary, c.Reader(ctx), "no healthy replicas")
+
+		c.healthy.Store(&[]*Connection{replica1, replica2})
+		first, second := c.Reader(ctx), c.Reader(ctx)
+		assert.NotSame(t, first, second, "replicas take turns")
+		assert.NotSame(t, primary, first)
+		assert.NotSame(t, primary, second)
+
+		assert.Same(t, primary, c.Reader(ReadFromPrimary(ctx)))
+	}
+}
+
+// newCountingConnection creates a connection whose pool counts connection attempts and fails them
+func newCountingConnection(t *testing.T, database string, attempts *atomic.Int32) *Connection {
+	cfg, err := pgxpool.ParseConfig("postgres://localhost:1/" + database)
+	require.NoError(t, err)
+	cfg.BeforeConnect = func(context.Context, *pgx.ConnConfig) error {
+		attempts.Add(1)
+		return errors.New("unreachable")
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return &Connection{pool: pool}
+}
+
+func TestClusterConnectionBeginTxRouting(t *testing.T) {
+	ctx := context.Background()
+	var primaryAttempts, replicaAttempts atomic.Int32
+	c := &ClusterConnection{primary: newCountingConnection(t, "primary", &primaryAttempts)}
+	c.healthy.Store(&[]*Connection{newCountingConnection(t, "replica", &replicaAttempts)})
+
+	_, err := c.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly, IsoLevel: pgx.Serializable})
+	require.Error(t, err)
+	assert.Zero(t, replicaAttempts.Load(), "serializable transactions skip replicas")
+	assert.Positive(t, primaryAttempts.Load())
+
+	_, err = c.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly, IsoLevel: pgx.RepeatableRead})
+	require.Error(t, err)
+	assert.Positive(t, replicaAttempts.Load(), "read-only transactions start on a replica")
+}
+
+func TestClusterConnectionCloseTwice(t *testing.T) {
+	c := &ClusterConnection{primary: newLazyConnection(t, "primary"), stop: make(chan struct{})}
+
+	c.Close()
+	assert.NotPanics(t, c.Close)
+}
@@ -0,0 +1,64 @@
+package pgxv5
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// ConnConfig describes a database to connect to. DSN escapes every value, so passwords
+// and database names may contain any characters.
+type ConnConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	// SSLMode is the sslmode parameter, e.g. "disable", "require" or "verify-full"
+	SSLMode string
+	// Params are further connection parameters, e.g. "application_name" or "search_path"
+	Params map[string]string
+}
+
+// DSN returns the connection URL, e.g. postgres://user:p%40ss@db:5432/orders?sslmode=require.
+// Empty fields are omitted, so libpq defaults and PG* environment variables apply to them.
+func (c ConnConfig) DSN() string {
+	return c.url().String()
+}
+
+// String returns the connection URL with the password redacted, so configs can be logged.
+func (c ConnConfig) String() string {
+	return c.url().Redacted()
+}
+
+func (c ConnConfig) url() *url.URL {
+	u := &url.URL{Scheme: "postgres", Host: c.Host}
+	if c.Port != 0 {
+		u.Host = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	}
+	switch {
+	case c.Password != "":
+		u.User = url.UserPassword(c.User, c.Password)
+	case c.User != "":
+		u.User = url.User(c.User)
+	}
+	if c.DBName != "" {
+		u.Path = "/" + c.DBName
+	}
+
+	query := url.Values{}
+	for key, value := range c.Params {
+		query.Set(key, value)
+	}
+	if c.SSLMode != "" {
+		query.Set("sslmode", c.SSLMode)
+	}
+	u.RawQuery = query.Encode()
+	return u
+}
+
+// NewConnectionPoolFromConfig creates a new connection pool for the database described by cfg.
+func NewConnectionPoolFromConfig(ctx context.Context, cfg ConnConfig, opts ...ConnectionPoolOption) (*Connection, error) {
+	return NewConnectionPool(ctx, cfg.DSN(), opts...)
+}
@@ -0,0 +1,34 @@
+package pgxv5
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnConfigDSN(t *testing.T) {
+	cfg := ConnConfig{
+		Host:     "db.internal",
+		Port:     5433,
+		User:     "orders",
+		Password: "p@ss:w/rd?#%",
+		DBName:   "orders db",
+		SSLMode:  "require",
+		Params:   map[string]string{"application_name": "orders-api"},
+	}
+
+	parsed, err := pgxpool.ParseConfig(cfg.DSN())
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", parsed.ConnConfig.Host)
+	assert.Equal(t, uint16(5433), parsed.ConnConfig.Port)
+	assert.Equal(t, "orders", parsed.ConnConfig.User)
+	assert.Equal(t, "p@ss:w/rd?#%", parsed.ConnConfig.Password)
+	assert.Equal(t, "orders db", parsed.ConnConfig.Database)
+	assert.Equal(t, "orders-api", parsed.ConnConfig.RuntimeParams["application_name"])
+	assert.NotNil(t, parsed.ConnConfig.TLSConfig)
+
+	assert.NotContains(t, cfg.String(), "p@ss")
+	assert.Equal(t, "postgres://[::1]:5432/app", ConnConfig{Host: "::1", Port: 5432, DBName: "app"}.DSN())
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
 	"time"
 
-	"github.com/exaring/otelpgx"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	pgxUUID "github.com/vgarvardt/pgx-google-uuid/v5"
+	"go.opentelemetry.io/otel/metric"
 )
 
 type connectionPoolOptions struct {
@@ -20,6 +24,18 @@ type connectionPoolOptions struct {
 	maxConnectionsCount int32
 	tlsConfig           *tls.Config
 	EnableTracing       bool
+	statementMaxLength  int
+	statementSanitizer  func(sql string) string
+	queryLogger         *QueryLogger
+	enableMetrics       bool
+	poolName            string
+
+	statementTimeout         time.Duration
+	lockTimeout              time.Duration
+	idleInTransactionTimeout time.Duration
+
+	healthLatencyThreshold     time.Duration
+	healthUtilizationThreshold float64
 }
 
 // ConnectionPoolOption is a function that configures connection pool options.
@@ -60,16 +76,43 @@ func WithTLS(cfg *tls.Config) ConnectionPoolOption {
 	}
 }
 
-// WithTracing turns on/off tracing through otelpgx
+// WithTracing turns on/off tracing through otelpgx (default: on). Query, Exec, SendBatch and
+// CopyFrom calls record spans with the statement (db.query.text), rows affected and errors;
+// see WithStatementMaxLength and WithStatementSanitizer to limit what is recorded.
 func WithTracing(enable bool) ConnectionPoolOption {
 	return func(opts *connectionPoolOptions) {
 		opts.EnableTracing = enable
 	}
 }
 
+// WithMetrics turns on/off pool metrics (connections, waits, timeouts) exported through OpenTelemetry
+func WithMetrics(enable bool) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.enableMetrics = enable
+	}
+}
+
+// WithPoolName sets the pool attribute of pool metrics (default: database name).
+// Set it to tell apart several pools to the same database.
+func WithPoolName(name string) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.poolName = name
+	}
+}
+
 // Connection represents a connection pool to the database.
 type Connection struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	metrics metric.Registration
+	health  healthThresholds
+	// lastWaitCount is the count of acquires that waited at the previous health check
+	lastWaitCount atomic.Int64
+}
+
+// healthThresholds are the thresholds of degraded health
+type healthThresholds struct {
+	latency     time.Duration
+	utilization float64
 }
 
 var (
@@ -86,12 +129,6 @@ func NewConnectionPool(ctx context.Context, connString string, opts ...Connectio
 		return nil, fmt.Errorf("can't parse connection string to config: %w", err)
 	}
 
-	// ...
-	connConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
-		pgxUUID.Register(conn.TypeMap())
-		return nil
-	}
-
 	// make options
 	options := &connectionPoolOptions{
 		maxConnIdleTime:     maxConnIdleTimeDefault,
@@ -99,11 +136,28 @@ func NewConnectionPool(ctx context.Context, connString string, opts ...Connectio
 		minConnectionsCount: minConnectionsCountDefault,
 		maxConnectionsCount: maxConnectionsCountDefault,
 		EnableTracing:       true, // default is true
+		enableMetrics:       true, // default is true
+		poolName:            connConfig.ConnConfig.Database,
+
+		healthLatencyThreshold:     healthLatencyThresholdDefault,
+		healthUtilizationThreshold: healthUtilizationThresholdDefault,
 	}
 	for _, opt := range opts {
 		opt(options)
 	}
 
+	timeouts := sessionTimeouts(options)
+	connConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		pgxUUID.Register(conn.TypeMap())
+		if timeouts == "" {
+			return nil
+		}
+		if _, err := conn.Exec(ctx, timeouts); err != nil {
+			return fmt.Errorf("can't set session timeouts: %w", err)
+		}
+		return nil
+	}
+
 	// apply options
 	connConfig.MaxConnIdleTime = options.maxConnIdleTime
 	connConfig.MaxConnLifetime = options.maxConnLifeTime
@@ -111,8 +165,18 @@ func NewConnectionPool(ctx context.Context, connString string, opts ...Connectio
 	connConfig.MaxConns = options.maxConnectionsCount
 	connConfig.ConnConfig.Config.TLSConfig = options.tlsConfig
 
+	var tracers []pgx.QueryTracer
 	if options.EnableTracing {
-		connConfig.ConnConfig.Tracer = otelpgx.NewTracer()
+		tracers = append(tracers, newTracer(options))
+	}
+	if options.queryLogger != nil {
+		tracers = append(tracers, newQueryLogTracer(options.queryLogger, options))
+	}
+	switch len(tracers) {
+	case 1:
+		connConfig.ConnConfig.Tracer = tracers[0]
+	case 2:
+		connConfig.ConnConfig.Tracer = multitracer.New(tracers...)
 	}
 
 	// connect to database
@@ -126,13 +190,31 @@ func NewConnectionPool(ctx context.Context, connString string, opts ...Connectio
 		return nil, fmt.Errorf("ping database error: %w", err)
 	}
 
-	return &Connection{
+	conn := &Connection{
 		pool: p,
-	}, nil
+		health: healthThresholds{
+			latency:     options.healthLatencyThreshold,
+			utilization: options.healthUtilizationThreshold,
+		},
+	}
+
+	if options.enableMetrics {
+		address := net.JoinHostPort(connConfig.ConnConfig.Host, strconv.Itoa(int(connConfig.ConnConfig.Port)))
+		conn.metrics, err = registerPoolMetrics(p, options.poolName, address)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("can't register pool metrics: %w", err)
+		}
+	}
+
+	return conn, nil
 }
 
 // Close closes the connection pool.
 func (c *Connection) Close() {
+	if c.metrics != nil {
+		_ = c.metrics.Unregister()
+	}
 	c.pool.Close()
 }
 
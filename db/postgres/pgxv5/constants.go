@@ -9,7 +9,8 @@ import (
 type key string
 
 const (
-	txKey key = "tx"
+	txKey     key = "tx"
+	tenantKey key = "tenant"
 )
 
 const (
@@ -17,6 +18,23 @@ const (
 	maxConnLifeTimeDefault     = time.Hour
 	minConnectionsCountDefault = 2
 	maxConnectionsCountDefault = 10
+
+	tenantPoolIdleTimeoutDefault = 10 * time.Minute
+
+	retryInitialBackoffDefault = 10 * time.Millisecond
+	retryMaxBackoffDefault     = time.Second
+
+	maxReplicaLagDefault        = 10 * time.Second
+	replicaCheckIntervalDefault = 5 * time.Second
+
+	notificationBufferDefault     = 64
+	listenerInitialBackoffDefault = 500 * time.Millisecond
+	listenerMaxBackoffDefault     = 30 * time.Second
+	// listenerPingInterval is how often an idle listener checks its connection
+	listenerPingInterval = 30 * time.Second
+
+	healthLatencyThresholdDefault     = 100 * time.Millisecond
+	healthUtilizationThresholdDefault = 0.9
 )
 
 // TxAccessMode is the transaction access mode (read write or read only)
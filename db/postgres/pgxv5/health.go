@@ -0,0 +1,105 @@
+package pgxv5
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HealthState is the state reported by HealthCheck. Its values match server.DependencyState.
+type HealthState string
+
+const (
+	HealthUp       HealthState = "up"
+	HealthDegraded HealthState = "degraded"
+	HealthDown     HealthState = "down"
+)
+
+// HealthStatus describes the database and the pool at the time of a health check
+type HealthStatus struct {
+	State HealthState
+	// Err is the ping error if the database is down
+	Err error
+	// Reason explains why the database is degraded
+	Reason      string
+	PingLatency time.Duration
+
+	TotalConns    int32
+	AcquiredConns int32
+	IdleConns     int32
+	MaxConns      int32
+	// Utilization is the share of MaxConns in use, from 0 to 1
+	Utilization float64
+	// WaitCount is the number of acquires that waited for a connection since the previous check
+	WaitCount int64
+}
+
+// Details returns the status as a map, e.g. for server.DependencyStatus.Details.
+func (s HealthStatus) Details() map[string]any {
+	return map[string]any{
+		"ping_latency":   s.PingLatency.String(),
+		"total_conns":    s.TotalConns,
+		"acquired_conns": s.AcquiredConns,
+		"idle_conns":     s.IdleConns,
+		"max_conns":      s.MaxConns,
+		"utilization":    s.Utilization,
+		"wait_count":     s.WaitCount,
+	}
+}
+
+// WithHealthThresholds sets when HealthCheck reports the database as degraded: when the ping takes longer
+// than latency or the share of connections in use reaches utilization (default: 100ms and 0.9).
+// Zero values disable a threshold.
+func WithHealthThresholds(latency time.Duration, utilization float64) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.healthLatencyThreshold = latency
+		opts.healthUtilizationThreshold = utilization
+	}
+}
+
+// HealthCheck pings the database and reports the ping latency and the pool usage. The database is down
+// if the ping fails, and degraded if the ping is slow, the pool is nearly exhausted or acquires had to wait
+// for a connection since the previous check. Fit for server.DependencyFunc:
+//
+//	server.DependencyFunc(func(ctx context.Context) server.DependencyStatus {
+//		status := conn.HealthCheck(ctx)
+//		return server.DependencyStatus{Name: "users-db", Kind: "postgres",
+//			State: server.DependencyState(status.State), Details: status.Details()}
+//	})
+func (c *Connection) HealthCheck(ctx context.Context) HealthStatus {
+	start := time.Now()
+	err := c.pool.Ping(ctx)
+	latency := time.Since(start)
+
+	stat := c.pool.Stat()
+	waits := stat.EmptyAcquireCount()
+	status := HealthStatus{
+		State:         HealthUp,
+		PingLatency:   latency,
+		TotalConns:    stat.TotalConns(),
+		AcquiredConns: stat.AcquiredConns(),
+		IdleConns:     stat.IdleConns(),
+		MaxConns:      stat.MaxConns(),
+		WaitCount:     waits - c.lastWaitCount.Swap(waits),
+	}
+	if status.MaxConns > 0 {
+		status.Utilization = float64(status.AcquiredConns) / float64(status.MaxConns)
+	}
+
+	switch {
+	case err != nil:
+		status.State, status.Err = HealthDown, err
+	case c.health.latency > 0 && latency > c.health.latency:
+		status.State, status.Reason = HealthDegraded, fmt.Sprintf("ping took %s", latency)
+	case c.health.utilization > 0 && status.Utilization >= c.health.utilization:
+		status.State, status.Reason = HealthDegraded, fmt.Sprintf("%d of %d connections in use", status.AcquiredConns, status.MaxConns)
+	case status.WaitCount > 0:
+		status.State, status.Reason = HealthDegraded, fmt.Sprintf("%d acquires waited for a connection", status.WaitCount)
+	}
+	return status
+}
+
+// Check pings the database, so a Connection is a server.ReadinessCheck. A degraded database is ready.
+func (c *Connection) Check(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
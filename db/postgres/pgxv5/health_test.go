@@ -0,0 +1,21 @@
+package pgxv5
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionHealthCheck(t *testing.T) {
+	conn := newLazyConnection(t, "health")
+
+	status := conn.HealthCheck(context.Background())
+	assert.Equal(t, HealthDown, status.State)
+	assert.Error(t, status.Err)
+	assert.Positive(t, status.MaxConns)
+	assert.Zero(t, status.AcquiredConns)
+	assert.Equal(t, status.MaxConns, status.Details()["max_conns"])
+
+	assert.Error(t, conn.Check(context.Background()))
+}
@@ -0,0 +1,271 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Notification is a NOTIFY message received by a Listener
+type Notification struct {
+	Channel string
+	Payload string
+	// PID is the process ID of the notifying server process
+	PID uint32
+}
+
+type listenerOptions struct {
+	handler     func(ctx context.Context, n Notification)
+	bufferSize  int
+	backoff     RetryPolicy
+	onReconnect func(ctx context.Context)
+	logger      *slog.Logger
+}
+
+// ListenerOption is a function that configures listener options.
+type ListenerOption func(opts *listenerOptions)
+
+// WithNotificationHandler delivers notifications to fn instead of the Notifications channel.
+// fn runs on the goroutine of Run, so notifications are received after it returns.
+func WithNotificationHandler(fn func(ctx context.Context, n Notification)) ListenerOption {
+	return func(opts *listenerOptions) {
+		opts.handler = fn
+	}
+}
+
+// WithNotificationBuffer sets the buffer size of the Notifications channel (default: 64).
+func WithNotificationBuffer(n int) ListenerOption {
+	return func(opts *listenerOptions) {
+		opts.bufferSize = n
+	}
+}
+
+// WithReconnectBackoff sets the delay before reconnecting after the connection is lost, doubling
+// from initial up to maxBackoff with jitter (default: 500ms up to 30s).
+func WithReconnectBackoff(initial, maxBackoff time.Duration) ListenerOption {
+	return func(opts *listenerOptions) {
+		opts.backoff = RetryPolicy{InitialBackoff: initial, MaxBackoff: maxBackoff}
+	}
+}
+
+// WithOnReconnect sets a function called after the listener reconnected and listens again.
+// Notifications sent while it was disconnected are lost, so caches should be invalidated in fn.
+func WithOnReconnect(fn func(ctx context.Context)) ListenerOption {
+	return func(opts *listenerOptions) {
+		opts.onReconnect = fn
+	}
+}
+
+// WithListenerLogger sets the logger of connection losses (default: slog.Default()).
+func WithListenerLogger(logger *slog.Logger) ListenerOption {
+	return func(opts *listenerOptions) {
+		opts.logger = logger
+	}
+}
+
+// Listener receives notifications of LISTEN channels on a dedicated connection, e.g. for cache
+// invalidation. It reconnects with backoff when the connection is lost and listens to all channels again.
+type Listener struct {
+	connString    string
+	opts          *listenerOptions
+	notifications chan Notification
+
+	mu       sync.Mutex
+	channels map[string]struct{}
+	changed  bool
+	wake     context.CancelFunc
+}
+
+// NewListener creates a Listener connecting to connString. It connects when Run is called.
+func NewListener(connString string, opts ...ListenerOption) *Listener {
+	options := &listenerOptions{
+		bufferSize: notificationBufferDefault,
+		backoff: RetryPolicy{
+			InitialBackoff: listenerInitialBackoffDefault,
+			MaxBackoff:     listenerMaxBackoffDefault,
+		},
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Listener{
+		connString:    connString,
+		opts:          options,
+		notifications: make(chan Notification, options.bufferSize),
+		channels:      make(map[string]struct{}),
+	}
+}
+
+// Listen subscribes to channels. Channels are listened to as soon as the listener is connected.
+func (l *Listener) Listen(channels ...string) {
+	l.update(func() {
+		for _, channel := range channels {
+			l.channels[channel] = struct{}{}
+		}
+	})
+}
+
+// Unlisten unsubscribes from channels.
+func (l *Listener) Unlisten(channels ...string) {
+	l.update(func() {
+		for _, channel := range channels {
+			delete(l.channels, channel)
+		}
+	})
+}
+
+// update changes the channels and interrupts waiting for notifications to apply them
+func (l *Listener) update(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fn()
+	l.changed = true
+	if l.wake != nil {
+		l.wake()
+	}
+}
+
+// Notifications returns the channel receiving notifications, unless WithNotificationHandler is used.
+// It is closed when Run returns. A slow receiver delays receiving further notifications,
+// which PostgreSQL queues meanwhile.
+func (l *Listener) Notifications() <-chan Notification {
+	return l.notifications
+}
+
+// Run connects, listens to the channels and delivers notifications until ctx is done,
+// reconnecting when the connection is lost. It fits app.WithWorker.
+func (l *Listener) Run(ctx context.Context) error {
+	defer close(l.notifications)
+
+	attempt, connected := 0, false
+	for {
+		established, err := l.session(ctx, connected)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if established {
+			connected, attempt = true, 0
+		}
+
+		delay := l.opts.backoff.backoff(attempt)
+		attempt++
+		l.opts.logger.WarnContext(ctx, "postgres listener connection lost", "error", err, "retry_in", delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// session connects and delivers notifications until the connection fails or ctx is done.
+// It reports whether the connection was established.
+func (l *Listener) session(ctx context.Context, reconnect bool) (bool, error) {
+	conn, err := pgx.Connect(ctx, l.connString)
+	if err != nil {
+		return false, fmt.Errorf("can't connect to database: %w", err)
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	listening := make(map[string]struct{})
+	if err := l.sync(ctx, conn, listening); err != nil {
+		return false, err
+	}
+	if reconnect && l.opts.onReconnect != nil {
+		l.opts.onReconnect(ctx)
+	}
+
+	for {
+		// Interrupted by Listen and Unlisten, and periodically to detect dead connections
+		waitCtx, cancel := context.WithTimeout(ctx, listenerPingInterval)
+		l.mu.Lock()
+		changed := l.changed
+		l.wake = cancel
+		l.mu.Unlock()
+
+		var (
+			n   *pgconn.Notification
+			err error
+		)
+		if !changed {
+			n, err = conn.WaitForNotification(waitCtx)
+		}
+		cancel()
+
+		l.mu.Lock()
+		l.wake = nil
+		l.mu.Unlock()
+
+		switch {
+		case ctx.Err() != nil:
+			return true, ctx.Err()
+		case changed || errors.Is(err, context.Canceled):
+			if err := l.sync(ctx, conn, listening); err != nil {
+				return true, err
+			}
+		case errors.Is(err, context.DeadlineExceeded):
+			if err := conn.Ping(ctx); err != nil {
+				return true, fmt.Errorf("ping failed: %w", err)
+			}
+		case err != nil:
+			return true, fmt.Errorf("can't wait for notification: %w", err)
+		default:
+			l.deliver(ctx, Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID})
+		}
+	}
+}
+
+// sync issues LISTEN and UNLISTEN for the channels added and removed since the last call
+func (l *Listener) sync(ctx context.Context, conn *pgx.Conn, listening map[string]struct{}) error {
+	l.mu.Lock()
+	wanted := make(map[string]struct{}, len(l.channels))
+	for channel := range l.channels {
+		wanted[channel] = struct{}{}
+	}
+	l.changed = false
+	l.mu.Unlock()
+
+	for channel := range wanted {
+		if _, ok := listening[channel]; ok {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("can't listen to %q: %w", channel, err)
+		}
+		listening[channel] = struct{}{}
+	}
+	for channel := range listening {
+		if _, ok := wanted[channel]; ok {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "UNLISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("can't unlisten %q: %w", channel, err)
+		}
+		delete(listening, channel)
+	}
+	return nil
+}
+
+func (l *Listener) deliver(ctx context.Context, n Notification) {
+	if l.opts.handler != nil {
+		l.opts.handler(ctx, n)
+		return
+	}
+
+	select {
+	case l.notifications <- n:
+	case <-ctx.Done():
+	}
+}
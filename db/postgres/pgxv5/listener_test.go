@@ -0,0 +1,54 @@
+package pgxv5
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rshelekhov/go-db/postgres/pgxv5/testutil"
+)
+
+func TestListener(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := testutil.NewTestDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	require.NoError(t, db.WaitForReady(ctx))
+
+	conn, err := NewConnectionPool(ctx, db.ConnStr())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	listener := NewListener(db.ConnStr())
+	listener.Listen("cache_invalidation")
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- listener.Run(runCtx) }()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	// LISTEN is issued asynchronously, so notify until the listener receives
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case n := <-listener.Notifications():
+			assert.Equal(t, "cache_invalidation", n.Channel)
+			assert.Equal(t, "users:42", n.Payload)
+			return
+		case <-ticker.C:
+			_, err := conn.Exec(ctx, "SELECT pg_notify($1, $2)", "cache_invalidation", "users:42")
+			require.NoError(t, err)
+		case <-timeout:
+			t.Fatal("no notification received")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of pool and transaction metrics
+const meterName = "github.com/rshelekhov/golib/db/postgres/pgxv5"
+
+// registerPoolMetrics reports pool stats through the global meter provider on every collection:
+//   - db_pool_connections{state} - idle and in_use connections
+//   - db_pool_max_connections
+//   - db_pool_wait_count_total - acquires that waited for a connection
+//   - db_pool_wait_duration_seconds_total - time spent waiting for a connection
+//   - db_pool_timeouts_total - acquires canceled before getting a connection
+//
+// All metrics have db_system, pool and address attributes.
+func registerPoolMetrics(pool *pgxpool.Pool, name, address string) (metric.Registration, error) {
+	meter := otel.GetMeterProvider().Meter(meterName)
+
+	connections, err1 := meter.Int64ObservableGauge("db_pool_connections",
+		metric.WithDescription("Number of connections in the pool by state."))
+	maxConnections, err2 := meter.Int64ObservableGauge("db_pool_max_connections",
+		metric.WithDescription("Maximum number of connections in the pool."))
+	waitCount, err3 := meter.Int64ObservableCounter("db_pool_wait_count_total",
+		metric.WithDescription("Total number of times a connection was waited for."))
+	waitDuration, err4 := meter.Float64ObservableCounter("db_pool_wait_duration_seconds_total",
+		metric.WithDescription("Total time spent waiting for a connection in seconds."), metric.WithUnit("s"))
+	timeouts, err5 := meter.Int64ObservableCounter("db_pool_timeouts_total",
+		metric.WithDescription("Total number of connection acquires that timed out or were canceled."))
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db_system", "postgresql"),
+		attribute.String("pool", name),
+		attribute.String("address", address),
+	}
+	base := metric.WithAttributes(attrs...)
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stat := pool.Stat()
+
+		o.ObserveInt64(connections, int64(stat.IdleConns()), base, metric.WithAttributes(attribute.String("state", "idle")))
+		o.ObserveInt64(connections, int64(stat.AcquiredConns()), base, metric.WithAttributes(attribute.String("state", "in_use")))
+		o.ObserveInt64(maxConnections, int64(stat.MaxConns()), base)
+		o.ObserveInt64(waitCount, stat.EmptyAcquireCount(), base)
+		o.ObserveFloat64(waitDuration, stat.EmptyAcquireWaitTime().Seconds(), base)
+		o.ObserveInt64(timeouts, stat.CanceledAcquireCount(), base)
+		return nil
+	}, connections, maxConnections, waitCount, waitDuration, timeouts)
+}
+
+// Transaction statuses
+const (
+	txStatusCommit   = "commit"
+	txStatusRollback = "rollback"
+)
+
+// txMetrics records transaction metrics through the global meter provider:
+//   - db_transaction_duration_seconds{isolation_level,status} - time from begin to commit or rollback
+//   - db_transactions_total{isolation_level,status} - status is commit or rollback
+//   - db_transaction_retries_total{isolation_level} - retries after serialization failures and deadlocks
+//
+// All metrics have the db_system attribute. A nil *txMetrics records nothing.
+type txMetrics struct {
+	duration metric.Float64Histogram
+	total    metric.Int64Counter
+	retries  metric.Int64Counter
+}
+
+func newTxMetrics() (*txMetrics, error) {
+	meter := otel.GetMeterProvider().Meter(meterName)
+
+	duration, err1 := meter.Float64Histogram("db_transaction_duration_seconds",
+		metric.WithDescription("Duration of database transactions in seconds."), metric.WithUnit("s"))
+	total, err2 := meter.Int64Counter("db_transactions_total",
+		metric.WithDescription("Total number of database transactions by status."))
+	retries, err3 := meter.Int64Counter("db_transaction_retries_total",
+		metric.WithDescription("Total number of database transaction retries."))
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, err
+	}
+
+	return &txMetrics{duration: duration, total: total, retries: retries}, nil
+}
+
+func (m *txMetrics) record(ctx context.Context, isoLevel pgx.TxIsoLevel, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("db_system", "postgresql"),
+		attribute.String("isolation_level", isolationLevel(isoLevel)),
+		attribute.String("status", status),
+	)
+	m.duration.Record(ctx, duration.Seconds(), attrs)
+	m.total.Add(ctx, 1, attrs)
+}
+
+func (m *txMetrics) retry(ctx context.Context, isoLevel pgx.TxIsoLevel) {
+	if m == nil {
+		return
+	}
+	m.retries.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("db_system", "postgresql"),
+		attribute.String("isolation_level", isolationLevel(isoLevel)),
+	))
+}
+
+// isolationLevel returns the isolation level attribute, e.g. "serializable"
+func isolationLevel(isoLevel pgx.TxIsoLevel) string {
+	if isoLevel == "" {
+		return "default"
+	}
+	return strings.ReplaceAll(string(isoLevel), " ", "_")
+}
@@ -0,0 +1,248 @@
+package pgxv5
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrInvalidCursor is returned for cursors not created by the same pagination
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page is a page of items and the cursor of the next page, empty on the last page
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// EncodeCursor encodes values into an opaque, URL-safe cursor. Values are encoded as JSON,
+// e.g. time.Time as an RFC 3339 string, which PostgreSQL parses back as a query argument.
+func EncodeCursor(values ...any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes the values of a cursor created by EncodeCursor. Integers are decoded as int64,
+// other numbers as float64. Cursors come from clients, so the values should only be used as query arguments.
+func DecodeCursor(cursor string) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values []any
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	for i, value := range values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if n, err := number.Int64(); err == nil {
+			values[i] = n
+			continue
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		values[i] = f
+	}
+	return values, nil
+}
+
+// SortColumn is a column of the order of a keyset pagination
+type SortColumn struct {
+	Name string
+	Desc bool
+}
+
+// Asc sorts by column in ascending order.
+func Asc(column string) SortColumn {
+	return SortColumn{Name: column}
+}
+
+// Desc sorts by column in descending order.
+func Desc(column string) SortColumn {
+	return SortColumn{Name: column, Desc: true}
+}
+
+// Keyset paginates queries by the values of the sort columns of the last item, which is stable under
+// concurrent inserts and doesn't slow down on later pages like OFFSET. The columns must be NOT NULL
+// and together unique, e.g. created_at and the primary key as the last column.
+type Keyset[T any] struct {
+	columns []SortColumn
+	key     func(item T) []any
+}
+
+// NewKeyset creates a Keyset ordered by columns. key returns the values of the columns of an item.
+func NewKeyset[T any](key func(item T) []any, columns ...SortColumn) *Keyset[T] {
+	return &Keyset[T]{columns: columns, key: key}
+}
+
+// Query runs query, which must not have ORDER BY or LIMIT, and returns the page of up to limit items
+// after cursor, or the first page for an empty cursor. Columns are referenced by their names in the result,
+// since the query is wrapped: SELECT * FROM (query) WHERE after cursor ORDER BY columns LIMIT limit.
+func (k *Keyset[T]) Query(ctx context.Context, q QueryEngine, query, cursor string, limit int, args ...any) (Page[T], error) {
+	if limit <= 0 {
+		return Page[T]{}, fmt.Errorf("page limit must be positive, got %d", limit)
+	}
+
+	sql := "SELECT * FROM (" + query + ") AS page"
+	where, cursorArgs, err := k.Where(cursor, len(args))
+	if err != nil {
+		return Page[T]{}, err
+	}
+	if where != "" {
+		sql += " WHERE " + where
+	}
+	args = append(args, cursorArgs...)
+	args = append(args, limit+1)
+	sql += " ORDER BY " + k.OrderBy() + " LIMIT $" + strconv.Itoa(len(args))
+
+	items, err := Select[T](ctx, q, sql, args...)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	page := Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		if page.NextCursor, err = k.Cursor(items[limit-1]); err != nil {
+			return Page[T]{}, err
+		}
+	}
+	return page, nil
+}
+
+// Cursor returns the cursor of the page following item.
+func (k *Keyset[T]) Cursor(item T) (string, error) {
+	return EncodeCursor(k.key(item)...)
+}
+
+// OrderBy returns the ORDER BY list of the sort columns, e.g. "created_at" DESC, "id" DESC.
+func (k *Keyset[T]) OrderBy() string {
+	terms := make([]string, len(k.columns))
+	for i, c := range k.columns {
+		terms[i] = sortIdentifier(c.Name) + direction(c.Desc)
+	}
+	return strings.Join(terms, ", ")
+}
+
+// Where returns the condition selecting the rows after cursor and its arguments, numbering placeholders
+// after the argOffset arguments of the query. It returns an empty condition for an empty cursor.
+func (k *Keyset[T]) Where(cursor string, argOffset int) (string, []any, error) {
+	if cursor == "" {
+		return "", nil, nil
+	}
+	values, err := DecodeCursor(cursor)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(values) != len(k.columns) {
+		return "", nil, fmt.Errorf("%w: %d values for %d columns", ErrInvalidCursor, len(values), len(k.columns))
+	}
+
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = "$" + strconv.Itoa(argOffset+i+1)
+	}
+
+	// A row comparison uses an index on the columns, but only works if all columns sort the same way
+	sameDirection := true
+	for _, c := range k.columns {
+		sameDirection = sameDirection && c.Desc == k.columns[0].Desc
+	}
+	if sameDirection {
+		columns := make([]string, len(k.columns))
+		for i, c := range k.columns {
+			columns[i] = sortIdentifier(c.Name)
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), comparison(k.columns[0].Desc),
+			strings.Join(placeholders, ", ")), values, nil
+	}
+
+	// (a > $1) OR (a = $1 AND b < $2) OR ...
+	var terms []string
+	for i, c := range k.columns {
+		var term []string
+		for j := range i {
+			term = append(term, sortIdentifier(k.columns[j].Name)+" = "+placeholders[j])
+		}
+		term = append(term, sortIdentifier(c.Name)+" "+comparison(c.Desc)+" "+placeholders[i])
+		terms = append(terms, "("+strings.Join(term, " AND ")+")")
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", values, nil
+}
+
+// QueryOffset runs query, which must have its own ORDER BY, and returns the page of up to limit items
+// after cursor, or the first page for an empty cursor. The cursor holds the offset, so rows inserted
+// or deleted meanwhile shift pages; prefer Keyset for large or frequently changing tables.
+func QueryOffset[T any](ctx context.Context, q QueryEngine, query, cursor string, limit int, args ...any) (Page[T], error) {
+	if limit <= 0 {
+		return Page[T]{}, fmt.Errorf("page limit must be positive, got %d", limit)
+	}
+
+	var offset int64
+	if cursor != "" {
+		values, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		var ok bool
+		if len(values) == 1 {
+			offset, ok = values[0].(int64)
+		}
+		if !ok || offset < 0 {
+			return Page[T]{}, fmt.Errorf("%w: no offset", ErrInvalidCursor)
+		}
+	}
+
+	args = append(args, limit+1, offset)
+	sql := query + " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	items, err := Select[T](ctx, q, sql, args...)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	page := Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		if page.NextCursor, err = EncodeCursor(offset + int64(limit)); err != nil {
+			return Page[T]{}, err
+		}
+	}
+	return page, nil
+}
+
+func sortIdentifier(column string) string {
+	return pgx.Identifier(strings.Split(column, ".")).Sanitize()
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return ""
+}
+
+// comparison returns the operator selecting rows after a value in the direction
+func comparison(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
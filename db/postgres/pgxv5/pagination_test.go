@@ -0,0 +1,52 @@
+package pgxv5
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	cursor, err := EncodeCursor(createdAt, int64(1<<60), 1.5, "a/b")
+	require.NoError(t, err)
+	assert.NotContains(t, cursor, "/")
+
+	values, err := DecodeCursor(cursor)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"2024-05-01T12:30:00Z", int64(1 << 60), 1.5, "a/b"}, values)
+
+	_, err = DecodeCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestKeysetWhere(t *testing.T) {
+	type item struct {
+		CreatedAt time.Time
+		ID        int64
+	}
+	key := func(i item) []any { return []any{i.CreatedAt, i.ID} }
+	cursor, err := NewKeyset(key).Cursor(item{CreatedAt: time.Unix(0, 0).UTC(), ID: 7})
+	require.NoError(t, err)
+
+	where, args, err := NewKeyset(key, Desc("created_at"), Desc("id")).Where("", 0)
+	require.NoError(t, err)
+	assert.Empty(t, where)
+	assert.Nil(t, args)
+
+	keyset := NewKeyset(key, Desc("created_at"), Desc("id"))
+	assert.Equal(t, `"created_at" DESC, "id" DESC`, keyset.OrderBy())
+	where, args, err = keyset.Where(cursor, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `("created_at", "id") < ($2, $3)`, where)
+	assert.Equal(t, []any{"1970-01-01T00:00:00Z", int64(7)}, args)
+
+	where, _, err = NewKeyset(key, Asc("created_at"), Desc("id")).Where(cursor, 0)
+	require.NoError(t, err)
+	assert.Equal(t, `(("created_at" > $1) OR ("created_at" = $1 AND "id" < $2))`, where)
+
+	_, _, err = NewKeyset(key, Asc("id")).Where(cursor, 0)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,119 @@
+package pgxv5
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryLogger logs the queries of the pools it is passed to with WithQueryLogger. Queries are logged
+// at DEBUG, slow queries at WARN and failed queries at ERROR, with the statement, duration, rows
+// affected and error; arguments are never logged. It can be turned off and tuned at runtime.
+type QueryLogger struct {
+	logger        *slog.Logger
+	enabled       atomic.Bool
+	slowThreshold atomic.Int64
+}
+
+// QueryLoggerOption is a function that configures a query logger.
+type QueryLoggerOption func(l *QueryLogger)
+
+// WithSlowQueryThreshold logs queries taking longer than d at WARN (default: 0, disabled).
+func WithSlowQueryThreshold(d time.Duration) QueryLoggerOption {
+	return func(l *QueryLogger) {
+		l.SetSlowQueryThreshold(d)
+	}
+}
+
+// NewQueryLogger creates an enabled QueryLogger writing to logger.
+func NewQueryLogger(logger *slog.Logger, opts ...QueryLoggerOption) *QueryLogger {
+	l := &QueryLogger{logger: logger}
+	l.enabled.Store(true)
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// SetEnabled turns logging on or off.
+func (l *QueryLogger) SetEnabled(enabled bool) {
+	l.enabled.Store(enabled)
+}
+
+// SetSlowQueryThreshold changes the duration after which queries are logged at WARN; 0 disables it.
+func (l *QueryLogger) SetSlowQueryThreshold(d time.Duration) {
+	l.slowThreshold.Store(int64(d))
+}
+
+// SlowQueryThreshold returns the duration after which queries are logged at WARN.
+func (l *QueryLogger) SlowQueryThreshold() time.Duration {
+	return time.Duration(l.slowThreshold.Load())
+}
+
+// WithQueryLogger logs the Query, QueryRow and Exec calls of the pool with logger. Statements are
+// rewritten like in spans, see WithStatementSanitizer and WithStatementMaxLength.
+func WithQueryLogger(logger *QueryLogger) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.queryLogger = logger
+	}
+}
+
+// queryLogTracer is the pgx tracer of a QueryLogger for a pool
+type queryLogTracer struct {
+	logger *QueryLogger
+	statementRewriter
+}
+
+func newQueryLogTracer(logger *QueryLogger, options *connectionPoolOptions) *queryLogTracer {
+	return &queryLogTracer{logger: logger, statementRewriter: newStatementRewriter(options)}
+}
+
+type queryLogKey struct{}
+
+// queryStart is the query being logged
+type queryStart struct {
+	sql   string
+	start time.Time
+}
+
+// TraceQueryStart is called at the beginning of Query, QueryRow and Exec calls
+func (t *queryLogTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !t.logger.enabled.Load() {
+		return ctx
+	}
+	return context.WithValue(ctx, queryLogKey{}, queryStart{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd is called at the end of Query, QueryRow and Exec calls
+func (t *queryLogTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryLogKey{}).(queryStart)
+	if !ok || !t.logger.enabled.Load() {
+		return
+	}
+	duration := time.Since(query.start)
+
+	level, msg := slog.LevelDebug, "query"
+	threshold := t.logger.SlowQueryThreshold()
+	switch {
+	case data.Err != nil:
+		level, msg = slog.LevelError, "query failed"
+	case threshold > 0 && duration > threshold:
+		level, msg = slog.LevelWarn, "slow query"
+	}
+	if !t.logger.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("sql", t.statement(query.sql)),
+		slog.Duration("duration", duration),
+		slog.Int64("rows", data.CommandTag.RowsAffected()),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, slog.Any("error", data.Err))
+	}
+	t.logger.logger.LogAttrs(ctx, level, msg, attrs...)
+}
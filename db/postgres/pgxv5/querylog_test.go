@@ -0,0 +1,40 @@
+package pgxv5
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewQueryLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	tracer := newQueryLogTracer(logger, &connectionPoolOptions{statementSanitizer: SanitizeStatement})
+
+	trace := func(sql string, duration time.Duration, err error) string {
+		buf.Reset()
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql})
+		time.Sleep(duration)
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 2"), Err: err})
+		return buf.String()
+	}
+
+	out := trace("UPDATE users SET name = 'John'", 0, nil)
+	assert.Contains(t, out, "level=DEBUG msg=query")
+	assert.Contains(t, out, `sql="UPDATE users SET name = ?"`)
+	assert.Contains(t, out, "rows=2")
+
+	logger.SetSlowQueryThreshold(time.Millisecond)
+	assert.Contains(t, trace("SELECT 1", 5*time.Millisecond, nil), "level=WARN msg=\"slow query\"")
+	assert.Contains(t, trace("SELECT 1", 0, errors.New("boom")), "level=ERROR msg=\"query failed\"")
+
+	logger.SetEnabled(false)
+	assert.Empty(t, trace("SELECT 1", 0, errors.New("boom")))
+}
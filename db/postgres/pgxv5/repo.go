@@ -0,0 +1,218 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Repo provides Insert, GetByID, Update, Delete and List for a table mapped to struct T, running
+// on the query engine of the context, so it joins transactions of the TransactionManager.
+//
+// The table is set with a table tag on a blank field and columns with db tags; fields without a tag
+// map to their snake_case name and db:"-" skips a field. Tag options mark the primary key (pk) and
+// columns written by the database only (readonly), e.g. serial keys and defaults:
+//
+//	type User struct {
+//		_         struct{}  `table:"admin.users"`
+//		ID        int64     `db:"id,pk,readonly"`
+//		Email     string    `db:"email"`
+//		CreatedAt time.Time `db:"created_at,readonly"`
+//	}
+type Repo[T any] struct {
+	txManager TransactionManagerAPI
+	columns   []repoColumn
+	pk        repoColumn
+
+	insertSQL string
+	getSQL    string
+	updateSQL string
+	deleteSQL string
+	listSQL   string
+}
+
+// repoColumn is a column of a Repo and the index of its struct field
+type repoColumn struct {
+	name     string
+	index    int
+	readonly bool
+}
+
+// NewRepo creates a Repo for T. It fails if T is not a struct with a table tag and a single pk column.
+func NewRepo[T any](txManager TransactionManagerAPI) (*Repo[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("repo type %s is not a struct", t)
+	}
+
+	r := &Repo[T]{txManager: txManager, pk: repoColumn{index: -1}}
+	var table string
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if name, ok := sf.Tag.Lookup("table"); ok {
+			table = name
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous {
+			return nil, fmt.Errorf("repo type %s embeds %s: embedded structs are not supported", t, sf.Name)
+		}
+
+		tag := sf.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		name, options, tagged := strings.Cut(tag, ",")
+		switch {
+		case name == "" && tagged:
+			// Rows are scanned by RowToStructByName, which needs the column name in the tag
+			return nil, fmt.Errorf("repo type %s: db tag of %s has no column name", t, sf.Name)
+		case name == "":
+			name = snakeCase(sf.Name)
+		}
+
+		column := repoColumn{name: pgx.Identifier{name}.Sanitize(), index: i}
+		pk := false
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "pk":
+				pk = true
+			case "readonly":
+				column.readonly = true
+			}
+		}
+		if pk {
+			if r.pk.index >= 0 {
+				return nil, fmt.Errorf("repo type %s has several pk fields", t)
+			}
+			r.pk = column
+		}
+		r.columns = append(r.columns, column)
+	}
+
+	if table == "" {
+		return nil, fmt.Errorf("repo type %s has no table tag", t)
+	}
+	if r.pk.index < 0 {
+		return nil, fmt.Errorf("repo type %s has no pk field", t)
+	}
+	r.prepare(pgx.Identifier(strings.Split(table, ".")).Sanitize())
+	return r, nil
+}
+
+// prepare builds the statements of the Repo
+func (r *Repo[T]) prepare(table string) {
+	var all, insert, placeholders, set []string
+	for _, c := range r.columns {
+		all = append(all, c.name)
+		if c.readonly {
+			continue
+		}
+		insert = append(insert, c.name)
+		placeholders = append(placeholders, "$"+strconv.Itoa(len(insert)))
+		if c.index != r.pk.index {
+			set = append(set, c.name+" = $"+strconv.Itoa(len(set)+1))
+		}
+	}
+	columns := strings.Join(all, ", ")
+
+	r.insertSQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
+		table, strings.Join(insert, ", "), strings.Join(placeholders, ", "), columns)
+	if len(insert) == 0 {
+		r.insertSQL = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", table, columns)
+	}
+	r.getSQL = fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", columns, table, r.pk.name)
+	if len(set) > 0 {
+		r.updateSQL = fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d RETURNING %s",
+			table, strings.Join(set, ", "), r.pk.name, len(set)+1, columns)
+	}
+	r.deleteSQL = fmt.Sprintf("DELETE FROM %s WHERE %s = $1", table, r.pk.name)
+	r.listSQL = fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", columns, table, r.pk.name)
+}
+
+// Insert inserts v, except its readonly columns, and returns the inserted row.
+func (r *Repo[T]) Insert(ctx context.Context, v T) (T, error) {
+	var args []any
+	for _, c := range r.columns {
+		if !c.readonly {
+			args = append(args, r.value(v, c))
+		}
+	}
+	return Get[T](ctx, r.txManager.GetQueryEngine(ctx), r.insertSQL, args...)
+}
+
+// GetByID returns the row with primary key id. It returns an error matching pgx.ErrNoRows if there is none.
+func (r *Repo[T]) GetByID(ctx context.Context, id any) (T, error) {
+	return Get[T](ctx, r.txManager.GetQueryEngine(ctx), r.getSQL, id)
+}
+
+// Update updates the row with the primary key of v, except its readonly columns, and returns the updated row.
+// It returns an error matching pgx.ErrNoRows if there is none.
+func (r *Repo[T]) Update(ctx context.Context, v T) (T, error) {
+	if r.updateSQL == "" {
+		var zero T
+		return zero, errors.New("repo has no columns to update")
+	}
+
+	var args []any
+	for _, c := range r.columns {
+		if !c.readonly && c.index != r.pk.index {
+			args = append(args, r.value(v, c))
+		}
+	}
+	args = append(args, r.value(v, r.pk))
+	return Get[T](ctx, r.txManager.GetQueryEngine(ctx), r.updateSQL, args...)
+}
+
+// Delete deletes the row with primary key id. It returns pgx.ErrNoRows if there is none.
+func (r *Repo[T]) Delete(ctx context.Context, id any) error {
+	tag, err := r.txManager.GetQueryEngine(ctx).Exec(ctx, r.deleteSQL, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// List returns up to limit rows ordered by primary key, skipping offset rows. A limit of 0 returns all rows.
+func (r *Repo[T]) List(ctx context.Context, limit, offset int) ([]T, error) {
+	sql := r.listSQL
+	var args []any
+	if limit > 0 {
+		args = append(args, limit)
+		sql += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if offset > 0 {
+		args = append(args, offset)
+		sql += " OFFSET $" + strconv.Itoa(len(args))
+	}
+	return Select[T](ctx, r.txManager.GetQueryEngine(ctx), sql, args...)
+}
+
+func (r *Repo[T]) value(v T, c repoColumn) any {
+	return reflect.ValueOf(v).Field(c.index).Interface()
+}
+
+// snakeCase converts a field name to a column name, e.g. UserID to user_id
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package pgxv5
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRepo(t *testing.T) {
+	type user struct {
+		_         struct{} `table:"admin.users"`
+		ID        int64    `db:"id,pk,readonly"`
+		Email     string   `db:"email"`
+		UserName  string
+		Secret    string    `db:"-"`
+		CreatedAt time.Time `db:"created_at,readonly"`
+	}
+
+	r, err := NewRepo[user](nil)
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "admin"."users" ("email", "user_name") VALUES ($1, $2) RETURNING "id", "email", "user_name", "created_at"`, r.insertSQL)
+	assert.Equal(t, `SELECT "id", "email", "user_name", "created_at" FROM "admin"."users" WHERE "id" = $1`, r.getSQL)
+	assert.Equal(t, `UPDATE "admin"."users" SET "email" = $1, "user_name" = $2 WHERE "id" = $3 RETURNING "id", "email", "user_name", "created_at"`, r.updateSQL)
+	assert.Equal(t, `DELETE FROM "admin"."users" WHERE "id" = $1`, r.deleteSQL)
+
+	type noTable struct {
+		ID int64 `db:"id,pk"`
+	}
+	_, err = NewRepo[noTable](nil)
+	assert.ErrorContains(t, err, "no table tag")
+
+	type noPK struct {
+		_  struct{} `table:"t"`
+		ID int64    `db:"id"`
+	}
+	_, err = NewRepo[noPK](nil)
+	assert.ErrorContains(t, err, "no pk field")
+
+	type unnamed struct {
+		_  struct{} `table:"t"`
+		ID int64    `db:",pk"`
+	}
+	_, err = NewRepo[unnamed](nil)
+	assert.ErrorContains(t, err, "no column name")
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"ID":        "id",
+		"UserID":    "user_id",
+		"CreatedAt": "created_at",
+		"HTTPCode":  "http_code",
+		"name":      "name",
+	} {
+		assert.Equal(t, want, snakeCase(name), name)
+	}
+}
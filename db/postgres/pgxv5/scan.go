@@ -0,0 +1,50 @@
+package pgxv5
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Select runs a query and scans all rows into a slice of T, e.g. with the query engine
+// of TransactionManager.GetQueryEngine inside or outside a transaction:
+//
+//	users, err := pgxv5.Select[User](ctx, txManager.GetQueryEngine(ctx), "SELECT id, name FROM users")
+//
+// Structs are scanned by column name: the name of the field, case-insensitively, or its db tag;
+// every column needs a field. Other types, e.g. int64 or uuid.UUID, are scanned from a single column.
+func Select[T any](ctx context.Context, q QueryEngine, sql string, args ...any) ([]T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, rowTo[T]())
+}
+
+// Get runs a query and scans its first row into T like Select. It returns an error matching
+// pgx.ErrNoRows if there is no row.
+func Get[T any](ctx context.Context, q QueryEngine, sql string, args ...any) (T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return pgx.CollectOneRow(rows, rowTo[T]())
+}
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// rowTo returns the row function scanning T by column name if it's a struct without its own scanning
+func rowTo[T any]() pgx.RowToFunc[T] {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(scannerType) {
+		return pgx.RowToStructByName[T]
+	}
+	return pgx.RowTo[T]
+}
@@ -0,0 +1,61 @@
+package pgxv5
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRow is a row with the given columns, scanning values by assignment
+type fakeRow struct {
+	columns []string
+	values  []any
+}
+
+func (r fakeRow) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, column := range r.columns {
+		fields[i].Name = column
+	}
+	return fields
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[i]))
+	}
+	return nil
+}
+
+func (r fakeRow) Values() ([]any, error) { return r.values, nil }
+
+func (r fakeRow) RawValues() [][]byte { return nil }
+
+func TestRowTo(t *testing.T) {
+	t.Run("struct by column name", func(t *testing.T) {
+		type user struct {
+			Name string `db:"full_name"`
+			ID   int64
+		}
+		u, err := rowTo[user]()(fakeRow{columns: []string{"id", "full_name"}, values: []any{int64(7), "Ann"}})
+		require.NoError(t, err)
+		assert.Equal(t, user{ID: 7, Name: "Ann"}, u)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		id, err := rowTo[int64]()(fakeRow{columns: []string{"count"}, values: []any{int64(3)}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), id)
+	})
+
+	t.Run("struct scanned as value", func(t *testing.T) {
+		now := time.Now()
+		ts, err := rowTo[time.Time]()(fakeRow{columns: []string{"now"}, values: []any{now}})
+		require.NoError(t, err)
+		assert.Equal(t, now, ts)
+	})
+}
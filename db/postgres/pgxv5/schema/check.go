@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DriftError is returned by Check when the live schema differs from the expected one
+type DriftError struct {
+	Drift []Drift
+}
+
+func (e *DriftError) Error() string {
+	items := make([]string, len(e.Drift))
+	for i, d := range e.Drift {
+		items[i] = d.String()
+	}
+	return fmt.Sprintf("schema drift detected: %s", strings.Join(items, "; "))
+}
+
+// Check snapshots the live schema and compares it with the expected one, typically at startup:
+//
+//	//go:embed schema.json
+//	var expectedSchema []byte
+//
+//	expected, _ := schema.Parse(expectedSchema)
+//	if err := schema.Check(ctx, pool, expected); err != nil {
+//		logger.Warn("database schema differs from migrations", "error", err)
+//	}
+//
+// It returns *DriftError if the schemas differ.
+func Check(ctx context.Context, q Querier, expected *Snapshot, opts ...Option) error {
+	actual, err := Introspect(ctx, q, opts...)
+	if err != nil {
+		return err
+	}
+	if drift := Diff(expected, actual); len(drift) > 0 {
+		return &DriftError{Drift: drift}
+	}
+	return nil
+}
+
+// Report is the response of the drift endpoint
+type Report struct {
+	InSync bool    `json:"in_sync"`
+	Drift  []Drift `json:"drift,omitempty"`
+}
+
+// Handler creates an HTTP handler reporting schema drift as JSON for admin endpoints.
+// It responds with 200 OK if the schemas match and 409 Conflict if they differ.
+func Handler(q Querier, expected *Snapshot, opts ...Option) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actual, err := Introspect(r.Context(), q, opts...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		drift := Diff(expected, actual)
+		status := http.StatusOK
+		if len(drift) > 0 {
+			status = http.StatusConflict
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(Report{InSync: len(drift) == 0, Drift: drift})
+	}
+}
@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DriftKind is the kind of difference between the expected and the live schema
+type DriftKind string
+
+const (
+	// Missing objects are expected but absent from the database
+	Missing DriftKind = "missing"
+	// Unexpected objects exist in the database but are not expected
+	Unexpected DriftKind = "unexpected"
+	// Changed objects exist in both, but differ
+	Changed DriftKind = "changed"
+)
+
+// Drift is a difference between the expected and the live schema
+type Drift struct {
+	Kind DriftKind `json:"kind"`
+	// Object is "table", "column", "index" or "constraint"
+	Object string `json:"object"`
+	// Name is the qualified name of the object, e.g. "public.users.email"
+	Name     string `json:"name"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func (d Drift) String() string {
+	switch d.Kind {
+	case Changed:
+		return fmt.Sprintf("%s %s %s: expected %q, got %q", d.Kind, d.Object, d.Name, d.Expected, d.Actual)
+	default:
+		return fmt.Sprintf("%s %s %s", d.Kind, d.Object, d.Name)
+	}
+}
+
+// Diff compares the live schema with the expected one
+func Diff(expected, actual *Snapshot) []Drift {
+	var drift []Drift
+
+	diffNamed(&drift, "table", "", expected.Tables, actual.Tables, Table.QualifiedName, func(_ string, e, a Table) {
+		prefix := e.QualifiedName() + "."
+		diffNamed(&drift, "column", prefix, e.Columns, a.Columns, Column.name, func(name string, e, a Column) {
+			if e != a {
+				drift = append(drift, Drift{Kind: Changed, Object: "column", Name: name, Expected: e.String(), Actual: a.String()})
+			}
+		})
+		diffNamed(&drift, "index", prefix, e.Indexes, a.Indexes, Index.name, func(name string, e, a Index) {
+			if e != a {
+				drift = append(drift, Drift{Kind: Changed, Object: "index", Name: name, Expected: e.Definition, Actual: a.Definition})
+			}
+		})
+		diffNamed(&drift, "constraint", prefix, e.Constraints, a.Constraints, Constraint.name, func(name string, e, a Constraint) {
+			if e != a {
+				drift = append(drift, Drift{Kind: Changed, Object: "constraint", Name: name, Expected: e.Definition, Actual: a.Definition})
+			}
+		})
+	})
+
+	return drift
+}
+
+// diffNamed reports missing and unexpected objects and calls compare for objects present in both
+func diffNamed[T any](drift *[]Drift, object, prefix string, expected, actual []T, name func(T) string, compare func(name string, e, a T)) {
+	actualByName := make(map[string]T, len(actual))
+	for _, a := range actual {
+		actualByName[name(a)] = a
+	}
+
+	seen := make(map[string]bool, len(expected))
+	for _, e := range expected {
+		n := name(e)
+		seen[n] = true
+		a, ok := actualByName[n]
+		if !ok {
+			*drift = append(*drift, Drift{Kind: Missing, Object: object, Name: prefix + n})
+			continue
+		}
+		compare(prefix+n, e, a)
+	}
+
+	for _, a := range actual {
+		if n := name(a); !seen[n] {
+			*drift = append(*drift, Drift{Kind: Unexpected, Object: object, Name: prefix + n})
+		}
+	}
+}
+
+func (c Column) name() string     { return c.Name }
+func (i Index) name() string      { return i.Name }
+func (c Constraint) name() string { return c.Name }
+
+// String describes the column type, nullability and default, e.g. "text NOT NULL DEFAULT 'none'::text"
+func (c Column) String() string {
+	var b strings.Builder
+	b.WriteString(c.Type)
+	if !c.Nullable {
+		b.WriteString(" NOT NULL")
+	}
+	if c.Default != "" {
+		b.WriteString(" DEFAULT ")
+		b.WriteString(c.Default)
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	expected := &Snapshot{Tables: []Table{
+		{Schema: "public", Name: "orders", Columns: []Column{{Name: "id", Type: "bigint"}}},
+		{
+			Schema: "public",
+			Name:   "users",
+			Columns: []Column{
+				{Name: "email", Type: "text"},
+				{Name: "id", Type: "bigint"},
+			},
+			Indexes: []Index{
+				{Name: "users_email_idx", Definition: "CREATE INDEX users_email_idx ON public.users USING btree (email)"},
+			},
+			Constraints: []Constraint{
+				{Name: "users_pkey", Type: "primary_key", Definition: "PRIMARY KEY (id)"},
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, expected.Write(&buf))
+	actual, err := Parse(buf.Bytes())
+	require.NoError(t, err)
+	require.Empty(t, Diff(expected, actual))
+
+	actual.Tables = actual.Tables[1:] // drop orders
+	users := &actual.Tables[0]
+	users.Columns[0].Nullable = true
+	users.Columns = append(users.Columns, Column{Name: "name", Type: "text"})
+	users.Indexes = nil
+
+	require.Equal(t, []Drift{
+		{Kind: Missing, Object: "table", Name: "public.orders"},
+		{Kind: Changed, Object: "column", Name: "public.users.email", Expected: "text NOT NULL", Actual: "text"},
+		{Kind: Unexpected, Object: "column", Name: "public.users.name"},
+		{Kind: Missing, Object: "index", Name: "public.users.users_email_idx"},
+	}, Diff(expected, actual))
+}
@@ -0,0 +1,281 @@
+// Package schema snapshots the schema of a PostgreSQL database in a canonical form
+// and detects drift from the schema expected by migrations.
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Querier runs queries, e.g. *pgxpool.Pool or a pgxv5 connection
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Snapshot is the canonical form of a database schema: tables, columns, indexes and
+// constraints sorted by name, so that equal schemas produce equal snapshots.
+type Snapshot struct {
+	Tables []Table `json:"tables"`
+}
+
+// Table is a table with its columns, indexes and constraints
+type Table struct {
+	Schema      string       `json:"schema"`
+	Name        string       `json:"name"`
+	Columns     []Column     `json:"columns"`
+	Indexes     []Index      `json:"indexes,omitempty"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+}
+
+// Column is a table column. Column order is not part of the snapshot.
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Default  string `json:"default,omitempty"`
+}
+
+// Index is an index with its definition as returned by pg_indexes
+type Index struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// Constraint is a primary key, foreign key, unique, check or exclusion constraint
+type Constraint struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Definition string `json:"definition"`
+}
+
+// QualifiedName returns the schema-qualified name of the table
+func (t Table) QualifiedName() string {
+	return t.Schema + "." + t.Name
+}
+
+// options holds configuration for Introspect
+type options struct {
+	schemas  []string
+	excluded []string
+}
+
+// Option is a function that configures introspection options.
+type Option func(opts *options)
+
+// WithSchemas sets the schemas to introspect (default: public).
+func WithSchemas(schemas ...string) Option {
+	return func(opts *options) {
+		opts.schemas = schemas
+	}
+}
+
+// WithExcludedTables excludes tables, e.g. migration bookkeeping tables. Names may be
+// schema-qualified. The default excludes schema_migrations and goose_db_version.
+func WithExcludedTables(tables ...string) Option {
+	return func(opts *options) {
+		opts.excluded = tables
+	}
+}
+
+func defaultOptions() *options {
+	return &options{
+		schemas:  []string{"public"},
+		excluded: []string{"schema_migrations", "goose_db_version"},
+	}
+}
+
+func (o *options) isExcluded(schema, table string) bool {
+	return slices.Contains(o.excluded, table) || slices.Contains(o.excluded, schema+"."+table)
+}
+
+const (
+	tablesQuery = `
+SELECT n.nspname, c.relname
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition AND n.nspname = ANY($1)`
+
+	columnsQuery = `
+SELECT n.nspname, c.relname, a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+       coalesce(pg_get_expr(d.adbin, d.adrelid), '')
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition AND a.attnum > 0 AND NOT a.attisdropped
+  AND n.nspname = ANY($1)`
+
+	indexesQuery = `
+SELECT schemaname, tablename, indexname, indexdef
+FROM pg_indexes
+WHERE schemaname = ANY($1)`
+
+	constraintsQuery = `
+SELECT n.nspname, c.relname, con.conname, con.contype::text, pg_get_constraintdef(con.oid)
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE con.contype IN ('p', 'f', 'u', 'c', 'x') AND n.nspname = ANY($1)`
+)
+
+var constraintTypes = map[string]string{
+	"p": "primary_key",
+	"f": "foreign_key",
+	"u": "unique",
+	"c": "check",
+	"x": "exclusion",
+}
+
+// Introspect snapshots the live schema of the database
+func Introspect(ctx context.Context, q Querier, opts ...Option) (*Snapshot, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	tables := make(map[string]*Table)
+	table := func(schema, name string) *Table {
+		if o.isExcluded(schema, name) {
+			return nil
+		}
+		key := schema + "." + name
+		t, ok := tables[key]
+		if !ok {
+			t = &Table{Schema: schema, Name: name}
+			tables[key] = t
+		}
+		return t
+	}
+
+	err := query(ctx, q, tablesQuery, o.schemas, func(rows pgx.Rows) error {
+		var schema, name string
+		if err := rows.Scan(&schema, &name); err != nil {
+			return err
+		}
+		table(schema, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+
+	err = query(ctx, q, columnsQuery, o.schemas, func(rows pgx.Rows) error {
+		var schema, name string
+		var c Column
+		if err := rows.Scan(&schema, &name, &c.Name, &c.Type, &c.Nullable, &c.Default); err != nil {
+			return err
+		}
+		if t := table(schema, name); t != nil {
+			t.Columns = append(t.Columns, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query columns: %w", err)
+	}
+
+	err = query(ctx, q, indexesQuery, o.schemas, func(rows pgx.Rows) error {
+		var schema, name string
+		var i Index
+		if err := rows.Scan(&schema, &name, &i.Name, &i.Definition); err != nil {
+			return err
+		}
+		if t, ok := tables[schema+"."+name]; ok {
+			t.Indexes = append(t.Indexes, i)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+
+	err = query(ctx, q, constraintsQuery, o.schemas, func(rows pgx.Rows) error {
+		var schema, name, contype string
+		var c Constraint
+		if err := rows.Scan(&schema, &name, &c.Name, &contype, &c.Definition); err != nil {
+			return err
+		}
+		c.Type = constraintTypes[contype]
+		if t, ok := tables[schema+"."+name]; ok {
+			t.Constraints = append(t.Constraints, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+
+	s := &Snapshot{Tables: make([]Table, 0, len(tables))}
+	for _, t := range tables {
+		s.Tables = append(s.Tables, *t)
+	}
+	s.sort()
+	return s, nil
+}
+
+func query(ctx context.Context, q Querier, sql string, schemas []string, scan func(pgx.Rows) error) error {
+	rows, err := q.Query(ctx, sql, schemas)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// sort puts the snapshot in canonical order
+func (s *Snapshot) sort() {
+	slices.SortFunc(s.Tables, func(a, b Table) int {
+		return strings.Compare(a.QualifiedName(), b.QualifiedName())
+	})
+	for i := range s.Tables {
+		t := &s.Tables[i]
+		slices.SortFunc(t.Columns, func(a, b Column) int { return strings.Compare(a.Name, b.Name) })
+		slices.SortFunc(t.Indexes, func(a, b Index) int { return strings.Compare(a.Name, b.Name) })
+		slices.SortFunc(t.Constraints, func(a, b Constraint) int { return strings.Compare(a.Name, b.Name) })
+	}
+}
+
+// Write writes the snapshot as indented JSON, e.g. to commit the schema expected by migrations
+func (s *Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteFile writes the snapshot to a file
+func (s *Snapshot) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Parse parses a snapshot written by Write, e.g. embedded with go:embed
+func Parse(data []byte) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse schema snapshot: %w", err)
+	}
+	s.sort()
+	return &s, nil
+}
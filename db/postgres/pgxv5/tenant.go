@@ -0,0 +1,351 @@
+package pgxv5
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoTenant is returned when the context has no tenant
+	ErrNoTenant = errors.New("no tenant in context")
+	// ErrTenantPoolsExhausted is returned when a tenant pool can't be created within the limits
+	// because all other pools are in use
+	ErrTenantPoolsExhausted = errors.New("tenant pool limits exhausted")
+)
+
+// ContextWithTenant returns a copy of ctx carrying the tenant
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant set by ContextWithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey).(string)
+	return tenant, ok && tenant != ""
+}
+
+// TenantResolver returns the connection string of the tenant database
+type TenantResolver func(ctx context.Context, tenant string) (string, error)
+
+type tenantPoolsOptions struct {
+	poolOptions []ConnectionPoolOption
+	maxPools    int
+	maxConns    int32
+	idleTimeout time.Duration
+}
+
+// TenantPoolsOption is a function that configures tenant pools options.
+type TenantPoolsOption func(opts *tenantPoolsOptions)
+
+// WithTenantPoolOptions sets the options of every tenant pool. The pool name defaults to the tenant.
+func WithTenantPoolOptions(opts ...ConnectionPoolOption) TenantPoolsOption {
+	return func(o *tenantPoolsOptions) {
+		o.poolOptions = append(o.poolOptions, opts...)
+	}
+}
+
+// WithMaxTenantPools limits the number of open pools (default: unlimited).
+func WithMaxTenantPools(n int) TenantPoolsOption {
+	return func(o *tenantPoolsOptions) {
+		o.maxPools = n
+	}
+}
+
+// WithMaxTotalConnections limits the sum of the maximum connections of all open pools (default: unlimited).
+func WithMaxTotalConnections(n int32) TenantPoolsOption {
+	return func(o *tenantPoolsOptions) {
+		o.maxConns = n
+	}
+}
+
+// WithTenantPoolIdleTimeout sets how long an unused pool stays open (default: 10m, zero keeps pools open).
+func WithTenantPoolIdleTimeout(d time.Duration) TenantPoolsOption {
+	return func(o *tenantPoolsOptions) {
+		o.idleTimeout = d
+	}
+}
+
+// TenantPools maintains a connection pool per tenant database for database-per-tenant services.
+// Pools are created on first use and closed when idle. When a limit is reached, the least recently
+// used pools without acquired connections are closed to make room.
+type TenantPools struct {
+	resolve  TenantResolver
+	opts     *tenantPoolsOptions
+	poolMax  int32
+	connect  func(ctx context.Context, connString string, opts ...ConnectionPoolOption) (*Connection, error)
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu         sync.Mutex
+	pools      map[string]*tenantPool
+	lru        *list.List // of tenants, most recently used first
+	totalConns int32
+}
+
+type tenantPool struct {
+	tenant   string
+	conn     *Connection
+	err      error
+	ready    chan struct{}
+	lastUsed time.Time
+	leases   int
+	elem     *list.Element
+}
+
+// NewTenantPools creates a pool manager resolving tenant databases with resolve
+func NewTenantPools(resolve TenantResolver, opts ...TenantPoolsOption) *TenantPools {
+	o := &tenantPoolsOptions{idleTimeout: tenantPoolIdleTimeoutDefault}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// Every pool reserves its maximum size from the total connections budget
+	poolOpts := &connectionPoolOptions{maxConnectionsCount: maxConnectionsCountDefault}
+	for _, opt := range o.poolOptions {
+		opt(poolOpts)
+	}
+
+	m := &TenantPools{
+		resolve: resolve,
+		opts:    o,
+		poolMax: poolOpts.maxConnectionsCount,
+		connect: NewConnectionPool,
+		stop:    make(chan struct{}),
+		pools:   make(map[string]*tenantPool),
+		lru:     list.New(),
+	}
+
+	if o.idleTimeout > 0 {
+		go m.closeIdleLoop()
+	}
+
+	return m
+}
+
+// Pool returns the pool of the tenant from the context, see Get
+func (m *TenantPools) Pool(ctx context.Context) (*Connection, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+	return m.Get(ctx, tenant)
+}
+
+// Get returns the pool of the tenant, creating it if needed. The pool may be closed once it is idle,
+// so get it for every unit of work instead of keeping it; use Do to run longer work.
+func (m *TenantPools) Get(ctx context.Context, tenant string) (*Connection, error) {
+	p, err := m.acquire(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	m.release(p)
+	return p.conn, nil
+}
+
+// Do runs fn with the pool of the tenant from the context. The pool is not closed while fn runs.
+func (m *TenantPools) Do(ctx context.Context, fn func(ctx context.Context, conn *Connection) error) error {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+
+	p, err := m.acquire(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	defer m.release(p)
+
+	return fn(ctx, p.conn)
+}
+
+// acquire returns the ready pool of the tenant with a lease held
+func (m *TenantPools) acquire(ctx context.Context, tenant string) (*tenantPool, error) {
+	m.mu.Lock()
+	p, ok := m.pools[tenant]
+	if !ok {
+		// Resolve before making room, so that unknown tenants don't evict pools
+		m.mu.Unlock()
+		connString, err := m.resolve(ctx, tenant)
+		if err != nil {
+			return nil, fmt.Errorf("can't resolve database of tenant %s: %w", tenant, err)
+		}
+
+		m.mu.Lock()
+		if p, ok = m.pools[tenant]; !ok {
+			return m.create(ctx, tenant, connString)
+		}
+	}
+
+	p.leases++
+	p.lastUsed = time.Now()
+	m.lru.MoveToFront(p.elem)
+	m.mu.Unlock()
+
+	select {
+	case <-p.ready:
+	case <-ctx.Done():
+		m.release(p)
+		return nil, ctx.Err()
+	}
+	if p.err != nil {
+		m.release(p)
+		return nil, p.err
+	}
+	return p, nil
+}
+
+// create opens the pool of the tenant with a lease held. Must be called with the lock held, unlocks it.
+func (m *TenantPools) create(ctx context.Context, tenant, connString string) (*tenantPool, error) {
+	evicted, err := m.reserveLocked()
+	if err != nil {
+		m.mu.Unlock()
+		closePools(evicted)
+		return nil, fmt.Errorf("can't open pool of tenant %s: %w", tenant, err)
+	}
+	p := &tenantPool{tenant: tenant, ready: make(chan struct{}), lastUsed: time.Now(), leases: 1}
+	p.elem = m.lru.PushFront(tenant)
+	m.pools[tenant] = p
+	m.totalConns += m.poolMax
+	m.mu.Unlock()
+
+	closePools(evicted)
+
+	opts := append([]ConnectionPoolOption{WithPoolName(tenant)}, m.opts.poolOptions...)
+	p.conn, p.err = m.connect(ctx, connString, opts...)
+	if p.err != nil {
+		p.err = fmt.Errorf("can't open pool of tenant %s: %w", tenant, p.err)
+		m.mu.Lock()
+		m.removeLocked(p)
+		m.mu.Unlock()
+	}
+	close(p.ready)
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p, nil
+}
+
+func (m *TenantPools) release(p *tenantPool) {
+	m.mu.Lock()
+	p.leases--
+	m.mu.Unlock()
+}
+
+// reserveLocked makes room for a new pool, removing least recently used idle pools.
+// The removed pools must be closed by the caller after unlocking.
+func (m *TenantPools) reserveLocked() ([]*tenantPool, error) {
+	var evicted []*tenantPool
+	fits := func() bool {
+		return (m.opts.maxPools <= 0 || len(m.pools) < m.opts.maxPools) &&
+			(m.opts.maxConns <= 0 || m.totalConns+m.poolMax <= m.opts.maxConns)
+	}
+
+	for e := m.lru.Back(); e != nil && !fits(); {
+		p := m.pools[e.Value.(string)]
+		e = e.Prev()
+		if p.idle() {
+			m.removeLocked(p)
+			evicted = append(evicted, p)
+		}
+	}
+
+	if !fits() {
+		// Keep the idle pools removed so far: they would be evicted next time anyway
+		return evicted, ErrTenantPoolsExhausted
+	}
+	return evicted, nil
+}
+
+func (m *TenantPools) removeLocked(p *tenantPool) {
+	if m.pools[p.tenant] != p {
+		return
+	}
+	delete(m.pools, p.tenant)
+	m.lru.Remove(p.elem)
+	m.totalConns -= m.poolMax
+}
+
+// idle reports whether the pool is ready and unused. Must be called with the lock held.
+func (p *tenantPool) idle() bool {
+	select {
+	case <-p.ready:
+	default:
+		return false
+	}
+	return p.conn != nil && p.leases == 0 && p.conn.pool.Stat().AcquiredConns() == 0
+}
+
+func (m *TenantPools) closeIdleLoop() {
+	ticker := time.NewTicker(m.opts.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.closeIdle(time.Now().Add(-m.opts.idleTimeout))
+		}
+	}
+}
+
+// closeIdle closes idle pools not used since before
+func (m *TenantPools) closeIdle(before time.Time) {
+	var idle []*tenantPool
+
+	m.mu.Lock()
+	for e := m.lru.Back(); e != nil; {
+		p := m.pools[e.Value.(string)]
+		e = e.Prev()
+		if p.lastUsed.After(before) {
+			break
+		}
+		if p.idle() {
+			m.removeLocked(p)
+			idle = append(idle, p)
+		}
+	}
+	m.mu.Unlock()
+
+	closePools(idle)
+}
+
+func closePools(pools []*tenantPool) {
+	for _, p := range pools {
+		p.conn.Close()
+	}
+}
+
+// Len returns the number of open pools
+func (m *TenantPools) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pools)
+}
+
+// Close closes all pools and stops closing idle pools
+func (m *TenantPools) Close() {
+	m.stopOnce.Do(func() { close(m.stop) })
+
+	m.mu.Lock()
+	pools := make([]*tenantPool, 0, len(m.pools))
+	for _, p := range m.pools {
+		pools = append(pools, p)
+	}
+	m.pools = make(map[string]*tenantPool)
+	m.lru.Init()
+	m.totalConns = 0
+	m.mu.Unlock()
+
+	for _, p := range pools {
+		<-p.ready
+		if p.conn != nil {
+			p.conn.Close()
+		}
+	}
+}
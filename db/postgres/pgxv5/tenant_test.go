@@ -0,0 +1,75 @@
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// newLazyTenantPools creates tenant pools whose pools never connect
+func newLazyTenantPools(t *testing.T, opts ...TenantPoolsOption) (*TenantPools, *[]string) {
+	var opened []string
+	m := NewTenantPools(func(_ context.Context, tenant string) (string, error) {
+		if tenant == "unknown" {
+			return "", errors.New("tenant not found")
+		}
+		return "postgres://localhost:1/" + tenant, nil
+	}, opts...)
+	m.connect = func(ctx context.Context, connString string, _ ...ConnectionPoolOption) (*Connection, error) {
+		cfg, err := pgxpool.ParseConfig(connString)
+		require.NoError(t, err)
+		pool, err := pgxpool.NewWithConfig(ctx, cfg)
+		require.NoError(t, err)
+		opened = append(opened, cfg.ConnConfig.Database)
+		return &Connection{pool: pool}, nil
+	}
+	t.Cleanup(m.Close)
+	return m, &opened
+}
+
+func TestTenantPools(t *testing.T) {
+	ctx := context.Background()
+	m, opened := newLazyTenantPools(t,
+		WithTenantPoolOptions(WithMaxConnectionsCount(4)),
+		WithMaxTotalConnections(8),
+		WithTenantPoolIdleTimeout(0),
+	)
+
+	_, err := m.Pool(ctx)
+	require.ErrorIs(t, err, ErrNoTenant)
+
+	a, err := m.Pool(ContextWithTenant(ctx, "a"))
+	require.NoError(t, err)
+	again, err := m.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Same(t, a, again)
+
+	_, err = m.Get(ctx, "b")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, *opened)
+
+	// "a" is the least recently used idle pool and makes room for "c"
+	_, err = m.Get(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, 2, m.Len())
+
+	err = m.Do(ContextWithTenant(ctx, "b"), func(ctx context.Context, _ *Connection) error {
+		return m.Do(ContextWithTenant(ctx, "c"), func(ctx context.Context, _ *Connection) error {
+			// Both pools are in use
+			_, err := m.Get(ctx, "d")
+			return err
+		})
+	})
+	require.ErrorIs(t, err, ErrTenantPoolsExhausted)
+
+	_, err = m.Get(ctx, "unknown")
+	require.ErrorContains(t, err, "tenant not found")
+	require.Equal(t, 2, m.Len())
+
+	m.closeIdle(time.Now())
+	require.Equal(t, 0, m.Len())
+}
@@ -0,0 +1,33 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultLeakTimeout is how long leak assertions wait for connections to be released
+const DefaultLeakTimeout = 5 * time.Second
+
+// AssertNoAcquiredConns fails the test if connections of the pool are still
+// acquired after DefaultLeakTimeout. A non-zero count usually means rows or
+// a transaction were not closed. Typically deferred right after the pool is created:
+//
+//	defer testutil.AssertNoAcquiredConns(t, conn.Pool())
+func AssertNoAcquiredConns(t testing.TB, pool *pgxpool.Pool) {
+	t.Helper()
+
+	deadline := time.Now().Add(DefaultLeakTimeout)
+	for {
+		acquired := pool.Stat().AcquiredConns()
+		if acquired == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("connection leak: %d pgx pool connections are still acquired", acquired)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,93 @@
+package pgxv5
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithStatementTimeout sets statement_timeout of every connection of the pool (default: server setting),
+// so the server aborts statements running longer than d.
+func WithStatementTimeout(d time.Duration) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.statementTimeout = d
+	}
+}
+
+// WithLockTimeout sets lock_timeout of every connection of the pool (default: server setting),
+// so statements waiting longer than d for a lock fail instead of queueing behind it.
+func WithLockTimeout(d time.Duration) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.lockTimeout = d
+	}
+}
+
+// WithIdleInTransactionTimeout sets idle_in_transaction_session_timeout of every connection of the pool
+// (default: server setting), so the server terminates sessions left idle in a transaction longer than d.
+func WithIdleInTransactionTimeout(d time.Duration) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.idleInTransactionTimeout = d
+	}
+}
+
+// sessionTimeouts returns the SET statements of the configured timeouts, or an empty string
+func sessionTimeouts(options *connectionPoolOptions) string {
+	var statements []string
+	for _, setting := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"statement_timeout", options.statementTimeout},
+		{"lock_timeout", options.lockTimeout},
+		{"idle_in_transaction_session_timeout", options.idleInTransactionTimeout},
+	} {
+		if setting.value > 0 {
+			statements = append(statements, fmt.Sprintf("SET %s = %d", setting.name, timeoutMillis(setting.value)))
+		}
+	}
+	return strings.Join(statements, "; ")
+}
+
+// timeoutMillis converts d to the milliseconds of timeout settings, rounding up
+// because 0 disables them
+func timeoutMillis(d time.Duration) int64 {
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
+
+// RunWithStatementTimeout executes the given function within a ReadCommitted transaction whose statements
+// the server aborts after timeout. Within a transaction of ctx, the timeout applies to the statements of
+// the function only and the previous one is restored afterwards.
+func (m *TransactionManager) RunWithStatementTimeout(ctx context.Context, timeout time.Duration, f func(txCtx context.Context) error) error {
+	_, nested := ctx.Value(txKey).(*Transaction)
+	return m.RunReadCommitted(ctx, func(txCtx context.Context) error {
+		q := m.GetQueryEngine(txCtx)
+
+		var previous string
+		if nested {
+			if err := q.QueryRow(txCtx, "SELECT current_setting('statement_timeout')").Scan(&previous); err != nil {
+				return fmt.Errorf("failed to read statement timeout: %w", err)
+			}
+		}
+		if err := setLocalStatementTimeout(txCtx, q, strconv.FormatInt(timeoutMillis(timeout), 10)); err != nil {
+			return err
+		}
+
+		if err := f(txCtx); err != nil {
+			return err
+		}
+		if nested {
+			return setLocalStatementTimeout(txCtx, q, previous)
+		}
+		return nil
+	})
+}
+
+// setLocalStatementTimeout sets statement_timeout until the end of the transaction
+func setLocalStatementTimeout(ctx context.Context, q QueryEngine, value string) error {
+	if _, err := q.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", value); err != nil {
+		return fmt.Errorf("failed to set statement timeout: %w", err)
+	}
+	return nil
+}
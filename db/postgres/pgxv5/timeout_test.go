@@ -0,0 +1,84 @@
+package pgxv5
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rshelekhov/go-db/postgres/pgxv5/testutil"
+)
+
+func TestSessionTimeouts(t *testing.T) {
+	assert.Empty(t, sessionTimeouts(&connectionPoolOptions{}))
+	assert.Equal(t,
+		"SET statement_timeout = 5000; SET idle_in_transaction_session_timeout = 1",
+		sessionTimeouts(&connectionPoolOptions{statementTimeout: 5 * time.Second, idleInTransactionTimeout: time.Microsecond}),
+	)
+}
+
+func TestRunWithStatementTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := testutil.NewTestDB(ctx)
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	require.NoError(t, db.WaitForReady(ctx))
+
+	conn, err := NewConnectionPool(ctx, db.ConnStr())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	txManager := NewTransactionManager(conn)
+	statementTimeout := func(txCtx context.Context) string {
+		var timeout string
+		require.NoError(t, txManager.GetQueryEngine(txCtx).QueryRow(txCtx, "SHOW statement_timeout").Scan(&timeout))
+		return timeout
+	}
+
+	t.Run("Aborts Statement", func(t *testing.T) {
+		start := time.Now()
+		err := txManager.RunWithStatementTimeout(ctx, 100*time.Millisecond, func(txCtx context.Context) error {
+			assert.Equal(t, "100ms", statementTimeout(txCtx))
+			_, err := txManager.GetQueryEngine(txCtx).Exec(txCtx, "SELECT pg_sleep(5)")
+			return err
+		})
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		assert.Equal(t, "57014", pgErr.Code)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		// The timeout was local to the transaction, so the connection is back to the server setting
+		err = txManager.RunReadCommitted(ctx, func(txCtx context.Context) error {
+			assert.Equal(t, "0", statementTimeout(txCtx))
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Restores Outer Timeout", func(t *testing.T) {
+		err := txManager.RunReadCommitted(ctx, func(txCtx context.Context) error {
+			q := txManager.GetQueryEngine(txCtx)
+			if _, err := q.Exec(txCtx, "SET LOCAL statement_timeout = '5s'"); err != nil {
+				return err
+			}
+
+			err := txManager.RunWithStatementTimeout(txCtx, 100*time.Millisecond, func(txCtx context.Context) error {
+				assert.Equal(t, "100ms", statementTimeout(txCtx))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, "5s", statementTimeout(txCtx))
+
+			// Statements after the nested call are no longer bound by its timeout
+			_, err = q.Exec(txCtx, "SELECT pg_sleep(0.3)")
+			return err
+		})
+		require.NoError(t, err)
+	})
+}
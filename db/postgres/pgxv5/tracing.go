@@ -0,0 +1,177 @@
+package pgxv5
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+)
+
+// truncatedSuffix marks statements truncated by WithStatementMaxLength
+const truncatedSuffix = "..."
+
+// WithStatementMaxLength truncates statements recorded in spans to n bytes (default: no limit),
+// so large generated queries, e.g. bulk inserts, don't exceed span attribute limits.
+func WithStatementMaxLength(n int) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.statementMaxLength = n
+	}
+}
+
+// WithStatementSanitizer rewrites statements before they are recorded in spans, e.g. with
+// SanitizeStatement to remove literals inlined into the SQL. Queries are executed unchanged.
+func WithStatementSanitizer(fn func(sql string) string) ConnectionPoolOption {
+	return func(opts *connectionPoolOptions) {
+		opts.statementSanitizer = fn
+	}
+}
+
+// newTracer creates the pgx tracer recording spans for Query, QueryRow, Exec, SendBatch, CopyFrom,
+// Prepare, connect and acquire calls, with the statement, rows affected and errors
+func newTracer(options *connectionPoolOptions, opts ...otelpgx.Option) pgx.QueryTracer {
+	tracer := otelpgx.NewTracer(opts...)
+	if options.statementSanitizer == nil && options.statementMaxLength <= 0 {
+		return tracer
+	}
+
+	return &statementTracer{
+		Tracer:            tracer,
+		statementRewriter: newStatementRewriter(options),
+	}
+}
+
+// statementTracer rewrites the statements passed to otelpgx; the other trace methods are promoted
+type statementTracer struct {
+	*otelpgx.Tracer
+	statementRewriter
+}
+
+// TraceQueryStart is called at the beginning of Query, QueryRow and Exec calls
+func (t *statementTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	data.SQL = t.statement(data.SQL)
+	return t.Tracer.TraceQueryStart(ctx, conn, data)
+}
+
+// TraceBatchQuery is called after each query of a batch
+func (t *statementTracer) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchQueryData) {
+	data.SQL = t.statement(data.SQL)
+	t.Tracer.TraceBatchQuery(ctx, conn, data)
+}
+
+// TracePrepareStart is called at the beginning of Prepare calls
+func (t *statementTracer) TracePrepareStart(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	data.SQL = t.statement(data.SQL)
+	return t.Tracer.TracePrepareStart(ctx, conn, data)
+}
+
+// statementRewriter applies WithStatementSanitizer and WithStatementMaxLength to recorded statements
+type statementRewriter struct {
+	sanitize  func(sql string) string
+	maxLength int
+}
+
+func newStatementRewriter(options *connectionPoolOptions) statementRewriter {
+	return statementRewriter{sanitize: options.statementSanitizer, maxLength: options.statementMaxLength}
+}
+
+func (r statementRewriter) statement(sql string) string {
+	if r.sanitize != nil {
+		sql = r.sanitize(sql)
+	}
+	return truncateStatement(sql, r.maxLength)
+}
+
+// truncateStatement cuts sql to at most n bytes on a rune boundary, marking it as truncated
+func truncateStatement(sql string, n int) string {
+	if n <= 0 || len(sql) <= n {
+		return sql
+	}
+
+	cut := max(n-len(truncatedSuffix), 0)
+	for cut > 0 && !utf8.RuneStart(sql[cut]) {
+		cut--
+	}
+	return sql[:cut] + truncatedSuffix
+}
+
+// SanitizeStatement replaces string and numeric literals of sql with "?", so values inlined
+// into statements, e.g. by query builders, aren't recorded. Placeholders like $1, identifiers,
+// quoted identifiers and comments are kept.
+func SanitizeStatement(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			// String literal; '' is an escaped quote
+			j := i + 1
+			for j < len(sql) {
+				if sql[j] == '\'' {
+					if j+1 < len(sql) && sql[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			b.WriteByte('?')
+			i = j + 1
+		case c == '"':
+			// Quoted identifier
+			j := strings.IndexByte(sql[i+1:], '"')
+			if j < 0 {
+				b.WriteString(sql[i:])
+				return b.String()
+			}
+			b.WriteString(sql[i : i+j+2])
+			i += j + 2
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			// Line comment
+			j := strings.IndexByte(sql[i:], '\n')
+			if j < 0 {
+				b.WriteString(sql[i:])
+				return b.String()
+			}
+			b.WriteString(sql[i : i+j])
+			i += j
+		case isDigit(c) && (i == 0 || !isIdentByte(sql[i-1])):
+			j := i
+			for j < len(sql) && (isDigit(sql[j]) || sql[j] == '.') {
+				j++
+			}
+			// Exponent, e.g. 1.5e-3
+			if j+1 < len(sql) && (sql[j] == 'e' || sql[j] == 'E') {
+				k := j + 1
+				if sql[k] == '+' || sql[k] == '-' {
+					k++
+				}
+				if k < len(sql) && isDigit(sql[k]) {
+					j = k
+					for j < len(sql) && isDigit(sql[j]) {
+						j++
+					}
+				}
+			}
+			b.WriteByte('?')
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdentByte reports whether c can precede a digit within an identifier or a placeholder
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= utf8.RuneSelf
+}
@@ -0,0 +1,59 @@
+package pgxv5
+
+import (
+	"context"
+	"testing"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+)
+
+func TestSanitizeStatement(t *testing.T) {
+	for sql, want := range map[string]string{
+		"SELECT * FROM users WHERE id = $1":                        "SELECT * FROM users WHERE id = $1",
+		"SELECT * FROM users WHERE email = 'a@b.c' AND age > 42":   "SELECT * FROM users WHERE email = ? AND age > ?",
+		"INSERT INTO t2 (\"col1\", name) VALUES (1.5e-3, 'it''s')": "INSERT INTO t2 (\"col1\", name) VALUES (?, ?)",
+		"SELECT 1 -- limit 10\nFROM t":                             "SELECT ? -- limit 10\nFROM t",
+	} {
+		require.Equal(t, want, SanitizeStatement(sql))
+	}
+}
+
+func TestTruncateStatement(t *testing.T) {
+	require.Equal(t, "SELECT 1", truncateStatement("SELECT 1", 0))
+	require.Equal(t, "SELECT 1", truncateStatement("SELECT 1", 8))
+	require.Equal(t, "SELE...", truncateStatement("SELECT 1 FROM t", 7))
+	// Multi-byte runes are not split
+	require.Equal(t, "'...", truncateStatement("'ééé'", 5))
+}
+
+func TestStatementTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	tracer := newTracer(&connectionPoolOptions{
+		statementSanitizer: SanitizeStatement,
+		statementMaxLength: 32,
+	}, otelpgx.WithTracerProvider(tp))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+		SQL: "UPDATE users SET name = 'secret name' WHERE id = 42 AND deleted_at IS NULL",
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	var statement string
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == semconv.DBQueryTextKey {
+			statement = kv.Value.AsString()
+		}
+	}
+	require.Equal(t, "UPDATE users SET name = ? WHE...", statement)
+}
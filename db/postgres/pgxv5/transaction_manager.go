@@ -2,35 +2,157 @@ package pgxv5
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+)
+
+// SQLSTATE codes of errors after which a transaction can be retried
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
 )
 
 // TransactionManager manages database transactions with different isolation levels and access modes.
 type TransactionManager struct {
-	conn *Connection
+	conn       ConnectionAPI
+	retry      RetryPolicy
+	savepoints bool
+	metrics    *txMetrics
+}
+
+// TransactionManagerOption is a function that configures a transaction manager.
+type TransactionManagerOption func(m *transactionManagerOptions)
+
+type transactionManagerOptions struct {
+	retry         RetryPolicy
+	savepoints    bool
+	enableMetrics bool
+}
+
+// RetryPolicy controls retries of transactions failing with a serialization failure or a deadlock.
+// Retries wait with exponential backoff and jitter, so conflicting transactions don't collide again.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one; values below 2 disable retries
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry (default: 10ms)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay, which doubles after every retry (default: 1s)
+	MaxBackoff time.Duration
+}
+
+// backoff returns the delay before the retry following the given attempt, counted from 0,
+// randomized between half of the delay and the full delay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial, maxBackoff := p.InitialBackoff, p.MaxBackoff
+	if initial <= 0 {
+		initial = retryInitialBackoffDefault
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = retryMaxBackoffDefault
+	}
+
+	d := maxBackoff
+	if attempt < 32 && initial<<attempt > 0 && initial<<attempt < maxBackoff {
+		d = initial << attempt
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// WithTransactionMetrics turns on/off transaction duration, commit/rollback and retry metrics
+// exported through OpenTelemetry (default: off)
+func WithTransactionMetrics(enable bool) TransactionManagerOption {
+	return func(opts *transactionManagerOptions) {
+		opts.enableMetrics = enable
+	}
+}
+
+// WithSavepoints turns on/off running nested transactions in savepoints (default: off). With savepoints,
+// a nested call returning an error rolls back to its savepoint only, so the outer transaction can handle
+// the error and continue; without them, nested calls run directly in the outer transaction.
+// The isolation level and access mode of nested calls are those of the outer transaction.
+func WithSavepoints(enable bool) TransactionManagerOption {
+	return func(opts *transactionManagerOptions) {
+		opts.savepoints = enable
+	}
 }
 
-// NewTransactionManager creates a new transaction manager.
-func NewTransactionManager(conn *Connection) *TransactionManager {
-	return &TransactionManager{conn: conn}
+// WithRetryPolicy sets how transactions failing with a serialization failure or a deadlock are retried
+// (default: not retried). It is the only retry setting of the manager; RunSerializableWithRetry takes
+// the backoff from it. The function must be safe to run several times.
+func WithRetryPolicy(policy RetryPolicy) TransactionManagerOption {
+	return func(opts *transactionManagerOptions) {
+		opts.retry = policy
+	}
 }
 
-// runTransaction executes the given function within a transaction.
+// NewTransactionManager creates a new transaction manager for a *Connection, or a *ClusterConnection
+// running read-only transactions on replicas.
+func NewTransactionManager(conn ConnectionAPI, opts ...TransactionManagerOption) *TransactionManager {
+	options := &transactionManagerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	m := &TransactionManager{conn: conn, retry: options.retry, savepoints: options.savepoints}
+	if options.enableMetrics {
+		metrics, err := newTxMetrics()
+		if err != nil {
+			otel.Handle(fmt.Errorf("can't create transaction metrics: %w", err))
+		}
+		m.metrics = metrics
+	}
+	return m
+}
+
+// runTransaction executes the given function within a transaction, retrying it if configured.
 // If a transaction already exists in the context, it will be reused.
-func (m *TransactionManager) runTransaction(ctx context.Context, txOpts pgx.TxOptions, fn func(ctx context.Context) error) (err error) {
+func (m *TransactionManager) runTransaction(ctx context.Context, txOpts pgx.TxOptions, fn func(ctx context.Context) error) error {
+	return m.runTransactionWithRetry(ctx, txOpts, m.retry, fn)
+}
+
+// runTransactionWithRetry executes the given function within a transaction, retrying it according to policy.
+// Nested transactions are not retried on their own: the outermost transaction is.
+func (m *TransactionManager) runTransactionWithRetry(ctx context.Context, txOpts pgx.TxOptions, policy RetryPolicy, fn func(ctx context.Context) error) error {
 	// If it's nested Transaction, skip initiating a new one and return func(ctx context.Context) error
-	if _, ok := ctx.Value(txKey).(*Transaction); ok {
+	if tx, ok := ctx.Value(txKey).(*Transaction); ok {
+		if m.savepoints {
+			return m.runSavepoint(ctx, tx, fn)
+		}
 		return fn(ctx)
 	}
 
+	for attempt := 0; ; attempt++ {
+		err := m.runTransactionOnce(ctx, txOpts, fn)
+		if err == nil || attempt+1 >= policy.MaxAttempts || !isRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		m.metrics.retry(ctx, txOpts.IsoLevel)
+	}
+}
+
+// runTransactionOnce begins a transaction, executes the given function and commits or rolls back.
+func (m *TransactionManager) runTransactionOnce(ctx context.Context, txOpts pgx.TxOptions, fn func(ctx context.Context) error) (err error) {
 	var tx *Transaction
 
 	// Begin runTransaction
+	start := time.Now()
 	pgxTx, err := m.conn.BeginTx(ctx, txOpts)
 	if err != nil {
-		return fmt.Errorf("can't begin transaction: %v", err)
+		return fmt.Errorf("can't begin transaction: %w", err)
 	}
 
 	tx = &Transaction{Tx: pgxTx}
@@ -47,19 +169,22 @@ func (m *TransactionManager) runTransaction(ctx context.Context, txOpts pgx.TxOp
 
 		// if func(ctx context.Context) error didn't return error - commit
 		if err == nil {
-			// if commit returns error -> rollback
-			err = tx.Commit(ctx)
-			if err != nil {
-				err = fmt.Errorf("commit failed: %v", err)
+			// A failed commit closes the transaction, so there is nothing to roll back. Its error is kept,
+			// e.g. a serialization failure detected at commit, so the transaction can be retried.
+			if err = tx.Commit(ctx); err != nil {
+				err = fmt.Errorf("commit failed: %w", err)
+				m.metrics.record(ctx, txOpts.IsoLevel, txStatusRollback, time.Since(start))
+				return
 			}
+			m.metrics.record(ctx, txOpts.IsoLevel, txStatusCommit, time.Since(start))
+			return
 		}
 
 		// rollback on any error
-		if err != nil {
-			if errRollback := tx.Rollback(ctx); errRollback != nil {
-				err = fmt.Errorf("rollback failed: %v", errRollback)
-			}
+		if errRollback := tx.Rollback(ctx); errRollback != nil && !errors.Is(errRollback, pgx.ErrTxClosed) {
+			err = errors.Join(err, fmt.Errorf("rollback failed: %w", errRollback))
 		}
+		m.metrics.record(ctx, txOpts.IsoLevel, txStatusRollback, time.Since(start))
 	}()
 
 	// Execute the code inside the runTransaction.
@@ -72,6 +197,52 @@ func (m *TransactionManager) runTransaction(ctx context.Context, txOpts pgx.TxOp
 	return err
 }
 
+// runSavepoint executes the given function within a savepoint of tx, releasing it if the function
+// succeeds and rolling back to it otherwise. The error of the function is returned to the outer one.
+func (m *TransactionManager) runSavepoint(ctx context.Context, tx *Transaction, fn func(ctx context.Context) error) (err error) {
+	// Begin on a transaction creates a savepoint
+	pgxTx, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't create savepoint: %w", err)
+	}
+
+	savepoint := &Transaction{Tx: pgxTx}
+	ctx = context.WithValue(ctx, txKey, savepoint)
+
+	defer func() {
+		// recover from panic
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic recovered: %v", r)
+		}
+
+		// release the savepoint if func(ctx context.Context) error didn't return error
+		if err == nil {
+			err = savepoint.Commit(ctx)
+			if err != nil {
+				err = fmt.Errorf("release savepoint failed: %w", err)
+			}
+		}
+
+		// roll back to the savepoint on any error
+		if err != nil {
+			if errRollback := savepoint.Rollback(ctx); errRollback != nil {
+				err = fmt.Errorf("rollback to savepoint failed: %v: %w", errRollback, err)
+			}
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// isRetryable reports whether err is a serialization failure or a deadlock
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
 // GetQueryEngine returns the appropriate query engine based on the context.
 // If a transaction exists in the context, it returns the transaction.
 // Otherwise, it returns the connection.
@@ -105,6 +276,17 @@ func (m *TransactionManager) RunSerializable(ctx context.Context, f func(txCtx c
 	}, f)
 }
 
+// RunSerializableWithRetry executes the given function within a Serializable transaction, making up to
+// maxAttempts attempts while it fails with a serialization failure or a deadlock. The backoff between
+// attempts is taken from the retry policy of the manager. The function must be safe to run several times.
+func (m *TransactionManager) RunSerializableWithRetry(ctx context.Context, maxAttempts int, f func(txCtx context.Context) error) error {
+	policy := m.retry
+	policy.MaxAttempts = maxAttempts
+	return m.runTransactionWithRetry(ctx, pgx.TxOptions{
+		IsoLevel: pgx.Serializable,
+	}, policy, f)
+}
+
 // RunReadCommittedWithAccessMode executes the given function within a ReadCommitted transaction with specified access mode.
 func (m *TransactionManager) RunReadCommittedWithAccessMode(ctx context.Context, accessMode TxAccessMode, f func(txCtx context.Context) error) error {
 	return m.runTransaction(ctx, pgx.TxOptions{
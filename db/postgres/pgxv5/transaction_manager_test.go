@@ -2,10 +2,18 @@ package pgxv5
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	"github.com/rshelekhov/go-db/postgres/pgxv5/testutil"
 )
@@ -170,4 +178,269 @@ func TestTransactionManager(t *testing.T) {
 		})
 		require.NoError(t, err)
 	})
+
+	t.Run("Savepoints", func(t *testing.T) {
+		txManager := NewTransactionManager(conn, WithSavepoints(true))
+		err := txManager.RunReadCommitted(ctx, func(txCtx context.Context) error {
+			if _, err := txManager.GetQueryEngine(txCtx).Exec(txCtx, "INSERT INTO test (value) VALUES ($1)", "outer"); err != nil {
+				return err
+			}
+
+			innerErr := txManager.RunReadCommitted(txCtx, func(innerCtx context.Context) error {
+				if _, err := txManager.GetQueryEngine(innerCtx).Exec(innerCtx, "INSERT INTO test (value) VALUES ($1)", "inner"); err != nil {
+					return err
+				}
+				return errors.New("inner failed")
+			})
+			assert.EqualError(t, innerErr, "inner failed")
+			return nil
+		})
+		require.NoError(t, err)
+
+		var count int
+		err = conn.QueryRow(ctx, "SELECT count(*) FROM test WHERE value = $1", "outer").Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		err = conn.QueryRow(ctx, "SELECT count(*) FROM test WHERE value = $1", "inner").Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Serializable With Retry", func(t *testing.T) {
+		attempts := 0
+		err := txManager.RunSerializableWithRetry(ctx, 3, func(txCtx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+
+		attempts = 0
+		err = txManager.RunSerializableWithRetry(ctx, 2, func(txCtx context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "40P01"}
+		})
+		require.Error(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("Statement Timeout", func(t *testing.T) {
+		err := txManager.RunWithStatementTimeout(ctx, 50*time.Millisecond, func(txCtx context.Context) error {
+			_, err := txManager.GetQueryEngine(txCtx).Exec(txCtx, "SELECT pg_sleep(1)")
+			return err
+		})
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		assert.Equal(t, "57014", pgErr.Code)
+
+		err = txManager.RunReadCommitted(ctx, func(txCtx context.Context) error {
+			err := txManager.RunWithStatementTimeout(txCtx, time.Second, func(txCtx context.Context) error { return nil })
+			if err != nil {
+				return err
+			}
+
+			var timeout string
+			err = txManager.GetQueryEngine(txCtx).QueryRow(txCtx, "SHOW statement_timeout").Scan(&timeout)
+			assert.Equal(t, "0", timeout)
+			return err
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Repo", func(t *testing.T) {
+		type row struct {
+			_     struct{} `table:"test"`
+			ID    int64    `db:"id,pk,readonly"`
+			Value string   `db:"value"`
+		}
+		repo, err := NewRepo[row](txManager)
+		require.NoError(t, err)
+
+		err = txManager.RunReadCommitted(ctx, func(txCtx context.Context) error {
+			inserted, err := repo.Insert(txCtx, row{Value: "repo"})
+			require.NoError(t, err)
+			assert.Positive(t, inserted.ID)
+
+			inserted.Value = "repo updated"
+			updated, err := repo.Update(txCtx, inserted)
+			require.NoError(t, err)
+			assert.Equal(t, inserted, updated)
+
+			got, err := repo.GetByID(txCtx, inserted.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "repo updated", got.Value)
+
+			rows, err := repo.List(txCtx, 1, 0)
+			require.NoError(t, err)
+			assert.Len(t, rows, 1)
+
+			require.NoError(t, repo.Delete(txCtx, inserted.ID))
+			_, err = repo.GetByID(txCtx, inserted.ID)
+			assert.ErrorIs(t, err, pgx.ErrNoRows)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		for i := range 5 {
+			_, err := conn.Exec(ctx, "INSERT INTO test (value) VALUES ($1)", fmt.Sprintf("page%d", i))
+			require.NoError(t, err)
+		}
+		type row struct {
+			ID    int64
+			Value string
+		}
+		query := "SELECT id, value FROM test WHERE value LIKE $1"
+
+		keyset := NewKeyset(func(r row) []any { return []any{r.ID} }, Asc("id"))
+		var values []string
+		cursor := ""
+		for {
+			page, err := keyset.Query(ctx, conn, query, cursor, 2, "page%")
+			require.NoError(t, err)
+			for _, r := range page.Items {
+				values = append(values, r.Value)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		assert.Equal(t, []string{"page0", "page1", "page2", "page3", "page4"}, values)
+
+		page, err := QueryOffset[row](ctx, conn, query+" ORDER BY id DESC", "", 3, "page%")
+		require.NoError(t, err)
+		require.Len(t, page.Items, 3)
+		page, err = QueryOffset[row](ctx, conn, query+" ORDER BY id DESC", page.NextCursor, 3, "page%")
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Bulk Upsert", func(t *testing.T) {
+		var id int64
+		err := conn.QueryRow(ctx, "INSERT INTO test (value) VALUES ($1) RETURNING id", "bulk").Scan(&id)
+		require.NoError(t, err)
+
+		n, err := txManager.BulkUpsert(ctx, "test", []string{"id", "value"}, [][]any{
+			{id, "bulk updated"},
+			{id + 1000, "bulk inserted"},
+			{id + 1000, "bulk inserted last"},
+		}, []string{"id"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		var value string
+		require.NoError(t, conn.QueryRow(ctx, "SELECT value FROM test WHERE id = $1", id).Scan(&value))
+		assert.Equal(t, "bulk updated", value)
+		require.NoError(t, conn.QueryRow(ctx, "SELECT value FROM test WHERE id = $1", id+1000).Scan(&value))
+		assert.Equal(t, "bulk inserted last", value)
+	})
+
+	t.Run("Transaction Metrics", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		prev := otel.GetMeterProvider()
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		defer otel.SetMeterProvider(prev)
+
+		txManager := NewTransactionManager(conn, WithTransactionMetrics(true))
+		err := txManager.RunSerializable(ctx, func(txCtx context.Context) error { return nil })
+		require.NoError(t, err)
+		err = txManager.RunSerializable(ctx, func(txCtx context.Context) error { return errors.New("failed") })
+		require.Error(t, err)
+
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(ctx, &rm))
+
+		counts := make(map[string]int64)
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != "db_transactions_total" {
+					continue
+				}
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					iso, _ := dp.Attributes.Value("isolation_level")
+					status, _ := dp.Attributes.Value("status")
+					counts[iso.AsString()+"/"+status.AsString()] = dp.Value
+				}
+			}
+		}
+		assert.Equal(t, map[string]int64{"serializable/commit": 1, "serializable/rollback": 1}, counts)
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, isRetryable(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, isRetryable(fmt.Errorf("commit failed: %w", &pgconn.PgError{Code: "40P01"})))
+	assert.False(t, isRetryable(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, isRetryable(errors.New("failed")))
+}
+
+// fakeTx is a transaction whose commit fails with commitErr; like pgx, it is closed after the commit
+type fakeTx struct {
+	pgx.Tx
+	commitErr error
+	closed    bool
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.closed = true
+	return tx.commitErr
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	if tx.closed {
+		return pgx.ErrTxClosed
+	}
+	tx.closed = true
+	return nil
+}
+
+// fakeTxConn begins transactions whose commits fail with the errors of commitErrs in order
+type fakeTxConn struct {
+	ConnectionAPI
+	commitErrs []error
+	begins     int
+}
+
+func (c *fakeTxConn) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	if c.begins < len(c.commitErrs) {
+		tx.commitErr = c.commitErrs[c.begins]
+	}
+	c.begins++
+	return tx, nil
+}
+
+func TestTransactionRetryOnCommitFailure(t *testing.T) {
+	ctx := context.Background()
+	serializationFailure := &pgconn.PgError{Code: "40001"}
+
+	conn := &fakeTxConn{commitErrs: []error{serializationFailure, serializationFailure}}
+	txManager := NewTransactionManager(conn, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	err := txManager.RunSerializable(ctx, func(txCtx context.Context) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 3, conn.begins)
+
+	conn = &fakeTxConn{commitErrs: []error{serializationFailure, serializationFailure}}
+	err = NewTransactionManager(conn).RunSerializableWithRetry(ctx, 2, func(txCtx context.Context) error { return nil })
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	assert.Equal(t, "40001", pgErr.Code)
+	assert.Equal(t, 2, conn.begins)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt, want := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond} {
+		got := p.backoff(attempt)
+		assert.GreaterOrEqual(t, got, want/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, got, want, "attempt %d", attempt)
+	}
+	assert.LessOrEqual(t, p.backoff(100), 50*time.Millisecond)
+	assert.LessOrEqual(t, RetryPolicy{}.backoff(0), retryInitialBackoffDefault)
 }
@@ -2,18 +2,22 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Connection represents a connection to Redis.
 type Connection struct {
-	client *redis.Client
-	tracer trace.Tracer
+	client   *redis.Client
+	replicas *replicaSet
+	tracer   trace.Tracer
+	metrics  metric.Registration
 }
 
 // connectionOptions holds configuration for Redis connection
@@ -30,6 +34,12 @@ type connectionOptions struct {
 	writeTimeout  time.Duration
 	idleTimeout   time.Duration
 	enableTracing bool
+	enableMetrics bool
+	poolName      string
+
+	replicas             []string
+	maxReplicaLag        time.Duration
+	replicaCheckInterval time.Duration
 }
 
 // ConnectionOption is a function that configures connection options.
@@ -119,6 +129,43 @@ func WithTracing(enable bool) ConnectionOption {
 	}
 }
 
+// WithMetrics turns on/off pool metrics (connections, waits, timeouts) exported through OpenTelemetry
+func WithMetrics(enable bool) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.enableMetrics = enable
+	}
+}
+
+// WithPoolName sets the pool attribute of pool metrics (default: primary address).
+func WithPoolName(name string) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.poolName = name
+	}
+}
+
+// WithReplicas sets replica addresses ("host:port") serving read-only operations.
+// Writes, transactions and pipelines always use the primary.
+func WithReplicas(addrs ...string) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.replicas = addrs
+	}
+}
+
+// WithMaxReplicaLag sets how stale replica data may be. Replicas that haven't heard
+// from the primary for longer, or are disconnected from it, don't serve reads until they catch up.
+func WithMaxReplicaLag(lag time.Duration) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.maxReplicaLag = lag
+	}
+}
+
+// WithReplicaCheckInterval sets how often replica lag is checked.
+func WithReplicaCheckInterval(interval time.Duration) ConnectionOption {
+	return func(opts *connectionOptions) {
+		opts.replicaCheckInterval = interval
+	}
+}
+
 // NewConnection creates a new connection to Redis.
 func NewConnection(ctx context.Context, opts ...ConnectionOption) (ConnectionAPI, error) {
 	// Apply default options
@@ -134,6 +181,10 @@ func NewConnection(ctx context.Context, opts ...ConnectionOption) (ConnectionAPI
 		writeTimeout:  DefaultConnectionTimeout,
 		idleTimeout:   DefaultIdleTimeout,
 		enableTracing: true, // default is true
+		enableMetrics: true, // default is true
+
+		maxReplicaLag:        DefaultMaxReplicaLag,
+		replicaCheckInterval: DefaultReplicaCheckInterval,
 	}
 
 	for _, opt := range opts {
@@ -156,6 +207,9 @@ func NewConnection(ctx context.Context, opts ...ConnectionOption) (ConnectionAPI
 		ConnMaxIdleTime: connOpts.idleTimeout,
 	}
 
+	// Replicas share the settings of the primary, copied before the client fills in its defaults
+	replicaOpts := *clientOpts
+
 	client := redis.NewClient(clientOpts)
 
 	// Test connection
@@ -167,15 +221,44 @@ func NewConnection(ctx context.Context, opts ...ConnectionOption) (ConnectionAPI
 		client: client,
 	}
 
+	if len(connOpts.replicas) > 0 {
+		conn.replicas = newReplicaSet(ctx, connOpts.replicas, replicaOpts, connOpts.maxReplicaLag, connOpts.replicaCheckInterval)
+	}
+
 	if connOpts.enableTracing {
 		conn.tracer = otel.Tracer("redis")
 	}
 
+	if connOpts.enableMetrics {
+		clients := []*redis.Client{client}
+		if conn.replicas != nil {
+			clients = append(clients, conn.replicas.clients...)
+		}
+
+		poolName := connOpts.poolName
+		if poolName == "" {
+			poolName = clientOpts.Addr
+		}
+
+		registration, err := registerPoolMetrics(poolName, clients)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to register pool metrics: %w", err)
+		}
+		conn.metrics = registration
+	}
+
 	return conn, nil
 }
 
 // Close closes the connection to Redis.
 func (c *Connection) Close() error {
+	if c.metrics != nil {
+		_ = c.metrics.Unregister()
+	}
+	if c.replicas != nil {
+		return errors.Join(c.client.Close(), c.replicas.close())
+	}
 	return c.client.Close()
 }
 
@@ -184,6 +267,21 @@ func (c *Connection) Client() *redis.Client {
 	return c.client
 }
 
+// reader returns the client serving read-only operations: a healthy replica if any,
+// unless ctx requires reading from the primary.
+func (c *Connection) reader(ctx context.Context) *redis.Client {
+	if c.replicas == nil {
+		return c.client
+	}
+	if primary, _ := ctx.Value(primaryReadKey{}).(bool); primary {
+		return c.client
+	}
+	if replica := c.replicas.client(); replica != nil {
+		return replica
+	}
+	return c.client
+}
+
 // Ping checks the connection to the Redis server.
 func (c *Connection) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
@@ -195,7 +293,7 @@ func (c *Connection) Set(ctx context.Context, key string, value any, expiration
 }
 
 func (c *Connection) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+	return c.reader(ctx).Get(ctx, key).Result()
 }
 
 func (c *Connection) Del(ctx context.Context, keys ...string) (int64, error) {
@@ -203,7 +301,7 @@ func (c *Connection) Del(ctx context.Context, keys ...string) (int64, error) {
 }
 
 func (c *Connection) Exists(ctx context.Context, keys ...string) (int64, error) {
-	return c.client.Exists(ctx, keys...).Result()
+	return c.reader(ctx).Exists(ctx, keys...).Result()
 }
 
 func (c *Connection) Expire(ctx context.Context, key string, expiration time.Duration) error {
@@ -215,7 +313,7 @@ func (c *Connection) ExpireAt(ctx context.Context, key string, tm time.Time) err
 }
 
 func (c *Connection) TTL(ctx context.Context, key string) (time.Duration, error) {
-	return c.client.TTL(ctx, key).Result()
+	return c.reader(ctx).TTL(ctx, key).Result()
 }
 
 // Hash operations
@@ -224,11 +322,11 @@ func (c *Connection) HSet(ctx context.Context, key string, values ...any) error
 }
 
 func (c *Connection) HGet(ctx context.Context, key, field string) (string, error) {
-	return c.client.HGet(ctx, key, field).Result()
+	return c.reader(ctx).HGet(ctx, key, field).Result()
 }
 
 func (c *Connection) HGetAll(ctx context.Context, key string) (map[string]string, error) {
-	return c.client.HGetAll(ctx, key).Result()
+	return c.reader(ctx).HGetAll(ctx, key).Result()
 }
 
 func (c *Connection) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
@@ -236,19 +334,19 @@ func (c *Connection) HDel(ctx context.Context, key string, fields ...string) (in
 }
 
 func (c *Connection) HExists(ctx context.Context, key, field string) (bool, error) {
-	return c.client.HExists(ctx, key, field).Result()
+	return c.reader(ctx).HExists(ctx, key, field).Result()
 }
 
 func (c *Connection) HKeys(ctx context.Context, key string) ([]string, error) {
-	return c.client.HKeys(ctx, key).Result()
+	return c.reader(ctx).HKeys(ctx, key).Result()
 }
 
 func (c *Connection) HVals(ctx context.Context, key string) ([]string, error) {
-	return c.client.HVals(ctx, key).Result()
+	return c.reader(ctx).HVals(ctx, key).Result()
 }
 
 func (c *Connection) HLen(ctx context.Context, key string) (int64, error) {
-	return c.client.HLen(ctx, key).Result()
+	return c.reader(ctx).HLen(ctx, key).Result()
 }
 
 // List operations
@@ -269,11 +367,11 @@ func (c *Connection) RPop(ctx context.Context, key string) (string, error) {
 }
 
 func (c *Connection) LLen(ctx context.Context, key string) (int64, error) {
-	return c.client.LLen(ctx, key).Result()
+	return c.reader(ctx).LLen(ctx, key).Result()
 }
 
 func (c *Connection) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
-	return c.client.LRange(ctx, key, start, stop).Result()
+	return c.reader(ctx).LRange(ctx, key, start, stop).Result()
 }
 
 // Set operations
@@ -286,15 +384,15 @@ func (c *Connection) SRem(ctx context.Context, key string, members ...any) (int6
 }
 
 func (c *Connection) SMembers(ctx context.Context, key string) ([]string, error) {
-	return c.client.SMembers(ctx, key).Result()
+	return c.reader(ctx).SMembers(ctx, key).Result()
 }
 
 func (c *Connection) SIsMember(ctx context.Context, key string, member any) (bool, error) {
-	return c.client.SIsMember(ctx, key, member).Result()
+	return c.reader(ctx).SIsMember(ctx, key, member).Result()
 }
 
 func (c *Connection) SCard(ctx context.Context, key string) (int64, error) {
-	return c.client.SCard(ctx, key).Result()
+	return c.reader(ctx).SCard(ctx, key).Result()
 }
 
 // Sorted Set operations
@@ -307,36 +405,36 @@ func (c *Connection) ZRem(ctx context.Context, key string, members ...any) (int6
 }
 
 func (c *Connection) ZScore(ctx context.Context, key, member string) (float64, error) {
-	return c.client.ZScore(ctx, key, member).Result()
+	return c.reader(ctx).ZScore(ctx, key, member).Result()
 }
 
 func (c *Connection) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
-	return c.client.ZRange(ctx, key, start, stop).Result()
+	return c.reader(ctx).ZRange(ctx, key, start, stop).Result()
 }
 
 func (c *Connection) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
-	return c.client.ZRevRange(ctx, key, start, stop).Result()
+	return c.reader(ctx).ZRevRange(ctx, key, start, stop).Result()
 }
 
 func (c *Connection) ZCard(ctx context.Context, key string) (int64, error) {
-	return c.client.ZCard(ctx, key).Result()
+	return c.reader(ctx).ZCard(ctx, key).Result()
 }
 
 // Scan operations
 func (c *Connection) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
-	return c.client.Scan(ctx, cursor, match, count).Result()
+	return c.reader(ctx).Scan(ctx, cursor, match, count).Result()
 }
 
 func (c *Connection) HScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
-	return c.client.HScan(ctx, key, cursor, match, count).Result()
+	return c.reader(ctx).HScan(ctx, key, cursor, match, count).Result()
 }
 
 func (c *Connection) SScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
-	return c.client.SScan(ctx, key, cursor, match, count).Result()
+	return c.reader(ctx).SScan(ctx, key, cursor, match, count).Result()
 }
 
 func (c *Connection) ZScan(ctx context.Context, key string, cursor uint64, match string, count int64) ([]string, uint64, error) {
-	return c.client.ZScan(ctx, key, cursor, match, count).Result()
+	return c.reader(ctx).ZScan(ctx, key, cursor, match, count).Result()
 }
 
 // Pipeline operations
@@ -29,6 +29,7 @@ func TestRedisConnection(t *testing.T) {
 	)
 	require.NoError(t, err)
 	defer conn.Close()
+	defer testutil.AssertNoActiveConns(t, conn.Client())
 
 	t.Run("String operations", func(t *testing.T) {
 		// Test Set/Get
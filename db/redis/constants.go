@@ -15,4 +15,9 @@ const (
 	DefaultMinIdleConns = 5
 	// DefaultDB is the default database number
 	DefaultDB = 0
+	// DefaultMaxReplicaLag is the default staleness tolerance of replicas serving reads.
+	// Idle primaries ping replicas every 10 seconds by default, so it should not be lower than that.
+	DefaultMaxReplicaLag = 15 * time.Second
+	// DefaultReplicaCheckInterval is the default interval between replica health checks
+	DefaultReplicaCheckInterval = 5 * time.Second
 )
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of pool and transaction metrics
+const meterName = "github.com/rshelekhov/golib/db/redis"
+
+// registerPoolMetrics reports pool stats of the primary and replica clients through the global meter provider
+// on every collection:
+//   - db_pool_connections{state} - idle and in_use connections
+//   - db_pool_max_connections
+//   - db_pool_wait_count_total - times a connection was waited for
+//   - db_pool_wait_duration_seconds_total - time spent waiting for a connection
+//   - db_pool_timeouts_total - waits that timed out
+//
+// All metrics have db_system, pool and address attributes, the address telling the primary and replicas apart.
+func registerPoolMetrics(name string, clients []*redis.Client) (metric.Registration, error) {
+	meter := otel.GetMeterProvider().Meter(meterName)
+
+	connections, err1 := meter.Int64ObservableGauge("db_pool_connections",
+		metric.WithDescription("Number of connections in the pool by state."))
+	maxConnections, err2 := meter.Int64ObservableGauge("db_pool_max_connections",
+		metric.WithDescription("Maximum number of connections in the pool."))
+	waitCount, err3 := meter.Int64ObservableCounter("db_pool_wait_count_total",
+		metric.WithDescription("Total number of times a connection was waited for."))
+	waitDuration, err4 := meter.Float64ObservableCounter("db_pool_wait_duration_seconds_total",
+		metric.WithDescription("Total time spent waiting for a connection in seconds."), metric.WithUnit("s"))
+	timeouts, err5 := meter.Int64ObservableCounter("db_pool_timeouts_total",
+		metric.WithDescription("Total number of connection waits that timed out."))
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, c := range clients {
+			stats := c.PoolStats()
+			base := metric.WithAttributes(
+				attribute.String("db_system", "redis"),
+				attribute.String("pool", name),
+				attribute.String("address", c.Options().Addr),
+			)
+
+			idle := int64(stats.IdleConns)
+			o.ObserveInt64(connections, idle, base, metric.WithAttributes(attribute.String("state", "idle")))
+			o.ObserveInt64(connections, int64(stats.TotalConns)-idle, base, metric.WithAttributes(attribute.String("state", "in_use")))
+			o.ObserveInt64(maxConnections, int64(c.Options().PoolSize), base)
+			o.ObserveInt64(waitCount, int64(stats.WaitCount), base)
+			o.ObserveFloat64(waitDuration, time.Duration(stats.WaitDurationNs).Seconds(), base)
+			o.ObserveInt64(timeouts, int64(stats.Timeouts), base)
+		}
+		return nil
+	}, connections, maxConnections, waitCount, waitDuration, timeouts)
+}
+
+// Transaction modes and statuses
+const (
+	txModeTransaction = "transaction"
+	txModePipeline    = "pipeline"
+
+	txStatusCommit   = "commit"
+	txStatusRollback = "rollback"
+)
+
+// txMetrics records transaction and pipeline metrics through the global meter provider:
+//   - db_transaction_duration_seconds{mode,status} - time from the start of the function to EXEC or discard
+//   - db_transactions_total{mode,status} - status is commit (executed) or rollback (discarded or failed)
+//
+// All metrics have the db_system attribute, mode is transaction or pipeline. A nil *txMetrics records nothing.
+type txMetrics struct {
+	duration metric.Float64Histogram
+	total    metric.Int64Counter
+}
+
+func newTxMetrics() (*txMetrics, error) {
+	meter := otel.GetMeterProvider().Meter(meterName)
+
+	duration, err1 := meter.Float64Histogram("db_transaction_duration_seconds",
+		metric.WithDescription("Duration of database transactions in seconds."), metric.WithUnit("s"))
+	total, err2 := meter.Int64Counter("db_transactions_total",
+		metric.WithDescription("Total number of database transactions by status."))
+	if err := errors.Join(err1, err2); err != nil {
+		return nil, err
+	}
+
+	return &txMetrics{duration: duration, total: total}, nil
+}
+
+func (m *txMetrics) record(ctx context.Context, mode, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("db_system", "redis"),
+		attribute.String("mode", mode),
+		attribute.String("status", status),
+	)
+	m.duration.Record(ctx, duration.Seconds(), attrs)
+	m.total.Add(ctx, 1, attrs)
+}
@@ -0,0 +1,140 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type primaryReadKey struct{}
+
+// ReadFromPrimary returns a context whose read operations go to the primary,
+// e.g. to read a value right after writing it.
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+// replicaSet routes read-only operations to replicas that are connected to the primary
+// and not lagging behind it by more than maxLag.
+type replicaSet struct {
+	clients  []*redis.Client
+	maxLag   time.Duration
+	interval time.Duration
+
+	healthy atomic.Pointer[[]*redis.Client]
+	next    atomic.Uint64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newReplicaSet(ctx context.Context, addrs []string, base redis.Options, maxLag, interval time.Duration) *replicaSet {
+	s := &replicaSet{
+		maxLag:   maxLag,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	for _, addr := range addrs {
+		opts := base
+		opts.Addr = addr
+		s.clients = append(s.clients, redis.NewClient(&opts))
+	}
+
+	s.check(ctx)
+
+	s.wg.Add(1)
+	go s.run()
+
+	return s
+}
+
+// client returns the next healthy replica, or nil if there is none
+func (s *replicaSet) client() *redis.Client {
+	healthy := s.healthy.Load()
+	if healthy == nil || len(*healthy) == 0 {
+		return nil
+	}
+	n := s.next.Add(1)
+	return (*healthy)[n%uint64(len(*healthy))]
+}
+
+func (s *replicaSet) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+			s.check(ctx)
+			cancel()
+		}
+	}
+}
+
+// check refreshes the list of healthy replicas
+func (s *replicaSet) check(ctx context.Context) {
+	healthy := make([]*redis.Client, 0, len(s.clients))
+	for _, c := range s.clients {
+		if s.isFresh(ctx, c) {
+			healthy = append(healthy, c)
+		}
+	}
+	s.healthy.Store(&healthy)
+}
+
+// isFresh reports whether the replica is connected to the primary and received data from it within maxLag
+func (s *replicaSet) isFresh(ctx context.Context, c *redis.Client) bool {
+	info, err := c.Info(ctx, "replication").Result()
+	if err != nil {
+		return false
+	}
+
+	fields := parseInfo(info)
+	if fields["role"] == "master" {
+		// Promoted replica, its data is current
+		return true
+	}
+	if fields["master_link_status"] != "up" || fields["master_sync_in_progress"] == "1" {
+		return false
+	}
+
+	lastIO, err := strconv.Atoi(fields["master_last_io_seconds_ago"])
+	if err != nil || lastIO < 0 {
+		return false
+	}
+	return time.Duration(lastIO)*time.Second <= s.maxLag
+}
+
+func (s *replicaSet) close() error {
+	close(s.stop)
+	s.wg.Wait()
+
+	var errs []error
+	for _, c := range s.clients {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// parseInfo parses the key:value lines of an INFO reply
+func parseInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && !strings.HasPrefix(key, "#") {
+			fields[key] = value
+		}
+	}
+	return fields
+}
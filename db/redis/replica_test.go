@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInfo(t *testing.T) {
+	info := "# Replication\r\nrole:slave\r\nmaster_link_status:up\r\nmaster_last_io_seconds_ago:3\r\n"
+
+	fields := parseInfo(info)
+
+	assert.Equal(t, "slave", fields["role"])
+	assert.Equal(t, "up", fields["master_link_status"])
+	assert.Equal(t, "3", fields["master_last_io_seconds_ago"])
+	assert.NotContains(t, fields, "# Replication")
+}
+
+func TestConnectionReader(t *testing.T) {
+	ctx := context.Background()
+	primary := redis.NewClient(&redis.Options{Addr: "primary:6379"})
+	replica1 := redis.NewClient(&redis.Options{Addr: "replica-1:6379"})
+	replica2 := redis.NewClient(&redis.Options{Addr: "replica-2:6379"})
+
+	conn := &Connection{client: primary, replicas: &replicaSet{}}
+
+	t.Run("no healthy replicas", func(t *testing.T) {
+		conn.replicas.healthy.Store(&[]*redis.Client{})
+		assert.Same(t, primary, conn.reader(ctx))
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		conn.replicas.healthy.Store(&[]*redis.Client{replica1, replica2})
+		first, second := conn.reader(ctx), conn.reader(ctx)
+		assert.NotSame(t, first, second)
+		assert.NotSame(t, primary, first)
+		assert.NotSame(t, primary, second)
+	})
+
+	t.Run("read from primary", func(t *testing.T) {
+		assert.Same(t, primary, conn.reader(ReadFromPrimary(ctx)))
+	})
+}
@@ -0,0 +1,34 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultLeakTimeout is how long leak assertions wait for connections to be released
+const DefaultLeakTimeout = 5 * time.Second
+
+// AssertNoActiveConns fails the test if connections of the client pool are still
+// in use after DefaultLeakTimeout. A non-zero count usually means a pipeline,
+// pub/sub or Conn was not closed. Typically deferred right after the client is created:
+//
+//	defer testutil.AssertNoActiveConns(t, conn.Client())
+func AssertNoActiveConns(t testing.TB, client *redis.Client) {
+	t.Helper()
+
+	deadline := time.Now().Add(DefaultLeakTimeout)
+	for {
+		stats := client.PoolStats()
+		active := int(stats.TotalConns) - int(stats.IdleConns)
+		if active <= 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("connection leak: %d redis pool connections are still in use", active)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
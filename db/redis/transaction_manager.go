@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
 )
 
 type key string
@@ -16,7 +17,23 @@ const (
 
 // TransactionManager manages Redis transactions using pipelines.
 type TransactionManager struct {
-	conn *Connection
+	conn    *Connection
+	metrics *txMetrics
+}
+
+// TransactionManagerOption is a function that configures a transaction manager.
+type TransactionManagerOption func(opts *transactionManagerOptions)
+
+type transactionManagerOptions struct {
+	enableMetrics bool
+}
+
+// WithTransactionMetrics turns on/off transaction and pipeline duration and status metrics
+// exported through OpenTelemetry (default: off)
+func WithTransactionMetrics(enable bool) TransactionManagerOption {
+	return func(opts *transactionManagerOptions) {
+		opts.enableMetrics = enable
+	}
 }
 
 // Pipeline wraps Redis pipeline to implement QueryEngine interface.
@@ -25,8 +42,21 @@ type Pipeline struct {
 }
 
 // NewTransactionManager creates a new transaction manager.
-func NewTransactionManager(conn *Connection) *TransactionManager {
-	return &TransactionManager{conn: conn}
+func NewTransactionManager(conn *Connection, opts ...TransactionManagerOption) *TransactionManager {
+	options := &transactionManagerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	m := &TransactionManager{conn: conn}
+	if options.enableMetrics {
+		metrics, err := newTxMetrics()
+		if err != nil {
+			otel.Handle(fmt.Errorf("failed to create transaction metrics: %w", err))
+		}
+		m.metrics = metrics
+	}
+	return m
 }
 
 // GetQueryEngine returns the appropriate query engine based on the context.
@@ -47,6 +77,8 @@ func (m *TransactionManager) RunTransaction(ctx context.Context, fn func(ctx con
 		return fn(ctx)
 	}
 
+	start := time.Now()
+
 	// Create transaction pipeline
 	pipe := m.conn.client.TxPipeline()
 	pipeline := &Pipeline{pipe: pipe}
@@ -58,14 +90,17 @@ func (m *TransactionManager) RunTransaction(ctx context.Context, fn func(ctx con
 	if err := fn(ctx); err != nil {
 		// Discard the pipeline on error
 		pipe.Discard()
+		m.metrics.record(ctx, txModeTransaction, txStatusRollback, time.Since(start))
 		return fmt.Errorf("transaction execution failed: %w", err)
 	}
 
 	// Execute the pipeline
 	if _, err := pipe.Exec(ctx); err != nil {
+		m.metrics.record(ctx, txModeTransaction, txStatusRollback, time.Since(start))
 		return fmt.Errorf("transaction execution failed: %w", err)
 	}
 
+	m.metrics.record(ctx, txModeTransaction, txStatusCommit, time.Since(start))
 	return nil
 }
 
@@ -76,6 +111,8 @@ func (m *TransactionManager) RunPipeline(ctx context.Context, fn func(ctx contex
 		return fn(ctx)
 	}
 
+	start := time.Now()
+
 	// Create pipeline
 	pipe := m.conn.client.Pipeline()
 	pipeline := &Pipeline{pipe: pipe}
@@ -87,14 +124,17 @@ func (m *TransactionManager) RunPipeline(ctx context.Context, fn func(ctx contex
 	if err := fn(ctx); err != nil {
 		// Discard the pipeline on error
 		pipe.Discard()
+		m.metrics.record(ctx, txModePipeline, txStatusRollback, time.Since(start))
 		return fmt.Errorf("pipeline execution failed: %w", err)
 	}
 
 	// Execute the pipeline
 	if _, err := pipe.Exec(ctx); err != nil {
+		m.metrics.record(ctx, txModePipeline, txStatusRollback, time.Since(start))
 		return fmt.Errorf("pipeline execution failed: %w", err)
 	}
 
+	m.metrics.record(ctx, txModePipeline, txStatusCommit, time.Since(start))
 	return nil
 }
 
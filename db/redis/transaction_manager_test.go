@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTransactionMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	// Empty pipelines and discarded transactions don't reach the server
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	defer client.Close()
+	tm := NewTransactionManager(&Connection{client: client}, WithTransactionMetrics(true))
+
+	err := tm.RunPipeline(ctx, func(ctx context.Context) error { return nil })
+	require.NoError(t, err)
+	err = tm.RunTransaction(ctx, func(ctx context.Context) error { return errors.New("failed") })
+	require.Error(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	counts := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "db_transactions_total" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				mode, _ := dp.Attributes.Value("mode")
+				status, _ := dp.Attributes.Value("status")
+				counts[mode.AsString()+"/"+status.AsString()] = dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"pipeline/commit": 1, "transaction/rollback": 1}, counts)
+}
@@ -1,7 +1,9 @@
 package s3
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -392,3 +394,96 @@ func (c *Connection) ObjectExists(ctx context.Context, bucket, key string) (bool
 	}
 	return true, nil
 }
+
+// UploadLargeObject streams data of unknown size to S3 using a multipart upload,
+// buffering one part at a time. It returns the number of bytes uploaded.
+// partSize below MinPartSize is raised to DefaultPartSize. The upload is aborted on error.
+func (c *Connection) UploadLargeObject(ctx context.Context, bucket, key string, data io.Reader, partSize int64) (int64, error) {
+	if partSize < MinPartSize {
+		partSize = DefaultPartSize
+	}
+
+	upload, err := c.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		ACL:    aws.String(DefaultACL),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	abort := func(cause error) (int64, error) {
+		// Use a fresh context so that the upload is aborted even if ctx is canceled
+		_, abortErr := c.client.AbortMultipartUploadWithContext(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: upload.UploadId,
+		})
+		return 0, errors.Join(cause, abortErr)
+	}
+
+	var (
+		parts []*s3.CompletedPart
+		total int64
+		buf   = make([]byte, partSize)
+	)
+
+	for partNumber := int64(1); ; partNumber++ {
+		n, readErr := io.ReadFull(data, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return abort(fmt.Errorf("failed to read part %d: %w", partNumber, readErr))
+		}
+		// An empty object still needs one (empty) part
+		if n == 0 && len(parts) > 0 {
+			break
+		}
+
+		part, err := c.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(key),
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int64(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if err != nil {
+			return abort(fmt.Errorf("failed to upload part %d: %w", partNumber, err))
+		}
+
+		parts = append(parts, &s3.CompletedPart{ETag: part.ETag, PartNumber: aws.Int64(partNumber)})
+		total += int64(n)
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	_, err = c.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return abort(fmt.Errorf("failed to complete multipart upload: %w", err))
+	}
+
+	return total, nil
+}
+
+// GetObjectFrom downloads an object starting at the given byte offset,
+// which allows resuming interrupted downloads.
+func (c *Connection) GetObjectFrom(ctx context.Context, bucket, key string, offset int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	result, err := c.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return result.Body, nil
+}
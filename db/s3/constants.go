@@ -11,4 +11,8 @@ const (
 	DefaultACL = "private"
 	// DefaultMaxRetries is the default number of retries for S3 operations
 	DefaultMaxRetries = 3
+	// MinPartSize is the minimum size of every multipart upload part except the last one
+	MinPartSize = 5 * 1024 * 1024
+	// DefaultPartSize is the default part size for UploadLargeObject
+	DefaultPartSize = 16 * 1024 * 1024
 )
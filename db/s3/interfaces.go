@@ -83,6 +83,10 @@ type HelperAPI interface {
 	DeleteObjectSimple(ctx context.Context, bucket, key string) error
 	// ObjectExists checks if an object exists in S3.
 	ObjectExists(ctx context.Context, bucket, key string) (bool, error)
+	// UploadLargeObject streams data of unknown size to S3 using a multipart upload.
+	UploadLargeObject(ctx context.Context, bucket, key string, data io.Reader, partSize int64) (int64, error)
+	// GetObjectFrom downloads an object starting at the given byte offset.
+	GetObjectFrom(ctx context.Context, bucket, key string, offset int64) (io.ReadCloser, error)
 }
 
 // ConnectionAPI defines the interface for all S3 operations.
@@ -0,0 +1,192 @@
+package sqlotel
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// otelConn instruments a driver connection. Optional interfaces not implemented by the
+// wrapped connection fall back to what database/sql would do without them.
+type otelConn struct {
+	driver.Conn
+	inst *instrumenter
+}
+
+var (
+	_ driver.ConnPrepareContext = (*otelConn)(nil)
+	_ driver.ConnBeginTx        = (*otelConn)(nil)
+	_ driver.ExecerContext      = (*otelConn)(nil)
+	_ driver.QueryerContext     = (*otelConn)(nil)
+	_ driver.Pinger             = (*otelConn)(nil)
+	_ driver.SessionResetter    = (*otelConn)(nil)
+	_ driver.Validator          = (*otelConn)(nil)
+	_ driver.NamedValueChecker  = (*otelConn)(nil)
+)
+
+func (c *otelConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	c.inst.record(ctx, "PREPARE", query, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &otelStmt{Stmt: stmt, query: query, inst: c.inst}, nil
+}
+
+func (c *otelConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var tx driver.Tx
+	var err error
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else if opts.Isolation != 0 || opts.ReadOnly {
+		return nil, errors.New("sqlotel: driver does not support transaction options")
+	} else {
+		tx, err = c.Conn.Begin() //nolint:staticcheck // fallback for drivers without ConnBeginTx
+	}
+	c.inst.record(ctx, "BEGIN", "", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &otelTx{Tx: tx, ctx: ctx, inst: c.inst}, nil
+}
+
+func (c *otelConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	c.inst.record(ctx, "", query, start, err)
+	return res, err
+}
+
+func (c *otelConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	c.inst.record(ctx, "", query, start, err)
+	return rows, err
+}
+
+func (c *otelConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *otelConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *otelConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *otelConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// otelStmt instruments a prepared statement
+type otelStmt struct {
+	driver.Stmt
+	query string
+	inst  *instrumenter
+}
+
+var (
+	_ driver.StmtExecContext   = (*otelStmt)(nil)
+	_ driver.StmtQueryContext  = (*otelStmt)(nil)
+	_ driver.NamedValueChecker = (*otelStmt)(nil)
+)
+
+func (s *otelStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else if values, convErr := namedValuesToValues(args); convErr != nil {
+		return nil, convErr
+	} else {
+		res, err = s.Stmt.Exec(values) //nolint:staticcheck // fallback for drivers without StmtExecContext
+	}
+	s.inst.record(ctx, "", s.query, start, err)
+	return res, err
+}
+
+func (s *otelStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else if values, convErr := namedValuesToValues(args); convErr != nil {
+		return nil, convErr
+	} else {
+		rows, err = s.Stmt.Query(values) //nolint:staticcheck // fallback for drivers without StmtQueryContext
+	}
+	s.inst.record(ctx, "", s.query, start, err)
+	return rows, err
+}
+
+func (s *otelStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sqlotel: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// otelTx instruments commits and rollbacks
+type otelTx struct {
+	driver.Tx
+	ctx  context.Context
+	inst *instrumenter
+}
+
+func (t *otelTx) Commit() error {
+	start := time.Now()
+	err := t.Tx.Commit()
+	t.inst.record(t.ctx, "COMMIT", "", start, err)
+	return err
+}
+
+func (t *otelTx) Rollback() error {
+	start := time.Now()
+	err := t.Tx.Rollback()
+	t.inst.record(t.ctx, "ROLLBACK", "", start, err)
+	return err
+}
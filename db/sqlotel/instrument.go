@@ -0,0 +1,134 @@
+package sqlotel
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the instrumentation scope of spans and metrics
+const instrumentationName = "github.com/rshelekhov/golib/db/sqlotel"
+
+// operations are the statement keywords used as operation names, anything else is "OTHER"
+// to keep metric cardinality bounded
+var operations = map[string]bool{
+	"SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "REPLACE": true,
+	"WITH": true, "CALL": true, "EXEC": true, "EXECUTE": true, "EXPLAIN": true, "SHOW": true, "SET": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true,
+	"BEGIN": true, "COMMIT": true, "ROLLBACK": true, "PREPARE": true,
+}
+
+type instrumenter struct {
+	opts     *options
+	tracer   trace.Tracer
+	queries  metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+func newInstrumenter(o *options) *instrumenter {
+	i := &instrumenter{
+		opts:   o,
+		tracer: otel.Tracer(instrumentationName),
+	}
+
+	if o.metrics {
+		meter := otel.GetMeterProvider().Meter(instrumentationName)
+		var err1, err2 error
+		i.queries, err1 = meter.Int64Counter("db_client_queries_total",
+			metric.WithDescription("Total number of database queries."))
+		i.duration, err2 = meter.Float64Histogram("db_client_query_duration_seconds",
+			metric.WithDescription("Database query duration in seconds."), metric.WithUnit("s"))
+		if err := errors.Join(err1, err2); err != nil {
+			otel.Handle(err)
+			i.queries, i.duration = nil, nil
+		}
+	}
+
+	return i
+}
+
+// record reports an operation that started at start: a span, metrics and a slow query log.
+// The span is created after the fact, so operations the driver skips with driver.ErrSkip
+// (and database/sql retries another way) are not reported twice.
+func (i *instrumenter) record(ctx context.Context, op, query string, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	if op == "" {
+		op = operation(query)
+	}
+	end := time.Now()
+	elapsed := end.Sub(start)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", i.opts.system),
+		attribute.String("db.operation.name", op),
+	}
+	if i.opts.database != "" {
+		attrs = append(attrs, attribute.String("db.namespace", i.opts.database))
+	}
+	if i.opts.queryText && query != "" {
+		attrs = append(attrs, attribute.String("db.query.text", query))
+	}
+
+	ctx, span := i.tracer.Start(ctx, op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attrs...),
+	)
+	status := "ok"
+	if err != nil {
+		status = "error"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+
+	if i.queries != nil {
+		base := metric.WithAttributes(
+			attribute.String("db_system", i.opts.system),
+			attribute.String("operation", op),
+		)
+		i.queries.Add(ctx, 1, base, metric.WithAttributes(attribute.String("status", status)))
+		i.duration.Record(ctx, elapsed.Seconds(), base)
+	}
+
+	if i.opts.slowQuery > 0 && elapsed >= i.opts.slowQuery {
+		args := []any{"db_system", i.opts.system, "operation", op, "duration", elapsed}
+		if i.opts.queryText && query != "" {
+			args = append(args, "query", query)
+		}
+		if err != nil {
+			args = append(args, "error", err)
+		}
+		i.opts.logger.WarnContext(ctx, "slow query", args...)
+	}
+}
+
+// operation returns the statement keyword of the query, e.g. "SELECT"
+func operation(query string) string {
+	query = strings.TrimLeftFunc(query, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '('
+	})
+	end := strings.IndexFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end >= 0 {
+		query = query[:end]
+	}
+
+	op := strings.ToUpper(query)
+	if operations[op] {
+		return op
+	}
+	return "OTHER"
+}
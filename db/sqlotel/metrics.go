@@ -0,0 +1,51 @@
+package sqlotel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterPoolMetrics reports sql.DB pool stats through the global meter provider on every collection,
+// using the same metrics as the golib pgx, redis and mongo clients:
+//   - db_pool_connections{state} - idle and in_use connections
+//   - db_pool_max_connections
+//   - db_pool_wait_count_total - queries that waited for a connection
+//   - db_pool_wait_duration_seconds_total - time spent waiting for a connection
+//
+// All metrics have db_system and pool attributes. Unregister the returned registration when the db is closed.
+func RegisterPoolMetrics(db *sql.DB, system, pool string) (metric.Registration, error) {
+	meter := otel.GetMeterProvider().Meter(instrumentationName)
+
+	connections, err1 := meter.Int64ObservableGauge("db_pool_connections",
+		metric.WithDescription("Number of connections in the pool by state."))
+	maxConnections, err2 := meter.Int64ObservableGauge("db_pool_max_connections",
+		metric.WithDescription("Maximum number of connections in the pool."))
+	waitCount, err3 := meter.Int64ObservableCounter("db_pool_wait_count_total",
+		metric.WithDescription("Total number of times a connection was waited for."))
+	waitDuration, err4 := meter.Float64ObservableCounter("db_pool_wait_duration_seconds_total",
+		metric.WithDescription("Total time spent waiting for a connection in seconds."), metric.WithUnit("s"))
+	if err := errors.Join(err1, err2, err3, err4); err != nil {
+		return nil, err
+	}
+
+	base := metric.WithAttributes(
+		attribute.String("db_system", system),
+		attribute.String("pool", pool),
+	)
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		stats := db.Stats()
+
+		o.ObserveInt64(connections, int64(stats.Idle), base, metric.WithAttributes(attribute.String("state", "idle")))
+		o.ObserveInt64(connections, int64(stats.InUse), base, metric.WithAttributes(attribute.String("state", "in_use")))
+		o.ObserveInt64(maxConnections, int64(stats.MaxOpenConnections), base)
+		o.ObserveInt64(waitCount, stats.WaitCount, base)
+		o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), base)
+		return nil
+	}, connections, maxConnections, waitCount, waitDuration)
+}
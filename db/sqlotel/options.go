@@ -0,0 +1,76 @@
+package sqlotel
+
+import (
+	"log/slog"
+	"time"
+)
+
+// DefaultSlowQueryThreshold is the default duration above which queries are logged as slow
+const DefaultSlowQueryThreshold = 500 * time.Millisecond
+
+// options holds configuration for instrumented databases
+type options struct {
+	system    string
+	database  string
+	queryText bool
+	slowQuery time.Duration
+	logger    *slog.Logger
+	metrics   bool
+}
+
+// Option is a function that configures instrumentation options.
+type Option func(opts *options)
+
+// WithSystem sets the db_system attribute, e.g. "mysql" or "oracle" (default: the driver name).
+func WithSystem(system string) Option {
+	return func(opts *options) {
+		opts.system = system
+	}
+}
+
+// WithDatabaseName sets the db.namespace span attribute.
+func WithDatabaseName(name string) Option {
+	return func(opts *options) {
+		opts.database = name
+	}
+}
+
+// WithQueryText turns on/off recording query text in spans and slow query logs (default: on).
+// Query arguments are never recorded.
+func WithQueryText(enable bool) Option {
+	return func(opts *options) {
+		opts.queryText = enable
+	}
+}
+
+// WithSlowQueryThreshold sets the duration above which queries are logged (default: DefaultSlowQueryThreshold).
+// Zero disables slow query logging.
+func WithSlowQueryThreshold(threshold time.Duration) Option {
+	return func(opts *options) {
+		opts.slowQuery = threshold
+	}
+}
+
+// WithLogger sets the logger for slow queries (default: slog.Default()).
+func WithLogger(logger *slog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
+// WithMetrics turns on/off query metrics (default: on).
+func WithMetrics(enable bool) Option {
+	return func(opts *options) {
+		opts.metrics = enable
+	}
+}
+
+func defaultOptions(system string) *options {
+	return &options{
+		system:    system,
+		queryText: true,
+		slowQuery: DefaultSlowQueryThreshold,
+		logger:    slog.Default(),
+		metrics:   true,
+	}
+}
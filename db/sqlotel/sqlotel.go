@@ -0,0 +1,84 @@
+// Package sqlotel instruments database/sql drivers with OpenTelemetry spans, metrics
+// and slow query logging, for services on database/sql drivers such as MySQL or Oracle.
+package sqlotel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+)
+
+// Open opens a database with the registered driver, instrumenting every connection.
+// The db_system attribute defaults to the driver name.
+func Open(driverName, dsn string, opts ...Option) (*sql.DB, error) {
+	// database/sql exposes registered drivers only through a DB; it does not connect yet
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	_ = db.Close()
+
+	var connector driver.Connector = dsnConnector{dsn: dsn, driver: d}
+	if dc, ok := d.(driver.DriverContext); ok {
+		if connector, err = dc.OpenConnector(dsn); err != nil {
+			return nil, fmt.Errorf("failed to open %s connector: %w", driverName, err)
+		}
+	}
+
+	return sql.OpenDB(WrapConnector(connector, append([]Option{WithSystem(driverName)}, opts...)...)), nil
+}
+
+// OpenDB opens a database using the connector, instrumenting every connection
+func OpenDB(connector driver.Connector, opts ...Option) *sql.DB {
+	return sql.OpenDB(WrapConnector(connector, opts...))
+}
+
+// WrapConnector instruments the connections of the connector.
+// The db_system attribute defaults to "other_sql".
+func WrapConnector(connector driver.Connector, opts ...Option) driver.Connector {
+	o := defaultOptions("other_sql")
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return &otelConnector{Connector: connector, inst: newInstrumenter(o)}
+}
+
+// dsnConnector is a connector for drivers not implementing driver.DriverContext
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type otelConnector struct {
+	driver.Connector
+	inst *instrumenter
+}
+
+func (c *otelConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &otelConn{Conn: conn, inst: c.inst}, nil
+}
+
+// Close closes the wrapped connector if it holds resources, called by sql.DB.Close
+func (c *otelConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
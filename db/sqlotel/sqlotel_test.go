@@ -0,0 +1,167 @@
+package sqlotel
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeDriver implements only the mandatory driver interfaces, exercising the fallbacks
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if strings.Contains(s.query, "fail") {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "pg_sleep") {
+		time.Sleep(20 * time.Millisecond)
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{ done bool }
+
+func (*fakeRows) Columns() []string { return []string{"n"} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func init() {
+	sql.Register("sqlotel-fake", fakeDriver{})
+}
+
+func TestInstrumentation(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	prevTracer := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	defer otel.SetTracerProvider(prevTracer)
+
+	reader := sdkmetric.NewManualReader()
+	prevMeter := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prevMeter)
+
+	var logs bytes.Buffer
+	db, err := Open("sqlotel-fake", "", WithSystem("fake"),
+		WithSlowQueryThreshold(10*time.Millisecond),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	var n int
+	if err := db.QueryRowContext(ctx, "SELECT pg_sleep(0.02)").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO t VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.ExecContext(ctx, "UPDATE fail"); err == nil {
+		t.Fatal("expected exec error")
+	}
+
+	var names []string
+	for _, s := range spans.Ended() {
+		names = append(names, s.Name())
+	}
+	want := "PREPARE SELECT BEGIN PREPARE INSERT COMMIT PREPARE UPDATE"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("spans = %q, want %q", got, want)
+	}
+	if last := spans.Ended()[len(spans.Ended())-1]; last.Status().Description != "syntax error" {
+		t.Errorf("expected error status on UPDATE span, got %+v", last.Status())
+	}
+
+	if !strings.Contains(logs.String(), "slow query") || !strings.Contains(logs.String(), "operation=SELECT") {
+		t.Errorf("expected slow SELECT to be logged, got %q", logs.String())
+	}
+	if strings.Contains(logs.String(), "INSERT") {
+		t.Errorf("expected fast queries not to be logged, got %q", logs.String())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "db_client_queries_total" {
+			continue
+		}
+		var total int64
+		for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+			total += dp.Value
+		}
+		if total != 8 {
+			t.Errorf("expected 8 recorded operations, got %d", total)
+		}
+		return
+	}
+	t.Error("db_client_queries_total not recorded")
+}
+
+func TestOperation(t *testing.T) {
+	for query, want := range map[string]string{
+		"select 1":                    "SELECT",
+		"  (SELECT 1) UNION SELECT 2": "SELECT",
+		"WITH x AS (SELECT 1) SELECT": "WITH",
+		"VACUUM users":                "OTHER",
+		"":                            "OTHER",
+	} {
+		if got := operation(query); got != want {
+			t.Errorf("operation(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
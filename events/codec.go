@@ -0,0 +1,216 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Codec encodes envelopes
+type Codec interface {
+	// ContentType is the content type of encoded envelopes
+	ContentType() string
+	Marshal(e *Envelope) ([]byte, error)
+	Unmarshal(data []byte, e *Envelope) error
+}
+
+var (
+	// JSON encodes envelopes as JSON objects. JSON payloads are embedded as "data",
+	// other payloads are base64-encoded as "data_base64".
+	JSON Codec = jsonCodec{}
+	// Protobuf encodes envelopes as protobuf messages. See protoCodec for the message definition.
+	Protobuf Codec = protoCodec{}
+)
+
+type jsonEnvelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	TraceState      string          `json:"tracestate,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      []byte          `json:"data_base64,omitempty"`
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/cloudevents+json"
+}
+
+func (jsonCodec) Marshal(e *Envelope) ([]byte, error) {
+	out := jsonEnvelope{
+		SpecVersion:     e.SpecVersion,
+		ID:              e.ID,
+		Type:            e.Type,
+		Source:          e.Source,
+		Time:            e.Time,
+		DataContentType: e.DataContentType,
+		DataSchema:      e.DataSchema,
+		TraceParent:     e.TraceParent,
+		TraceState:      e.TraceState,
+	}
+	switch {
+	case len(e.Data) == 0:
+	case e.DataContentType == ContentTypeJSON:
+		if !json.Valid(e.Data) {
+			return nil, errors.New("envelope data is not valid JSON")
+		}
+		out.Data = e.Data
+	default:
+		out.DataBase64 = e.Data
+	}
+	return json.Marshal(out)
+}
+
+func (jsonCodec) Unmarshal(data []byte, e *Envelope) error {
+	var in jsonEnvelope
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	*e = Envelope{
+		SpecVersion:     in.SpecVersion,
+		ID:              in.ID,
+		Type:            in.Type,
+		Source:          in.Source,
+		Time:            in.Time,
+		DataContentType: in.DataContentType,
+		DataSchema:      in.DataSchema,
+		TraceParent:     in.TraceParent,
+		TraceState:      in.TraceState,
+		Data:            in.DataBase64,
+	}
+	if in.Data != nil {
+		e.Data = in.Data
+	}
+	return nil
+}
+
+// protoCodec encodes envelopes as the following message:
+//
+//	message Envelope {
+//	  string spec_version = 1;
+//	  string id = 2;
+//	  string type = 3;
+//	  string source = 4;
+//	  google.protobuf.Timestamp time = 5;
+//	  string data_content_type = 6;
+//	  string data_schema = 7;
+//	  string traceparent = 8;
+//	  string tracestate = 9;
+//	  bytes data = 10;
+//	}
+type protoCodec struct{}
+
+const (
+	fieldSpecVersion protowire.Number = iota + 1
+	fieldID
+	fieldType
+	fieldSource
+	fieldTime
+	fieldDataContentType
+	fieldDataSchema
+	fieldTraceParent
+	fieldTraceState
+	fieldData
+)
+
+func (protoCodec) ContentType() string {
+	return "application/cloudevents+protobuf"
+}
+
+func (protoCodec) Marshal(e *Envelope) ([]byte, error) {
+	var b []byte
+	appendString := func(num protowire.Number, v string) {
+		if v != "" {
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendString(b, v)
+		}
+	}
+
+	appendString(fieldSpecVersion, e.SpecVersion)
+	appendString(fieldID, e.ID)
+	appendString(fieldType, e.Type)
+	appendString(fieldSource, e.Source)
+	if !e.Time.IsZero() {
+		ts, err := proto.Marshal(timestamppb.New(e.Time))
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, fieldTime, protowire.BytesType)
+		b = protowire.AppendBytes(b, ts)
+	}
+	appendString(fieldDataContentType, e.DataContentType)
+	appendString(fieldDataSchema, e.DataSchema)
+	appendString(fieldTraceParent, e.TraceParent)
+	appendString(fieldTraceState, e.TraceState)
+	if len(e.Data) > 0 {
+		b = protowire.AppendTag(b, fieldData, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.Data)
+	}
+	return b, nil
+}
+
+func (protoCodec) Unmarshal(data []byte, e *Envelope) error {
+	*e = Envelope{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("failed to decode envelope: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType || num < fieldSpecVersion || num > fieldData {
+			// Skip unknown fields for forward compatibility
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return fmt.Errorf("failed to decode envelope: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return fmt.Errorf("failed to decode envelope: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch num {
+		case fieldSpecVersion:
+			e.SpecVersion = string(v)
+		case fieldID:
+			e.ID = string(v)
+		case fieldType:
+			e.Type = string(v)
+		case fieldSource:
+			e.Source = string(v)
+		case fieldTime:
+			var ts timestamppb.Timestamp
+			if err := proto.Unmarshal(v, &ts); err != nil {
+				return fmt.Errorf("failed to decode envelope time: %w", err)
+			}
+			e.Time = ts.AsTime()
+		case fieldDataContentType:
+			e.DataContentType = string(v)
+		case fieldDataSchema:
+			e.DataSchema = string(v)
+		case fieldTraceParent:
+			e.TraceParent = string(v)
+		case fieldTraceState:
+			e.TraceState = string(v)
+		case fieldData:
+			e.Data = append([]byte(nil), v...)
+		}
+	}
+	return nil
+}
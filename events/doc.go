@@ -0,0 +1,13 @@
+// Package events defines a versioned event envelope for messaging.
+//
+// An Envelope carries the event id, type, source and time, the W3C trace
+// context of the producer and the encoded payload. Its attributes follow the
+// CloudEvents naming. New and Decode convert typed payloads: protobuf messages
+// are encoded as protobuf, everything else as JSON. Envelopes are encoded with
+// the JSON or Protobuf codec.
+//
+// For Kafka, Serializer puts the payload into the record value in the Confluent
+// schema registry wire format and the envelope attributes into record headers,
+// validating the envelope and its payload against the registered schema at
+// publish time.
+package events
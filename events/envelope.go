@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/protobuf/proto"
+)
+
+// SpecVersion is the version of the envelope format
+const SpecVersion = "1.0"
+
+// Payload content types
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/protobuf"
+)
+
+// ErrInvalidEnvelope is returned when an envelope misses required attributes
+var ErrInvalidEnvelope = errors.New("invalid event envelope")
+
+// Envelope is an event with its metadata
+type Envelope struct {
+	SpecVersion string
+	ID          string
+	// Type identifies the event, e.g. "com.example.order.created.v1"
+	Type string
+	// Source identifies the producer, e.g. "/orders-service"
+	Source string
+	Time   time.Time
+	// DataContentType is the content type of Data
+	DataContentType string
+	// DataSchema identifies the payload schema, e.g. a URI or a schema registry subject
+	DataSchema string
+	// TraceParent and TraceState carry the W3C trace context of the producer
+	TraceParent string
+	TraceState  string
+	Data        []byte
+}
+
+// Option configures an envelope created by New
+type Option func(e *Envelope)
+
+// WithID sets the event id (default: a random UUID)
+func WithID(id string) Option {
+	return func(e *Envelope) {
+		e.ID = id
+	}
+}
+
+// WithTime sets the event time (default: now)
+func WithTime(t time.Time) Option {
+	return func(e *Envelope) {
+		e.Time = t
+	}
+}
+
+// WithDataSchema sets the payload schema reference
+func WithDataSchema(schema string) Option {
+	return func(e *Envelope) {
+		e.DataSchema = schema
+	}
+}
+
+// New creates an envelope with the payload encoded as protobuf if it is a proto.Message
+// and as JSON otherwise. The trace context of ctx is propagated with the event.
+func New[T any](ctx context.Context, eventType, source string, payload T, opts ...Option) (*Envelope, error) {
+	e := &Envelope{
+		SpecVersion: SpecVersion,
+		ID:          uuid.NewString(),
+		Type:        eventType,
+		Source:      source,
+		Time:        time.Now().UTC(),
+	}
+
+	var err error
+	if m, ok := any(payload).(proto.Message); ok {
+		e.DataContentType = ContentTypeProtobuf
+		e.Data, err = proto.Marshal(m)
+	} else {
+		e.DataContentType = ContentTypeJSON
+		e.Data, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", eventType, err)
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	e.TraceParent = carrier.Get("traceparent")
+	e.TraceState = carrier.Get("tracestate")
+
+	return e, nil
+}
+
+// Decode decodes the payload of the envelope according to its content type.
+// T must be a pointer to a message type for protobuf payloads.
+func Decode[T any](e *Envelope) (T, error) {
+	var payload T
+
+	switch e.DataContentType {
+	case ContentTypeProtobuf:
+		typ := reflect.TypeFor[T]()
+		if typ.Kind() != reflect.Pointer {
+			return payload, fmt.Errorf("protobuf payload of %s requires a message pointer, got %s", e.Type, typ)
+		}
+		m, ok := reflect.New(typ.Elem()).Interface().(proto.Message)
+		if !ok {
+			return payload, fmt.Errorf("protobuf payload of %s requires a proto.Message, got %s", e.Type, typ)
+		}
+		if err := proto.Unmarshal(e.Data, m); err != nil {
+			return payload, fmt.Errorf("failed to decode %s payload: %w", e.Type, err)
+		}
+		return m.(T), nil
+	case ContentTypeJSON, "":
+		if err := json.Unmarshal(e.Data, &payload); err != nil {
+			return payload, fmt.Errorf("failed to decode %s payload: %w", e.Type, err)
+		}
+		return payload, nil
+	default:
+		return payload, fmt.Errorf("unsupported content type %q of %s", e.DataContentType, e.Type)
+	}
+}
+
+// Validate checks that the envelope has all required attributes
+func (e *Envelope) Validate() error {
+	var missing []string
+	for _, attr := range []struct{ name, value string }{
+		{"specversion", e.SpecVersion},
+		{"id", e.ID},
+		{"type", e.Type},
+		{"source", e.Source},
+	} {
+		if attr.value == "" {
+			missing = append(missing, attr.name)
+		}
+	}
+	if e.Time.IsZero() {
+		missing = append(missing, "time")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %v", ErrInvalidEnvelope, missing)
+	}
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("%w: unsupported specversion %q", ErrInvalidEnvelope, e.SpecVersion)
+	}
+	return nil
+}
+
+// Context returns ctx with the producer's trace context as the remote span context,
+// so that consumer spans join the producer's trace
+func (e *Envelope) Context(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	if e.TraceParent != "" {
+		carrier.Set("traceparent", e.TraceParent)
+	}
+	if e.TraceState != "" {
+		carrier.Set("tracestate", e.TraceState)
+	}
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
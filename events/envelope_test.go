@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type orderCreated struct {
+	OrderID string `json:"order_id"`
+	Amount  int    `json:"amount"`
+}
+
+func TestEnvelope(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	e, err := New(ctx, "order.created.v1", "/orders", orderCreated{OrderID: "42", Amount: 100})
+	require.NoError(t, err)
+	require.NoError(t, e.Validate())
+	require.Equal(t, ContentTypeJSON, e.DataContentType)
+	require.NotEmpty(t, e.ID)
+
+	remote := trace.SpanContextFromContext(e.Context(context.Background()))
+	require.Equal(t, sc.TraceID(), remote.TraceID())
+	require.True(t, remote.IsRemote())
+
+	payload, err := Decode[orderCreated](e)
+	require.NoError(t, err)
+	require.Equal(t, orderCreated{OrderID: "42", Amount: 100}, payload)
+
+	protoEvent, err := New(ctx, "order.note.v1", "/orders", wrapperspb.String("fragile"),
+		WithID("fixed"), WithTime(time.Unix(1700000000, 5).UTC()), WithDataSchema("orders-value"))
+	require.NoError(t, err)
+	require.Equal(t, ContentTypeProtobuf, protoEvent.DataContentType)
+
+	note, err := Decode[*wrapperspb.StringValue](protoEvent)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(wrapperspb.String("fragile"), note))
+
+	_, err = Decode[wrapperspb.StringValue](protoEvent)
+	require.Error(t, err)
+
+	for _, codec := range []Codec{JSON, Protobuf} {
+		for _, in := range []*Envelope{e, protoEvent} {
+			data, err := codec.Marshal(in)
+			require.NoError(t, err)
+
+			var out Envelope
+			require.NoError(t, codec.Unmarshal(data, &out))
+			require.True(t, in.Time.Equal(out.Time), codec.ContentType())
+			out.Time = in.Time
+			require.Equal(t, *in, out, codec.ContentType())
+		}
+	}
+
+	require.ErrorIs(t, (&Envelope{SpecVersion: SpecVersion}).Validate(), ErrInvalidEnvelope)
+}
@@ -0,0 +1,244 @@
+package events
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Header is a Kafka record header
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+const (
+	// headerPrefix prefixes envelope attributes in Kafka headers
+	headerPrefix      = "ce_"
+	headerContentType = "content-type"
+
+	// wireMagic starts payloads in the schema registry wire format:
+	// magic byte, 4-byte schema id, protobuf message indexes (protobuf only), payload
+	wireMagic  byte = 0
+	wireHeader      = 5
+)
+
+// SubjectStrategy returns the schema registry subject of the event payload
+type SubjectStrategy func(topic string, e *Envelope) string
+
+// TopicNameStrategy uses "<topic>-value", the Confluent default
+func TopicNameStrategy(topic string, _ *Envelope) string {
+	return topic + "-value"
+}
+
+// RecordNameStrategy uses the event type, for topics carrying several event types
+func RecordNameStrategy(_ string, e *Envelope) string {
+	return e.Type
+}
+
+// PayloadValidator validates the payload of an event against its registered schema,
+// e.g. with a JSON schema library
+type PayloadValidator func(schema Schema, e *Envelope) error
+
+// Serializer converts envelopes to Kafka records: the payload in the schema registry wire format
+// as the record value and the envelope attributes as headers.
+type Serializer struct {
+	registry   *SchemaRegistry
+	subject    SubjectStrategy
+	validators []PayloadValidator
+}
+
+// SerializerOption configures a Serializer
+type SerializerOption func(s *Serializer)
+
+// WithSubjectStrategy sets the subject strategy (default: TopicNameStrategy)
+func WithSubjectStrategy(strategy SubjectStrategy) SerializerOption {
+	return func(s *Serializer) {
+		s.subject = strategy
+	}
+}
+
+// WithPayloadValidator adds a payload validator run by Serialize
+func WithPayloadValidator(v PayloadValidator) SerializerOption {
+	return func(s *Serializer) {
+		s.validators = append(s.validators, v)
+	}
+}
+
+// NewSerializer creates a Serializer resolving payload schemas in the registry
+func NewSerializer(registry *SchemaRegistry, opts ...SerializerOption) *Serializer {
+	s := &Serializer{
+		registry: registry,
+		subject:  TopicNameStrategy,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Serialize validates the envelope and its payload against the latest schema of its subject
+// and returns the record value and headers. Protobuf payloads must be the first message of their schema.
+func (s *Serializer) Serialize(ctx context.Context, topic string, e *Envelope) ([]byte, []Header, error) {
+	if err := e.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	subject := s.subject(topic, e)
+	schema, err := s.registry.Latest(ctx, subject)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkPayload(schema, e); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s payload for %s: %w", e.Type, subject, err)
+	}
+	for _, validate := range s.validators {
+		if err := validate(schema, e); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s payload for %s: %w", e.Type, subject, err)
+		}
+	}
+
+	value := make([]byte, wireHeader, wireHeader+1+len(e.Data))
+	value[0] = wireMagic
+	binary.BigEndian.PutUint32(value[1:], uint32(schema.ID))
+	if schema.Type == SchemaTypeProtobuf {
+		// Message indexes [0] of the first message are encoded as a single zero byte
+		value = append(value, 0)
+	}
+	value = append(value, e.Data...)
+
+	return value, headers(e), nil
+}
+
+// Deserialize restores the envelope from a record value and headers, resolving the payload schema by id
+func (s *Serializer) Deserialize(ctx context.Context, value []byte, headers []Header) (*Envelope, error) {
+	e, err := fromHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(value) < wireHeader || value[0] != wireMagic {
+		return nil, errors.New("record value is not in the schema registry wire format")
+	}
+	schema, err := s.registry.SchemaByID(ctx, int(binary.BigEndian.Uint32(value[1:wireHeader])))
+	if err != nil {
+		return nil, err
+	}
+
+	data := value[wireHeader:]
+	if schema.Type == SchemaTypeProtobuf {
+		if data, err = skipMessageIndexes(data); err != nil {
+			return nil, err
+		}
+	}
+	e.Data = data
+
+	if e.DataContentType == "" {
+		e.DataContentType = contentType(schema.Type)
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// checkPayload checks that the payload encoding matches the schema type
+func checkPayload(schema Schema, e *Envelope) error {
+	if want := contentType(schema.Type); want != "" && e.DataContentType != want {
+		return fmt.Errorf("content type %q does not match %s schema %d", e.DataContentType, schema.Type, schema.ID)
+	}
+	if e.DataContentType == ContentTypeJSON && !json.Valid(e.Data) {
+		return errors.New("payload is not valid JSON")
+	}
+	return nil
+}
+
+func contentType(schemaType SchemaType) string {
+	switch schemaType {
+	case SchemaTypeJSON:
+		return ContentTypeJSON
+	case SchemaTypeProtobuf:
+		return ContentTypeProtobuf
+	default:
+		return ""
+	}
+}
+
+func skipMessageIndexes(data []byte) ([]byte, error) {
+	count, n := protowire.ConsumeVarint(data)
+	if n < 0 {
+		return nil, fmt.Errorf("invalid protobuf message indexes: %w", protowire.ParseError(n))
+	}
+	data = data[n:]
+	for range protowire.DecodeZigZag(count) {
+		_, n := protowire.ConsumeVarint(data)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid protobuf message indexes: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+	}
+	return data, nil
+}
+
+func headers(e *Envelope) []Header {
+	attrs := []struct{ key, value string }{
+		{"specversion", e.SpecVersion},
+		{"id", e.ID},
+		{"type", e.Type},
+		{"source", e.Source},
+		{"time", e.Time.Format(time.RFC3339Nano)},
+		{"dataschema", e.DataSchema},
+		{"traceparent", e.TraceParent},
+		{"tracestate", e.TraceState},
+	}
+
+	h := make([]Header, 0, len(attrs)+1)
+	for _, attr := range attrs {
+		if attr.value != "" {
+			h = append(h, Header{Key: headerPrefix + attr.key, Value: []byte(attr.value)})
+		}
+	}
+	if e.DataContentType != "" {
+		h = append(h, Header{Key: headerContentType, Value: []byte(e.DataContentType)})
+	}
+	return h
+}
+
+func fromHeaders(headers []Header) (*Envelope, error) {
+	e := &Envelope{}
+	for _, h := range headers {
+		v := string(h.Value)
+		switch h.Key {
+		case headerPrefix + "specversion":
+			e.SpecVersion = v
+		case headerPrefix + "id":
+			e.ID = v
+		case headerPrefix + "type":
+			e.Type = v
+		case headerPrefix + "source":
+			e.Source = v
+		case headerPrefix + "time":
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid time: %w", ErrInvalidEnvelope, err)
+			}
+			e.Time = t
+		case headerPrefix + "dataschema":
+			e.DataSchema = v
+		case headerPrefix + "traceparent":
+			e.TraceParent = v
+		case headerPrefix + "tracestate":
+			e.TraceState = v
+		case headerContentType:
+			e.DataContentType = v
+		}
+	}
+	return e, nil
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializer(t *testing.T) {
+	var lookups atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", registryContentType)
+		switch r.URL.Path {
+		case "/subjects/orders-value/versions/latest":
+			lookups.Add(1)
+			_ = json.NewEncoder(w).Encode(registrySchema{ID: 7, Subject: "orders-value", Version: 1, SchemaType: SchemaTypeJSON, Schema: `{"type":"object"}`})
+		case "/subjects/orders-value/versions":
+			_ = json.NewEncoder(w).Encode(registrySchema{ID: 7})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(RegistryError{Code: 40401, Message: "Subject not found"})
+		}
+	}))
+	defer registry.Close()
+
+	ctx := context.Background()
+	client := NewSchemaRegistry(registry.URL)
+
+	id, err := client.Register(ctx, "orders-value", SchemaTypeJSON, `{"type":"object"}`)
+	require.NoError(t, err)
+	require.Equal(t, 7, id)
+
+	errRejected := errors.New("rejected")
+	s := NewSerializer(client, WithPayloadValidator(func(schema Schema, e *Envelope) error {
+		if string(e.Data) == `{"order_id":"","amount":0}` {
+			return errRejected
+		}
+		return nil
+	}))
+
+	e, err := New(ctx, "order.created.v1", "/orders", orderCreated{OrderID: "42", Amount: 100})
+	require.NoError(t, err)
+
+	value, headers, err := s.Serialize(ctx, "orders", e)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 7}, value[:wireHeader])
+
+	out, err := s.Deserialize(ctx, value, headers)
+	require.NoError(t, err)
+	require.Equal(t, e.ID, out.ID)
+	require.Equal(t, e.TraceParent, out.TraceParent)
+	require.Equal(t, e.Data, out.Data)
+	require.True(t, e.Time.Equal(out.Time))
+
+	empty, err := New(ctx, "order.created.v1", "/orders", orderCreated{})
+	require.NoError(t, err)
+	_, _, err = s.Serialize(ctx, "orders", empty)
+	require.ErrorIs(t, err, errRejected)
+
+	e.DataContentType = ContentTypeProtobuf
+	_, _, err = s.Serialize(ctx, "orders", e)
+	require.ErrorContains(t, err, "does not match")
+
+	_, _, err = s.Serialize(ctx, "payments", e)
+	var regErr *RegistryError
+	require.ErrorAs(t, err, &regErr)
+	require.Equal(t, 40401, regErr.Code)
+
+	require.EqualValues(t, 1, lookups.Load(), "latest schema is cached")
+}
@@ -0,0 +1,225 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaType is the type of a schema in the schema registry
+type SchemaType string
+
+const (
+	SchemaTypeAvro     SchemaType = "AVRO"
+	SchemaTypeJSON     SchemaType = "JSON"
+	SchemaTypeProtobuf SchemaType = "PROTOBUF"
+)
+
+const (
+	// DefaultSchemaCacheTTL is how long the latest schema of a subject is cached
+	DefaultSchemaCacheTTL = time.Minute
+
+	registryContentType = "application/vnd.schemaregistry.v1+json"
+)
+
+// Schema is a schema registered in the schema registry
+type Schema struct {
+	ID      int
+	Subject string
+	Version int
+	Type    SchemaType
+	// Definition is the schema text, e.g. a JSON schema or a .proto file
+	Definition string
+}
+
+// RegistryError is an error response of the schema registry
+type RegistryError struct {
+	StatusCode int
+	Code       int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *RegistryError) Error() string {
+	return fmt.Sprintf("schema registry: %s (status %d, code %d)", e.Message, e.StatusCode, e.Code)
+}
+
+// SchemaRegistry is a client of the Confluent schema registry REST API
+type SchemaRegistry struct {
+	url      string
+	client   *http.Client
+	username string
+	password string
+	ttl      time.Duration
+
+	mu     sync.Mutex
+	byID   map[int]Schema
+	latest map[string]cachedSchema
+}
+
+type cachedSchema struct {
+	schema    Schema
+	expiresAt time.Time
+}
+
+// RegistryOption configures a SchemaRegistry
+type RegistryOption func(r *SchemaRegistry)
+
+// WithBasicAuth sets the registry credentials
+func WithBasicAuth(username, password string) RegistryOption {
+	return func(r *SchemaRegistry) {
+		r.username = username
+		r.password = password
+	}
+}
+
+// WithHTTPClient sets the HTTP client (default: http.DefaultClient)
+func WithHTTPClient(client *http.Client) RegistryOption {
+	return func(r *SchemaRegistry) {
+		r.client = client
+	}
+}
+
+// WithSchemaCacheTTL sets how long the latest schema of a subject is cached (default: DefaultSchemaCacheTTL).
+// Schemas looked up by id never change and are cached for the lifetime of the client.
+func WithSchemaCacheTTL(ttl time.Duration) RegistryOption {
+	return func(r *SchemaRegistry) {
+		r.ttl = ttl
+	}
+}
+
+// NewSchemaRegistry creates a schema registry client for the registry at baseURL
+func NewSchemaRegistry(baseURL string, opts ...RegistryOption) *SchemaRegistry {
+	r := &SchemaRegistry{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: http.DefaultClient,
+		ttl:    DefaultSchemaCacheTTL,
+		byID:   make(map[int]Schema),
+		latest: make(map[string]cachedSchema),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r
+}
+
+// registrySchema is the schema representation of the registry API
+type registrySchema struct {
+	ID         int        `json:"id,omitempty"`
+	Subject    string     `json:"subject,omitempty"`
+	Version    int        `json:"version,omitempty"`
+	SchemaType SchemaType `json:"schemaType,omitempty"`
+	Schema     string     `json:"schema"`
+}
+
+func (s registrySchema) toSchema() Schema {
+	// The registry omits the type of Avro schemas
+	if s.SchemaType == "" {
+		s.SchemaType = SchemaTypeAvro
+	}
+	return Schema{ID: s.ID, Subject: s.Subject, Version: s.Version, Type: s.SchemaType, Definition: s.Schema}
+}
+
+// Register registers the schema under the subject and returns its id.
+// Registering an already registered schema returns the existing id.
+func (r *SchemaRegistry) Register(ctx context.Context, subject string, schemaType SchemaType, definition string) (int, error) {
+	req := registrySchema{Schema: definition}
+	if schemaType != SchemaTypeAvro {
+		req.SchemaType = schemaType
+	}
+
+	var resp registrySchema
+	if err := r.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", req, &resp); err != nil {
+		return 0, fmt.Errorf("failed to register schema for %s: %w", subject, err)
+	}
+	return resp.ID, nil
+}
+
+// Latest returns the latest schema registered under the subject
+func (r *SchemaRegistry) Latest(ctx context.Context, subject string) (Schema, error) {
+	r.mu.Lock()
+	cached, ok := r.latest[subject]
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.schema, nil
+	}
+
+	var resp registrySchema
+	if err := r.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &resp); err != nil {
+		return Schema{}, fmt.Errorf("failed to get latest schema of %s: %w", subject, err)
+	}
+	schema := resp.toSchema()
+
+	r.mu.Lock()
+	r.latest[subject] = cachedSchema{schema: schema, expiresAt: time.Now().Add(r.ttl)}
+	r.byID[schema.ID] = schema
+	r.mu.Unlock()
+
+	return schema, nil
+}
+
+// SchemaByID returns the schema with the given id
+func (r *SchemaRegistry) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	r.mu.Lock()
+	schema, ok := r.byID[id]
+	r.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	var resp registrySchema
+	if err := r.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &resp); err != nil {
+		return Schema{}, fmt.Errorf("failed to get schema %d: %w", id, err)
+	}
+	resp.ID = id
+	schema = resp.toSchema()
+
+	r.mu.Lock()
+	r.byID[id] = schema
+	r.mu.Unlock()
+
+	return schema, nil
+}
+
+func (r *SchemaRegistry) do(ctx context.Context, method, path string, in, out any) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.url+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", registryContentType)
+	if in != nil {
+		req.Header.Set("Content-Type", registryContentType)
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		regErr := &RegistryError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(regErr); err != nil || regErr.Message == "" {
+			regErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return regErr
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
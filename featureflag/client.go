@@ -0,0 +1,114 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client evaluates the flags of a source
+type Client struct {
+	source         Source
+	meterProvider  metric.MeterProvider
+	tracerProvider trace.TracerProvider
+
+	evaluations metric.Int64Counter
+	tracer      trace.Tracer
+}
+
+// NewClient creates a Client reading flags from source
+func NewClient(source Source, opts ...ClientOption) *Client {
+	c := &Client{
+		source:         source,
+		meterProvider:  otel.GetMeterProvider(),
+		tracerProvider: otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.initTelemetry()
+	return c
+}
+
+// Evaluate evaluates the flag with the key against ec. Disabled flags have no value.
+// Every evaluation is traced and counted by flag, variant and reason.
+func (c *Client) Evaluate(ctx context.Context, key string, ec EvalContext) (Evaluation, error) {
+	ctx, span := c.startEvaluation(ctx, key)
+	eval, err := c.evaluate(ctx, key, ec)
+	c.recordEvaluation(ctx, span, key, eval, err)
+	return eval, err
+}
+
+func (c *Client) evaluate(ctx context.Context, key string, ec EvalContext) (Evaluation, error) {
+	flag, err := c.source.Flag(ctx, key)
+	if err != nil {
+		return Evaluation{}, err
+	}
+	if flag.Disabled {
+		return Evaluation{Key: key, Reason: ReasonDisabled}, nil
+	}
+
+	for _, rule := range flag.Rules {
+		if !rule.matches(key, ec) {
+			continue
+		}
+		reason := ReasonTargetingMatch
+		if rule.Rollout > 0 {
+			reason = ReasonSplit
+		}
+		return flag.variant(rule.Variant, reason)
+	}
+
+	reason := ReasonDefault
+	if len(flag.Rules) == 0 {
+		reason = ReasonStatic
+	}
+	return flag.variant(flag.DefaultVariant, reason)
+}
+
+func (f Flag) variant(name string, reason Reason) (Evaluation, error) {
+	value, ok := f.Variants[name]
+	if !ok {
+		return Evaluation{}, fmt.Errorf("%w: %s has no variant %q", ErrInvalidFlag, f.Key, name)
+	}
+	return Evaluation{Key: f.Key, Value: value, Variant: name, Reason: reason}, nil
+}
+
+// Bool evaluates a bool flag for the tenant, user and attributes in ctx.
+// It returns def if the flag is missing, disabled or not a bool.
+func (c *Client) Bool(ctx context.Context, key string, def bool) bool {
+	return value(c, ctx, key, def, Evaluation.BoolValue)
+}
+
+// String evaluates a string flag for the tenant, user and attributes in ctx.
+// It returns def if the flag is missing, disabled or not a string.
+func (c *Client) String(ctx context.Context, key string, def string) string {
+	return value(c, ctx, key, def, Evaluation.StringValue)
+}
+
+// Int evaluates an integer flag for the tenant, user and attributes in ctx.
+// It returns def if the flag is missing, disabled or not an integer.
+func (c *Client) Int(ctx context.Context, key string, def int64) int64 {
+	return value(c, ctx, key, def, Evaluation.IntValue)
+}
+
+// Float evaluates a number flag for the tenant, user and attributes in ctx.
+// It returns def if the flag is missing, disabled or not a number.
+func (c *Client) Float(ctx context.Context, key string, def float64) float64 {
+	return value(c, ctx, key, def, Evaluation.FloatValue)
+}
+
+func value[T any](c *Client, ctx context.Context, key string, def T, convert func(Evaluation) (T, bool)) T {
+	eval, err := c.Evaluate(ctx, key, EvalContextFromContext(ctx))
+	if err != nil {
+		return def
+	}
+	v, ok := convert(eval)
+	if !ok {
+		return def
+	}
+	return v
+}
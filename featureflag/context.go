@@ -0,0 +1,82 @@
+package featureflag
+
+import (
+	"context"
+	"maps"
+)
+
+type contextKey int
+
+const (
+	tenantKey contextKey = iota
+	userKey
+	attributesKey
+)
+
+// ContextWithTenant returns a copy of ctx carrying the tenant flags are evaluated for
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant set by ContextWithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey).(string)
+	return tenant, ok && tenant != ""
+}
+
+// ContextWithUser returns a copy of ctx carrying the user flags are evaluated for
+func ContextWithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext returns the user set by ContextWithUser
+func UserFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(userKey).(string)
+	return user, ok && user != ""
+}
+
+// ContextWithAttributes returns a copy of ctx carrying attributes matched by rules,
+// e.g. the plan or the region. They are added to the attributes already in ctx.
+func ContextWithAttributes(ctx context.Context, attrs map[string]string) context.Context {
+	merged := maps.Clone(attributesFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(attrs))
+	}
+	maps.Copy(merged, attrs)
+	return context.WithValue(ctx, attributesKey, merged)
+}
+
+func attributesFromContext(ctx context.Context) map[string]string {
+	attrs, _ := ctx.Value(attributesKey).(map[string]string)
+	return attrs
+}
+
+// EvalContext is what a flag is evaluated against
+type EvalContext struct {
+	// TargetingKey identifies the subject of percentage rollouts. It defaults to the user, then the tenant.
+	TargetingKey string
+	Tenant       string
+	User         string
+	Attributes   map[string]string
+}
+
+// EvalContextFromContext returns the evaluation context of the tenant, user and attributes in ctx
+func EvalContextFromContext(ctx context.Context) EvalContext {
+	var ec EvalContext
+	ec.Tenant, _ = TenantFromContext(ctx)
+	ec.User, _ = UserFromContext(ctx)
+	ec.Attributes = maps.Clone(attributesFromContext(ctx))
+	return ec
+}
+
+// targetingKey returns the key percentage rollouts are bucketed by
+func (ec EvalContext) targetingKey() string {
+	switch {
+	case ec.TargetingKey != "":
+		return ec.TargetingKey
+	case ec.User != "":
+		return ec.User
+	default:
+		return ec.Tenant
+	}
+}
@@ -0,0 +1,11 @@
+// Package featureflag evaluates feature flags against the tenant and user of a request.
+//
+// A Flag has named variants and an ordered list of rules. The first rule matching
+// the evaluation context selects its variant; rules can target tenants, users and
+// attributes and roll out to a stable percentage of targeting keys. Flags are read
+// from a Source, e.g. MemorySource, on every evaluation, so changes apply at once.
+//
+// The tenant and user are carried in the request context by ContextWithTenant and
+// ContextWithUser. The openfeature subpackage exposes a Client as an OpenFeature
+// provider.
+package featureflag
@@ -0,0 +1,184 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var onOff = map[string]any{"on": true, "off": false}
+
+func TestEvaluate(t *testing.T) {
+	source := NewMemorySource(
+		Flag{Key: "static", Variants: onOff, DefaultVariant: "on"},
+		Flag{Key: "disabled", Variants: onOff, DefaultVariant: "on", Disabled: true},
+		Flag{Key: "broken", Variants: onOff, DefaultVariant: "maybe"},
+		Flag{
+			Key:            "targeted",
+			Variants:       onOff,
+			DefaultVariant: "off",
+			Rules: []Rule{
+				{Variant: "on", Users: []string{"alice"}},
+				{Variant: "on", Tenants: []string{"acme"}, Attributes: map[string]string{"plan": "pro"}},
+			},
+		},
+	)
+	client := NewClient(source)
+
+	tests := []struct {
+		name    string
+		key     string
+		ec      EvalContext
+		value   any
+		reason  Reason
+		wantErr error
+	}{
+		{name: "static", key: "static", value: true, reason: ReasonStatic},
+		{name: "disabled", key: "disabled", value: nil, reason: ReasonDisabled},
+		{name: "user rule", key: "targeted", ec: EvalContext{User: "alice"}, value: true, reason: ReasonTargetingMatch},
+		{
+			name:   "tenant and attribute rule",
+			key:    "targeted",
+			ec:     EvalContext{Tenant: "acme", Attributes: map[string]string{"plan": "pro"}},
+			value:  true,
+			reason: ReasonTargetingMatch,
+		},
+		{
+			name:   "attribute mismatch",
+			key:    "targeted",
+			ec:     EvalContext{Tenant: "acme", Attributes: map[string]string{"plan": "free"}},
+			value:  false,
+			reason: ReasonDefault,
+		},
+		{name: "no rule matches", key: "targeted", ec: EvalContext{User: "bob"}, value: false, reason: ReasonDefault},
+		{name: "not found", key: "missing", wantErr: ErrFlagNotFound},
+		{name: "unknown variant", key: "broken", wantErr: ErrInvalidFlag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval, err := client.Evaluate(context.Background(), tt.key, tt.ec)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if eval.Value != tt.value || eval.Reason != tt.reason {
+				t.Errorf("expected %v (%s), got %v (%s)", tt.value, tt.reason, eval.Value, eval.Reason)
+			}
+		})
+	}
+}
+
+func TestRollout(t *testing.T) {
+	client := NewClient(NewMemorySource(Flag{
+		Key:            "rollout",
+		Variants:       onOff,
+		DefaultVariant: "off",
+		Rules:          []Rule{{Variant: "on", Rollout: 30}},
+	}))
+
+	on := 0
+	for i := range 1000 {
+		ec := EvalContext{User: fmt.Sprintf("user-%d", i)}
+		eval, err := client.Evaluate(context.Background(), "rollout", ec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := client.Evaluate(context.Background(), "rollout", ec); again.Value != eval.Value {
+			t.Fatalf("expected stable variant for %s", ec.User)
+		}
+		if eval.Value == true {
+			on++
+			if eval.Reason != ReasonSplit {
+				t.Errorf("expected %s reason, got %s", ReasonSplit, eval.Reason)
+			}
+		}
+	}
+	if on < 250 || on > 350 {
+		t.Errorf("expected about 300 of 1000 users in a 30%% rollout, got %d", on)
+	}
+
+	// Without a targeting key a rollout can't be applied
+	eval, err := client.Evaluate(context.Background(), "rollout", EvalContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eval.Value != false {
+		t.Errorf("expected rollout to skip contexts without a targeting key, got %v", eval.Value)
+	}
+}
+
+func TestTypedValues(t *testing.T) {
+	source := NewMemorySource(
+		Flag{Key: "enabled", Variants: onOff, DefaultVariant: "on"},
+		Flag{Key: "theme", Variants: map[string]any{"dark": "dark"}, DefaultVariant: "dark"},
+		Flag{Key: "limit", Variants: map[string]any{"high": float64(500)}, DefaultVariant: "high"},
+		Flag{Key: "ratio", Variants: map[string]any{"half": 1}, DefaultVariant: "half"},
+		Flag{
+			Key:            "tenant-limit",
+			Variants:       map[string]any{"low": 10, "high": 100},
+			DefaultVariant: "low",
+			Rules:          []Rule{{Variant: "high", Tenants: []string{"acme"}}},
+		},
+	)
+	client := NewClient(source)
+	ctx := context.Background()
+
+	if !client.Bool(ctx, "enabled", false) {
+		t.Error("expected enabled flag to be true")
+	}
+	if got := client.String(ctx, "theme", "light"); got != "dark" {
+		t.Errorf("expected dark theme, got %s", got)
+	}
+	if got := client.Int(ctx, "limit", 0); got != 500 {
+		t.Errorf("expected whole float to convert to 500, got %d", got)
+	}
+	if got := client.Float(ctx, "ratio", 0); got != 1 {
+		t.Errorf("expected int to convert to 1, got %v", got)
+	}
+	if got := client.Int(ctx, "theme", 7); got != 7 {
+		t.Errorf("expected default for a type mismatch, got %d", got)
+	}
+	if got := client.Bool(ctx, "missing", true); !got {
+		t.Error("expected default for a missing flag")
+	}
+
+	// The tenant in the context selects the rule
+	if got := client.Int(ContextWithTenant(ctx, "acme"), "tenant-limit", 0); got != 100 {
+		t.Errorf("expected tenant limit 100, got %d", got)
+	}
+
+	source.Delete("enabled")
+	if client.Bool(ctx, "enabled", false) {
+		t.Error("expected default after the flag is deleted")
+	}
+}
+
+func TestEvalContextFromContext(t *testing.T) {
+	ctx := ContextWithTenant(context.Background(), "acme")
+	ctx = ContextWithUser(ctx, "alice")
+	ctx = ContextWithAttributes(ctx, map[string]string{"plan": "pro"})
+	ctx = ContextWithAttributes(ctx, map[string]string{"region": "eu"})
+
+	ec := EvalContextFromContext(ctx)
+	if ec.Tenant != "acme" || ec.User != "alice" {
+		t.Errorf("expected tenant acme and user alice, got %q and %q", ec.Tenant, ec.User)
+	}
+	if ec.Attributes["plan"] != "pro" || ec.Attributes["region"] != "eu" {
+		t.Errorf("expected merged attributes, got %v", ec.Attributes)
+	}
+	if ec.targetingKey() != "alice" {
+		t.Errorf("expected the user as targeting key, got %q", ec.targetingKey())
+	}
+
+	// The attributes of the context are not shared with evaluation contexts
+	ec.Attributes["plan"] = "free"
+	if EvalContextFromContext(ctx).Attributes["plan"] != "pro" {
+		t.Error("expected context attributes to be unchanged")
+	}
+}
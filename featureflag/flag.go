@@ -0,0 +1,137 @@
+package featureflag
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"slices"
+)
+
+var (
+	// ErrFlagNotFound is returned when the source has no flag with the key
+	ErrFlagNotFound = errors.New("flag not found")
+	// ErrInvalidFlag is returned when a flag refers to a variant it doesn't define
+	ErrInvalidFlag = errors.New("invalid flag")
+)
+
+// Reason explains how the value of a flag was chosen. The values match the OpenFeature reasons.
+type Reason string
+
+const (
+	// ReasonStatic is returned for flags without rules
+	ReasonStatic Reason = "STATIC"
+	// ReasonDefault is returned when no rule matched
+	ReasonDefault Reason = "DEFAULT"
+	// ReasonTargetingMatch is returned when a rule without rollout matched
+	ReasonTargetingMatch Reason = "TARGETING_MATCH"
+	// ReasonSplit is returned when a rule with rollout matched
+	ReasonSplit Reason = "SPLIT"
+	// ReasonDisabled is returned for disabled flags, which have no value
+	ReasonDisabled Reason = "DISABLED"
+)
+
+// Flag is a feature flag with named variants, e.g. "on" and "off"
+type Flag struct {
+	Key            string
+	Variants       map[string]any
+	DefaultVariant string
+	// Rules are checked in order and the first matching rule selects its variant
+	Rules []Rule
+	// Disabled flags evaluate to the default value of the caller
+	Disabled bool
+}
+
+// Rule selects a variant for the evaluation contexts it matches. Empty conditions match any context.
+type Rule struct {
+	Variant string
+	// Tenants and Users the rule applies to
+	Tenants []string
+	Users   []string
+	// Attributes that must all have the given values
+	Attributes map[string]string
+	// Rollout limits the rule to a stable share of targeting keys, from 1 to 100 percent.
+	// 0 applies the rule to all matching contexts.
+	Rollout int
+}
+
+// matches reports whether the rule applies to ec
+func (r Rule) matches(flagKey string, ec EvalContext) bool {
+	if len(r.Tenants) > 0 && !slices.Contains(r.Tenants, ec.Tenant) {
+		return false
+	}
+	if len(r.Users) > 0 && !slices.Contains(r.Users, ec.User) {
+		return false
+	}
+	for name, value := range r.Attributes {
+		if got, ok := ec.Attributes[name]; !ok || got != value {
+			return false
+		}
+	}
+	if r.Rollout > 0 {
+		key := ec.targetingKey()
+		return key != "" && bucket(flagKey, key) < r.Rollout
+	}
+	return true
+}
+
+// bucket maps a targeting key to a percentile. Flags are salted with their key,
+// so the same users are not always the first to get every rollout.
+func bucket(flagKey, targetingKey string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flagKey))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(targetingKey))
+	return int(h.Sum32() % 100)
+}
+
+// Evaluation is the result of evaluating a flag
+type Evaluation struct {
+	Key     string
+	Value   any
+	Variant string
+	Reason  Reason
+}
+
+// BoolValue returns the value as a bool
+func (e Evaluation) BoolValue() (bool, bool) {
+	v, ok := e.Value.(bool)
+	return v, ok
+}
+
+// StringValue returns the value as a string
+func (e Evaluation) StringValue() (string, bool) {
+	v, ok := e.Value.(string)
+	return v, ok
+}
+
+// IntValue returns the value as an int64. Whole float64 values are accepted, since flags decoded from JSON have them.
+func (e Evaluation) IntValue() (int64, bool) {
+	switch v := e.Value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// FloatValue returns the value as a float64. Integer values are converted.
+func (e Evaluation) FloatValue() (float64, bool) {
+	switch v := e.Value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
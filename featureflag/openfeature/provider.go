@@ -0,0 +1,147 @@
+// Package openfeature exposes featureflag as an OpenFeature provider, so services using
+// the OpenFeature SDK can evaluate golib flags without code changes:
+//
+//	client := featureflag.NewClient(source)
+//	if err := of.SetProviderAndWait(openfeature.NewProvider(client)); err != nil {
+//		return err
+//	}
+//
+// The evaluation context is built from the tenant, user and attributes of the request
+// context (featureflag.ContextWithTenant, featureflag.ContextWithUser) and overridden by
+// the OpenFeature evaluation context: the targeting key, the "tenant" and "user" keys
+// and any other key as an attribute.
+package openfeature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/rshelekhov/golib/featureflag"
+)
+
+// ProviderName is the name of the provider in its metadata
+const ProviderName = "golib-featureflag"
+
+// Evaluation context keys mapped to the tenant and the user
+const (
+	TenantKey = "tenant"
+	UserKey   = "user"
+)
+
+// Provider is an OpenFeature provider evaluating flags with a featureflag.Client
+type Provider struct {
+	client *featureflag.Client
+}
+
+var _ of.FeatureProvider = (*Provider)(nil)
+
+// NewProvider creates a Provider evaluating flags with client
+func NewProvider(client *featureflag.Client) *Provider {
+	return &Provider{client: client}
+}
+
+// Metadata returns the name of the provider
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: ProviderName}
+}
+
+// Hooks returns no hooks
+func (p *Provider) Hooks() []of.Hook {
+	return nil
+}
+
+// BooleanEvaluation evaluates a bool flag
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx of.FlattenedContext) of.BoolResolutionDetail {
+	value, detail := resolve(p, ctx, flag, defaultValue, evalCtx, featureflag.Evaluation.BoolValue)
+	return of.BoolResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// StringEvaluation evaluates a string flag
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, evalCtx of.FlattenedContext) of.StringResolutionDetail {
+	value, detail := resolve(p, ctx, flag, defaultValue, evalCtx, featureflag.Evaluation.StringValue)
+	return of.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// FloatEvaluation evaluates a number flag
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, evalCtx of.FlattenedContext) of.FloatResolutionDetail {
+	value, detail := resolve(p, ctx, flag, defaultValue, evalCtx, featureflag.Evaluation.FloatValue)
+	return of.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// IntEvaluation evaluates an integer flag
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, evalCtx of.FlattenedContext) of.IntResolutionDetail {
+	value, detail := resolve(p, ctx, flag, defaultValue, evalCtx, featureflag.Evaluation.IntValue)
+	return of.IntResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// ObjectEvaluation evaluates a flag of any type
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, evalCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	value, detail := resolve(p, ctx, flag, defaultValue, evalCtx, func(e featureflag.Evaluation) (any, bool) {
+		return e.Value, true
+	})
+	return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// resolve evaluates a flag and converts its value, falling back to defaultValue on errors and for disabled flags
+func resolve[T any](p *Provider, ctx context.Context, flag string, defaultValue T, evalCtx of.FlattenedContext,
+	convert func(featureflag.Evaluation) (T, bool),
+) (T, of.ProviderResolutionDetail) {
+	eval, err := p.client.Evaluate(ctx, flag, evalContext(ctx, evalCtx))
+	if err != nil {
+		return defaultValue, of.ProviderResolutionDetail{
+			ResolutionError: resolutionError(err),
+			Reason:          of.ErrorReason,
+		}
+	}
+
+	detail := of.ProviderResolutionDetail{Reason: of.Reason(eval.Reason), Variant: eval.Variant}
+	if eval.Reason == featureflag.ReasonDisabled {
+		return defaultValue, detail
+	}
+	value, ok := convert(eval)
+	if !ok {
+		return defaultValue, of.ProviderResolutionDetail{
+			ResolutionError: of.NewTypeMismatchResolutionError(fmt.Sprintf("flag %s has a %T value", flag, eval.Value)),
+			Reason:          of.ErrorReason,
+		}
+	}
+	return value, detail
+}
+
+func resolutionError(err error) of.ResolutionError {
+	switch {
+	case errors.Is(err, featureflag.ErrFlagNotFound):
+		return of.NewFlagNotFoundResolutionError(err.Error())
+	case errors.Is(err, featureflag.ErrInvalidFlag):
+		return of.NewParseErrorResolutionError(err.Error())
+	default:
+		return of.NewGeneralResolutionError(err.Error())
+	}
+}
+
+// evalContext maps the request context and the OpenFeature evaluation context to a featureflag.EvalContext
+func evalContext(ctx context.Context, evalCtx of.FlattenedContext) featureflag.EvalContext {
+	ec := featureflag.EvalContextFromContext(ctx)
+	for key, value := range evalCtx {
+		s, ok := value.(string)
+		if !ok {
+			s = fmt.Sprint(value)
+		}
+		switch key {
+		case of.TargetingKey:
+			ec.TargetingKey = s
+		case TenantKey:
+			ec.Tenant = s
+		case UserKey:
+			ec.User = s
+		default:
+			if ec.Attributes == nil {
+				ec.Attributes = make(map[string]string, len(evalCtx))
+			}
+			ec.Attributes[key] = s
+		}
+	}
+	return ec
+}
@@ -0,0 +1,107 @@
+package openfeature
+
+import (
+	"context"
+	"testing"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/rshelekhov/golib/featureflag"
+)
+
+func newTestClient(t *testing.T) *of.Client {
+	t.Helper()
+
+	source := featureflag.NewMemorySource(
+		featureflag.Flag{
+			Key:            "checkout",
+			Variants:       map[string]any{"on": true, "off": false},
+			DefaultVariant: "off",
+			Rules: []featureflag.Rule{
+				{Variant: "on", Tenants: []string{"acme"}},
+				{Variant: "on", Users: []string{"alice"}},
+				{Variant: "on", Attributes: map[string]string{"beta": "true"}},
+			},
+		},
+		featureflag.Flag{Key: "theme", Variants: map[string]any{"dark": "dark"}, DefaultVariant: "dark"},
+		featureflag.Flag{Key: "limit", Variants: map[string]any{"high": 100}, DefaultVariant: "high"},
+		featureflag.Flag{Key: "ratio", Variants: map[string]any{"half": 0.5}, DefaultVariant: "half"},
+		featureflag.Flag{Key: "disabled", Variants: map[string]any{"on": true}, DefaultVariant: "on", Disabled: true},
+	)
+
+	domain := t.Name()
+	if err := of.SetNamedProviderAndWait(domain, NewProvider(featureflag.NewClient(source))); err != nil {
+		t.Fatal(err)
+	}
+	return of.NewClient(domain)
+}
+
+func TestProvider(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	t.Run("tenant from the request context", func(t *testing.T) {
+		details, err := client.BooleanValueDetails(featureflag.ContextWithTenant(ctx, "acme"), "checkout", false, of.EvaluationContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !details.Value || details.Variant != "on" || details.Reason != of.TargetingMatchReason {
+			t.Errorf("expected on by targeting match, got %v %s %s", details.Value, details.Variant, details.Reason)
+		}
+	})
+
+	t.Run("targeting key and attributes from the evaluation context", func(t *testing.T) {
+		if !client.Boolean(ctx, "checkout", false, of.NewEvaluationContext("", map[string]any{UserKey: "alice"})) {
+			t.Error("expected user key to match the user rule")
+		}
+		if !client.Boolean(ctx, "checkout", false, of.NewEvaluationContext("bob", map[string]any{"beta": true})) {
+			t.Error("expected beta attribute to match the attribute rule")
+		}
+		if client.Boolean(ctx, "checkout", true, of.NewEvaluationContext("bob", nil)) {
+			t.Error("expected default variant off for an untargeted user")
+		}
+	})
+
+	t.Run("evaluation context overrides the request context", func(t *testing.T) {
+		reqCtx := featureflag.ContextWithTenant(ctx, "acme")
+		if client.Boolean(reqCtx, "checkout", true, of.NewEvaluationContext("bob", map[string]any{TenantKey: "globex"})) {
+			t.Error("expected the tenant of the evaluation context to win")
+		}
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		if got := client.String(ctx, "theme", "light", of.EvaluationContext{}); got != "dark" {
+			t.Errorf("expected dark, got %s", got)
+		}
+		if got := client.Int(ctx, "limit", 0, of.EvaluationContext{}); got != 100 {
+			t.Errorf("expected 100, got %d", got)
+		}
+		if got := client.Float(ctx, "ratio", 0, of.EvaluationContext{}); got != 0.5 {
+			t.Errorf("expected 0.5, got %v", got)
+		}
+		if got := client.Object(ctx, "theme", nil, of.EvaluationContext{}); got != "dark" {
+			t.Errorf("expected dark object, got %v", got)
+		}
+	})
+
+	t.Run("disabled flag", func(t *testing.T) {
+		details, err := client.BooleanValueDetails(ctx, "disabled", false, of.EvaluationContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if details.Value || details.Reason != of.DisabledReason {
+			t.Errorf("expected default value with disabled reason, got %v %s", details.Value, details.Reason)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		details, err := client.BooleanValueDetails(ctx, "missing", true, of.EvaluationContext{})
+		if err == nil || details.ErrorCode != of.FlagNotFoundCode || !details.Value {
+			t.Errorf("expected flag not found with default value, got %v %s %v", err, details.ErrorCode, details.Value)
+		}
+
+		intDetails, err := client.IntValueDetails(ctx, "theme", 7, of.EvaluationContext{})
+		if err == nil || intDetails.ErrorCode != of.TypeMismatchCode || intDetails.Value != 7 {
+			t.Errorf("expected type mismatch with default value, got %v %s %d", err, intDetails.ErrorCode, intDetails.Value)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Source provides flags by key. It returns an error matching ErrFlagNotFound for unknown keys.
+type Source interface {
+	Flag(ctx context.Context, key string) (Flag, error)
+}
+
+// MemorySource keeps flags in memory, e.g. loaded from a config file and replaced with Set
+// when the file changes. It is safe for concurrent use.
+type MemorySource struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+// NewMemorySource creates a MemorySource holding flags
+func NewMemorySource(flags ...Flag) *MemorySource {
+	s := &MemorySource{flags: make(map[string]Flag, len(flags))}
+	s.Set(flags...)
+	return s
+}
+
+// Flag returns the flag with the key
+func (s *MemorySource) Flag(_ context.Context, key string) (Flag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flag, ok := s.flags[key]
+	if !ok {
+		return Flag{}, fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+	}
+	return flag, nil
+}
+
+// Set adds flags, replacing flags with the same keys
+func (s *MemorySource) Set(flags ...Flag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, flag := range flags {
+		s.flags[flag.Key] = flag
+	}
+}
+
+// Delete removes the flags with the keys
+func (s *MemorySource) Delete(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.flags, key)
+	}
+}
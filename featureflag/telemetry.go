@@ -0,0 +1,77 @@
+package featureflag
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the instrumentation scope of flag metrics and traces
+const instrumentationName = "github.com/rshelekhov/golib/featureflag"
+
+// reasonError is the reason attribute of failed evaluations, as in OpenFeature
+const reasonError = "ERROR"
+
+// ClientOption configures a Client
+type ClientOption func(c *Client)
+
+// WithMeterProvider sets the provider used to create metrics.
+// The global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) ClientOption {
+	return func(c *Client) {
+		c.meterProvider = mp
+	}
+}
+
+// WithTracerProvider sets the provider used to create spans.
+// The global provider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(c *Client) {
+		c.tracerProvider = tp
+	}
+}
+
+// initTelemetry creates the instruments of the client:
+//   - featureflag_evaluations_total{flag,variant,reason} - evaluations by variant and reason, ERROR for failed ones
+func (c *Client) initTelemetry() {
+	c.tracer = c.tracerProvider.Tracer(instrumentationName)
+
+	evaluations, err := c.meterProvider.Meter(instrumentationName).Int64Counter("featureflag_evaluations_total",
+		metric.WithDescription("Total number of feature flag evaluations by flag, variant and reason."))
+	if err != nil {
+		otel.Handle(err)
+		evaluations = noop.Int64Counter{}
+	}
+	c.evaluations = evaluations
+}
+
+// startEvaluation starts the span of a flag evaluation
+func (c *Client) startEvaluation(ctx context.Context, key string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "featureflag.Evaluate", trace.WithAttributes(attribute.String("feature_flag.key", key)))
+}
+
+// recordEvaluation counts an evaluation and ends its span
+func (c *Client) recordEvaluation(ctx context.Context, span trace.Span, key string, eval Evaluation, err error) {
+	reason := string(eval.Reason)
+	if err != nil {
+		reason = reasonError
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(
+		attribute.String("feature_flag.variant", eval.Variant),
+		attribute.String("feature_flag.reason", reason),
+	)
+	span.End()
+
+	c.evaluations.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("flag", key),
+		attribute.String("variant", eval.Variant),
+		attribute.String("reason", reason),
+	))
+}
@@ -0,0 +1,83 @@
+package featureflag
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEvaluateTelemetry(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	spans := tracetest.NewSpanRecorder()
+	client := NewClient(
+		NewMemorySource(
+			Flag{Key: "static", Variants: onOff, DefaultVariant: "on"},
+			Flag{
+				Key:            "targeted",
+				Variants:       onOff,
+				DefaultVariant: "off",
+				Rules:          []Rule{{Variant: "on", Users: []string{"alice"}}},
+			},
+		),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+	)
+
+	ctx := context.Background()
+	client.Bool(ctx, "static", false)
+	client.Bool(ctx, "static", false)
+	client.Bool(ContextWithUser(ctx, "alice"), "targeted", false)
+	client.Bool(ContextWithUser(ctx, "bob"), "targeted", false)
+	client.Bool(ctx, "missing", false)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	evaluations := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "featureflag_evaluations_total" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				flag, _ := dp.Attributes.Value("flag")
+				variant, _ := dp.Attributes.Value("variant")
+				reason, _ := dp.Attributes.Value("reason")
+				evaluations[flag.AsString()+"/"+variant.AsString()+"/"+reason.AsString()] = dp.Value
+			}
+		}
+	}
+	want := map[string]int64{
+		"static/on/STATIC":            2,
+		"targeted/on/TARGETING_MATCH": 1,
+		"targeted/off/DEFAULT":        1,
+		"missing//ERROR":              1,
+	}
+	if len(evaluations) != len(want) {
+		t.Errorf("evaluations = %v, want %v", evaluations, want)
+	}
+	for key, n := range want {
+		if evaluations[key] != n {
+			t.Errorf("evaluations of %s = %d, want %d", key, evaluations[key], n)
+		}
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 5 {
+		t.Fatalf("got %d spans, want 5", len(ended))
+	}
+	for _, span := range ended {
+		if span.Name() != "featureflag.Evaluate" {
+			t.Errorf("span name = %q", span.Name())
+		}
+	}
+	if failed := ended[4]; failed.Status().Code != codes.Error || len(failed.Events()) == 0 {
+		t.Errorf("expected the missing flag span to record the error, got %+v", failed.Status())
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/rshelekhov/golib/leaktest
+
+go 1.24.2
+
+require go.uber.org/goleak v1.3.0
@@ -0,0 +1,76 @@
+// Package leaktest wraps go.uber.org/goleak with allowlists for background
+// goroutines started by the library's dependencies (OTel batchers, connection pools),
+// so tests only fail on goroutines leaked by the code under test.
+package leaktest
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// Option configures goroutine leak detection. It is an alias of goleak.Option,
+// so any goleak option can be passed directly.
+type Option = goleak.Option
+
+// otelFunctions are long-lived goroutines of the OpenTelemetry SDK. They exit on
+// provider Shutdown, which tests often skip.
+var otelFunctions = []string{
+	"go.opentelemetry.io/otel/sdk/trace.(*batchSpanProcessor).processQueue",
+	"go.opentelemetry.io/otel/sdk/metric.(*PeriodicReader).run",
+	"go.opentelemetry.io/otel/sdk/log.(*BatchProcessor).poll.func1",
+	"go.opentelemetry.io/otel/sdk/log.exportSync.func1",
+}
+
+// poolFunctions are background goroutines of database and RPC client pools
+var poolFunctions = []string{
+	"github.com/jackc/pgx/v5/pgxpool.(*Pool).backgroundHealthCheck",
+	"github.com/jackc/pgx/v5/pgxpool.(*Pool).triggerHealthCheck.func1",
+	"github.com/redis/go-redis/v9/internal/pool.(*ConnPool).checkMinIdleConns.func1",
+	"github.com/redis/go-redis/v9/internal/pool.(*ConnPool).tryDial",
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology.(*pool).maintain",
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology.(*Server).update",
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology.(*rttMonitor).start",
+	"google.golang.org/grpc/internal/grpcsync.(*CallbackSerializer).run",
+}
+
+// DefaultOptions returns options that ignore the library's known background
+// goroutines. They are applied by VerifyNone and VerifyTestMain automatically.
+func DefaultOptions() []Option {
+	opts := make([]Option, 0, len(otelFunctions)+len(poolFunctions))
+	for _, fn := range append(otelFunctions, poolFunctions...) {
+		opts = append(opts, goleak.IgnoreAnyFunction(fn))
+	}
+	return opts
+}
+
+// IgnoreFunction returns an option that ignores goroutines with the given
+// function anywhere in their stack, e.g. a service's own background worker
+func IgnoreFunction(fn string) Option {
+	return goleak.IgnoreAnyFunction(fn)
+}
+
+// IgnoreCurrent returns an option that ignores all goroutines running at the
+// moment it is called. Use it when a test shares fixtures started elsewhere.
+func IgnoreCurrent() Option {
+	return goleak.IgnoreCurrent()
+}
+
+// VerifyNone fails the test if unexpected goroutines are still running.
+// Typically deferred at the start of a test:
+//
+//	defer leaktest.VerifyNone(t)
+func VerifyNone(t testing.TB, opts ...Option) {
+	t.Helper()
+	goleak.VerifyNone(t, append(DefaultOptions(), opts...)...)
+}
+
+// VerifyTestMain runs the tests of a package and fails if goroutines are
+// leaked once all of them have finished:
+//
+//	func TestMain(m *testing.M) {
+//		leaktest.VerifyTestMain(m)
+//	}
+func VerifyTestMain(m *testing.M, opts ...Option) {
+	goleak.VerifyTestMain(m, append(DefaultOptions(), opts...)...)
+}
@@ -0,0 +1,27 @@
+package leaktest
+
+import (
+	"testing"
+)
+
+func blockForever(stop <-chan struct{}) {
+	<-stop
+}
+
+func TestVerifyNoneIgnoresFunction(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go blockForever(stop)
+
+	VerifyNone(t, IgnoreFunction("github.com/rshelekhov/golib/leaktest.blockForever"))
+}
+
+func TestVerifyNoneIgnoresCurrent(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go blockForever(stop)
+
+	VerifyNone(t, IgnoreCurrent())
+}
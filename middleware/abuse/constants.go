@@ -0,0 +1,24 @@
+package abuse
+
+import "time"
+
+// Constants for abuse detection
+const (
+	// DefaultWindow is the default period over which requests per fingerprint are counted
+	DefaultWindow = time.Minute
+
+	// DefaultKeyPrefix is prepended to fingerprints when storing counters
+	DefaultKeyPrefix = "abuse:"
+
+	// FingerprintHeader is the response header carrying the computed fingerprint
+	// when WithFingerprintHeader is enabled
+	FingerprintHeader = "X-Request-Fingerprint"
+)
+
+// DefaultFingerprintHeaders are request headers mixed into the fingerprint
+// in addition to the client IP and User-Agent
+var DefaultFingerprintHeaders = []string{
+	"Accept",
+	"Accept-Language",
+	"Accept-Encoding",
+}
@@ -0,0 +1,63 @@
+package abuse
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Decision is the outcome of evaluating a request
+type Decision int
+
+const (
+	// Allow passes the request to the next handler
+	Allow Decision = iota
+	// Challenge asks the client to prove it is legitimate (captcha, proof of work, retry later)
+	Challenge
+	// Block rejects the request
+	Block
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Allow:
+		return "allow"
+	case Challenge:
+		return "challenge"
+	case Block:
+		return "block"
+	default:
+		return "unknown"
+	}
+}
+
+// Signal describes a request being evaluated
+type Signal struct {
+	Request     *http.Request
+	Fingerprint string
+	ClientIP    string
+	// Count is the number of requests with this fingerprint in the current window,
+	// including this one. It is zero if the store failed.
+	Count  int64
+	Window time.Duration
+}
+
+// DecisionFunc decides what to do with a request.
+// It is the extension point for custom rules, e.g. allowlists, reputation lookups or bot scores.
+type DecisionFunc func(ctx context.Context, s Signal) Decision
+
+// ThresholdDecision returns a DecisionFunc that challenges clients exceeding
+// challengeAt requests per window and blocks clients exceeding blockAt.
+// A zero threshold disables the corresponding decision.
+func ThresholdDecision(challengeAt, blockAt int64) DecisionFunc {
+	return func(_ context.Context, s Signal) Decision {
+		switch {
+		case blockAt > 0 && s.Count > blockAt:
+			return Block
+		case challengeAt > 0 && s.Count > challengeAt:
+			return Challenge
+		default:
+			return Allow
+		}
+	}
+}
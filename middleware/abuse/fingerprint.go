@@ -0,0 +1,67 @@
+package abuse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Fingerprinter computes a stable identifier of the client sending a request
+type Fingerprinter struct {
+	headers      []string
+	trustProxies bool
+}
+
+// NewFingerprinter creates a fingerprinter that hashes the client IP, User-Agent
+// and the given headers. If no headers are provided, DefaultFingerprintHeaders are used.
+// When trustProxies is set, the client IP is taken from X-Forwarded-For or X-Real-IP;
+// enable it only behind a proxy that overwrites these headers.
+func NewFingerprinter(trustProxies bool, headers ...string) *Fingerprinter {
+	if len(headers) == 0 {
+		headers = DefaultFingerprintHeaders
+	}
+	return &Fingerprinter{
+		headers:      headers,
+		trustProxies: trustProxies,
+	}
+}
+
+// Fingerprint returns the hex-encoded SHA-256 fingerprint of the request
+func (f *Fingerprinter) Fingerprint(r *http.Request) string {
+	h := sha256.New()
+
+	// Values are separated by a zero byte so that ("ab", "c") and ("a", "bc") differ
+	writeField := func(v string) {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+
+	writeField(f.ClientIP(r))
+	writeField(r.UserAgent())
+	for _, name := range f.headers {
+		writeField(strings.Join(r.Header.Values(name), ","))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ClientIP returns the IP address of the client
+func (f *Fingerprinter) ClientIP(r *http.Request) string {
+	if f.trustProxies {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			ip, _, _ := strings.Cut(xff, ",")
+			return strings.TrimSpace(ip)
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return strings.TrimSpace(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
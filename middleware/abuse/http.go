@@ -0,0 +1,191 @@
+package abuse
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of the middleware metrics
+const meterName = "github.com/rshelekhov/golib/middleware/abuse"
+
+// Middleware tracks request rates per client fingerprint and applies a Decision to each request
+type Middleware struct {
+	store            Store
+	fingerprinter    *Fingerprinter
+	decide           DecisionFunc
+	window           time.Duration
+	keyPrefix        string
+	exposeHeader     bool
+	challengeHandler http.Handler
+	blockHandler     http.Handler
+	meterProvider    metric.MeterProvider
+
+	decisions   metric.Int64Counter
+	storeErrors metric.Int64Counter
+}
+
+// Option configures the Middleware
+type Option func(*Middleware)
+
+// WithWindow sets the period over which requests per fingerprint are counted
+func WithWindow(window time.Duration) Option {
+	return func(m *Middleware) {
+		m.window = window
+	}
+}
+
+// WithKeyPrefix sets the prefix of counter keys in the store
+func WithKeyPrefix(prefix string) Option {
+	return func(m *Middleware) {
+		m.keyPrefix = prefix
+	}
+}
+
+// WithFingerprinter replaces the default fingerprinter,
+// e.g. to trust proxy headers or mix in additional headers
+func WithFingerprinter(f *Fingerprinter) Option {
+	return func(m *Middleware) {
+		m.fingerprinter = f
+	}
+}
+
+// WithDecision sets the hook deciding whether to allow, challenge or block a request.
+// By default all requests are allowed and only counted.
+func WithDecision(decide DecisionFunc) Option {
+	return func(m *Middleware) {
+		m.decide = decide
+	}
+}
+
+// WithChallengeHandler sets the handler serving challenged requests.
+// By default they get 429 Too Many Requests with a Retry-After header.
+func WithChallengeHandler(h http.Handler) Option {
+	return func(m *Middleware) {
+		m.challengeHandler = h
+	}
+}
+
+// WithBlockHandler sets the handler serving blocked requests.
+// By default they get 403 Forbidden.
+func WithBlockHandler(h http.Handler) Option {
+	return func(m *Middleware) {
+		m.blockHandler = h
+	}
+}
+
+// WithFingerprintHeader adds the computed fingerprint to responses in FingerprintHeader,
+// which helps correlating client reports with logs
+func WithFingerprintHeader(enable bool) Option {
+	return func(m *Middleware) {
+		m.exposeHeader = enable
+	}
+}
+
+// WithMeterProvider sets the provider used to create metrics.
+// The global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(m *Middleware) {
+		m.meterProvider = mp
+	}
+}
+
+// NewMiddleware creates a new abuse detection middleware backed by the given store
+func NewMiddleware(store Store, opts ...Option) *Middleware {
+	m := &Middleware{
+		store:         store,
+		fingerprinter: NewFingerprinter(false),
+		decide:        func(context.Context, Signal) Decision { return Allow },
+		window:        DefaultWindow,
+		keyPrefix:     DefaultKeyPrefix,
+		meterProvider: otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.challengeHandler == nil {
+		m.challengeHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(m.window.Seconds())))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		})
+	}
+	if m.blockHandler == nil {
+		m.blockHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		})
+	}
+
+	m.initMetrics()
+
+	return m
+}
+
+func (m *Middleware) initMetrics() {
+	meter := m.meterProvider.Meter(meterName)
+
+	var err error
+	m.decisions, err = meter.Int64Counter(
+		"abuse_decisions_total",
+		metric.WithDescription("Total number of requests by abuse detection decision."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	m.storeErrors, err = meter.Int64Counter(
+		"abuse_store_errors_total",
+		metric.WithDescription("Total number of failed rate counter updates."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// Handler wraps next with abuse detection.
+// Store errors do not fail the request: the decision hook is called with a zero Count.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		signal := Signal{
+			Request:     r,
+			Fingerprint: m.fingerprinter.Fingerprint(r),
+			ClientIP:    m.fingerprinter.ClientIP(r),
+			Window:      m.window,
+		}
+
+		count, err := m.store.Incr(ctx, m.keyPrefix+signal.Fingerprint, m.window)
+		if err != nil {
+			m.storeErrors.Add(ctx, 1)
+		} else {
+			signal.Count = count
+		}
+
+		decision := m.decide(ctx, signal)
+		m.decisions.Add(ctx, 1, metric.WithAttributes(attribute.String("decision", decision.String())))
+
+		if m.exposeHeader {
+			w.Header().Set(FingerprintHeader, signal.Fingerprint)
+		}
+
+		switch decision {
+		case Challenge:
+			m.challengeHandler.ServeHTTP(w, r)
+		case Block:
+			m.blockHandler.ServeHTTP(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// HTTPMiddleware returns an HTTP middleware performing abuse detection with the given store
+func HTTPMiddleware(store Store, opts ...Option) func(http.Handler) http.Handler {
+	return NewMiddleware(store, opts...).Handler
+}
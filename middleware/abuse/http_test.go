@@ -0,0 +1,96 @@
+package abuse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type failingStore struct{}
+
+func (failingStore) Incr(context.Context, string, time.Duration) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func newAbuseTestHandler(store Store, opts ...Option) http.Handler {
+	return HTTPMiddleware(store, opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func serveAbuseRequest(h http.Handler, remoteAddr, userAgent string) int {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = remoteAddr
+	r.Header.Set("User-Agent", userAgent)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestMiddlewareThresholds(t *testing.T) {
+	h := newAbuseTestHandler(NewMemoryStore(), WithDecision(ThresholdDecision(2, 3)))
+
+	expected := []int{
+		http.StatusOK,
+		http.StatusOK,
+		http.StatusTooManyRequests,
+		http.StatusForbidden,
+	}
+	for i, want := range expected {
+		if got := serveAbuseRequest(h, "10.0.0.1:1234", "bot/1.0"); got != want {
+			t.Errorf("request %d: expected status %d, got %d", i+1, want, got)
+		}
+	}
+
+	// Another client has its own counter
+	if got := serveAbuseRequest(h, "10.0.0.2:1234", "bot/1.0"); got != http.StatusOK {
+		t.Errorf("expected other client to be allowed, got %d", got)
+	}
+}
+
+func TestMiddlewareFailsOpen(t *testing.T) {
+	var signal Signal
+	h := newAbuseTestHandler(failingStore{}, WithDecision(func(_ context.Context, s Signal) Decision {
+		signal = s
+		return ThresholdDecision(0, 1)(context.Background(), s)
+	}))
+
+	if got := serveAbuseRequest(h, "10.0.0.1:1234", "curl"); got != http.StatusOK {
+		t.Errorf("expected request to be allowed on store error, got %d", got)
+	}
+	if signal.Count != 0 || signal.ClientIP != "10.0.0.1" {
+		t.Errorf("unexpected signal %+v", signal)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	newRequest := func(remoteAddr, xff, ua string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("User-Agent", ua)
+		if xff != "" {
+			r.Header.Set("X-Forwarded-For", xff)
+		}
+		return r
+	}
+
+	direct := NewFingerprinter(false)
+	if direct.Fingerprint(newRequest("1.1.1.1:1", "", "a")) == direct.Fingerprint(newRequest("1.1.1.1:1", "", "b")) {
+		t.Error("expected different user agents to produce different fingerprints")
+	}
+	if direct.Fingerprint(newRequest("1.1.1.1:1", "", "a")) != direct.Fingerprint(newRequest("1.1.1.1:2", "", "a")) {
+		t.Error("expected client port not to affect the fingerprint")
+	}
+	if ip := direct.ClientIP(newRequest("1.1.1.1:1", "2.2.2.2", "a")); ip != "1.1.1.1" {
+		t.Errorf("expected X-Forwarded-For to be ignored, got %s", ip)
+	}
+
+	proxied := NewFingerprinter(true)
+	if ip := proxied.ClientIP(newRequest("1.1.1.1:1", "2.2.2.2, 3.3.3.3", "a")); ip != "2.2.2.2" {
+		t.Errorf("expected first X-Forwarded-For address, got %s", ip)
+	}
+}
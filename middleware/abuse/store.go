@@ -0,0 +1,103 @@
+package abuse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store counts requests per key in fixed time windows.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Incr increments the counter of the key and returns the new value.
+	// The counter starts at zero again once window has passed since its first increment.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// incrScript increments the counter and sets its expiration on first use,
+// so that the window is fixed and not extended by subsequent requests
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// RedisStore is a Store shared by all replicas of a service
+type RedisStore struct {
+	client redis.Scripter
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a store backed by the given Redis client
+// (*redis.Client, *redis.ClusterClient or a redis.UniversalClient)
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Incr increments the counter in Redis
+func (s *RedisStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	n, err := incrScript.Run(ctx, s.client, []string{key}, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment rate counter: %w", err)
+	}
+	return n, nil
+}
+
+// MemoryStore is an in-process Store implementation.
+// It is suitable for single-instance services and tests; use RedisStore
+// when running multiple replicas.
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]memoryCounter
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// memorySweepInterval limits how often Incr scans for expired counters
+const memorySweepInterval = time.Minute
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: make(map[string]memoryCounter),
+		now:      time.Now,
+	}
+}
+
+// Incr increments the counter and periodically evicts expired ones
+func (s *MemoryStore) Incr(_ context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		for k, c := range s.counters {
+			if !now.Before(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = memoryCounter{expiresAt: now.Add(window)}
+	}
+	c.count++
+	s.counters[key] = c
+
+	return c.count, nil
+}
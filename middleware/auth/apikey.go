@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeys returns a function authenticating callers by the static key in APIKeyHeader.
+// keys maps every accepted key to the name of its owner, set as the Subject of the Principal.
+func APIKeys(keys map[string]string) Func {
+	type entry struct {
+		hash [sha256.Size]byte
+		name string
+	}
+	entries := make([]entry, 0, len(keys))
+	for key, name := range keys {
+		entries = append(entries, entry{hash: sha256.Sum256([]byte(key)), name: name})
+	}
+
+	return func(ctx context.Context) (context.Context, error) {
+		key := Credential(ctx, APIKeyHeader)
+		if key == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing API key")
+		}
+
+		// Compare hashes of every key in constant time, so timing doesn't reveal valid keys
+		hash := sha256.Sum256([]byte(key))
+		var name string
+		for _, e := range entries {
+			if subtle.ConstantTimeCompare(hash[:], e.hash[:]) == 1 {
+				name = e.name
+			}
+		}
+		if name == "" {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		return WithPrincipal(ctx, &Principal{Subject: name, Method: "api_key"}), nil
+	}
+}
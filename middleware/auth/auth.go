@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Func authenticates a request from the credentials in its incoming gRPC metadata, which
+// the HTTP middleware fills from the request headers. It returns the context passed to
+// the handler, usually carrying a Principal, or an error rejecting the request:
+// a gRPC status, e.g. PermissionDenied, or any other error treated as Unauthenticated.
+type Func func(ctx context.Context) (context.Context, error)
+
+// Principal is the authenticated caller
+type Principal struct {
+	// Subject identifies the caller, e.g. the "sub" claim of a JWT or the name of an API key
+	Subject string
+	// Method is the authentication method, e.g. "jwt" or "api_key"
+	Method string
+	// Claims are the claims of a JWT
+	Claims map[string]any
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal set by the authentication function
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Credential returns the first value of the metadata key, e.g. APIKeyHeader
+func Credential(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// BearerToken returns the token of the "authorization" metadata with the Bearer scheme
+func BearerToken(ctx context.Context) (string, error) {
+	scheme, token, ok := strings.Cut(Credential(ctx, "authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	return token, nil
+}
+
+// Any tries the functions in order and returns the result of the first that succeeds,
+// e.g. to accept both JWTs and API keys. If all fail, the error of the first is returned.
+func Any(fns ...Func) Func {
+	return func(ctx context.Context) (context.Context, error) {
+		var first error
+		for _, fn := range fns {
+			authCtx, err := fn(ctx)
+			if err == nil {
+				return authCtx, nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		if first == nil {
+			first = status.Error(codes.Unauthenticated, "no authentication method")
+		}
+		return nil, first
+	}
+}
+
+// authenticator applies an authentication function to gRPC calls and HTTP requests
+type authenticator struct {
+	fn     Func
+	exempt []string
+}
+
+// Option configures the interceptors and middleware
+type Option func(*authenticator)
+
+// WithExempt adds gRPC methods or HTTP paths that are not authenticated. Names ending with "/"
+// exempt every method of a service or every path under a prefix, e.g. "/grpc.health.v1.Health/"
+// or "/public/". DefaultExempt is always exempt.
+func WithExempt(names ...string) Option {
+	return func(a *authenticator) {
+		a.exempt = append(a.exempt, names...)
+	}
+}
+
+func newAuthenticator(fn Func, opts ...Option) *authenticator {
+	a := &authenticator{
+		fn:     fn,
+		exempt: append([]string(nil), DefaultExempt...),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *authenticator) isExempt(name string) bool {
+	for _, e := range a.exempt {
+		if name == e || strings.HasSuffix(e, "/") && strings.HasPrefix(name, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate runs the function, converting errors without a gRPC status to Unauthenticated
+func (a *authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	authCtx, err := a.fn(ctx)
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			err = status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, err
+	}
+	if authCtx == nil {
+		authCtx = ctx
+	}
+	return authCtx, nil
+}
+
+// httpStatus returns the HTTP status of an authentication error
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Internal, codes.Unknown:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusUnauthorized
+	}
+}
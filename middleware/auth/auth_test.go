@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJWT(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kid atomic.Value
+	kid.Store("old")
+	var fetches atomic.Int32
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "OKP", "crv": "Ed25519", "kid": kid.Load().(string), "x": base64.RawURLEncoding.EncodeToString(pub),
+		}}})
+	}))
+	defer jwksServer.Close()
+
+	sign := func(kid string, exp time.Time) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": "user-1", "iss": "https://issuer", "exp": exp.Unix()})
+		token.Header["kid"] = kid
+		s, err := token.SignedString(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	interceptor := UnaryServerInterceptor(JWT(NewJWKS(jwksServer.URL, WithMinRefreshInterval(0)), WithIssuer("https://issuer")))
+	call := func(method, token string) (*Principal, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		var p *Principal
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ any) (any, error) {
+			p, _ = PrincipalFromContext(ctx)
+			return nil, nil
+		})
+		return p, err
+	}
+
+	p, err := call("/orders.v1.OrderService/GetOrder", sign("old", time.Now().Add(time.Minute)))
+	if err != nil || p.Subject != "user-1" || p.Method != "jwt" {
+		t.Fatalf("valid token = %+v, %v", p, err)
+	}
+	if _, err := call("/orders.v1.OrderService/GetOrder", sign("old", time.Now().Add(-time.Minute))); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expired token code = %v, want Unauthenticated", status.Code(err))
+	}
+	if _, err := call("/grpc.health.v1.Health/Check", ""); err != nil {
+		t.Errorf("health check error = %v, want exempt", err)
+	}
+
+	// A token signed with a rotated key triggers a refresh
+	kid.Store("new")
+	if _, err := call("/orders.v1.OrderService/GetOrder", sign("new", time.Now().Add(time.Minute))); err != nil {
+		t.Errorf("rotated key error = %v", err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("JWKS fetches = %d, want 2", n)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	handler := HTTPMiddleware(APIKeys(map[string]string{"secret": "billing"}), WithExempt("/public/"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p, ok := PrincipalFromContext(r.Context()); ok {
+				w.Header().Set("X-Subject", p.Subject)
+			}
+		}))
+
+	for _, tt := range []struct {
+		path, key   string
+		wantCode    int
+		wantSubject string
+	}{
+		{"/v1/orders", "secret", http.StatusOK, "billing"},
+		{"/v1/orders", "wrong", http.StatusUnauthorized, ""},
+		{"/v1/orders", "", http.StatusUnauthorized, ""},
+		{"/public/docs", "", http.StatusOK, ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set(APIKeyHeader, tt.key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.wantCode || rec.Header().Get("X-Subject") != tt.wantSubject {
+			t.Errorf("%s with key %q = %d %q, want %d %q", tt.path, tt.key, rec.Code, rec.Header().Get("X-Subject"), tt.wantCode, tt.wantSubject)
+		}
+	}
+}
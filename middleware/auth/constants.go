@@ -0,0 +1,22 @@
+package auth
+
+import "time"
+
+// Constants for authentication
+const (
+	// APIKeyHeader is the header, or gRPC metadata key, carrying static API keys
+	APIKeyHeader = "X-API-Key"
+
+	// DefaultJWKSRefreshInterval is how often the keys of a JWKS are refreshed
+	DefaultJWKSRefreshInterval = time.Hour
+
+	// DefaultJWKSMinRefreshInterval limits refreshes triggered by tokens signed with unknown keys
+	DefaultJWKSMinRefreshInterval = time.Minute
+)
+
+// DefaultExempt are the gRPC services and HTTP paths never authenticated: health checks and reflection
+var DefaultExempt = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
@@ -0,0 +1,16 @@
+module github.com/rshelekhov/golib/middleware/auth
+
+go 1.24.2
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.3.0
+	google.golang.org/grpc v1.74.2
+)
+
+require (
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
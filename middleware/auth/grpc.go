@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor authenticating calls with fn
+func UnaryServerInterceptor(fn Func, opts ...Option) grpc.UnaryServerInterceptor {
+	a := newAuthenticator(fn, opts...)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if a.isExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor authenticating streams with fn
+func StreamServerInterceptor(fn Func, opts ...Option) grpc.StreamServerInterceptor {
+	a := newAuthenticator(fn, opts...)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if a.isExempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPMiddleware returns an HTTP middleware authenticating requests with fn. The request headers
+// are added to the incoming gRPC metadata of the context, under lowercase names, so the same
+// function serves both protocols. Rejected requests get 401 (403 for PermissionDenied).
+func HTTPMiddleware(fn Func, opts ...Option) func(http.Handler) http.Handler {
+	a := newAuthenticator(fn, opts...)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if a.isExempt(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			md, _ := metadata.FromIncomingContext(r.Context())
+			md = md.Copy()
+			for name, values := range r.Header {
+				md.Append(strings.ToLower(name), values...)
+			}
+
+			ctx, err := a.authenticate(metadata.NewIncomingContext(r.Context(), md))
+			if err != nil {
+				code := httpStatus(err)
+				if code == http.StatusUnauthorized {
+					w.Header().Set("WWW-Authenticate", "Bearer")
+				}
+				http.Error(w, status.Convert(err).Message(), code)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errKeysUnavailable is wrapped by the errors of failed JWKS fetches
+var errKeysUnavailable = errors.New("signing keys unavailable")
+
+// JWKS is a JSON Web Key Set fetched from a URL, e.g. "https://issuer/.well-known/jwks.json".
+// Keys are fetched on first use and refreshed periodically, and when a token is signed
+// with an unknown key, so rotated keys are picked up without a restart.
+type JWKS struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	now                func() time.Time
+
+	mu          sync.RWMutex
+	keys        map[string]any
+	fetched     time.Time
+	lastAttempt time.Time
+
+	refreshMu sync.Mutex
+}
+
+// JWKSOption configures a JWKS
+type JWKSOption func(*JWKS)
+
+// WithHTTPClient sets the client fetching the key set (default: a client with a 10s timeout)
+func WithHTTPClient(client *http.Client) JWKSOption {
+	return func(j *JWKS) {
+		j.client = client
+	}
+}
+
+// WithRefreshInterval sets how often keys are refreshed (default: DefaultJWKSRefreshInterval)
+func WithRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.refreshInterval = interval
+	}
+}
+
+// WithMinRefreshInterval limits refreshes triggered by unknown keys (default: DefaultJWKSMinRefreshInterval),
+// so tokens with made-up key IDs can't flood the issuer
+func WithMinRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.minRefreshInterval = interval
+	}
+}
+
+// NewJWKS creates a key set fetched from url
+func NewJWKS(url string, opts ...JWKSOption) *JWKS {
+	j := &JWKS{
+		url:                url,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		refreshInterval:    DefaultJWKSRefreshInterval,
+		minRefreshInterval: DefaultJWKSMinRefreshInterval,
+		now:                time.Now,
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// Key returns the public key with the key ID kid. An empty kid matches the only key of a set with one key.
+// Stale keys are refreshed first; if the refresh fails, the cached keys are used.
+func (j *JWKS) Key(ctx context.Context, kid string) (any, error) {
+	j.mu.RLock()
+	stale := j.now().Sub(j.fetched) >= j.refreshInterval
+	j.mu.RUnlock()
+
+	var refreshErr error
+	if stale {
+		refreshErr = j.refresh(ctx, 0)
+	}
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+
+	// The key may have been rotated since the last refresh
+	if !stale {
+		refreshErr = j.refresh(ctx, j.minRefreshInterval)
+	}
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	if refreshErr != nil {
+		return nil, refreshErr
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// Refresh fetches the key set now
+func (j *JWKS) Refresh(ctx context.Context) error {
+	return j.refresh(ctx, 0)
+}
+
+func (j *JWKS) lookup(kid string) (any, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the key set unless it was attempted within minInterval.
+// Concurrent callers wait for a single fetch.
+func (j *JWKS) refresh(ctx context.Context, minInterval time.Duration) error {
+	attempt := j.now()
+	j.refreshMu.Lock()
+	defer j.refreshMu.Unlock()
+
+	j.mu.RLock()
+	last := j.lastAttempt
+	j.mu.RUnlock()
+	// Another caller has fetched while this one waited, or fetched too recently
+	if last.After(attempt) || attempt.Sub(last) < minInterval {
+		return nil
+	}
+
+	keys, err := j.fetch(ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.lastAttempt = j.now()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errKeysUnavailable, err)
+	}
+	j.keys, j.fetched = keys, j.lastAttempt
+	return nil
+}
+
+// jwk is a JSON Web Key of RFC 7517
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (j *JWKS) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, ok := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
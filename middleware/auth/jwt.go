@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultJWTAlgorithms are the signing algorithms accepted by JWT: asymmetric ones only,
+// as keys come from a public key set
+var DefaultJWTAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// JWTOption configures JWT validation
+type JWTOption func(*jwtConfig)
+
+type jwtConfig struct {
+	issuer     string
+	audience   string
+	algorithms []string
+	leeway     time.Duration
+}
+
+// WithIssuer requires the "iss" claim to be issuer
+func WithIssuer(issuer string) JWTOption {
+	return func(c *jwtConfig) {
+		c.issuer = issuer
+	}
+}
+
+// WithAudience requires the "aud" claim to contain audience
+func WithAudience(audience string) JWTOption {
+	return func(c *jwtConfig) {
+		c.audience = audience
+	}
+}
+
+// WithAlgorithms sets the accepted signing algorithms (default: DefaultJWTAlgorithms)
+func WithAlgorithms(algorithms ...string) JWTOption {
+	return func(c *jwtConfig) {
+		c.algorithms = algorithms
+	}
+}
+
+// WithLeeway tolerates clock skew when validating "exp", "nbf" and "iat"
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(c *jwtConfig) {
+		c.leeway = leeway
+	}
+}
+
+// JWT returns a function authenticating callers by a bearer JWT signed with a key of keys.
+// Tokens must not be expired. The Principal carries the "sub" claim and all claims.
+func JWT(keys *JWKS, opts ...JWTOption) Func {
+	cfg := &jwtConfig{algorithms: DefaultJWTAlgorithms}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.leeway),
+	}
+	if cfg.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(cfg.issuer))
+	}
+	if cfg.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(cfg.audience))
+	}
+	parser := jwt.NewParser(parserOpts...)
+
+	return func(ctx context.Context) (context.Context, error) {
+		raw, err := BearerToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		claims := jwt.MapClaims{}
+		_, err = parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return keys.Key(ctx, kid)
+		})
+		if errors.Is(err, errKeysUnavailable) {
+			return nil, status.Error(codes.Unavailable, "signing keys unavailable")
+		}
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token: "+err.Error())
+		}
+
+		subject, _ := claims.GetSubject()
+		return WithPrincipal(ctx, &Principal{Subject: subject, Method: "jwt", Claims: claims}), nil
+	}
+}
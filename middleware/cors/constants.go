@@ -0,0 +1,15 @@
+package cors
+
+// Defaults of the middleware
+var (
+	// DefaultAllowedMethods are allowed in cross-origin requests unless WithAllowedMethods is used
+	DefaultAllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	// DefaultAllowedHeaders are allowed in cross-origin requests unless WithAllowedHeaders is used
+	DefaultAllowedHeaders = []string{"Content-Type", "Authorization"}
+)
+
+// Headers of gRPC-Web calls, allowed and exposed by WithGRPCWeb
+var (
+	GRPCWebRequestHeaders  = []string{"X-Grpc-Web", "X-User-Agent", "Grpc-Timeout"}
+	GRPCWebResponseHeaders = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+)
@@ -0,0 +1,157 @@
+package cors
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Option configures the middleware
+type Option func(*config)
+
+// WithAllowedMethods sets the methods allowed in cross-origin requests
+func WithAllowedMethods(methods ...string) Option {
+	return func(c *config) {
+		c.methods = methods
+	}
+}
+
+// WithAllowedHeaders sets the request headers allowed in cross-origin requests.
+// "*" allows the headers requested by the preflight request.
+func WithAllowedHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.headers = headers
+	}
+}
+
+// WithExposedHeaders sets the response headers readable by the scripts of other origins
+func WithExposedHeaders(headers ...string) Option {
+	return func(c *config) {
+		c.exposed = append(c.exposed, headers...)
+	}
+}
+
+// WithAllowCredentials allows cross-origin requests with cookies and HTTP authentication.
+// The allowed origins are then always echoed, as browsers reject "*" with credentials.
+func WithAllowCredentials(allow bool) Option {
+	return func(c *config) {
+		c.credentials = allow
+	}
+}
+
+// WithMaxAge sets how long browsers cache preflight responses; zero leaves it to the browser
+func WithMaxAge(d time.Duration) Option {
+	return func(c *config) {
+		c.maxAge = d
+	}
+}
+
+// WithOriginPatterns allows the origins matching any of the patterns, e.g.
+// regexp.MustCompile(`^https://pr-\d+\.preview\.example\.com$`)
+func WithOriginPatterns(patterns ...*regexp.Regexp) Option {
+	return func(c *config) {
+		c.patterns = append(c.patterns, patterns...)
+	}
+}
+
+// WithPreflightPassthrough passes preflight requests to the next handler after setting
+// the CORS headers, instead of responding with 204 No Content
+func WithPreflightPassthrough(passthrough bool) Option {
+	return func(c *config) {
+		c.passthrough = passthrough
+	}
+}
+
+// WithGRPCWeb allows the request headers and exposes the response headers of gRPC-Web calls
+func WithGRPCWeb() Option {
+	return func(c *config) {
+		c.grpcWeb = true
+	}
+}
+
+type config struct {
+	methods     []string
+	headers     []string
+	exposed     []string
+	credentials bool
+	maxAge      time.Duration
+	patterns    []*regexp.Regexp
+	passthrough bool
+	grpcWeb     bool
+
+	// Origins by kind: "*", exact origins and wildcard origins, e.g. "https://*.example.com"
+	anyOrigin bool
+	origins   map[string]bool
+	wildcards []wildcard
+}
+
+// wildcard is an origin with one "*", e.g. "https://*.example.com"
+type wildcard struct {
+	prefix, suffix string
+}
+
+func (w wildcard) match(origin string) bool {
+	return len(origin) >= len(w.prefix)+len(w.suffix) &&
+		strings.HasPrefix(origin, w.prefix) && strings.HasSuffix(origin, w.suffix)
+}
+
+func newConfig(origins []string, opts ...Option) *config {
+	c := &config{
+		methods: DefaultAllowedMethods,
+		headers: DefaultAllowedHeaders,
+		origins: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, origin := range origins {
+		switch {
+		case origin == "*":
+			c.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			prefix, suffix, _ := strings.Cut(strings.ToLower(origin), "*")
+			c.wildcards = append(c.wildcards, wildcard{prefix: prefix, suffix: suffix})
+		default:
+			c.origins[strings.ToLower(origin)] = true
+		}
+	}
+
+	if c.grpcWeb {
+		c.headers = append(append([]string(nil), c.headers...), GRPCWebRequestHeaders...)
+		c.exposed = append(c.exposed, GRPCWebResponseHeaders...)
+	}
+	return c
+}
+
+// allowed reports whether requests of origin are allowed
+func (c *config) allowed(origin string) bool {
+	if c.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if c.origins[origin] {
+		return true
+	}
+	for _, w := range c.wildcards {
+		if w.match(origin) {
+			return true
+		}
+	}
+	for _, p := range c.patterns {
+		if p.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsAnyHeader reports whether the headers requested by preflight requests are allowed
+func (c *config) allowsAnyHeader() bool {
+	for _, h := range c.headers {
+		if h == "*" {
+			return true
+		}
+	}
+	return false
+}
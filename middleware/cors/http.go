@@ -1,27 +1,61 @@
 package cors
 
-import "net/http"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Middleware creates middleware for handling CORS. Origins are exact origins, "*" allowing any origin,
+// or wildcard origins, e.g. "https://*.example.com"; options configure the rest.
+// Preflight requests are answered with 204 No Content without calling the next handler.
+func Middleware(origins []string, opts ...Option) func(http.Handler) http.Handler {
+	c := newConfig(origins, opts...)
+	methods := strings.Join(c.methods, ", ")
+	headers := strings.Join(c.headers, ", ")
+	exposed := strings.Join(c.exposed, ", ")
 
-// Middleware creates middleware for handling CORS
-func Middleware(origins []string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Set CORS headers
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-
-			// Check if the origin is allowed
 			origin := r.Header.Get("Origin")
-			for _, allowed := range origins {
-				if allowed == "*" || allowed == origin {
-					w.Header().Set("Access-Control-Allow-Origin", origin)
-					break
+			preflight := r.Method == http.MethodOptions && origin != "" &&
+				r.Header.Get("Access-Control-Request-Method") != ""
+
+			// Responses depend on the origin unless every origin gets the same headers
+			h := w.Header()
+			if !c.anyOrigin || c.credentials {
+				h.Add("Vary", "Origin")
+			}
+
+			if origin != "" && c.allowed(origin) {
+				h.Set("Access-Control-Allow-Origin", origin)
+				if c.credentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+
+				if preflight {
+					h.Add("Vary", "Access-Control-Request-Method")
+					h.Add("Vary", "Access-Control-Request-Headers")
+					h.Set("Access-Control-Allow-Methods", methods)
+					if c.allowsAnyHeader() {
+						if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+							h.Set("Access-Control-Allow-Headers", requested)
+						}
+					} else {
+						h.Set("Access-Control-Allow-Headers", headers)
+					}
+					if c.maxAge > 0 {
+						h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.maxAge/time.Second)))
+					}
+				} else if exposed != "" {
+					h.Set("Access-Control-Expose-Headers", exposed)
 				}
 			}
 
-			// Handle preflight requests
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
+			// Handle preflight requests; other OPTIONS requests reach the handler
+			if preflight && !c.passthrough {
+				w.WriteHeader(http.StatusNoContent)
 				return
 			}
 
@@ -0,0 +1,69 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := Middleware([]string{"https://app.example.com", "https://*.staging.example.com"},
+		WithOriginPatterns(regexp.MustCompile(`^https://pr-\d+\.preview\.example\.com$`)),
+		WithAllowCredentials(true),
+		WithMaxAge(10*time.Minute),
+		WithGRPCWeb(),
+	)(next)
+
+	serve := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/orders.v1.OrderService/GetOrder", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, tt := range []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://web.staging.example.com", true},
+		{"https://pr-42.preview.example.com", true},
+		{"https://evil.example.com", false},
+		{"https://staging.example.com.evil.io", false},
+	} {
+		rec := serve(http.MethodPost, tt.origin, false)
+		if got := rec.Header().Get("Access-Control-Allow-Origin") == tt.origin; got != tt.allowed {
+			t.Errorf("origin %s allowed = %v, want %v", tt.origin, got, tt.allowed)
+		}
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("origin %s status = %d, want the handler", tt.origin, rec.Code)
+		}
+	}
+
+	rec := serve(http.MethodPost, "https://app.example.com", false)
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "true" || rec.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Errorf("response headers = %v, want credentials and exposed headers", rec.Header())
+	}
+
+	rec = serve(http.MethodOptions, "https://app.example.com", true)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Max-Age") != "600" ||
+		rec.Header().Get("Access-Control-Allow-Headers") != "Content-Type, Authorization, X-Grpc-Web, X-User-Agent, Grpc-Timeout" {
+		t.Errorf("preflight = %d %v", rec.Code, rec.Header())
+	}
+
+	// OPTIONS requests that aren't preflights reach the handler
+	if rec := serve(http.MethodOptions, "", false); rec.Code != http.StatusTeapot {
+		t.Errorf("OPTIONS status = %d, want the handler", rec.Code)
+	}
+}
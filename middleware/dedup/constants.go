@@ -0,0 +1,15 @@
+package dedup
+
+import "time"
+
+// Constants for request deduplication
+const (
+	// Header is the gRPC metadata header carrying the client-generated message ID
+	Header = "X-Message-ID"
+
+	// DefaultTTL is the default time a response is kept for replay
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultReservationTTL is the default time a message ID stays reserved while its request is handled
+	DefaultReservationTTL = time.Minute
+)
@@ -0,0 +1,211 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Interceptor replays cached responses for retried requests carrying the same message ID
+type Interceptor struct {
+	store          Store
+	ttl            time.Duration
+	reservationTTL time.Duration
+	methods        map[string]struct{}
+}
+
+// Option configures the Interceptor
+type Option func(*Interceptor)
+
+// WithTTL sets how long responses are kept for replay
+func WithTTL(ttl time.Duration) Option {
+	return func(i *Interceptor) {
+		i.ttl = ttl
+	}
+}
+
+// WithReservationTTL sets how long a message ID stays reserved while its request is handled.
+// It should exceed the longest handler run, since a duplicate arriving after it expires is handled again.
+func WithReservationTTL(ttl time.Duration) Option {
+	return func(i *Interceptor) {
+		i.reservationTTL = ttl
+	}
+}
+
+// WithMethods restricts deduplication to the given full method names
+// (e.g. "/orders.v1.OrderService/CreateOrder"). By default all unary methods are deduplicated.
+func WithMethods(methods ...string) Option {
+	return func(i *Interceptor) {
+		for _, m := range methods {
+			i.methods[m] = struct{}{}
+		}
+	}
+}
+
+// NewInterceptor creates a new deduplication interceptor backed by the given store
+func NewInterceptor(store Store, opts ...Option) *Interceptor {
+	i := &Interceptor{
+		store:          store,
+		ttl:            DefaultTTL,
+		reservationTTL: DefaultReservationTTL,
+		methods:        make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor that returns the stored
+// response when a request with an already processed message ID is retried.
+// The message ID is reserved while the handler runs, so a duplicate arriving meanwhile fails
+// with codes.Aborted instead of running the handler again.
+// Only successful responses are stored, so failed calls can be retried with the same ID.
+// Store errors do not fail the request: the handler is called as if no response was stored.
+func (i *Interceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !i.applies(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		messageID := extractMessageID(ctx)
+		if messageID == "" {
+			return handler(ctx, req)
+		}
+
+		reqMsg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		fingerprint, err := requestFingerprint(reqMsg)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		key := info.FullMethod + ":" + messageID
+
+		reserved, err := i.store.Reserve(ctx, key, i.reservationTTL)
+		if err != nil {
+			return handler(ctx, req)
+		}
+		if !reserved {
+			data, found, err := i.store.Get(ctx, key)
+			switch {
+			case err != nil:
+				return handler(ctx, req)
+			case !found || len(data) == 0:
+				// Not found means the reservation was released a moment ago, e.g. after a failure
+				return nil, status.Error(codes.Aborted, "request with the same message ID is in progress")
+			default:
+				return replay(data, fingerprint)
+			}
+		}
+
+		// The reservation is released unless the response is stored, also if the handler panics
+		stored := false
+		defer func() {
+			if !stored {
+				_ = i.store.Delete(context.WithoutCancel(ctx), key)
+			}
+		}()
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		if respMsg, ok := resp.(proto.Message); ok {
+			if data, err := encodeEntry(fingerprint, respMsg); err == nil {
+				stored = i.store.Set(ctx, key, data, i.ttl) == nil
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+// UnaryServerInterceptorFunc returns a gRPC unary server interceptor function
+// for convenience when you don't need the Interceptor struct
+func UnaryServerInterceptorFunc(store Store, opts ...Option) grpc.UnaryServerInterceptor {
+	return NewInterceptor(store, opts...).UnaryServerInterceptor()
+}
+
+func (i *Interceptor) applies(fullMethod string) bool {
+	if len(i.methods) == 0 {
+		return true
+	}
+	_, ok := i.methods[fullMethod]
+	return ok
+}
+
+// extractMessageID extracts the message ID from gRPC metadata
+func extractMessageID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(Header)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// requestFingerprint hashes the deterministic wire form of the request
+func requestFingerprint(req proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+// encodeEntry serializes the request fingerprint followed by the response wrapped into anypb.Any
+func encodeEntry(fingerprint []byte, resp proto.Message) ([]byte, error) {
+	wrapped, err := anypb.New(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := proto.Marshal(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(make([]byte, 0, len(fingerprint)+len(b)), fingerprint...), b...), nil
+}
+
+// replay decodes a stored entry and returns the cached response
+func replay(data, fingerprint []byte) (any, error) {
+	if len(data) < sha256.Size {
+		return nil, status.Error(codes.Internal, "corrupted deduplication entry")
+	}
+
+	if !bytes.Equal(data[:sha256.Size], fingerprint) {
+		return nil, status.Error(codes.FailedPrecondition, "message ID was already used with a different request")
+	}
+
+	var wrapped anypb.Any
+	if err := proto.Unmarshal(data[sha256.Size:], &wrapped); err != nil {
+		return nil, status.Error(codes.Internal, "corrupted deduplication entry")
+	}
+
+	resp, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to decode cached response")
+	}
+
+	return resp, nil
+}
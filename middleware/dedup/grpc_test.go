@@ -0,0 +1,209 @@
+package dedup
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testMethod = "/test.v1.TestService/Create"
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptorFunc(NewMemoryStore())
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return wrapperspb.Int64(int64(calls)), nil
+	}
+
+	call := func(messageID string, req proto.Message) (any, error) {
+		ctx := context.Background()
+		if messageID != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(Header, messageID))
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+
+	t.Run("Replays response for retried message ID", func(t *testing.T) {
+		first, err := call("msg-1", wrapperspb.String("order"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := call("msg-1", wrapperspb.String("order"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !proto.Equal(first.(proto.Message), second.(proto.Message)) {
+			t.Errorf("expected replayed response %v, got %v", first, second)
+		}
+		if calls != 1 {
+			t.Errorf("expected handler to be called once, got %d", calls)
+		}
+	})
+
+	t.Run("Rejects reused message ID with different request", func(t *testing.T) {
+		_, err := call("msg-1", wrapperspb.String("another order"))
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected FailedPrecondition, got %v", err)
+		}
+	})
+
+	t.Run("Calls handler without message ID", func(t *testing.T) {
+		before := calls
+		_, _ = call("", wrapperspb.String("order"))
+		_, _ = call("", wrapperspb.String("order"))
+		if calls != before+2 {
+			t.Errorf("expected handler to be called twice, got %d", calls-before)
+		}
+	})
+}
+
+func TestUnaryServerInterceptorDoesNotCacheErrors(t *testing.T) {
+	interceptor := UnaryServerInterceptorFunc(NewMemoryStore())
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "msg-1"))
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		if calls == 1 {
+			return nil, status.Error(codes.Unavailable, "try again")
+		}
+		return wrapperspb.Bool(true), nil
+	}
+
+	if _, err := interceptor(ctx, wrapperspb.String("order"), info, handler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if _, err := interceptor(ctx, wrapperspb.String("order"), info, handler); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected handler to be called twice, got %d", calls)
+	}
+}
+
+func TestUnaryServerInterceptorRejectsConcurrentDuplicate(t *testing.T) {
+	interceptor := UnaryServerInterceptorFunc(NewMemoryStore())
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "msg-1"))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	handler := func(ctx context.Context, req any) (any, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return wrapperspb.Bool(true), nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := interceptor(ctx, wrapperspb.String("order"), info, handler)
+		done <- err
+	}()
+	<-started
+
+	if _, err := interceptor(ctx, wrapperspb.String("order"), info, handler); status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted while the first request runs, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := interceptor(ctx, wrapperspb.String("order"), info, handler)
+	if err != nil {
+		t.Fatalf("expected replayed response, got %v", err)
+	}
+	if !proto.Equal(resp.(proto.Message), wrapperspb.Bool(true)) {
+		t.Errorf("expected replayed response true, got %v", resp)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected handler to be called once, got %d", got)
+	}
+}
+
+func TestUnaryServerInterceptorReleasesReservationOnPanic(t *testing.T) {
+	interceptor := UnaryServerInterceptorFunc(NewMemoryStore())
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "msg-1"))
+
+	func() {
+		defer func() { _ = recover() }()
+		_, _ = interceptor(ctx, wrapperspb.String("order"), info, func(ctx context.Context, req any) (any, error) {
+			panic("boom")
+		})
+	}()
+
+	_, err := interceptor(ctx, wrapperspb.String("order"), info, func(ctx context.Context, req any) (any, error) {
+		return wrapperspb.Bool(true), nil
+	})
+	if err != nil {
+		t.Fatalf("expected retry after a panic to run, got %v", err)
+	}
+}
+
+func TestMemoryStoreReserve(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if ok, _ := store.Reserve(ctx, "k", time.Minute); !ok {
+		t.Fatal("expected first reservation to succeed")
+	}
+	if ok, _ := store.Reserve(ctx, "k", time.Minute); ok {
+		t.Error("expected second reservation to fail")
+	}
+	if value, found, _ := store.Get(ctx, "k"); !found || len(value) != 0 {
+		t.Errorf("expected reserved key with empty value, got %v %v", value, found)
+	}
+
+	_ = store.Set(ctx, "k", []byte("response"), time.Hour)
+	if ok, _ := store.Reserve(ctx, "k", time.Minute); ok {
+		t.Error("expected reservation of a stored key to fail")
+	}
+
+	_ = store.Delete(ctx, "k")
+	if ok, _ := store.Reserve(ctx, "k", time.Minute); !ok {
+		t.Error("expected reservation after Delete to succeed")
+	}
+
+	// An expired reservation can be taken again
+	now = now.Add(2 * time.Minute)
+	if ok, _ := store.Reserve(ctx, "k", time.Minute); !ok {
+		t.Error("expected reservation after expiry to succeed")
+	}
+}
+
+func TestMemoryStoreEvictsExpiredEntries(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_ = store.Set(ctx, "stored", []byte("response"), time.Second)
+	_, _ = store.Reserve(ctx, "reserved", time.Second)
+
+	now = now.Add(evictInterval + time.Second)
+	_ = store.Set(ctx, "fresh", []byte("response"), time.Hour)
+
+	if len(store.entries) != 1 {
+		t.Errorf("expected only the fresh entry to be kept, got %d entries", len(store.entries))
+	}
+}
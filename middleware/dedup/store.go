@@ -0,0 +1,122 @@
+package dedup
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store persists serialized responses keyed by message ID.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the stored value and true if the key exists and has not expired.
+	// The value of a reserved key is empty.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value for the given TTL, replacing a reservation.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Reserve atomically stores an empty value for the given TTL if the key does not exist,
+	// like SET NX in Redis, and reports whether it did.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Delete removes the key.
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore keeps responses and reservations in the memory of the process. A retry routed
+// to another replica does not see them and runs the handler again, so services with several
+// replicas need a Store shared between them.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastEvict time.Time
+	now       func() time.Time
+}
+
+// memoryEntry is a stored response, or a reservation if value is empty
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// evictInterval is how often Set and Reserve drop expired entries.
+// Most message IDs are never retried, so their entries are not removed by Get.
+const evictInterval = time.Minute
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the stored value if it exists and has not expired
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if s.now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// Set stores the response, replacing the reservation of the key
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evictExpired(now)
+
+	s.entries[key] = memoryEntry{
+		value:     value,
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+// Reserve stores an empty value if the key does not exist or has expired
+func (s *MemoryStore) Reserve(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evictExpired(now)
+	if entry, ok := s.entries[key]; ok && !now.After(entry.expiresAt) {
+		return false, nil
+	}
+
+	s.entries[key] = memoryEntry{expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// Delete removes the key
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// evictExpired drops expired entries at most once per evictInterval. It must be called with mu held.
+func (s *MemoryStore) evictExpired(now time.Time) {
+	if now.Sub(s.lastEvict) < evictInterval {
+		return
+	}
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastEvict = now
+}
@@ -0,0 +1,19 @@
+package priority
+
+import "time"
+
+// Constants for priority scheduling
+const (
+	// Header is the gRPC metadata header carrying the priority class of a call
+	Header = "X-Priority"
+
+	// DefaultMaxWaitTime is the default time a call waits for a free slot of its class
+	DefaultMaxWaitTime = 100 * time.Millisecond
+)
+
+// Names of the default priority classes
+const (
+	ClassCritical    = "critical"
+	ClassInteractive = "interactive"
+	ClassBatch       = "batch"
+)
@@ -0,0 +1,218 @@
+// Package priority schedules gRPC calls by priority class. Each class has its own concurrency quota
+// backed by a bulkhead, and lower classes are shed first when the server as a whole is busy,
+// so batch clients can't starve interactive traffic.
+package priority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rshelekhov/golib/resilience/bulkhead"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// meterName is the instrumentation scope of the priority metrics
+const meterName = "github.com/rshelekhov/golib/middleware/priority"
+
+// Outcomes of the priority_requests_total metric
+const (
+	outcomeAdmitted = "admitted"
+	outcomeShed     = "shed"
+	outcomeRejected = "rejected"
+)
+
+// Class is a priority class with its own concurrency quota
+type Class struct {
+	// Name is the value of the priority header selecting the class, e.g. "batch"
+	Name string
+	// MaxConcurrent is the number of calls of the class handled at once
+	MaxConcurrent int
+	// MaxWaiting is the number of calls waiting for a free slot of the class; more are rejected
+	MaxWaiting int
+	// ShedAt is the load of the server, the share of all quotas in use, from which calls
+	// of the class are rejected without waiting, e.g. 0.6 for batch traffic. Zero never sheds.
+	ShedAt float64
+}
+
+// DefaultClasses favor interactive traffic: batch calls are shed once the server is 60% busy,
+// interactive ones at 90%, critical ones only when their own quota is exhausted
+func DefaultClasses() []Class {
+	return []Class{
+		{Name: ClassCritical, MaxConcurrent: 50, MaxWaiting: 50},
+		{Name: ClassInteractive, MaxConcurrent: 100, MaxWaiting: 100, ShedAt: 0.9},
+		{Name: ClassBatch, MaxConcurrent: 20, MaxWaiting: 20, ShedAt: 0.6},
+	}
+}
+
+// Scheduler admits calls according to their priority class
+type Scheduler struct {
+	header       string
+	defaultClass string
+	maxWaitTime  time.Duration
+	classes      map[string]*class
+	capacity     int
+
+	requests metric.Int64Counter
+}
+
+type class struct {
+	Class
+	bulkhead *bulkhead.Bulkhead
+}
+
+// Option configures the Scheduler
+type Option func(*Scheduler)
+
+// WithHeader sets the metadata key carrying the priority class (default: Header)
+func WithHeader(name string) Option {
+	return func(s *Scheduler) {
+		s.header = name
+	}
+}
+
+// WithDefaultClass sets the class of calls without a known priority (default: ClassInteractive)
+func WithDefaultClass(name string) Option {
+	return func(s *Scheduler) {
+		s.defaultClass = name
+	}
+}
+
+// WithMaxWaitTime limits how long a call waits for a free slot (default: DefaultMaxWaitTime).
+// Zero waits until the call's deadline.
+func WithMaxWaitTime(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.maxWaitTime = d
+	}
+}
+
+// NewScheduler creates a scheduler with the given classes, DefaultClasses if none are given
+func NewScheduler(classes []Class, opts ...Option) (*Scheduler, error) {
+	if len(classes) == 0 {
+		classes = DefaultClasses()
+	}
+
+	s := &Scheduler{
+		header:       Header,
+		defaultClass: ClassInteractive,
+		maxWaitTime:  DefaultMaxWaitTime,
+		classes:      make(map[string]*class, len(classes)),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for _, c := range classes {
+		if c.Name == "" || c.MaxConcurrent <= 0 {
+			return nil, fmt.Errorf("invalid priority class %q: name and max concurrency are required", c.Name)
+		}
+		if _, ok := s.classes[c.Name]; ok {
+			return nil, fmt.Errorf("duplicate priority class %q", c.Name)
+		}
+		s.classes[c.Name] = &class{
+			Class: c,
+			bulkhead: bulkhead.New("priority_"+c.Name, c.MaxConcurrent,
+				bulkhead.WithMaxWaiting(c.MaxWaiting),
+				bulkhead.WithMaxWaitTime(s.maxWaitTime),
+			),
+		}
+		s.capacity += c.MaxConcurrent
+	}
+	if _, ok := s.classes[s.defaultClass]; !ok {
+		return nil, fmt.Errorf("default priority class %q is not defined", s.defaultClass)
+	}
+
+	var err error
+	s.requests, err = otel.GetMeterProvider().Meter(meterName).Int64Counter(
+		"priority_requests_total",
+		metric.WithDescription("Total number of calls by priority class and outcome: admitted, shed or rejected."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return s, nil
+}
+
+// load returns the share of all class quotas in use
+func (s *Scheduler) load() float64 {
+	inFlight := 0
+	for _, c := range s.classes {
+		inFlight += c.bulkhead.InFlight()
+	}
+	return float64(inFlight) / float64(s.capacity)
+}
+
+// classOf returns the class requested in the incoming metadata or the default class
+func (s *Scheduler) classOf(ctx context.Context) *class {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(s.header); len(values) > 0 {
+			if c, ok := s.classes[values[0]]; ok {
+				return c
+			}
+		}
+	}
+	return s.classes[s.defaultClass]
+}
+
+// acquire admits a call or returns a ResourceExhausted status. A nil error must be followed by c.release.
+func (s *Scheduler) acquire(ctx context.Context) (*class, error) {
+	c := s.classOf(ctx)
+
+	if c.ShedAt > 0 && s.load() >= c.ShedAt {
+		s.record(ctx, c, outcomeShed)
+		return nil, status.Errorf(codes.ResourceExhausted, "server is busy, %s priority calls are shed", c.Name)
+	}
+
+	if err := c.bulkhead.Acquire(ctx); err != nil {
+		if !errors.Is(err, bulkhead.ErrFull) {
+			return nil, status.FromContextError(err).Err()
+		}
+		s.record(ctx, c, outcomeRejected)
+		return nil, status.Errorf(codes.ResourceExhausted, "%s priority quota exhausted", c.Name)
+	}
+
+	s.record(ctx, c, outcomeAdmitted)
+	return c, nil
+}
+
+func (s *Scheduler) record(ctx context.Context, c *class, outcome string) {
+	s.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("class", c.Name),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor admitting calls by priority class
+func (s *Scheduler) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		c, err := s.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer c.bulkhead.Release(ctx)
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor admitting streams by priority class.
+// A stream holds its slot until it ends.
+func (s *Scheduler) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c, err := s.acquire(ss.Context())
+		if err != nil {
+			return err
+		}
+		defer c.bulkhead.Release(ss.Context())
+
+		return handler(srv, ss)
+	}
+}
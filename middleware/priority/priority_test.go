@@ -0,0 +1,90 @@
+package priority
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestScheduler(t *testing.T) {
+	s, err := NewScheduler([]Class{
+		{Name: ClassInteractive, MaxConcurrent: 3},
+		{Name: ClassBatch, MaxConcurrent: 2, ShedAt: 0.5},
+	})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	interceptor := s.UnaryServerInterceptor()
+
+	withClass := func(name string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, name))
+	}
+
+	// Hold calls open until released
+	started := make(chan struct{})
+	hold := func(ctx context.Context, release chan struct{}) {
+		go func() {
+			_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+				started <- struct{}{}
+				<-release
+				return nil, nil
+			})
+		}()
+		<-started
+	}
+	call := func(ctx context.Context) codes.Code {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	// Batch fills its own quota, interactive traffic is not affected
+	release := make(chan struct{})
+	hold(withClass(ClassBatch), release)
+	hold(withClass(ClassBatch), release)
+	if code := call(withClass(ClassBatch)); code != codes.ResourceExhausted {
+		t.Errorf("batch call over quota = %v, want ResourceExhausted", code)
+	}
+	if code := call(context.Background()); code != codes.OK {
+		t.Errorf("call without priority = %v, want OK", code)
+	}
+	close(release)
+	for s.load() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Busy interactive traffic sheds batch calls although the batch quota is free
+	release = make(chan struct{})
+	hold(withClass(ClassInteractive), release)
+	hold(withClass(ClassInteractive), release)
+	hold(withClass(ClassInteractive), release)
+	if code := call(withClass(ClassBatch)); code != codes.ResourceExhausted {
+		t.Errorf("batch call under load = %v, want ResourceExhausted", code)
+	}
+	close(release)
+}
+
+func TestNewSchedulerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []Class
+		opts    []Option
+	}{
+		{name: "missing quota", classes: []Class{{Name: ClassInteractive}}},
+		{name: "duplicate", classes: []Class{{Name: ClassInteractive, MaxConcurrent: 1}, {Name: ClassInteractive, MaxConcurrent: 1}}},
+		{name: "unknown default", classes: []Class{{Name: ClassBatch, MaxConcurrent: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewScheduler(tt.classes, tt.opts...); err == nil {
+				t.Error("NewScheduler() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package ratelimit
+
+// Constants for rate limiting
+const (
+	// DefaultKeyPrefix is prepended to bucket keys in the limiter
+	DefaultKeyPrefix = "ratelimit:"
+)
+
+// DefaultExempt are the gRPC services never limited: health checks and reflection
+var DefaultExempt = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
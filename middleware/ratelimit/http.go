@@ -0,0 +1,27 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// Handler wraps next, rejecting requests beyond the limit of their route with
+// 429 Too Many Requests and a Retry-After header. The route is the pattern matched
+// by http.ServeMux, or the path if there is none.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Pattern
+		if route == "" {
+			route = r.URL.Path
+		}
+
+		allowed, wait := l.allow(r.Context(), route, l.httpKey(r, route))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
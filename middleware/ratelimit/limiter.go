@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst tokens.
+// Every request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerSecond returns a limit of n requests per second with bursts of n
+func PerSecond(n int) Limit {
+	return Limit{Rate: float64(n), Burst: n}
+}
+
+// PerMinute returns a limit of n requests per minute with bursts of n
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Limiter takes tokens from the buckets of keys.
+// Implementations must be safe for concurrent use.
+type Limiter interface {
+	// Allow takes a token from the bucket of key. If the bucket is empty, it returns false
+	// and the time until a token is available.
+	Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// MemoryLimiter is an in-process Limiter.
+// It is suitable for single-instance services and tests; use RedisLimiter
+// to share limits between the replicas of a service.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // When the bucket is full again, after which it can be evicted
+}
+
+var _ Limiter = (*MemoryLimiter)(nil)
+
+// memorySweepInterval limits how often Allow scans for full buckets
+const memorySweepInterval = time.Minute
+
+// NewMemoryLimiter creates a new in-memory limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]memoryBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket and periodically evicts full buckets
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Rate <= 0 {
+		return false, 0, fmt.Errorf("rate of %s must be positive", key)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= memorySweepInterval {
+		for k, b := range l.buckets {
+			if !now.Before(b.full) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = memoryBucket{tokens: float64(limit.Burst), updated: now}
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	var wait time.Duration
+	if allowed {
+		b.tokens--
+	} else {
+		wait = tokenWait(1-b.tokens, limit.Rate)
+	}
+	b.full = now.Add(tokenWait(float64(limit.Burst)-b.tokens, limit.Rate))
+	l.buckets[key] = b
+
+	return allowed, wait, nil
+}
+
+// tokenWait returns the time to refill n tokens at rate
+func tokenWait(n, rate float64) time.Duration {
+	return time.Duration(math.Ceil(n / rate * float64(time.Second)))
+}
@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/peer"
+)
+
+// meterName is the instrumentation scope of the rate limiting metrics
+const meterName = "github.com/rshelekhov/golib/middleware/ratelimit"
+
+// KeyFunc returns the bucket key of a gRPC call, without the key prefix
+type KeyFunc func(ctx context.Context, fullMethod string) string
+
+// HTTPKeyFunc returns the bucket key of an HTTP request matching route, without the key prefix
+type HTTPKeyFunc func(r *http.Request, route string) string
+
+// MethodKey shares a bucket between all callers of a method
+func MethodKey(_ context.Context, fullMethod string) string {
+	return fullMethod
+}
+
+// MethodPeerKey gives every client IP its own bucket per method
+func MethodPeerKey(ctx context.Context, fullMethod string) string {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = hostOf(p.Addr.String())
+	}
+	return fullMethod + "|" + ip
+}
+
+// RouteKey shares a bucket between all clients of a route
+func RouteKey(_ *http.Request, route string) string {
+	return route
+}
+
+// RouteIPKey gives every client IP, taken from the connection, its own bucket per route.
+// Behind a proxy, use a HTTPKeyFunc reading the client IP from the headers set by the proxy.
+func RouteIPKey(r *http.Request, route string) string {
+	return route + "|" + hostOf(r.RemoteAddr)
+}
+
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// RateLimiter limits gRPC calls per method and HTTP requests per route with token buckets
+type RateLimiter struct {
+	limiter       Limiter
+	limit         Limit
+	limits        map[string]Limit
+	exempt        []string
+	keyPrefix     string
+	key           KeyFunc
+	httpKey       HTTPKeyFunc
+	meterProvider metric.MeterProvider
+
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+}
+
+// Option configures the RateLimiter
+type Option func(*RateLimiter)
+
+// WithLimit overrides the default limit for a gRPC full method name
+// (e.g. "/orders.v1.OrderService/CreateOrder") or an HTTP route (e.g. "POST /v1/orders")
+func WithLimit(name string, limit Limit) Option {
+	return func(l *RateLimiter) {
+		l.limits[name] = limit
+	}
+}
+
+// WithExempt adds gRPC methods or HTTP routes that are never limited. Names ending with "/"
+// exempt every method of a service, e.g. "/grpc.health.v1.Health/". DefaultExempt is always exempt.
+func WithExempt(names ...string) Option {
+	return func(l *RateLimiter) {
+		l.exempt = append(l.exempt, names...)
+	}
+}
+
+// WithKeyPrefix sets the prefix of bucket keys in the limiter
+func WithKeyPrefix(prefix string) Option {
+	return func(l *RateLimiter) {
+		l.keyPrefix = prefix
+	}
+}
+
+// WithKeyFunc sets the bucket key of gRPC calls (default: MethodKey)
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(l *RateLimiter) {
+		l.key = fn
+	}
+}
+
+// WithHTTPKeyFunc sets the bucket key of HTTP requests (default: RouteIPKey)
+func WithHTTPKeyFunc(fn HTTPKeyFunc) Option {
+	return func(l *RateLimiter) {
+		l.httpKey = fn
+	}
+}
+
+// WithMeterProvider sets the provider used to create metrics.
+// The global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(l *RateLimiter) {
+		l.meterProvider = mp
+	}
+}
+
+// New creates a rate limiter taking tokens from limiter, with limit for the methods
+// and routes without their own
+func New(limiter Limiter, limit Limit, opts ...Option) *RateLimiter {
+	l := &RateLimiter{
+		limiter:       limiter,
+		limit:         limit,
+		limits:        make(map[string]Limit),
+		exempt:        append([]string(nil), DefaultExempt...),
+		keyPrefix:     DefaultKeyPrefix,
+		key:           MethodKey,
+		httpKey:       RouteIPKey,
+		meterProvider: otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.initMetrics()
+
+	return l
+}
+
+func (l *RateLimiter) initMetrics() {
+	meter := l.meterProvider.Meter(meterName)
+
+	var err error
+	l.requests, err = meter.Int64Counter(
+		"ratelimit_requests_total",
+		metric.WithDescription("Total number of rate limited requests by decision."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	l.errors, err = meter.Int64Counter(
+		"ratelimit_errors_total",
+		metric.WithDescription("Total number of failed rate limiter calls."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// allow takes a token for the method or route name. Limiter errors do not fail the request.
+func (l *RateLimiter) allow(ctx context.Context, name, key string) (bool, time.Duration) {
+	if l.isExempt(name) {
+		return true, 0
+	}
+
+	limit, ok := l.limits[name]
+	if !ok {
+		limit = l.limit
+	}
+
+	allowed, wait, err := l.limiter.Allow(ctx, l.keyPrefix+key, limit)
+	if err != nil {
+		l.errors.Add(ctx, 1)
+		return true, 0
+	}
+
+	decision := "allowed"
+	if !allowed {
+		decision = "rejected"
+	}
+	l.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("name", name),
+		attribute.String("decision", decision),
+	))
+	return allowed, wait
+}
+
+func (l *RateLimiter) isExempt(name string) bool {
+	for _, e := range l.exempt {
+		if name == e || strings.HasSuffix(e, "/") && strings.HasPrefix(name, e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 2}
+
+	for i := range 2 {
+		if ok, _, _ := l.Allow(context.Background(), "k", limit); !ok {
+			t.Fatalf("Allow() #%d = false, want burst allowed", i+1)
+		}
+	}
+	ok, wait, _ := l.Allow(context.Background(), "k", limit)
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("Allow() = %v, %v, want rejected for 500ms", ok, wait)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _, _ := l.Allow(context.Background(), "k", limit); !ok {
+		t.Error("Allow() after refill = false")
+	}
+	if _, _, err := l.Allow(context.Background(), "k", Limit{}); err == nil {
+		t.Error("Allow() with zero rate error = nil")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	rl := New(NewMemoryLimiter(), Limit{Rate: 1, Burst: 1},
+		WithLimit("/orders.v1.OrderService/ListOrders", PerSecond(100)),
+	)
+	call := func(method string) error {
+		_, err := rl.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(context.Context, any) (any, error) { return nil, nil })
+		return err
+	}
+
+	_ = call("/orders.v1.OrderService/CreateOrder")
+	err := call("/orders.v1.OrderService/CreateOrder")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call code = %v, want ResourceExhausted", status.Code(err))
+	}
+	if details := status.Convert(err).Details(); len(details) != 1 || details[0].(*errdetails.RetryInfo).RetryDelay.AsDuration() <= 0 {
+		t.Errorf("details = %v, want RetryInfo", details)
+	}
+	for _, method := range []string{"/orders.v1.OrderService/ListOrders", "/grpc.health.v1.Health/Check"} {
+		if err := call(method); err != nil {
+			t.Errorf("%s error = %v, want its own limit", method, err)
+		}
+	}
+
+	handler := rl.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/orders", nil))
+		if rec.Code != want {
+			t.Errorf("request #%d = %d, want %d", i+1, rec.Code, want)
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "1" {
+			t.Errorf("Retry-After = %q, want 1", rec.Header().Get("Retry-After"))
+		}
+	}
+}
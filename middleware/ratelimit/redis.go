@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript refills the bucket for the time elapsed since its last update, measured with
+// the Redis clock so that replicas with skewed clocks share the same buckets, and takes a token.
+// The bucket expires once it would be full again.
+var allowScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate / 1000)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) * 1000 / rate) + 1)
+return {allowed, wait}
+`)
+
+// RedisLimiter is a Limiter shared by all replicas of a service
+type RedisLimiter struct {
+	client redis.Scripter
+}
+
+var _ Limiter = (*RedisLimiter)(nil)
+
+// NewRedisLimiter creates a limiter backed by the given Redis client
+// (*redis.Client, *redis.ClusterClient or a redis.UniversalClient)
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+// Allow takes a token from the bucket in Redis
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Rate <= 0 {
+		return false, 0, fmt.Errorf("rate of %s must be positive", key)
+	}
+
+	res, err := allowScript.Run(ctx, l.client, []string{key}, limit.Rate, limit.Burst).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -9,15 +9,15 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// UnaryServerInterceptor creates a gRPC unary interceptor for recovering from panics
-func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+// UnaryServerInterceptor creates a gRPC unary interceptor for recovering from panics.
+// Panics are logged with their stack, recorded on the span of the call and counted.
+func UnaryServerInterceptor(logger *slog.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	rec := newRecoverer(logger, opts...)
+
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.Error("grpc server panic recovered",
-					"error", r,
-					"method", info.FullMethod,
-				)
+				rec.recover(ctx, r, "grpc server panic recovered", "grpc", info.FullMethod)
 
 				err = status.Error(codes.Internal, "Internal server error")
 			}
@@ -28,16 +28,15 @@ func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 }
 
 // StreamServerInterceptor creates a gRPC stream interceptor for recovering from panics
-func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+func StreamServerInterceptor(logger *slog.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	rec := newRecoverer(logger, opts...)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
-				logger.Error("grpc stream server panic recovered",
-					"error", r,
-					"method", info.FullMethod,
-				)
+				rec.recover(ss.Context(), r, "grpc stream server panic recovered", "grpc", info.FullMethod)
 
-				_ = status.Error(codes.Internal, "Internal server error")
+				err = status.Error(codes.Internal, "Internal server error")
 			}
 		}()
 
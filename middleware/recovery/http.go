@@ -5,17 +5,26 @@ import (
 	"net/http"
 )
 
-// Middleware creates middleware for recovering from panics
-func Middleware(logger *slog.Logger) func(http.Handler) http.Handler {
+// Middleware creates middleware for recovering from panics. The method of the counter is
+// the method and pattern of the request, e.g. "GET /v1/orders/{id}", or only the method.
+// http.ErrAbortHandler is passed on, as net/http uses it to abort responses silently.
+func Middleware(logger *slog.Logger, opts ...Option) func(http.Handler) http.Handler {
+	rec := newRecoverer(logger, opts...)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					// Log the error
-					logger.Error("http server panic recovered",
-						"error", err,
+					if err == http.ErrAbortHandler {
+						panic(err)
+					}
+
+					method := r.Method
+					if r.Pattern != "" {
+						method += " " + r.Pattern
+					}
+					rec.recover(r.Context(), err, "http server panic recovered", "http", method,
 						"path", r.URL.Path,
-						"method", r.Method,
 					)
 
 					// Return 500 error
@@ -0,0 +1,120 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// meterName is the instrumentation scope of the recovery metrics
+const meterName = "github.com/rshelekhov/golib/middleware/recovery"
+
+// PanicError is a recovered panic with the stack of the panicking goroutine
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, e.g. a runtime error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Option configures the recovery interceptors and middleware
+type Option func(*recoverer)
+
+// WithMeterProvider sets the provider used to create the panic counter.
+// The global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(r *recoverer) {
+		r.meterProvider = mp
+	}
+}
+
+// WithRecordError sets the function recording panics on the span of the request,
+// e.g. tracing.RecordError to forward them to an error tracker. By default the panic
+// is recorded as a span event with the stack and the span status is set to Error.
+func WithRecordError(fn func(span trace.Span, err error)) Option {
+	return func(r *recoverer) {
+		r.recordError = fn
+	}
+}
+
+// WithRepanic panics again after logging, recording and counting a panic, so that it crashes
+// the process in development instead of turning into an Internal error
+func WithRepanic(repanic bool) Option {
+	return func(r *recoverer) {
+		r.repanic = repanic
+	}
+}
+
+type recoverer struct {
+	logger        *slog.Logger
+	meterProvider metric.MeterProvider
+	recordError   func(span trace.Span, err error)
+	repanic       bool
+	panics        metric.Int64Counter
+}
+
+func newRecoverer(logger *slog.Logger, opts ...Option) *recoverer {
+	r := &recoverer{
+		logger:        logger,
+		meterProvider: otel.GetMeterProvider(),
+		recordError:   recordError,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	// Metrics are best-effort: a failed instrument is replaced by a no-op one
+	r.panics, _ = r.meterProvider.Meter(meterName).Int64Counter("panics_recovered_total",
+		metric.WithDescription("Number of panics recovered in request handlers"))
+
+	return r
+}
+
+// recover logs, records and counts the panic value p of a request handler
+func (r *recoverer) recover(ctx context.Context, p any, msg, protocol, method string, attrs ...any) {
+	err := &PanicError{Value: p, Stack: debug.Stack()}
+
+	r.logger.ErrorContext(ctx, msg, append([]any{
+		"error", fmt.Sprint(p),
+		"method", method,
+		"stack", string(err.Stack),
+	}, attrs...)...)
+
+	r.recordError(trace.SpanFromContext(ctx), err)
+
+	if r.panics != nil {
+		r.panics.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("protocol", protocol),
+			attribute.String("method", method),
+		))
+	}
+
+	if r.repanic {
+		panic(p)
+	}
+}
+
+// recordError records err on the span with the stack of the panic
+func recordError(span trace.Span, err error) {
+	var stack string
+	if pe, ok := err.(*PanicError); ok {
+		stack = string(pe.Stack)
+	}
+	span.RecordError(err, trace.WithAttributes(attribute.String("exception.stacktrace", stack)))
+	span.SetStatus(otelcodes.Error, err.Error())
+}
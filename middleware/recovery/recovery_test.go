@@ -0,0 +1,76 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	reader := sdkmetric.NewManualReader()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	interceptor := UnaryServerInterceptor(logger, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "GetOrder")
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrderService/GetOrder"},
+		func(ctx context.Context, req any) (any, error) {
+			var m map[string]int
+			m["boom"]++
+			return nil, nil
+		})
+	span.End()
+
+	if status.Code(err) != codes.Internal {
+		t.Errorf("code = %v, want Internal", status.Code(err))
+	}
+	if !strings.Contains(logs.String(), "recovery_test.go") {
+		t.Errorf("log misses the stack: %s", logs.String())
+	}
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("span events = %v, want the panic", events)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if sum.DataPoints[0].Value != 1 {
+		t.Errorf("panics_recovered_total = %d, want 1", sum.DataPoints[0].Value)
+	}
+}
+
+func TestMiddlewareRepanic(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+
+	rec := httptest.NewRecorder()
+	Middleware(logger)(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want the panic of the handler", r)
+		}
+	}()
+	Middleware(logger, WithRepanic(true))(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -2,7 +2,12 @@ module github.com/rshelekhov/golib/middleware/validation
 
 go 1.24.2
 
-require google.golang.org/grpc v1.74.2
+require (
+	buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.36.10-20250912141014-52f32327d4b0.1
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250721164621-a45f3dfb1074
+	google.golang.org/grpc v1.74.2
+	google.golang.org/protobuf v1.36.10
+)
 
 require (
 	go.opentelemetry.io/otel v1.37.0 // indirect
@@ -10,6 +15,4 @@ require (
 	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20250721164621-a45f3dfb1074 // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
 )
@@ -4,19 +4,40 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
-// UnaryServerInterceptor creates a gRPC unary interceptor for validating requests
-func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+// UnaryServerInterceptor creates a gRPC unary interceptor for validating requests.
+// Invalid requests fail with InvalidArgument and a BadRequest detail listing the field violations.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	v := newValidator(opts...)
+
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		if validator, ok := req.(interface{ Validate() error }); ok {
-			if err := validator.Validate(); err != nil {
-				return nil, status.Error(codes.InvalidArgument, err.Error())
-			}
+		if err := v.validate(req); err != nil {
+			return nil, err
 		}
 
 		return handler(ctx, req)
 	}
 }
+
+// StreamServerInterceptor creates a gRPC stream interceptor validating every received message
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	v := newValidator(opts...)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss, validator: v})
+	}
+}
+
+// validatingStream validates the messages received from the client
+type validatingStream struct {
+	grpc.ServerStream
+	validator *validator
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.validator.validate(m)
+}
@@ -0,0 +1,163 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Func validates a protobuf message, e.g. protovalidate.Validate
+type Func func(msg proto.Message) error
+
+// Option configures the interceptors
+type Option func(*validator)
+
+// WithValidateFunc validates messages with fn after the methods generated by protoc-gen-validate,
+// so protovalidate runs next to, or instead of, the legacy validation:
+//
+//	validation.WithValidateFunc(func(msg proto.Message) error {
+//		return protovalidate.Validate(msg)
+//	})
+func WithValidateFunc(fn Func) Option {
+	return func(v *validator) {
+		v.fn = fn
+	}
+}
+
+type validator struct {
+	fn Func
+}
+
+func newValidator(opts ...Option) *validator {
+	v := &validator{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// validate returns an InvalidArgument status with the field violations of msg, if any
+func (v *validator) validate(msg any) error {
+	if err := validateLegacy(msg); err != nil {
+		return invalidArgument(err)
+	}
+	if pm, ok := msg.(proto.Message); ok && v.fn != nil {
+		if err := v.fn(pm); err != nil {
+			return invalidArgument(err)
+		}
+	}
+	return nil
+}
+
+// validateLegacy runs the methods generated by protoc-gen-validate, preferring ValidateAll
+// that reports every violation over Validate that stops at the first one
+func validateLegacy(msg any) error {
+	switch m := msg.(type) {
+	case interface{ ValidateAll() error }:
+		return m.ValidateAll()
+	case interface{ Validate() error }:
+		return m.Validate()
+	}
+	return nil
+}
+
+// invalidArgument converts a validation error into an InvalidArgument status with
+// a BadRequest detail listing the field violations
+func invalidArgument(err error) error {
+	// Errors of validators with their own status, e.g. Unavailable, are kept
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	st := status.New(codes.InvalidArgument, err.Error())
+	if violations := FieldViolations(err); len(violations) > 0 {
+		if detailed, derr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); derr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// FieldViolations returns the field violations of a protovalidate or protoc-gen-validate error,
+// or nil if err doesn't describe fields
+func FieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	var pv interface{ ToProto() *validate.Violations }
+	if errors.As(err, &pv) {
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, v := range pv.ToProto().GetViolations() {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       fieldPath(v.GetField()),
+				Description: v.GetMessage(),
+				Reason:      v.GetRuleId(),
+			})
+		}
+		return violations
+	}
+	return legacyViolations("", err)
+}
+
+// fieldPath formats a protovalidate field path, e.g. "items[0].sku" or `labels["env"]`
+func fieldPath(path *validate.FieldPath) string {
+	var b strings.Builder
+	for _, e := range path.GetElements() {
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(e.GetFieldName())
+
+		switch s := e.GetSubscript().(type) {
+		case *validate.FieldPathElement_Index:
+			fmt.Fprintf(&b, "[%d]", s.Index)
+		case *validate.FieldPathElement_BoolKey:
+			fmt.Fprintf(&b, "[%t]", s.BoolKey)
+		case *validate.FieldPathElement_IntKey:
+			fmt.Fprintf(&b, "[%d]", s.IntKey)
+		case *validate.FieldPathElement_UintKey:
+			fmt.Fprintf(&b, "[%d]", s.UintKey)
+		case *validate.FieldPathElement_StringKey:
+			fmt.Fprintf(&b, "[%q]", s.StringKey)
+		}
+	}
+	return b.String()
+}
+
+// legacyFieldError is implemented by the errors generated by protoc-gen-validate
+type legacyFieldError interface {
+	Field() string
+	Reason() string
+	Cause() error
+}
+
+// legacyViolations flattens protoc-gen-validate errors, descending into the multi errors of
+// ValidateAll and the causes of embedded messages, e.g. "Address.City"
+func legacyViolations(prefix string, err error) []*errdetails.BadRequest_FieldViolation {
+	if multi, ok := err.(interface{ AllErrors() []error }); ok {
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, e := range multi.AllErrors() {
+			violations = append(violations, legacyViolations(prefix, e)...)
+		}
+		return violations
+	}
+
+	fe, ok := err.(legacyFieldError)
+	if !ok {
+		return nil
+	}
+
+	field := fe.Field()
+	if prefix != "" {
+		field = prefix + "." + field
+	}
+	if cause := fe.Cause(); cause != nil {
+		if nested := legacyViolations(field, cause); len(nested) > 0 {
+			return nested
+		}
+	}
+	return []*errdetails.BadRequest_FieldViolation{{Field: field, Description: fe.Reason()}}
+}
@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// fieldError mimics the errors generated by protoc-gen-validate
+type fieldError struct {
+	field, reason string
+	cause         error
+}
+
+func (e fieldError) Field() string  { return e.field }
+func (e fieldError) Reason() string { return e.reason }
+func (e fieldError) Cause() error   { return e.cause }
+func (e fieldError) Error() string  { return "invalid " + e.field + ": " + e.reason }
+
+type multiError []error
+
+func (m multiError) AllErrors() []error { return m }
+func (m multiError) Error() string      { return errors.Join(m...).Error() }
+
+type legacyRequest struct{ err error }
+
+func (r legacyRequest) ValidateAll() error { return r.err }
+
+// protoError mimics protovalidate.ValidationError
+type protoError struct{ violations *validate.Violations }
+
+func (e protoError) ToProto() *validate.Violations { return e.violations }
+func (e protoError) Error() string                 { return "validation error" }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrderService/CreateOrder"}
+
+	legacy := legacyRequest{err: multiError{
+		fieldError{field: "CustomerId", reason: "value length must be at least 1 runes"},
+		fieldError{field: "Address", reason: "embedded message failed validation", cause: fieldError{field: "City", reason: "value is required"}},
+	}}
+	_, err := UnaryServerInterceptor()(context.Background(), legacy, info, handler)
+	assertViolations(t, err, "CustomerId", "Address.City")
+
+	violations := &validate.Violations{Violations: []*validate.Violation{{
+		Field: &validate.FieldPath{Elements: []*validate.FieldPathElement{
+			{FieldName: proto.String("items"), Subscript: &validate.FieldPathElement_Index{Index: 2}},
+			{FieldName: proto.String("sku")},
+		}},
+		RuleId:  proto.String("string.min_len"),
+		Message: proto.String("value length must be at least 3 characters"),
+	}}}
+	fn := func(msg proto.Message) error {
+		if msg.(*wrapperspb.StringValue).GetValue() == "" {
+			return protoError{violations: violations}
+		}
+		return nil
+	}
+	_, err = UnaryServerInterceptor(WithValidateFunc(fn))(context.Background(), wrapperspb.String(""), info, handler)
+	assertViolations(t, err, "items[2].sku")
+
+	if _, err := UnaryServerInterceptor(WithValidateFunc(fn))(context.Background(), wrapperspb.String("sku-1"), info, handler); err != nil {
+		t.Errorf("valid request error = %v", err)
+	}
+}
+
+func assertViolations(t *testing.T, err error, fields ...string) {
+	t.Helper()
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", st.Code())
+	}
+
+	var got []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				got = append(got, v.GetField())
+			}
+		}
+	}
+	if len(got) != len(fields) {
+		t.Fatalf("field violations = %v, want %v", got, fields)
+	}
+	for i := range fields {
+		if got[i] != fields[i] {
+			t.Errorf("field violations = %v, want %v", got, fields)
+		}
+	}
+}
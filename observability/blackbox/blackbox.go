@@ -0,0 +1,195 @@
+// Package blackbox keeps the key facts of the last requests in memory, like a flight recorder,
+// and dumps them to logs or object storage on panic or on demand. It helps post-incident analysis
+// when the requests of interest were not sampled by tracing.
+package blackbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSize is the number of requests kept by a Recorder
+	DefaultSize = 1000
+
+	// DefaultErrorLimit is the maximum length of recorded error messages
+	DefaultErrorLimit = 256
+
+	// DefaultMinPanicDumpInterval limits how often panics trigger a dump
+	DefaultMinPanicDumpInterval = time.Minute
+
+	// DefaultDumpTimeout limits dumps triggered by panics
+	DefaultDumpTimeout = 10 * time.Second
+)
+
+// Protocols of recorded requests
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Entry holds the key facts of a request
+type Entry struct {
+	Time     time.Time     `json:"time"`
+	Protocol string        `json:"protocol"`
+	Route    string        `json:"route"`
+	Status   int           `json:"status"`
+	Latency  time.Duration `json:"-"`
+	// RequestID is the X-Request-ID header or metadata, empty without one
+	RequestID string `json:"request_id,omitempty"`
+	// TraceID is the ID of the server span, empty without one
+	TraceID string `json:"trace_id,omitempty"`
+	// Error is the error message truncated to the recorder's error limit
+	Error string `json:"error,omitempty"`
+}
+
+// MarshalJSON renders the latency as a duration string, e.g. "1.5ms"
+func (e Entry) MarshalJSON() ([]byte, error) {
+	type entry Entry
+	return json.Marshal(struct {
+		entry
+		Latency string `json:"latency"`
+	}{entry: entry(e), Latency: e.Latency.String()})
+}
+
+// Dump is the content of the recorder at the time of a dump
+type Dump struct {
+	Reason  string    `json:"reason"`
+	Time    time.Time `json:"time"`
+	Entries []Entry   `json:"entries"`
+}
+
+// Sink receives dumps
+type Sink interface {
+	Write(ctx context.Context, dump Dump) error
+}
+
+// SinkFunc is a function implementing Sink
+type SinkFunc func(ctx context.Context, dump Dump) error
+
+func (f SinkFunc) Write(ctx context.Context, dump Dump) error {
+	return f(ctx, dump)
+}
+
+// Option configures a Recorder
+type Option func(*Recorder)
+
+// WithSize sets the number of requests kept (default: DefaultSize)
+func WithSize(size int) Option {
+	return func(r *Recorder) {
+		if size > 0 {
+			r.size = size
+		}
+	}
+}
+
+// WithErrorLimit sets the maximum length of recorded error messages (default: DefaultErrorLimit)
+func WithErrorLimit(limit int) Option {
+	return func(r *Recorder) {
+		if limit > 0 {
+			r.errorLimit = limit
+		}
+	}
+}
+
+// WithSink adds a sink receiving dumps, e.g. LogSink or ObjectSink
+func WithSink(sink Sink) Option {
+	return func(r *Recorder) {
+		r.sinks = append(r.sinks, sink)
+	}
+}
+
+// WithMinPanicDumpInterval sets the minimum time between dumps triggered by panics
+// (default: DefaultMinPanicDumpInterval), so that a panicking route doesn't flood the sinks.
+// Dumps requested with Dump or the admin handler are not limited.
+func WithMinPanicDumpInterval(interval time.Duration) Option {
+	return func(r *Recorder) {
+		r.minPanicDumpInterval = interval
+	}
+}
+
+// Recorder keeps the last requests in a ring buffer. It is safe for concurrent use.
+type Recorder struct {
+	size                 int
+	errorLimit           int
+	sinks                []Sink
+	minPanicDumpInterval time.Duration
+
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+
+	dumpMu        sync.Mutex
+	lastPanicDump time.Time
+}
+
+// New creates a recorder. Without sinks, dumps are only available through Snapshot and Handler.
+func New(opts ...Option) *Recorder {
+	r := &Recorder{
+		size:                 DefaultSize,
+		errorLimit:           DefaultErrorLimit,
+		minPanicDumpInterval: DefaultMinPanicDumpInterval,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.entries = make([]Entry, 0, r.size)
+	return r
+}
+
+// Record adds an entry, evicting the oldest one when the recorder is full
+func (r *Recorder) Record(e Entry) {
+	if len(e.Error) > r.errorLimit {
+		e.Error = e.Error[:r.errorLimit] + "..."
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) < r.size {
+		r.entries = append(r.entries, e)
+		return
+	}
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % r.size
+}
+
+// Snapshot returns the recorded entries, oldest first
+func (r *Recorder) Snapshot() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := make([]Entry, 0, len(r.entries))
+	snapshot = append(snapshot, r.entries[r.next:]...)
+	return append(snapshot, r.entries[:r.next]...)
+}
+
+// Dump writes a snapshot to all sinks and returns it. Errors of sinks are joined.
+func (r *Recorder) Dump(ctx context.Context, reason string) (Dump, error) {
+	dump := Dump{Reason: reason, Time: time.Now(), Entries: r.Snapshot()}
+
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.Write(ctx, dump); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return dump, errors.Join(errs...)
+}
+
+// dumpPanic dumps the recorder after a panic, unless another panic did so recently.
+// Sink errors are dropped: the panic is re-raised anyway.
+func (r *Recorder) dumpPanic(ctx context.Context) {
+	r.dumpMu.Lock()
+	if !r.lastPanicDump.IsZero() && time.Since(r.lastPanicDump) < r.minPanicDumpInterval {
+		r.dumpMu.Unlock()
+		return
+	}
+	r.lastPanicDump = time.Now()
+	r.dumpMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultDumpTimeout)
+	defer cancel()
+	_, _ = r.Dump(ctx, "panic")
+}
@@ -0,0 +1,136 @@
+package blackbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecorderRing(t *testing.T) {
+	rec := New(WithSize(3), WithErrorLimit(5))
+	for _, route := range []string{"a", "b", "c", "d"} {
+		rec.Record(Entry{Route: route, Error: "connection refused"})
+	}
+
+	entries := rec.Snapshot()
+	var routes []string
+	for _, e := range entries {
+		routes = append(routes, e.Route)
+	}
+	if got := strings.Join(routes, ","); got != "b,c,d" {
+		t.Errorf("routes = %s, want b,c,d", got)
+	}
+	if entries[0].Error != "conne..." {
+		t.Errorf("Error = %q, want truncated", entries[0].Error)
+	}
+}
+
+func TestMiddlewareDumpsOnPanic(t *testing.T) {
+	var dumps []Dump
+	rec := New(WithSink(SinkFunc(func(_ context.Context, d Dump) error {
+		dumps = append(dumps, d)
+		return nil
+	})))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) {})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	handler := Middleware(rec)(mux)
+
+	serve := func(path string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Request-ID", "req-"+strings.TrimPrefix(path, "/"))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	serve("/ok")
+	serve("/fail")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was not re-raised")
+			}
+		}()
+		serve("/panic")
+	}()
+
+	if len(dumps) != 1 || dumps[0].Reason != "panic" {
+		t.Fatalf("dumps = %v, want one panic dump", dumps)
+	}
+	entries := dumps[0].Entries
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, want 3", len(entries))
+	}
+	want := []struct {
+		route  string
+		status int
+		err    string
+	}{
+		{"GET /ok", http.StatusOK, ""},
+		{"GET /fail", http.StatusServiceUnavailable, "Service Unavailable"},
+		{"GET /panic", http.StatusInternalServerError, "panic: boom"},
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Route != w.route || e.Status != w.status || e.Error != w.err {
+			t.Errorf("entry %d = %s %d %q, want %s %d %q", i, e.Route, e.Status, e.Error, w.route, w.status, w.err)
+		}
+	}
+	if entries[0].RequestID != "req-ok" {
+		t.Errorf("RequestID = %q, want req-ok", entries[0].RequestID)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	rec := New()
+	interceptor := UnaryServerInterceptor(rec)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.Orders/Get"}
+
+	_, _ = interceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	})
+
+	entries := rec.Snapshot()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Route != info.FullMethod || e.Status != int(codes.NotFound) || e.RequestID != "req-1" {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	sinkErr := errors.New("bucket not found")
+	rec := New(WithSink(SinkFunc(func(context.Context, Dump) error { return sinkErr })))
+	rec.Record(Entry{Route: "GET /ok", Status: http.StatusOK})
+	handler := Handler(rec)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/debug/blackbox", nil))
+	var dump Dump
+	if err := json.NewDecoder(resp.Body).Decode(&dump); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(dump.Entries) != 1 || dump.Entries[0].Route != "GET /ok" {
+		t.Errorf("entries = %v", dump.Entries)
+	}
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/debug/blackbox", nil))
+	if resp.Code != http.StatusBadGateway || !strings.Contains(resp.Body.String(), sinkErr.Error()) {
+		t.Errorf("POST = %d %s, want 502 with sink error", resp.Code, resp.Body.String())
+	}
+}
@@ -0,0 +1,69 @@
+package blackbox
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor records every unary call with the full method name as route and
+// the gRPC code as status. Like Middleware, a panic is recorded with codes.Internal and
+// triggers a dump before it is re-raised, so the recovery interceptor must run before it.
+func UnaryServerInterceptor(r *Recorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+		defer func() {
+			if p := recover(); p != nil {
+				r.recordRPC(ctx, info.FullMethod, start, status.Error(codes.Internal, panicMessage(p)))
+				r.dumpPanic(ctx)
+				panic(p)
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+		r.recordRPC(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records every stream like UnaryServerInterceptor
+func StreamServerInterceptor(r *Recorder) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		defer func() {
+			if p := recover(); p != nil {
+				r.recordRPC(ss.Context(), info.FullMethod, start, status.Error(codes.Internal, panicMessage(p)))
+				r.dumpPanic(ss.Context())
+				panic(p)
+			}
+		}()
+
+		err = handler(srv, ss)
+		r.recordRPC(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (r *Recorder) recordRPC(ctx context.Context, method string, start time.Time, err error) {
+	e := Entry{
+		Time:     start,
+		Protocol: ProtocolGRPC,
+		Route:    method,
+		Status:   int(status.Code(err)),
+		Latency:  time.Since(start),
+		TraceID:  traceID(ctx),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			e.RequestID = values[0]
+		}
+	}
+	r.Record(e)
+}
@@ -0,0 +1,81 @@
+package blackbox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader is the request ID header set by middleware/requestid
+const requestIDHeader = "X-Request-ID"
+
+// Middleware records every request handled by next with the method and URL path as route.
+// Responses with status 500 and above record the status text as error. A panic is recorded
+// with status 500 and triggers a dump before it is re-raised, so Middleware must be wrapped
+// by the recovery middleware, not wrap it. It must also be wrapped by the tracing middleware
+// to record trace IDs:
+//
+//	handler = tracing.HTTPMiddleware(blackbox.Middleware(rec)(handler), "api")
+func Middleware(r *Recorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			status := http.StatusOK
+			w = httpsnoop.Wrap(w, httpsnoop.Hooks{
+				WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+					return func(code int) {
+						status = code
+						next(code)
+					}
+				},
+			})
+
+			record := func(errMessage string) {
+				requestID := req.Header.Get(requestIDHeader)
+				if requestID == "" {
+					requestID = w.Header().Get(requestIDHeader)
+				}
+				if errMessage == "" && status >= http.StatusInternalServerError {
+					errMessage = http.StatusText(status)
+				}
+				r.Record(Entry{
+					Time:      start,
+					Protocol:  ProtocolHTTP,
+					Route:     req.Method + " " + req.URL.Path,
+					Status:    status,
+					Latency:   time.Since(start),
+					RequestID: requestID,
+					TraceID:   traceID(req.Context()),
+					Error:     errMessage,
+				})
+			}
+
+			defer func() {
+				if p := recover(); p != nil {
+					status = http.StatusInternalServerError
+					record(panicMessage(p))
+					r.dumpPanic(req.Context())
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(w, req)
+			record("")
+		})
+	}
+}
+
+func traceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+func panicMessage(p any) string {
+	return fmt.Sprintf("panic: %v", p)
+}
@@ -0,0 +1,83 @@
+package blackbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"time"
+)
+
+// LogSink writes dumps as a single error-level record with the entries as an attribute
+func LogSink(logger *slog.Logger) Sink {
+	return SinkFunc(func(ctx context.Context, dump Dump) error {
+		logger.ErrorContext(ctx, "black box dump",
+			slog.String("reason", dump.Reason),
+			slog.Int("count", len(dump.Entries)),
+			slog.Any("entries", dump.Entries),
+		)
+		return nil
+	})
+}
+
+// PutFunc stores an object, e.g. with the PutObject method of an S3 client
+type PutFunc func(ctx context.Context, key string, body []byte) error
+
+// ObjectSink writes dumps as JSON objects named prefix/<time>-<reason>.json, e.g. to S3:
+//
+//	blackbox.ObjectSink(func(ctx context.Context, key string, body []byte) error {
+//		_, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+//			Bucket: aws.String("incidents"),
+//			Key:    aws.String(key),
+//			Body:   bytes.NewReader(body),
+//		})
+//		return err
+//	}, "orders")
+func ObjectSink(put PutFunc, prefix string) Sink {
+	return SinkFunc(func(ctx context.Context, dump Dump) error {
+		body, err := json.Marshal(dump)
+		if err != nil {
+			return fmt.Errorf("failed to encode dump: %w", err)
+		}
+		key := path.Join(prefix, fmt.Sprintf("%s-%s.json", dump.Time.UTC().Format("20060102T150405.000Z"), dump.Reason))
+		if err := put(ctx, key, body); err != nil {
+			return fmt.Errorf("failed to store dump %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// Handler serves the recorder for operators: GET responds with the recorded entries,
+// POST dumps them to the sinks with reason "admin" (or the reason query parameter).
+// It exposes request metadata, so mount it on an internal port or behind authentication.
+func Handler(r *Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var (
+			dump Dump
+			err  error
+		)
+		switch req.Method {
+		case http.MethodGet:
+			dump = Dump{Reason: "snapshot", Time: time.Now(), Entries: r.Snapshot()}
+		case http.MethodPost:
+			reason := req.URL.Query().Get("reason")
+			if reason == "" {
+				reason = "admin"
+			}
+			dump, err = r.Dump(req.Context(), reason)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(dump)
+	})
+}
@@ -1,23 +1,72 @@
 package observability
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/rshelekhov/golib/observability/logger"
+	"github.com/rshelekhov/golib/observability/profiling"
+	"github.com/rshelekhov/golib/observability/spool"
 	"github.com/rshelekhov/golib/observability/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 const (
-	EnvLocal = "local"
-	EnvDev   = "dev"
-	EnvProd  = "prod"
+	EnvLocal   = "local"
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
 )
 
-var supportedEnvs = map[string]struct{}{
-	EnvLocal: {},
-	EnvDev:   {},
-	EnvProd:  {},
+// EnvDefaults are the defaults NewConfig applies for an environment
+type EnvDefaults struct {
+	LogLevel slog.Level
+	// OTLPInsecure disables TLS for OTLP exporters unless overridden by ConfigParams or options
+	OTLPInsecure bool
+	// RequireOTLP makes the OTLP endpoint and transport type mandatory
+	RequireOTLP bool
+	// EnableMetrics turns metrics on in ConfigFromEnv unless METRICS_ENABLED is set
+	EnableMetrics bool
+	// TraceSampleRatio is the ratio of root traces sampled by ConfigFromEnv unless
+	// OTEL_TRACES_SAMPLER_ARG is set. Zero samples all traces.
+	TraceSampleRatio float64
+}
+
+var (
+	envsMu sync.RWMutex
+	envs   = map[string]EnvDefaults{
+		EnvLocal:   {LogLevel: slog.LevelDebug, OTLPInsecure: true},
+		EnvDev:     {LogLevel: slog.LevelInfo, OTLPInsecure: true, RequireOTLP: true, EnableMetrics: true},
+		EnvStaging: {LogLevel: slog.LevelInfo, RequireOTLP: true, EnableMetrics: true, TraceSampleRatio: 0.5},
+		EnvProd:    {LogLevel: slog.LevelInfo, RequireOTLP: true, EnableMetrics: true, TraceSampleRatio: 0.1},
+	}
+)
+
+// RegisterEnvironment makes an environment, e.g. "qa" or "canary", accepted by NewConfig
+// with the given defaults. Registering an existing name replaces its defaults.
+// It is meant to be called during program initialization.
+func RegisterEnvironment(name string, defaults EnvDefaults) {
+	if name == "" {
+		panic("observability: environment name is required")
+	}
+
+	envsMu.Lock()
+	defer envsMu.Unlock()
+	envs[name] = defaults
+}
+
+func lookupEnv(name string) (EnvDefaults, bool) {
+	envsMu.RLock()
+	defer envsMu.RUnlock()
+	defaults, ok := envs[name]
+	return defaults, ok
 }
 
 var supportedOTLPTransportTypes = map[tracing.OTLPTransportType]struct{}{
@@ -29,6 +78,7 @@ type Config struct {
 	Env               string
 	ServiceName       string
 	ServiceVersion    string
+	Ownership         Ownership
 	EnableMetrics     bool
 	OTLPEndpoint      string
 	OTLPTransportType tracing.OTLPTransportType
@@ -38,16 +88,71 @@ type Config struct {
 	// If true, uses TLS (default for production)
 	// If false, uses insecure connection (useful for local development)
 	OTLPInsecure bool
+
+	// Headers sent with every OTLP export request, e.g. API keys of managed collectors
+	OTLPHeaders map[string]string
+
+	// Custom TLS config for OTLP exporters. Takes precedence over OTLPInsecure
+	// and the certificate files below.
+	OTLPTLSConfig *tls.Config
+
+	// PEM files used to build the TLS config when OTLPTLSConfig is nil:
+	// a CA bundle to verify the collector and a client certificate for mutual TLS
+	OTLPCAFile   string
+	OTLPCertFile string
+	OTLPKeyFile  string
+
+	// If true, logs are also written to stdout in human-readable form
+	// alongside the exporter (ignored for local environment, which always uses pretty output)
+	ConsoleLogs bool
+
+	// If true and no OTLP endpoint is used, logs are written to stdout as plain slog JSON lines
+	// with trace_id and span_id instead of the OTel stdout exporter output
+	JSONLogs bool
+
+	// Extra attributes added to the resource of all signals, e.g. service.namespace or team
+	ResourceAttributes []attribute.KeyValue
+
+	// Resource detectors and options, e.g. resource.WithHost(), resource.WithContainer()
+	// or resource.WithDetectors(K8sDetector())
+	ResourceOptions []resource.Option
+
+	// Value of service.instance.id. A random UUID is generated if empty.
+	ServiceInstanceID string
+
+	// Batch processor settings for exported spans and logs. Zero values keep the SDK defaults.
+	TraceBatch tracing.BatchConfig
+	LogBatch   logger.BatchConfig
+
+	// Trace context propagation formats, see tracing.Config.Propagators
+	Propagators []string
+
+	// Span attribute redaction applied before export, see tracing.RedactionConfig
+	SpanRedaction *tracing.RedactionConfig
+
+	// Trace sampler, see tracing.Config.Sampler
+	TraceSampler sdktrace.Sampler
+
+	// Disk spool buffering spans and logs while the OTLP collector is unreachable.
+	// Used only with OTLP exporters, see spool.Config.
+	Spool *spool.Config
+
+	// If true, Init starts the pprof endpoint and continuous profiling configured in Profiling.
+	// Service name, version and environment are taken from this config.
+	EnableProfiling bool
+	Profiling       profiling.Config
 }
 
 type ConfigParams struct {
 	Env               string
 	ServiceName       string
 	ServiceVersion    string
+	Ownership         Ownership // Optional team, domain, tier and deployment ring
 	EnableMetrics     bool
 	OTLPEndpoint      string
 	OTLPTransportType string
 	OTLPInsecure      *bool // Use pointer to distinguish between "not set" and "explicitly false"
+	JSONLogs          bool  // Write plain JSON logs to stdout when logs are not exported via OTLP
 }
 
 func (c ConfigParams) Validate() error {
@@ -63,7 +168,7 @@ func (c ConfigParams) Validate() error {
 	if c.Env == "" {
 		errMessages = append(errMessages, "environment is required")
 	}
-	if _, ok := supportedEnvs[c.Env]; !ok {
+	if _, ok := lookupEnv(c.Env); !ok {
 		errMessages = append(errMessages, fmt.Sprintf("unsupported environment: %s (supported: %s)", c.Env, strings.Join(getSupportedEnvs(), ", ")))
 	}
 	if c.requiresOTLPEndpoint() && c.OTLPEndpoint == "" {
@@ -89,15 +194,14 @@ func isValidOTLPTransportType(transportType string) bool {
 }
 
 func (c ConfigParams) requiresOTLPEndpoint() bool {
-	return c.Env == EnvDev || c.Env == EnvProd
+	defaults, _ := lookupEnv(c.Env)
+	return defaults.RequireOTLP
 }
 
 func getSupportedEnvs() []string {
-	envs := make([]string, 0, len(supportedEnvs))
-	for env := range supportedEnvs {
-		envs = append(envs, env)
-	}
-	return envs
+	envsMu.RLock()
+	defer envsMu.RUnlock()
+	return slices.Sorted(maps.Keys(envs))
 }
 
 func getSupportedOTLPTransportTypes() []string {
@@ -109,21 +213,13 @@ func getSupportedOTLPTransportTypes() []string {
 }
 
 func getDefaultLogLevel(env string) slog.Level {
-	switch env {
-	case EnvLocal:
-		return slog.LevelDebug
-	default:
-		return slog.LevelInfo
-	}
+	defaults, _ := lookupEnv(env)
+	return defaults.LogLevel
 }
 
 func getDefaultOTLPInsecure(env string) bool {
-	switch env {
-	case EnvLocal, EnvDev:
-		return true // Use insecure connections for local/dev
-	default:
-		return false // Use TLS for production
-	}
+	defaults, _ := lookupEnv(env)
+	return defaults.OTLPInsecure
 }
 
 // Option defines a functional option for Config
@@ -143,6 +239,128 @@ func WithOTLPInsecure(insecure bool) Option {
 	}
 }
 
+// WithOTLPHeaders sets headers sent with every OTLP export request
+func WithOTLPHeaders(headers map[string]string) Option {
+	return func(cfg *Config) {
+		cfg.OTLPHeaders = headers
+	}
+}
+
+// WithOTLPTLSConfig sets a custom TLS config for OTLP exporters
+func WithOTLPTLSConfig(tlsCfg *tls.Config) Option {
+	return func(cfg *Config) {
+		cfg.OTLPTLSConfig = tlsCfg
+	}
+}
+
+// WithOTLPTLSFiles sets PEM files for OTLP TLS: a CA bundle and an optional client certificate and key.
+// Files are loaded by Init.
+func WithOTLPTLSFiles(caFile, certFile, keyFile string) Option {
+	return func(cfg *Config) {
+		cfg.OTLPCAFile = caFile
+		cfg.OTLPCertFile = certFile
+		cfg.OTLPKeyFile = keyFile
+	}
+}
+
+// WithConsoleLogs enables writing pretty logs to stdout in addition to the exporter
+func WithConsoleLogs(enable bool) Option {
+	return func(cfg *Config) {
+		cfg.ConsoleLogs = enable
+	}
+}
+
+// WithResourceAttributes adds attributes to the resource of all signals
+func WithResourceAttributes(attrs ...attribute.KeyValue) Option {
+	return func(cfg *Config) {
+		cfg.ResourceAttributes = append(cfg.ResourceAttributes, attrs...)
+	}
+}
+
+// WithResourceDetectors adds resource detectors, e.g. K8sDetector()
+func WithResourceDetectors(detectors ...resource.Detector) Option {
+	return func(cfg *Config) {
+		cfg.ResourceOptions = append(cfg.ResourceOptions, resource.WithDetectors(detectors...))
+	}
+}
+
+// WithHostResource adds host.name and host.id to the resource
+func WithHostResource() Option {
+	return func(cfg *Config) {
+		cfg.ResourceOptions = append(cfg.ResourceOptions, resource.WithHost(), resource.WithHostID())
+	}
+}
+
+// WithContainerResource adds container.id to the resource when running in a container
+func WithContainerResource() Option {
+	return func(cfg *Config) {
+		cfg.ResourceOptions = append(cfg.ResourceOptions, resource.WithContainer())
+	}
+}
+
+// WithServiceInstanceID sets service.instance.id instead of a generated UUID,
+// e.g. to the pod name when it is stable across restarts
+func WithServiceInstanceID(id string) Option {
+	return func(cfg *Config) {
+		cfg.ServiceInstanceID = id
+	}
+}
+
+// WithTraceBatch tunes the batch span processor, e.g. a larger queue for high-throughput services
+func WithTraceBatch(batch tracing.BatchConfig) Option {
+	return func(cfg *Config) {
+		cfg.TraceBatch = batch
+	}
+}
+
+// WithLogBatch tunes the batch log processor
+func WithLogBatch(batch logger.BatchConfig) Option {
+	return func(cfg *Config) {
+		cfg.LogBatch = batch
+	}
+}
+
+// WithSpool buffers spans and logs on disk while the OTLP collector is unreachable
+// and replays them once it recovers
+func WithSpool(cfg spool.Config) Option {
+	return func(c *Config) {
+		c.Spool = &cfg
+	}
+}
+
+// WithPropagators sets trace context propagation formats: "w3c", "tracecontext",
+// "baggage", "b3single", "b3multi" or "jaeger". Defaults to W3C Trace Context and Baggage.
+func WithPropagators(names ...string) Option {
+	return func(cfg *Config) {
+		cfg.Propagators = names
+	}
+}
+
+// WithSpanRedaction strips or hashes sensitive span attributes before export,
+// e.g. WithSpanRedaction(tracing.DefaultRedactionConfig())
+func WithSpanRedaction(redaction tracing.RedactionConfig) Option {
+	return func(cfg *Config) {
+		cfg.SpanRedaction = &redaction
+	}
+}
+
+// WithTraceSampler sets the trace sampler, e.g. sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.1)).
+// Requests force-sampled with the debug package are recorded regardless of it.
+func WithTraceSampler(sampler sdktrace.Sampler) Option {
+	return func(cfg *Config) {
+		cfg.TraceSampler = sampler
+	}
+}
+
+// WithProfiling enables profiling: a pprof endpoint (PprofAddr) and/or
+// pushing continuous profiles to a Pyroscope-compatible server (PushEndpoint)
+func WithProfiling(profilingCfg profiling.Config) Option {
+	return func(cfg *Config) {
+		cfg.EnableProfiling = true
+		cfg.Profiling = profilingCfg
+	}
+}
+
 // NewConfig creates config with environment-based defaults and optional overrides
 func NewConfig(params ConfigParams, opts ...Option) (Config, error) {
 	if err := params.Validate(); err != nil {
@@ -153,11 +371,13 @@ func NewConfig(params ConfigParams, opts ...Option) (Config, error) {
 		Env:               params.Env,
 		ServiceName:       params.ServiceName,
 		ServiceVersion:    params.ServiceVersion,
+		Ownership:         params.Ownership,
 		EnableMetrics:     params.EnableMetrics,
 		OTLPEndpoint:      params.OTLPEndpoint,
 		OTLPTransportType: tracing.OTLPTransportType(params.OTLPTransportType),
 		LogLevel:          getDefaultLogLevel(params.Env),
 		OTLPInsecure:      getDefaultOTLPInsecure(params.Env),
+		JSONLogs:          params.JSONLogs,
 	}
 
 	// If user explicitly set OTLPInsecure in params, use that instead of default
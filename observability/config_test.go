@@ -1,6 +1,8 @@
 package observability
 
 import (
+	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -83,9 +85,10 @@ func TestTLSConfigurationDefaults(t *testing.T) {
 		env      string
 		expected bool
 	}{
-		{EnvLocal, true}, // insecure for local
-		{EnvDev, true},   // insecure for dev
-		{EnvProd, false}, // secure for prod
+		{EnvLocal, true},    // insecure for local
+		{EnvDev, true},      // insecure for dev
+		{EnvStaging, false}, // secure for staging
+		{EnvProd, false},    // secure for prod
 	}
 
 	for _, tt := range tests {
@@ -144,3 +147,35 @@ func TestFunctionalOptionOverride(t *testing.T) {
 		t.Errorf("Expected functional option to override: OTLPInsecure=true, got %v", cfg.OTLPInsecure)
 	}
 }
+
+func TestRegisterEnvironment(t *testing.T) {
+	RegisterEnvironment("qa", EnvDefaults{LogLevel: slog.LevelDebug, RequireOTLP: true})
+
+	params := ConfigParams{
+		Env:            "qa",
+		ServiceName:    "test-service",
+		ServiceVersion: "1.0.0",
+	}
+
+	if _, err := NewConfig(params); err == nil || !strings.Contains(err.Error(), "OTLP endpoint is required") {
+		t.Errorf("expected OTLP endpoint to be required, got %v", err)
+	}
+
+	params.OTLPEndpoint = "localhost:4317"
+	params.OTLPTransportType = "grpc"
+	cfg, err := NewConfig(params)
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if cfg.LogLevel != slog.LevelDebug {
+		t.Errorf("expected debug log level, got %v", cfg.LogLevel)
+	}
+	if cfg.OTLPInsecure {
+		t.Error("expected TLS to be enabled")
+	}
+
+	params.Env = "canary"
+	if _, err := NewConfig(params); err == nil || !strings.Contains(err.Error(), "unsupported environment: canary") {
+		t.Errorf("expected unsupported environment error, got %v", err)
+	}
+}
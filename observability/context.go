@@ -0,0 +1,46 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rshelekhov/golib/observability/scope"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type contextKey struct{}
+
+// Inject returns a copy of ctx carrying obs, so library code can obtain it with FromContext
+func Inject(ctx context.Context, obs *Observability) context.Context {
+	return context.WithValue(ctx, contextKey{}, obs)
+}
+
+// FromContext returns the Observability injected into ctx.
+// If there is none, it returns an Observability backed by slog.Default()
+// and the global OpenTelemetry providers, so the result is always usable.
+func FromContext(ctx context.Context) *Observability {
+	if obs, ok := ctx.Value(contextKey{}).(*Observability); ok && obs != nil {
+		return obs
+	}
+	return &Observability{Logger: slog.Default()}
+}
+
+// Tracer returns a tracer of the instrumentation scope of name (see scope.Lookup) from TracerProvider,
+// or from the global provider if it is not set
+func (o *Observability) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	if o.TracerProvider != nil {
+		return scope.Lookup(name).Tracer(o.TracerProvider, opts...)
+	}
+	return scope.Lookup(name).Tracer(otel.GetTracerProvider(), opts...)
+}
+
+// Meter returns a meter of the instrumentation scope of name (see scope.Lookup) from MeterProvider,
+// or from the global provider if it is not set
+func (o *Observability) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	if o.MeterProvider != nil {
+		return scope.Lookup(name).Meter(o.MeterProvider, opts...)
+	}
+	return scope.Lookup(name).Meter(otel.GetMeterProvider(), opts...)
+}
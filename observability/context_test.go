@@ -0,0 +1,42 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Run("Returns injected observability", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		obs := &Observability{
+			Logger:         slog.Default(),
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		}
+		ctx := Inject(context.Background(), obs)
+
+		got := FromContext(ctx)
+		if got != obs {
+			t.Fatal("expected injected observability")
+		}
+
+		_, span := got.Tracer("test").Start(ctx, "operation")
+		span.End()
+		if len(exporter.GetSpans()) != 1 {
+			t.Error("expected span to be recorded by the injected provider")
+		}
+	})
+
+	t.Run("Falls back to defaults", func(t *testing.T) {
+		obs := FromContext(context.Background())
+		if obs.Logger == nil {
+			t.Fatal("expected default logger")
+		}
+		if obs.Tracer("test") == nil || obs.Meter("test") == nil {
+			t.Error("expected global tracer and meter")
+		}
+	})
+}
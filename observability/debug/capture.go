@@ -0,0 +1,23 @@
+package debug
+
+import "go.opentelemetry.io/otel/attribute"
+
+// Span attributes holding captured bodies
+const (
+	RequestBodyKey  = attribute.Key("debug.request.body")
+	ResponseBodyKey = attribute.Key("debug.response.body")
+)
+
+// DefaultBodyLimit is the maximum number of captured bytes of each body
+const DefaultBodyLimit = 4 << 10
+
+// truncatedSuffix marks bodies cut at the limit
+const truncatedSuffix = "...(truncated)"
+
+// truncate cuts s to limit bytes
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + truncatedSuffix
+}
@@ -0,0 +1,140 @@
+// Package debug lets a caller turn on debugging for a single request with a header,
+// e.g. X-Debug-Trace: 1, or a baggage member that follows the request across services.
+// Such requests are always traced, even when the sampler would drop them,
+// and optionally get a lower log level and captured bodies.
+package debug
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+
+	"github.com/rshelekhov/golib/observability/logger"
+	"github.com/rshelekhov/golib/observability/tracing"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+const (
+	// DefaultHeader is the HTTP header and gRPC metadata key that enables debugging
+	DefaultHeader = "X-Debug-Trace"
+
+	// DefaultBaggageKey is the baggage member that enables debugging, e.g. baggage: debug=1
+	DefaultBaggageKey = "debug"
+
+	// DefaultValue is the header or baggage value that enables debugging
+	DefaultValue = "1"
+)
+
+// Option configures Middleware and StatsHandler
+type Option func(*options)
+
+type options struct {
+	header     string
+	baggageKey string
+	value      string
+	signingKey []byte
+	logLevel   *slog.Level
+}
+
+// WithHeader sets the header name (default: DefaultHeader)
+func WithHeader(name string) Option {
+	return func(o *options) {
+		o.header = name
+	}
+}
+
+// WithBaggageKey sets the baggage member checked besides the header (default: DefaultBaggageKey).
+// An empty key disables baggage.
+//
+// Baggage is propagated to downstream services, so each of them debugs the same request.
+// Requests enabled only with the header are not propagated.
+func WithBaggageKey(key string) Option {
+	return func(o *options) {
+		o.baggageKey = key
+	}
+}
+
+// WithValue sets the header value that enables debugging (default: DefaultValue).
+// Set it to a shared secret so that clients can't force sampling in production at will.
+func WithValue(value string) Option {
+	return func(o *options) {
+		o.value = value
+	}
+}
+
+// WithSigningKey accepts only tokens created by Sign with key, instead of a fixed value.
+// Tokens expire, so a leaked one can't be used to debug requests indefinitely.
+func WithSigningKey(key []byte) Option {
+	return func(o *options) {
+		o.signingKey = key
+	}
+}
+
+// WithLogLevel lowers the log level of debugged requests, e.g. to slog.LevelDebug.
+// It applies to records logged with the request context by loggers created by logger.Init.
+func WithLogLevel(level slog.Level) Option {
+	return func(o *options) {
+		o.logLevel = &level
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		header:     DefaultHeader,
+		baggageKey: DefaultBaggageKey,
+		value:      DefaultValue,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// matches reports whether the header or baggage value enables debugging
+func (o options) matches(value string) bool {
+	if value == "" {
+		return false
+	}
+	if o.signingKey != nil {
+		return verify(o.signingKey, value)
+	}
+	return subtle.ConstantTimeCompare([]byte(value), []byte(o.value)) == 1
+}
+
+// matchesBaggage reports whether any of the baggage header values enables debugging
+func (o options) matchesBaggage(headers []string) bool {
+	if o.baggageKey == "" {
+		return false
+	}
+	for _, h := range headers {
+		bag, err := baggage.Parse(h)
+		if err != nil {
+			continue
+		}
+		if o.matches(bag.Member(o.baggageKey).Value()) {
+			return true
+		}
+	}
+	return false
+}
+
+type enabledKey struct{}
+
+// enable marks ctx as debugged and force-sampled and sets the debug log level
+func (o options) enable(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, enabledKey{}, true)
+	ctx = tracing.ContextWithForceSample(ctx)
+	if o.logLevel != nil {
+		ctx = logger.ContextWithLevel(ctx, *o.logLevel)
+	}
+	return ctx
+}
+
+// IsEnabled reports whether debugging was enabled for the request of ctx
+func IsEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(enabledKey{}).(bool)
+	return enabled
+}
@@ -0,0 +1,161 @@
+package debug
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rshelekhov/golib/observability/logger"
+	"github.com/rshelekhov/golib/observability/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+)
+
+func TestMiddleware(t *testing.T) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(tracing.NewForceSampler(sdktrace.NeverSample())))
+	defer tp.Shutdown(context.Background())
+
+	var sampled bool
+	var level slog.Level
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := tp.Tracer("test").Start(r.Context(), "request")
+		defer span.End()
+		sampled = span.SpanContext().IsSampled()
+		level, _ = logger.LevelFromContext(r.Context())
+	}), WithValue("secret"), WithLogLevel(slog.LevelDebug))
+
+	tests := []struct {
+		name    string
+		value   string
+		sampled bool
+	}{
+		{name: "no header", sampled: false},
+		{name: "wrong value", value: "1", sampled: false},
+		{name: "debug", value: "secret", sampled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampled, level = false, 0
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.value != "" {
+				req.Header.Set(DefaultHeader, tt.value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if sampled != tt.sampled {
+				t.Errorf("expected sampled=%v, got %v", tt.sampled, sampled)
+			}
+			if tt.sampled && level != slog.LevelDebug {
+				t.Errorf("expected debug log level, got %v", level)
+			}
+		})
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	var got context.Context
+	h := StatsHandler(recordingHandler{tag: func(ctx context.Context) { got = ctx }})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-debug-trace", "1"))
+	h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/svc/Method"})
+
+	if !tracing.IsForceSampled(got) {
+		t.Error("expected force-sampled context")
+	}
+	if _, ok := logger.LevelFromContext(got); ok {
+		t.Error("expected log level unchanged without WithLogLevel")
+	}
+}
+
+func TestMiddlewareBaggage(t *testing.T) {
+	key := []byte("secret")
+
+	tests := []struct {
+		name    string
+		baggage string
+		enabled bool
+	}{
+		{name: "signed", baggage: "user=42,debug=" + Sign(key, time.Now().Add(time.Hour)), enabled: true},
+		{name: "expired", baggage: "debug=" + Sign(key, time.Now().Add(-time.Second)), enabled: false},
+		{name: "wrong key", baggage: "debug=" + Sign([]byte("other"), time.Now().Add(time.Hour)), enabled: false},
+		{name: "unsigned", baggage: "debug=1", enabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var enabled bool
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				enabled = IsEnabled(r.Context())
+			}), WithSigningKey(key))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("baggage", tt.baggage)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if enabled != tt.enabled {
+				t.Errorf("expected enabled=%v, got %v", tt.enabled, enabled)
+			}
+		})
+	}
+}
+
+func TestCaptureBody(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+	traced := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tp.Tracer("test").Start(r.Context(), "request")
+		defer span.End()
+		CaptureBody(echo, 8).ServeHTTP(w, r.WithContext(ctx))
+	})
+	handler := Middleware(traced)
+
+	for _, debug := range []bool{false, true} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":"12345"}`))
+		if debug {
+			req.Header.Set(DefaultHeader, DefaultValue)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Body.String() != `{"id":"12345"}` {
+			t.Errorf("expected body passed through, got %q", rec.Body.String())
+		}
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans[0].Attributes) != 0 {
+		t.Errorf("expected no captured bodies without debug, got %v", spans[0].Attributes)
+	}
+
+	attrs := map[string]string{}
+	for _, kv := range spans[1].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	want := `{"id":"1` + truncatedSuffix
+	if attrs[string(RequestBodyKey)] != want || attrs[string(ResponseBodyKey)] != want {
+		t.Errorf("expected truncated bodies %q, got %v", want, attrs)
+	}
+}
+
+type recordingHandler struct {
+	stats.Handler
+	tag func(context.Context)
+}
+
+func (h recordingHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	h.tag(ctx)
+	return ctx
+}
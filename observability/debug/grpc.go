@@ -0,0 +1,85 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// StatsHandler enables debugging for RPCs carrying the debug metadata key or baggage member.
+// Server spans are started by the tracing stats handler before any interceptor runs,
+// so it wraps that handler instead of being an interceptor:
+//
+//	grpc.NewServer(grpc.StatsHandler(debug.StatsHandler(tracing.GRPCServerStatsHandler())))
+func StatsHandler(next stats.Handler, opts ...Option) stats.Handler {
+	return &statsHandler{next: next, opts: newOptions(opts)}
+}
+
+type statsHandler struct {
+	next stats.Handler
+	opts options
+}
+
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && h.enabled(md) {
+		ctx = h.opts.enable(ctx)
+	}
+	return h.next.TagRPC(ctx, info)
+}
+
+func (h *statsHandler) enabled(md metadata.MD) bool {
+	if values := md.Get(h.opts.header); len(values) > 0 && h.opts.matches(values[0]) {
+		return true
+	}
+	return h.opts.matchesBaggage(md.Get("baggage"))
+}
+
+func (h *statsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	h.next.HandleRPC(ctx, s)
+}
+
+func (h *statsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return h.next.TagConn(ctx, info)
+}
+
+func (h *statsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	h.next.HandleConn(ctx, s)
+}
+
+// UnaryServerInterceptor records the request and response messages of debugged RPCs, up to limit bytes each,
+// as attributes of the current span. Messages are encoded as protobuf JSON.
+// A limit of zero or less means DefaultBodyLimit.
+func UnaryServerInterceptor(limit int) grpc.UnaryServerInterceptor {
+	if limit <= 0 {
+		limit = DefaultBodyLimit
+	}
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !IsEnabled(ctx) {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(RequestBodyKey.String(truncate(encodeMessage(req), limit)))
+		if err == nil {
+			span.SetAttributes(ResponseBodyKey.String(truncate(encodeMessage(resp), limit)))
+		}
+		return resp, err
+	}
+}
+
+func encodeMessage(m any) string {
+	if msg, ok := m.(proto.Message); ok {
+		if b, err := protojson.Marshal(msg); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%v", m)
+}
@@ -0,0 +1,94 @@
+package debug
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/felixge/httpsnoop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware enables debugging for requests carrying the debug header or baggage member.
+// It must wrap the tracing middleware so that the server span is started from the marked context:
+//
+//	handler = debug.Middleware(tracing.HTTPMiddleware(handler, "api"), debug.WithLogLevel(slog.LevelDebug))
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if o.matches(r.Header.Get(o.header)) || o.matchesBaggage(r.Header.Values("baggage")) {
+			r = r.WithContext(o.enable(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CaptureBody records the request and response bodies of debugged requests, up to limit bytes each,
+// as attributes of the current span. Other requests pass through untouched.
+// It must be wrapped by the tracing middleware to see the server span:
+//
+//	handler = debug.Middleware(tracing.HTTPMiddleware(debug.CaptureBody(handler, 0), "api"))
+//
+// A limit of zero or less means DefaultBodyLimit. Bodies may contain personal data,
+// drop RequestBodyKey and ResponseBodyKey with tracing.RedactionConfig where that's not acceptable.
+func CaptureBody(next http.Handler, limit int) http.Handler {
+	if limit <= 0 {
+		limit = DefaultBodyLimit
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !IsEnabled(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		req := &limitedBuffer{limit: limit}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = readCloser{Reader: io.TeeReader(r.Body, req), Closer: r.Body}
+		}
+
+		resp := &limitedBuffer{limit: limit}
+		w = httpsnoop.Wrap(w, httpsnoop.Hooks{
+			Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+				return func(b []byte) (int, error) {
+					_, _ = resp.Write(b)
+					return next(b)
+				}
+			},
+		})
+
+		next.ServeHTTP(w, r)
+
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			RequestBodyKey.String(req.String()),
+			ResponseBodyKey.String(resp.String()),
+		)
+	})
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.limit - b.buf.Len(); len(p) > n {
+		b.buf.Write(p[:n])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + truncatedSuffix
+	}
+	return b.buf.String()
+}
@@ -0,0 +1,188 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rshelekhov/golib/observability/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Environment variables read by ConfigFromEnv. The OTLP ones follow the OpenTelemetry
+// SDK specification, so the same deployment manifests work for services in other languages.
+const (
+	// EnvAppEnv is the environment name, e.g. "prod". Defaults to EnvLocal.
+	EnvAppEnv = "APP_ENV"
+	// EnvLogLevel overrides the log level of the environment: "debug", "info", "warn" or "error"
+	EnvLogLevel = "LOG_LEVEL"
+	// EnvMetricsEnabled overrides EnvDefaults.EnableMetrics
+	EnvMetricsEnabled = "METRICS_ENABLED"
+	// EnvOTLPEndpoint is the collector address. A http:// scheme disables TLS, https:// enables it.
+	EnvOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// EnvOTLPProtocol is "grpc" (default) or "http/protobuf"
+	EnvOTLPProtocol = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	// EnvOTLPInsecure overrides the TLS default of the environment and the endpoint scheme
+	EnvOTLPInsecure = "OTEL_EXPORTER_OTLP_INSECURE"
+	// EnvOTLPHeaders are headers sent with every export request: "key1=value1,key2=value2"
+	EnvOTLPHeaders = "OTEL_EXPORTER_OTLP_HEADERS"
+	// EnvTraceSampleRatio overrides EnvDefaults.TraceSampleRatio, a number between 0 and 1
+	EnvTraceSampleRatio = "OTEL_TRACES_SAMPLER_ARG"
+)
+
+// ConfigFromEnv builds a config from the preset of the environment named by APP_ENV
+// (see EnvDefaults and RegisterEnvironment) and the environment variables above.
+// Kubernetes pod attributes are added to the resource when available.
+// Options are applied last and override both.
+func ConfigFromEnv(serviceName, serviceVersion string, opts ...Option) (Config, error) {
+	env := os.Getenv(EnvAppEnv)
+	if env == "" {
+		env = EnvLocal
+	}
+	defaults, _ := lookupEnv(env)
+
+	params := ConfigParams{
+		Env:            env,
+		ServiceName:    serviceName,
+		ServiceVersion: serviceVersion,
+		EnableMetrics:  defaults.EnableMetrics,
+	}
+	envOpts := []Option{WithResourceDetectors(K8sDetector())}
+	var errMessages []string
+
+	if v := os.Getenv(EnvMetricsEnabled); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("invalid %s: %q", EnvMetricsEnabled, v))
+		}
+		params.EnableMetrics = enabled
+	}
+
+	if v := os.Getenv(EnvOTLPEndpoint); v != "" {
+		endpoint, insecure := parseOTLPEndpoint(v)
+		params.OTLPEndpoint = endpoint
+		params.OTLPInsecure = insecure
+		params.OTLPTransportType = string(tracing.OTLPTransportGRPC)
+	}
+
+	if v := os.Getenv(EnvOTLPProtocol); v != "" {
+		transport, err := parseOTLPProtocol(v)
+		if err != nil {
+			errMessages = append(errMessages, err.Error())
+		}
+		params.OTLPTransportType = string(transport)
+	}
+
+	if v := os.Getenv(EnvOTLPInsecure); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("invalid %s: %q", EnvOTLPInsecure, v))
+		}
+		params.OTLPInsecure = &insecure
+	}
+
+	if v := os.Getenv(EnvOTLPHeaders); v != "" {
+		headers, err := parseOTLPHeaders(v)
+		if err != nil {
+			errMessages = append(errMessages, err.Error())
+		}
+		envOpts = append(envOpts, WithOTLPHeaders(headers))
+	}
+
+	if v := os.Getenv(EnvLogLevel); v != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("invalid %s: %q", EnvLogLevel, v))
+		}
+		envOpts = append(envOpts, WithLogLevel(level))
+	}
+
+	ratio := defaults.TraceSampleRatio
+	if v := os.Getenv(EnvTraceSampleRatio); v != "" {
+		var err error
+		ratio, err = strconv.ParseFloat(v, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			errMessages = append(errMessages, fmt.Sprintf("invalid %s: %q (must be between 0 and 1)", EnvTraceSampleRatio, v))
+		}
+	}
+	if ratio > 0 && ratio < 1 {
+		envOpts = append(envOpts, WithTraceSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))))
+	}
+
+	if len(errMessages) > 0 {
+		return Config{}, fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	return NewConfig(params, append(envOpts, opts...)...)
+}
+
+// InitFromEnv initializes observability with the config built by ConfigFromEnv
+func InitFromEnv(ctx context.Context, serviceName, serviceVersion string, opts ...Option) (*Observability, error) {
+	cfg, err := ConfigFromEnv(serviceName, serviceVersion, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure observability: %w", err)
+	}
+	return Init(ctx, cfg)
+}
+
+// MustInitFromEnv is like InitFromEnv but panics on error. It is meant for main:
+//
+//	obs := observability.MustInitFromEnv("orders", version)
+//	defer obs.Shutdown(context.Background())
+func MustInitFromEnv(serviceName, serviceVersion string, opts ...Option) *Observability {
+	obs, err := InitFromEnv(context.Background(), serviceName, serviceVersion, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("observability: %v", err))
+	}
+	return obs
+}
+
+// parseOTLPEndpoint strips the scheme of an endpoint URL. The scheme decides TLS;
+// without one the environment default is kept.
+func parseOTLPEndpoint(v string) (string, *bool) {
+	insecure := true
+	switch {
+	case strings.HasPrefix(v, "http://"):
+		return strings.TrimSuffix(strings.TrimPrefix(v, "http://"), "/"), &insecure
+	case strings.HasPrefix(v, "https://"):
+		insecure = false
+		return strings.TrimSuffix(strings.TrimPrefix(v, "https://"), "/"), &insecure
+	default:
+		return v, nil
+	}
+}
+
+func parseOTLPProtocol(v string) (tracing.OTLPTransportType, error) {
+	switch strings.ToLower(v) {
+	case "grpc":
+		return tracing.OTLPTransportGRPC, nil
+	case "http", "http/protobuf":
+		return tracing.OTLPTransportHTTP, nil
+	default:
+		return "", fmt.Errorf("invalid %s: %q (supported: grpc, http/protobuf)", EnvOTLPProtocol, v)
+	}
+}
+
+// parseOTLPHeaders parses comma-separated key=value pairs with percent-encoded values
+func parseOTLPHeaders(v string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid %s: malformed pair %q", EnvOTLPHeaders, pair)
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvOTLPHeaders, err)
+		}
+		headers[strings.TrimSpace(key)] = decoded
+	}
+	return headers, nil
+}
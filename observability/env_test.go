@@ -0,0 +1,74 @@
+package observability
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/rshelekhov/golib/observability/tracing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvAppEnv, EnvProd)
+	t.Setenv(EnvOTLPEndpoint, "http://collector:4318/")
+	t.Setenv(EnvOTLPProtocol, "http/protobuf")
+	t.Setenv(EnvOTLPHeaders, "api-key=secret%3D, team=payments")
+	t.Setenv(EnvLogLevel, "debug")
+
+	cfg, err := ConfigFromEnv("orders", "1.0.0", WithLogLevel(slog.LevelWarn))
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() error = %v", err)
+	}
+
+	if cfg.Env != EnvProd || cfg.ServiceName != "orders" || cfg.ServiceVersion != "1.0.0" {
+		t.Errorf("identity = %s/%s/%s, want prod/orders/1.0.0", cfg.Env, cfg.ServiceName, cfg.ServiceVersion)
+	}
+	if cfg.OTLPEndpoint != "collector:4318" || cfg.OTLPTransportType != tracing.OTLPTransportHTTP {
+		t.Errorf("OTLP = %s over %s, want collector:4318 over http", cfg.OTLPEndpoint, cfg.OTLPTransportType)
+	}
+	if !cfg.OTLPInsecure {
+		t.Error("OTLPInsecure = false, want true for http:// endpoint")
+	}
+	if cfg.OTLPHeaders["api-key"] != "secret=" || cfg.OTLPHeaders["team"] != "payments" {
+		t.Errorf("OTLPHeaders = %v", cfg.OTLPHeaders)
+	}
+	if !cfg.EnableMetrics {
+		t.Error("EnableMetrics = false, want prod preset")
+	}
+	if cfg.TraceSampler == nil || !strings.Contains(cfg.TraceSampler.Description(), "TraceIDRatioBased{0.1}") {
+		t.Errorf("TraceSampler = %v, want prod preset ratio", cfg.TraceSampler)
+	}
+	// Options override environment variables
+	if cfg.LogLevel != slog.LevelWarn {
+		t.Errorf("LogLevel = %v, want %v", cfg.LogLevel, slog.LevelWarn)
+	}
+}
+
+func TestConfigFromEnvDefaults(t *testing.T) {
+	t.Setenv(EnvAppEnv, "")
+
+	cfg, err := ConfigFromEnv("orders", "1.0.0")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() error = %v", err)
+	}
+	if cfg.Env != EnvLocal || cfg.LogLevel != slog.LevelDebug || cfg.TraceSampler != nil {
+		t.Errorf("config = %s, %v, %v, want local preset", cfg.Env, cfg.LogLevel, cfg.TraceSampler)
+	}
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	t.Setenv(EnvAppEnv, EnvProd)
+	t.Setenv(EnvOTLPProtocol, "thrift")
+	t.Setenv(EnvTraceSampleRatio, "2")
+	t.Setenv(EnvMetricsEnabled, "maybe")
+
+	_, err := ConfigFromEnv("orders", "1.0.0")
+	if err == nil {
+		t.Fatal("ConfigFromEnv() error = nil")
+	}
+	for _, name := range []string{EnvOTLPProtocol, EnvTraceSampleRatio, EnvMetricsEnabled} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention %s", err, name)
+		}
+	}
+}
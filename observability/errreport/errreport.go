@@ -0,0 +1,69 @@
+// Package errreport forwards error-level logs and span errors to error trackers such as Sentry.
+//
+// Reporters receive an Event with the error, message, trace context, stack trace and attributes.
+// Events come from two sources: Handler wraps a slog.Handler and reports error records,
+// and TracingHook reports errors recorded with tracing.RecordError.
+package errreport
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// maxStackDepth limits the number of captured stack frames
+const maxStackDepth = 64
+
+// Event is an error reported to a Reporter
+type Event struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Err is the error attribute of a log record or the error recorded on a span. May be nil for logs.
+	Err error
+	// TraceID and SpanID identify the active span, empty without one
+	TraceID string
+	SpanID  string
+	// Stack holds program counters of the call stack, innermost first. Use runtime.CallersFrames to resolve them.
+	Stack []uintptr
+	// Attributes of the log record or span. Group attributes are flattened with dot-separated keys.
+	Attributes map[string]any
+}
+
+// Frames resolves Stack into stack frames, innermost first
+func (e Event) Frames() []runtime.Frame {
+	if len(e.Stack) == 0 {
+		return nil
+	}
+
+	var frames []runtime.Frame
+	it := runtime.CallersFrames(e.Stack)
+	for {
+		frame, more := it.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// Reporter sends events to an error tracker. Report is called synchronously
+// on the logging or tracing path, so it must not block.
+type Reporter interface {
+	Report(ctx context.Context, event Event)
+}
+
+// ReporterFunc adapts a function to Reporter
+type ReporterFunc func(ctx context.Context, event Event)
+
+func (f ReporterFunc) Report(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// callers returns the stack skipping skip frames above the caller of callers
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
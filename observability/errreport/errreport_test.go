@@ -0,0 +1,83 @@
+package errreport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/rshelekhov/golib/observability/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type recorder struct {
+	events []Event
+}
+
+func (r *recorder) Report(_ context.Context, e Event) {
+	r.events = append(r.events, e)
+}
+
+func TestHandler(t *testing.T) {
+	rec := &recorder{}
+	log := slog.New(NewHandler(slog.NewTextHandler(io.Discard, nil), rec)).
+		With("service", "api").
+		WithGroup("request")
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "operation")
+	defer span.End()
+
+	errBoom := errors.New("boom")
+	log.InfoContext(ctx, "not reported")
+	log.ErrorContext(ctx, "request failed", "id", 42, "error", errBoom)
+
+	if len(rec.events) != 1 {
+		t.Fatalf("expected 1 reported event, got %d", len(rec.events))
+	}
+	e := rec.events[0]
+
+	if e.Message != "request failed" || !errors.Is(e.Err, errBoom) {
+		t.Errorf("unexpected event message %q and error %v", e.Message, e.Err)
+	}
+	if e.TraceID != span.SpanContext().TraceID().String() {
+		t.Errorf("expected trace ID %s, got %s", span.SpanContext().TraceID(), e.TraceID)
+	}
+	if e.Attributes["service"] != "api" || e.Attributes["request.id"] != int64(42) {
+		t.Errorf("unexpected attributes %v", e.Attributes)
+	}
+
+	frames := e.Frames()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestHandler") {
+		t.Errorf("expected stack to start at the logging call, got %+v", frames)
+	}
+}
+
+func TestTracingHook(t *testing.T) {
+	rec := &recorder{}
+	tracing.SetErrorHook(TracingHook(rec))
+	defer tracing.SetErrorHook(nil)
+
+	tp := sdktrace.NewTracerProvider()
+	_, span := tp.Tracer("test").Start(context.Background(), "charge")
+	defer span.End()
+
+	tracing.RecordError(span, errors.New("card declined"))
+
+	if len(rec.events) != 1 {
+		t.Fatalf("expected 1 reported event, got %d", len(rec.events))
+	}
+	e := rec.events[0]
+
+	if e.Message != "card declined" || e.Attributes["span.name"] != "charge" {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if e.SpanID != span.SpanContext().SpanID().String() {
+		t.Errorf("expected span ID %s, got %s", span.SpanContext().SpanID(), e.SpanID)
+	}
+	if frames := e.Frames(); len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestTracingHook") {
+		t.Errorf("expected stack to start at the RecordError call, got %+v", frames)
+	}
+}
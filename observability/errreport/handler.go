@@ -0,0 +1,139 @@
+package errreport
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HandlerOption configures Handler
+type HandlerOption func(*Handler)
+
+// WithMinLevel sets the minimal level of reported records (default: slog.LevelError)
+func WithMinLevel(level slog.Leveler) HandlerOption {
+	return func(h *Handler) {
+		h.minLevel = level
+	}
+}
+
+// Handler wraps a slog.Handler and reports records at or above the minimal level,
+// e.g. those logged with ErrorContext, before passing them to the next handler
+type Handler struct {
+	next     slog.Handler
+	reporter Reporter
+	minLevel slog.Leveler
+	attrs    []slog.Attr
+	groups   []string
+}
+
+// NewHandler creates a handler reporting error records to reporter
+func NewHandler(next slog.Handler, reporter Reporter, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		next:     next,
+		reporter: reporter,
+		minLevel: slog.LevelError,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.minLevel.Level() || h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= h.minLevel.Level() {
+		h.reporter.Report(ctx, h.event(ctx, record))
+	}
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		clone.attrs = append(clone.attrs, qualify(h.groups, a))
+	}
+	return &clone
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.groups = append(slices.Clone(h.groups), name)
+	return &clone
+}
+
+func (h *Handler) event(ctx context.Context, record slog.Record) Event {
+	event := Event{
+		Time:       record.Time,
+		Level:      record.Level,
+		Message:    record.Message,
+		Attributes: make(map[string]any),
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		event.TraceID = sc.TraceID().String()
+		event.SpanID = sc.SpanID().String()
+	}
+
+	// Start the stack at the logging call
+	event.Stack = callers(1)
+	if i := slices.Index(event.Stack, record.PC); i >= 0 {
+		event.Stack = event.Stack[i:]
+	}
+
+	for _, a := range h.attrs {
+		event.addAttr("", a)
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		event.addAttr("", qualify(h.groups, a))
+		return true
+	})
+
+	return event
+}
+
+// addAttr flattens the attribute into Attributes and picks up the first error value
+func (e *Event) addAttr(prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			e.addAttr(key, ga)
+		}
+		return
+	}
+
+	if err, ok := a.Value.Any().(error); ok {
+		if e.Err == nil {
+			e.Err = err
+		}
+		e.Attributes[key] = err.Error()
+		return
+	}
+	e.Attributes[key] = a.Value.Any()
+}
+
+// qualify nests the attribute into the handler groups
+func qualify(groups []string, a slog.Attr) slog.Attr {
+	for i := len(groups) - 1; i >= 0; i-- {
+		a = slog.Attr{Key: groups[i], Value: slog.GroupValue(a)}
+	}
+	return a
+}
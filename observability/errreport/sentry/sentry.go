@@ -0,0 +1,79 @@
+// Package sentry reports errreport events to Sentry
+package sentry
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/rshelekhov/golib/observability/errreport"
+)
+
+// Reporter sends events to Sentry. The SDK must be initialized with sentry.Init
+// or a client bound to the hub.
+type Reporter struct {
+	hub *sentrygo.Hub
+}
+
+var _ errreport.Reporter = (*Reporter)(nil)
+
+// NewReporter creates a reporter using the hub, or the current hub if nil.
+// A hub stored in the event context with sentry.SetHubOnContext takes precedence.
+func NewReporter(hub *sentrygo.Hub) *Reporter {
+	if hub == nil {
+		hub = sentrygo.CurrentHub()
+	}
+	return &Reporter{hub: hub}
+}
+
+func (r *Reporter) Report(ctx context.Context, e errreport.Event) {
+	hub := r.hub
+	if ctxHub := sentrygo.GetHubFromContext(ctx); ctxHub != nil {
+		hub = ctxHub
+	}
+
+	event := sentrygo.NewEvent()
+	event.Level = sentrygo.LevelError
+	event.Message = e.Message
+	if !e.Time.IsZero() {
+		event.Timestamp = e.Time
+	}
+	event.Extra = e.Attributes
+
+	if e.TraceID != "" {
+		event.Tags["trace_id"] = e.TraceID
+		event.Contexts["trace"] = sentrygo.Context{
+			"trace_id": e.TraceID,
+			"span_id":  e.SpanID,
+		}
+	}
+
+	stacktrace := newStacktrace(e)
+	if e.Err != nil {
+		event.Exception = []sentrygo.Exception{{
+			Type:       reflect.TypeOf(e.Err).String(),
+			Value:      e.Err.Error(),
+			Stacktrace: stacktrace,
+		}}
+	} else if stacktrace != nil {
+		event.Threads = []sentrygo.Thread{{Stacktrace: stacktrace, Current: true}}
+	}
+
+	hub.CaptureEvent(event)
+}
+
+// newStacktrace converts the event stack. Sentry expects frames ordered from outermost to innermost.
+func newStacktrace(e errreport.Event) *sentrygo.Stacktrace {
+	frames := e.Frames()
+	if len(frames) == 0 {
+		return nil
+	}
+
+	st := &sentrygo.Stacktrace{Frames: make([]sentrygo.Frame, 0, len(frames))}
+	for _, f := range frames {
+		st.Frames = append(st.Frames, sentrygo.NewFrame(f))
+	}
+	slices.Reverse(st.Frames)
+	return st
+}
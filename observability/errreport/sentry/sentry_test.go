@@ -0,0 +1,49 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/rshelekhov/golib/observability/errreport"
+)
+
+func TestReporter(t *testing.T) {
+	var captured []*sentrygo.Event
+	client, err := sentrygo.NewClient(sentrygo.ClientOptions{
+		BeforeSend: func(event *sentrygo.Event, _ *sentrygo.EventHint) *sentrygo.Event {
+			captured = append(captured, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	r := NewReporter(sentrygo.NewHub(client, sentrygo.NewScope()))
+	r.Report(context.Background(), errreport.Event{
+		Time:       time.Now(),
+		Message:    "request failed",
+		Err:        errors.New("boom"),
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		Attributes: map[string]any{"user_id": "42"},
+	})
+
+	if len(captured) != 1 {
+		t.Fatalf("expected 1 captured event, got %d", len(captured))
+	}
+	e := captured[0]
+
+	if len(e.Exception) != 1 || e.Exception[0].Value != "boom" {
+		t.Errorf("unexpected exception %+v", e.Exception)
+	}
+	if e.Tags["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace_id tag, got %v", e.Tags)
+	}
+	if e.Extra["user_id"] != "42" {
+		t.Errorf("expected attributes in extra, got %v", e.Extra)
+	}
+}
@@ -0,0 +1,44 @@
+package errreport
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rshelekhov/golib/observability/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingHook returns a hook reporting errors recorded with tracing.RecordError:
+//
+//	tracing.SetErrorHook(errreport.TracingHook(reporter))
+func TracingHook(reporter Reporter) tracing.ErrorHook {
+	return func(span trace.Span, err error) {
+		event := Event{
+			Time:       time.Now(),
+			Level:      slog.LevelError,
+			Err:        err,
+			Message:    err.Error(),
+			Attributes: make(map[string]any),
+			// Skip this hook and tracing.RecordError
+			Stack: callers(2),
+		}
+
+		sc := span.SpanContext()
+		if sc.IsValid() {
+			event.TraceID = sc.TraceID().String()
+			event.SpanID = sc.SpanID().String()
+		}
+
+		// SDK spans expose their name and attributes
+		if ro, ok := span.(sdktrace.ReadOnlySpan); ok {
+			event.Attributes["span.name"] = ro.Name()
+			for _, kv := range ro.Attributes() {
+				event.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+			}
+		}
+
+		reporter.Report(trace.ContextWithSpan(context.Background(), span), event)
+	}
+}
@@ -188,7 +188,7 @@ func customLogLevelExample() {
 func errorHandlingExample() {
 	// This will fail - unknown environment
 	cfg, err := observability.NewConfig(observability.ConfigParams{
-		Env:               "staging",
+		Env:               "qa",
 		ServiceName:       "my-service",
 		ServiceVersion:    "1.0.0",
 		EnableMetrics:     true,
@@ -197,7 +197,7 @@ func errorHandlingExample() {
 	})
 	if err != nil {
 		log.Printf("Expected error: %v", err)
-		// Output: unsupported environment: staging (supported: local, dev, prod)
+		// Output: unsupported environment: qa (supported: dev, local, prod, staging)
 	}
 
 	// This will fail - missing OTLP endpoint for prod
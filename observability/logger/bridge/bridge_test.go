@@ -0,0 +1,85 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newBridgeTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+}
+
+func decodeBridgeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log line %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestZap(t *testing.T) {
+	var buf bytes.Buffer
+	log := Zap(newBridgeTestLogger(&buf)).Named("client").With(zap.String("component", "k8s"))
+
+	log.Debug("filtered by slog level")
+	if buf.Len() != 0 {
+		t.Fatalf("expected debug entry to be filtered, got %s", buf.String())
+	}
+
+	log.Error("sync failed", zap.Error(errors.New("boom")), zap.Int("attempt", 3))
+
+	entry := decodeBridgeLine(t, &buf)
+	expected := map[string]any{
+		"level":     "ERROR",
+		"msg":       "sync failed",
+		"logger":    "client",
+		"component": "k8s",
+		"error":     "boom",
+		"attempt":   float64(3),
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestLogr(t *testing.T) {
+	var buf bytes.Buffer
+	log := Logr(newBridgeTestLogger(&buf))
+
+	log.V(1).Info("filtered by slog level")
+	if buf.Len() != 0 {
+		t.Fatalf("expected V(1) entry to be filtered, got %s", buf.String())
+	}
+
+	log.Info("reconciled", "name", "web")
+
+	entry := decodeBridgeLine(t, &buf)
+	if entry["msg"] != "reconciled" || entry["name"] != "web" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+}
+
+func TestGRPCLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewGRPCLogger(newBridgeTestLogger(&buf), 2)
+
+	if !log.V(2) || log.V(3) {
+		t.Error("unexpected verbosity")
+	}
+
+	log.Warningf("transport: %s", "closing")
+
+	entry := decodeBridgeLine(t, &buf)
+	if entry["level"] != "WARN" || entry["msg"] != "transport: closing" || entry["system"] != "grpc" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+}
@@ -0,0 +1,4 @@
+// Package bridge routes logs of third-party libraries through a *slog.Logger,
+// so controller-runtime, gRPC internals and other logr, zap or grpclog users
+// end up in the same OTLP or pretty pipeline as the service's own logs.
+package bridge
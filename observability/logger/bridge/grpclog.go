@@ -0,0 +1,88 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+// GRPCLogger is a grpclog.LoggerV2 writing to a *slog.Logger
+type GRPCLogger struct {
+	logger    *slog.Logger
+	verbosity int
+}
+
+var _ grpclog.LoggerV2 = (*GRPCLogger)(nil)
+
+// NewGRPCLogger creates a gRPC logger writing to l. verbosity is the level
+// reported by V, as GRPC_GO_LOG_VERBOSITY_LEVEL does for the default logger.
+// Install it with grpclog.SetLoggerV2 before any gRPC call.
+func NewGRPCLogger(l *slog.Logger, verbosity int) *GRPCLogger {
+	return &GRPCLogger{logger: l, verbosity: verbosity}
+}
+
+func (g *GRPCLogger) Info(args ...any)   { g.log(slog.LevelInfo, fmt.Sprint(args...)) }
+func (g *GRPCLogger) Infoln(args ...any) { g.log(slog.LevelInfo, sprintln(args...)) }
+func (g *GRPCLogger) Infof(format string, args ...any) {
+	g.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (g *GRPCLogger) Warning(args ...any)   { g.log(slog.LevelWarn, fmt.Sprint(args...)) }
+func (g *GRPCLogger) Warningln(args ...any) { g.log(slog.LevelWarn, sprintln(args...)) }
+func (g *GRPCLogger) Warningf(format string, args ...any) {
+	g.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (g *GRPCLogger) Error(args ...any)   { g.log(slog.LevelError, fmt.Sprint(args...)) }
+func (g *GRPCLogger) Errorln(args ...any) { g.log(slog.LevelError, sprintln(args...)) }
+func (g *GRPCLogger) Errorf(format string, args ...any) {
+	g.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// Fatal logs at Error level and exits, as the grpclog contract requires
+func (g *GRPCLogger) Fatal(args ...any) {
+	g.log(slog.LevelError, fmt.Sprint(args...))
+	os.Exit(1)
+}
+
+func (g *GRPCLogger) Fatalln(args ...any) {
+	g.log(slog.LevelError, sprintln(args...))
+	os.Exit(1)
+}
+
+func (g *GRPCLogger) Fatalf(format string, args ...any) {
+	g.log(slog.LevelError, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// V reports whether verbosity level l is enabled
+func (g *GRPCLogger) V(l int) bool {
+	return l <= g.verbosity
+}
+
+// log writes the message with the caller of the exported method as source
+func (g *GRPCLogger) log(level slog.Level, msg string) {
+	ctx := context.Background()
+	if !g.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, log and the exported method
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.AddAttrs(slog.String("system", "grpc"))
+	_ = g.logger.Handler().Handle(ctx, record)
+}
+
+// sprintln formats like fmt.Sprintln without the trailing newline
+func sprintln(args ...any) string {
+	s := fmt.Sprintln(args...)
+	return s[:len(s)-1]
+}
@@ -0,0 +1,13 @@
+package bridge
+
+import (
+	"log/slog"
+
+	"github.com/go-logr/logr"
+)
+
+// Logr returns a logr.Logger writing to l.
+// logr verbosity V(n) is mapped to slog level Info-n, so V(1) logs at Debug.
+func Logr(l *slog.Logger) logr.Logger {
+	return logr.FromSlogHandler(l.Handler())
+}
@@ -0,0 +1,89 @@
+package bridge
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ZapCore is a zapcore.Core writing entries to a slog.Handler
+type ZapCore struct {
+	handler slog.Handler
+}
+
+var _ zapcore.Core = (*ZapCore)(nil)
+
+// NewZapCore creates a core writing to l. Level filtering is done by the slog handler.
+func NewZapCore(l *slog.Logger) *ZapCore {
+	return &ZapCore{handler: l.Handler()}
+}
+
+// Zap returns a *zap.Logger writing to l
+func Zap(l *slog.Logger, opts ...zap.Option) *zap.Logger {
+	return zap.New(NewZapCore(l), append([]zap.Option{zap.AddCaller()}, opts...)...)
+}
+
+func (c *ZapCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), zapToSlogLevel(level))
+}
+
+func (c *ZapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &ZapCore{handler: c.handler.WithAttrs(zapFieldsToAttrs(fields))}
+}
+
+func (c *ZapCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *ZapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	record := slog.NewRecord(entry.Time, zapToSlogLevel(entry.Level), entry.Message, entry.Caller.PC)
+	if entry.LoggerName != "" {
+		record.AddAttrs(slog.String("logger", entry.LoggerName))
+	}
+	record.AddAttrs(zapFieldsToAttrs(fields)...)
+	if entry.Stack != "" {
+		record.AddAttrs(slog.String("stacktrace", entry.Stack))
+	}
+
+	return c.handler.Handle(context.Background(), record)
+}
+
+// Sync is a no-op: flushing is handled by the slog pipeline, e.g. LoggerProvider.Shutdown
+func (c *ZapCore) Sync() error {
+	return nil
+}
+
+// zapToSlogLevel maps zap levels to slog levels. Levels above Error
+// (DPanic, Panic, Fatal) are logged as Error; zap still panics or exits after writing.
+func zapToSlogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// zapFieldsToAttrs encodes zap fields into slog attributes.
+// Objects and namespaces become nested maps.
+func zapFieldsToAttrs(fields []zapcore.Field) []slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	attrs := make([]slog.Attr, 0, len(enc.Fields))
+	for k, v := range enc.Fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	return attrs
+}
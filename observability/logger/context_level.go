@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type levelContextKey struct{}
+
+// ContextWithLevel returns a context that lowers the minimum log level for records logged with it,
+// e.g. to get debug logs for a single request without changing the service level.
+// Only loggers created by Init honor it.
+func ContextWithLevel(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, levelContextKey{}, level)
+}
+
+// LevelFromContext returns the level set with ContextWithLevel
+func LevelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(levelContextKey{}).(slog.Level)
+	return level, ok
+}
@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestContextWithLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(&levelFilterHandler{
+		handler:  slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		minLevel: slog.LevelInfo,
+	})
+
+	log.DebugContext(context.Background(), "hidden")
+	log.DebugContext(ContextWithLevel(context.Background(), slog.LevelDebug), "visible")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Error("expected debug record without context level to be dropped")
+	}
+	if !strings.Contains(out, "visible") {
+		t.Error("expected debug record with context level to be logged")
+	}
+}
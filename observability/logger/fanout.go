@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// FanoutHandler dispatches each record to multiple handlers,
+// e.g. pretty stdout output and OTLP export at the same time
+type FanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// NewFanoutHandler creates a handler that writes records to all given handlers.
+// Nil handlers are ignored.
+func NewFanoutHandler(handlers ...slog.Handler) *FanoutHandler {
+	hs := make([]slog.Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+	return &FanoutHandler{handlers: hs}
+}
+
+// Enabled reports whether at least one handler accepts the level
+func (h *FanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a copy of the record to every handler that accepts its level.
+// All handlers are called even if some of them fail.
+func (h *FanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &FanoutHandler{handlers: handlers}
+}
+
+func (h *FanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &FanoutHandler{handlers: handlers}
+}
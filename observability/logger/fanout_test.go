@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingHandler accepts all records and fails to write them
+type failingHandler struct {
+	err error
+}
+
+func (h failingHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (h failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+func (h failingHandler) WithAttrs([]slog.Attr) slog.Handler        { return h }
+func (h failingHandler) WithGroup(string) slog.Handler             { return h }
+
+func TestFanoutHandler(t *testing.T) {
+	t.Run("writes records to every handler", func(t *testing.T) {
+		var text, json bytes.Buffer
+		log := slog.New(NewFanoutHandler(slog.NewTextHandler(&text, nil), nil, slog.NewJSONHandler(&json, nil)))
+
+		log.Info("fanned out", "user", "alice")
+
+		if !strings.Contains(text.String(), "msg=\"fanned out\" user=alice") {
+			t.Errorf("expected text record, got %q", text.String())
+		}
+		if !strings.Contains(json.String(), `"msg":"fanned out","user":"alice"`) {
+			t.Errorf("expected JSON record, got %q", json.String())
+		}
+	})
+
+	t.Run("enabled if any handler is enabled", func(t *testing.T) {
+		var debug, warn bytes.Buffer
+		handler := NewFanoutHandler(
+			slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			slog.NewTextHandler(&warn, &slog.HandlerOptions{Level: slog.LevelWarn}),
+		)
+
+		if !handler.Enabled(context.Background(), slog.LevelDebug) {
+			t.Error("expected debug to be enabled by the debug handler")
+		}
+		if NewFanoutHandler(slog.NewTextHandler(&warn, &slog.HandlerOptions{Level: slog.LevelWarn})).Enabled(context.Background(), slog.LevelInfo) {
+			t.Error("expected info to be disabled when no handler accepts it")
+		}
+		if NewFanoutHandler().Enabled(context.Background(), slog.LevelError) {
+			t.Error("expected a handler without handlers to be disabled")
+		}
+
+		// Each handler only gets the records of its level
+		slog.New(handler).Debug("debug record")
+		if !strings.Contains(debug.String(), "debug record") {
+			t.Error("expected debug record in the debug handler")
+		}
+		if warn.Len() != 0 {
+			t.Errorf("expected no record in the warn handler, got %q", warn.String())
+		}
+	})
+
+	t.Run("propagates WithAttrs and WithGroup", func(t *testing.T) {
+		var first, second bytes.Buffer
+		log := slog.New(NewFanoutHandler(slog.NewJSONHandler(&first, nil), slog.NewJSONHandler(&second, nil)))
+
+		log.With("service", "api").WithGroup("req").Info("grouped", "id", 7)
+
+		for name, buf := range map[string]*bytes.Buffer{"first": &first, "second": &second} {
+			if !strings.Contains(buf.String(), `"service":"api","req":{"id":7}`) {
+				t.Errorf("expected attrs and group in the %s handler, got %q", name, buf.String())
+			}
+		}
+	})
+
+	t.Run("joins handler errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		errA, errB := errors.New("a failed"), errors.New("b failed")
+		handler := NewFanoutHandler(failingHandler{err: errA}, slog.NewTextHandler(&buf, nil), failingHandler{err: errB})
+
+		err := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "record", 0))
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("expected both errors, got %v", err)
+		}
+		if !strings.Contains(buf.String(), "record") {
+			t.Error("expected handlers after a failing one to be called")
+		}
+	})
+}
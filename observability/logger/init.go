@@ -2,34 +2,118 @@ package logger
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"os"
+	"time"
 
+	"github.com/rshelekhov/golib/observability/spool"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/credentials"
+)
+
+// allLevels makes handlers accept records of every level, leaving level filtering to levelFilterHandler
+const allLevels = slog.Level(math.MinInt)
+
+type OTLPTransportType string
+
+const (
+	OTLPTransportGRPC OTLPTransportType = "grpc"
+	OTLPTransportHTTP OTLPTransportType = "http"
 )
 
 type Config struct {
-	ServiceName    string
-	ServiceVersion string
-	Env            string
-	Level          slog.Level
-	Endpoint       string // OTLP endpoint. If empty, stdout exporter is used.
-	OTLPInsecure   bool   // If true, uses insecure OTLP connection
+	ServiceName       string
+	ServiceVersion    string
+	Env               string
+	Level             slog.Level
+	Endpoint          string            // OTLP endpoint. If empty, stdout exporter is used.
+	OTLPTransportType OTLPTransportType // "grpc" (default) or "http", used only when Endpoint is set
+	OTLPInsecure      bool              // If true, uses insecure OTLP connection
+	OTLPHeaders       map[string]string // Headers sent with every export request, e.g. collector auth
+	OTLPTLSConfig     *tls.Config       // Custom TLS config (CA bundle, client certificate). Takes precedence over OTLPInsecure
+	ConsoleOutput     bool              // If true, also writes pretty logs to stdout in non-local environments
+	JSONOutput        bool              // If true and Endpoint is empty, writes plain slog JSON to stdout instead of the OTel stdout exporter
+
+	// Resource overrides the resource built from service name, version and environment
+	Resource *resource.Resource
+
+	// Batch tunes the batch log processor
+	Batch BatchConfig
+
+	// Spool, if set, buffers log records on disk while the OTLP collector is unreachable.
+	// Used only when Endpoint is set.
+	Spool *spool.Config
+}
+
+// BatchConfig configures the batch log processor. Zero values keep the SDK defaults
+// (queue of 2048 records, 1s export interval, 30s export timeout, batches of 512 records).
+type BatchConfig struct {
+	// MaxQueueSize is the maximum number of records buffered before old ones are dropped
+	MaxQueueSize int
+	// ExportInterval is the maximum delay before buffered records are exported
+	ExportInterval time.Duration
+	// ExportTimeout is the maximum duration of a single export
+	ExportTimeout time.Duration
+	// MaxExportBatchSize is the maximum number of records in one export. Must not exceed MaxQueueSize.
+	MaxExportBatchSize int
+}
+
+// defaultBatchQueueSize is the queue size of the SDK batch log processor
+const defaultBatchQueueSize = 2048
+
+// Validate reports negative settings and batches larger than the queue
+func (c BatchConfig) Validate() error {
+	if c.MaxQueueSize < 0 || c.ExportInterval < 0 || c.ExportTimeout < 0 || c.MaxExportBatchSize < 0 {
+		return errors.New("batch settings must not be negative")
+	}
+	queueSize := c.MaxQueueSize
+	if queueSize == 0 {
+		queueSize = defaultBatchQueueSize
+	}
+	if c.MaxExportBatchSize > queueSize {
+		return fmt.Errorf("max export batch size %d exceeds max queue size %d", c.MaxExportBatchSize, queueSize)
+	}
+	return nil
+}
+
+func (c BatchConfig) options() []log.BatchProcessorOption {
+	var opts []log.BatchProcessorOption
+	if c.MaxQueueSize > 0 {
+		opts = append(opts, log.WithMaxQueueSize(c.MaxQueueSize))
+	}
+	if c.ExportInterval > 0 {
+		opts = append(opts, log.WithExportInterval(c.ExportInterval))
+	}
+	if c.ExportTimeout > 0 {
+		opts = append(opts, log.WithExportTimeout(c.ExportTimeout))
+	}
+	if c.MaxExportBatchSize > 0 {
+		opts = append(opts, log.WithExportMaxBatchSize(c.MaxExportBatchSize))
+	}
+	return opts
 }
 
 // Init initializes OpenTelemetry LoggerProvider
 func Init(ctx context.Context, cfg Config) (*log.LoggerProvider, *slog.Logger, error) {
+	if err := cfg.Batch.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid log batch config: %w", err)
+	}
+
 	// For local environment, use pretty handler instead of OTEL
 	if cfg.Env == "local" {
+		// Level filtering is left to levelFilterHandler, which also honors ContextWithLevel
 		handler := NewPrettyHandler(os.Stdout, &PrettyHandlerOptions{
-			Level:     cfg.Level,
 			AddSource: true,
 		})
 
@@ -42,6 +126,26 @@ func Init(ctx context.Context, cfg Config) (*log.LoggerProvider, *slog.Logger, e
 		return nil, finalLogger, nil
 	}
 
+	// Plain JSON lines are easier to parse in standard log pipelines than OTel stdout exporter output
+	if cfg.Endpoint == "" && cfg.JSONOutput {
+		handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+			Level:     allLevels,
+			AddSource: true,
+		})
+
+		finalLogger := slog.New(&levelFilterHandler{
+			handler:  NewTraceContextHandler(handler),
+			minLevel: cfg.Level,
+		}).With(
+			slog.String(string(semconv.ServiceNameKey), cfg.ServiceName),
+			slog.String(string(semconv.ServiceVersionKey), cfg.ServiceVersion),
+			slog.String(string(semconv.DeploymentEnvironmentKey), cfg.Env),
+		)
+
+		// Return nil LoggerProvider since we're not using OTEL
+		return nil, finalLogger, nil
+	}
+
 	var exporter log.Exporter
 	var err error
 
@@ -52,31 +156,32 @@ func Init(ctx context.Context, cfg Config) (*log.LoggerProvider, *slog.Logger, e
 			return nil, nil, fmt.Errorf("failed to create stdout log exporter: %w", err)
 		}
 	} else {
-		// Create OTLP exporter with configurable TLS
-		opts := []otlploggrpc.Option{
-			otlploggrpc.WithEndpoint(cfg.Endpoint),
-		}
-		if cfg.OTLPInsecure {
-			opts = append(opts, otlploggrpc.WithInsecure())
-		}
-
-		exporter, err = otlploggrpc.New(ctx, opts...)
+		exporter, err = newOTLPExporter(ctx, cfg)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create otlp log exporter: %w", err)
+			return nil, nil, err
+		}
+		if cfg.Spool != nil {
+			exporter, err = spool.NewLogExporter(exporter, *cfg.Spool)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create log spool: %w", err)
+			}
 		}
 	}
 
 	// Create resource
-	res := resource.NewWithAttributes(
-		resource.Default().SchemaURL(),
-		semconv.ServiceName(cfg.ServiceName),
-		semconv.ServiceVersion(cfg.ServiceVersion),
-		semconv.DeploymentEnvironment(cfg.Env),
-	)
+	res := cfg.Resource
+	if res == nil {
+		res = resource.NewWithAttributes(
+			resource.Default().SchemaURL(),
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			semconv.DeploymentEnvironment(cfg.Env),
+		)
+	}
 
 	// Create LoggerProvider
 	lp := log.NewLoggerProvider(
-		log.WithProcessor(log.NewBatchProcessor(exporter)),
+		log.WithProcessor(log.NewBatchProcessor(exporter, cfg.Batch.options()...)),
 		log.WithResource(res),
 	)
 
@@ -84,7 +189,13 @@ func Init(ctx context.Context, cfg Config) (*log.LoggerProvider, *slog.Logger, e
 	global.SetLoggerProvider(lp)
 
 	// Create slog logger with level filtering
-	handler := otelslog.NewHandler(cfg.ServiceName, otelslog.WithLoggerProvider(lp))
+	var handler slog.Handler = otelslog.NewHandler(cfg.ServiceName, otelslog.WithLoggerProvider(lp))
+	if cfg.ConsoleOutput {
+		// Level filtering is left to levelFilterHandler, which also honors ContextWithLevel
+		handler = NewFanoutHandler(handler, NewPrettyHandler(os.Stdout, &PrettyHandlerOptions{
+			AddSource: true,
+		}))
+	}
 	finalLogger := slog.New(&levelFilterHandler{
 		handler:  handler,
 		minLevel: cfg.Level,
@@ -93,23 +204,85 @@ func Init(ctx context.Context, cfg Config) (*log.LoggerProvider, *slog.Logger, e
 	return lp, finalLogger, nil
 }
 
-// levelFilterHandler wraps a slog.Handler to filter by log level
+// newOTLPExporter creates an OTLP exporter for the configured transport with configurable TLS
+func newOTLPExporter(ctx context.Context, cfg Config) (log.Exporter, error) {
+	switch cfg.OTLPTransportType {
+	case OTLPTransportHTTP:
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(cfg.Endpoint),
+		}
+		switch {
+		case cfg.OTLPTLSConfig != nil:
+			opts = append(opts, otlploghttp.WithTLSClientConfig(cfg.OTLPTLSConfig))
+		case cfg.OTLPInsecure:
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(cfg.OTLPHeaders) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(cfg.OTLPHeaders))
+		}
+		if cfg.Spool != nil {
+			// Failed batches are spooled and replayed instead of retried in memory
+			opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig{Enabled: false}))
+		}
+
+		exporter, err := otlploghttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp http log exporter: %w", err)
+		}
+		return exporter, nil
+	case OTLPTransportGRPC, "":
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(cfg.Endpoint),
+		}
+		switch {
+		case cfg.OTLPTLSConfig != nil:
+			opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(cfg.OTLPTLSConfig)))
+		case cfg.OTLPInsecure:
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(cfg.OTLPHeaders) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.OTLPHeaders))
+		}
+		if cfg.Spool != nil {
+			// Failed batches are spooled and replayed instead of retried in memory
+			opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig{Enabled: false}))
+		}
+
+		exporter, err := otlploggrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp log exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("invalid otlp transport type: %s", cfg.OTLPTransportType)
+	}
+}
+
+// levelFilterHandler wraps a slog.Handler to filter by log level.
+// A level set with ContextWithLevel takes precedence over minLevel.
 type levelFilterHandler struct {
 	handler  slog.Handler
 	minLevel slog.Level
 }
 
 func (h *levelFilterHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.minLevel && h.handler.Enabled(ctx, level)
+	return level >= h.levelFor(ctx) && h.handler.Enabled(ctx, level)
 }
 
 func (h *levelFilterHandler) Handle(ctx context.Context, record slog.Record) error {
-	if record.Level >= h.minLevel {
+	if record.Level >= h.levelFor(ctx) {
 		return h.handler.Handle(ctx, record)
 	}
 	return nil
 }
 
+func (h *levelFilterHandler) levelFor(ctx context.Context) slog.Level {
+	if level, ok := LevelFromContext(ctx); ok {
+		return level
+	}
+	return h.minLevel
+}
+
 func (h *levelFilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &levelFilterHandler{
 		handler:  h.handler.WithAttrs(attrs),
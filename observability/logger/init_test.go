@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	_ = w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestInitContextLevel(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"local": {Env: "local", Level: slog.LevelInfo},
+		"json":  {Env: "prod", Level: slog.LevelInfo, JSONOutput: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out := captureStdout(t, func() {
+				_, log, err := Init(context.Background(), cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				log.DebugContext(context.Background(), "hidden")
+				log.DebugContext(ContextWithLevel(context.Background(), slog.LevelDebug), "visible")
+				log.InfoContext(context.Background(), "info")
+			})
+
+			if strings.Contains(out, "hidden") {
+				t.Error("expected debug record without context level to be dropped")
+			}
+			if !strings.Contains(out, "visible") {
+				t.Error("expected debug record with context level to be logged")
+			}
+			if !strings.Contains(out, "info") {
+				t.Error("expected info record to be logged")
+			}
+		})
+	}
+}
+
+func TestInitJSONOutput(t *testing.T) {
+	ctx, sc := spanContext(t)
+	cfg := Config{
+		ServiceName:    "test-service",
+		ServiceVersion: "1.0.0",
+		Env:            "prod",
+		Level:          slog.LevelInfo,
+		JSONOutput:     true,
+	}
+
+	out := captureStdout(t, func() {
+		provider, log, err := Init(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if provider != nil {
+			t.Error("expected no logger provider for plain JSON output")
+		}
+		log.InfoContext(ctx, "json record", "user", "alice")
+	})
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(out), &entry); err != nil {
+		t.Fatalf("expected a single JSON line, got %q: %v", out, err)
+	}
+	for key, want := range map[string]string{
+		"msg":          "json record",
+		"level":        "INFO",
+		"user":         "alice",
+		"service.name": "test-service",
+		TraceIDKey:     sc.TraceID().String(),
+		SpanIDKey:      sc.SpanID().String(),
+	} {
+		if entry[key] != want {
+			t.Errorf("expected %s=%s, got %v", key, want, entry[key])
+		}
+	}
+	if _, ok := entry[slog.SourceKey]; !ok {
+		t.Error("expected source to be added")
+	}
+}
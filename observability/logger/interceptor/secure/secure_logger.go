@@ -2,9 +2,10 @@ package secure
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
-	"regexp"
 
+	"github.com/rshelekhov/golib/observability/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -15,8 +16,12 @@ type SecureLogger struct {
 	log *slog.Logger
 }
 
-func NewSecureLogger(log *slog.Logger) *SecureLogger {
-	return &SecureLogger{log: log}
+// NewSecureLogger creates a gRPC logging interceptor that redacts sensitive data.
+// If no rules are provided, logger.DefaultMaskRules are used.
+func NewSecureLogger(log *slog.Logger, rules ...logger.MaskRule) *SecureLogger {
+	return &SecureLogger{
+		log: slog.New(logger.NewMaskingHandler(log.Handler(), rules...)),
+	}
 }
 
 // UnaryServerInterceptor returns a new unary server interceptor with secure logging
@@ -25,13 +30,13 @@ func (sl *SecureLogger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		// Log request
 		sl.log.Info("request received",
 			slog.String("method", info.FullMethod),
-			slog.Any("request", req),
+			slog.Any("request", sl.maskSensitiveData(req)),
 		)
 
 		// Call handler
 		resp, err := handler(ctx, req)
 
-		// Log response with masked sensitive data
+		// Log response, sensitive fields are redacted by the masking handler
 		if err != nil {
 			sl.log.Error("request failed",
 				slog.String("method", info.FullMethod),
@@ -50,42 +55,27 @@ func (sl *SecureLogger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
-// maskSensitiveData masks tokens and other sensitive information in response
-func (sl *SecureLogger) maskSensitiveData(resp any) any {
-	if resp == nil {
+// maskSensitiveData converts proto messages into a generic map so that
+// the masking handler can redact sensitive fields by key
+func (sl *SecureLogger) maskSensitiveData(msg any) any {
+	if msg == nil {
 		return nil
 	}
 
-	// Convert to proto message if possible
-	if protoMsg, ok := resp.(proto.Message); ok {
-		// Marshal to JSON
-		jsonBytes, err := protojson.Marshal(protoMsg)
-		if err != nil {
-			return resp
-		}
-
-		jsonStr := string(jsonBytes)
-
-		// Mask sensitive fields
-		jsonStr = sl.maskJSONField(jsonStr, "accessToken")
-		jsonStr = sl.maskJSONField(jsonStr, "refreshToken")
-		jsonStr = sl.maskJSONField(jsonStr, "access_token")
-		jsonStr = sl.maskJSONField(jsonStr, "refresh_token")
-		jsonStr = sl.maskJSONField(jsonStr, "password")
-		jsonStr = sl.maskJSONField(jsonStr, "token")
-
-		return jsonStr
+	protoMsg, ok := msg.(proto.Message)
+	if !ok {
+		return msg
 	}
 
-	return resp
-}
+	jsonBytes, err := protojson.Marshal(protoMsg)
+	if err != nil {
+		return msg
+	}
 
-// maskJSONField masks a specific field in JSON string using regex
-func (sl *SecureLogger) maskJSONField(jsonStr, fieldName string) string {
-	// Regex pattern to match: "fieldName":"any_value"
-	pattern := `("` + regexp.QuoteMeta(fieldName) + `"):"([^"]*?)"`
-	re := regexp.MustCompile(pattern)
+	var fields map[string]any
+	if err := json.Unmarshal(jsonBytes, &fields); err != nil {
+		return msg
+	}
 
-	// Replace with masked value
-	return re.ReplaceAllString(jsonStr, `$1:"***MASKED***"`)
+	return fields
 }
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// MaskedValue is the placeholder that replaces redacted values
+const MaskedValue = "***MASKED***"
+
+// MaskRule describes which attribute values should be redacted by MaskingHandler.
+// A rule matches either by attribute key (Keys) or by value content (Pattern).
+type MaskRule struct {
+	// Keys are attribute keys whose values are fully replaced.
+	// Matching ignores case, '_' and '-', so "access_token" also matches "accessToken".
+	Keys []string
+	// Pattern, if set, is applied to every string value and each match is replaced.
+	Pattern *regexp.Regexp
+	// Verify, if set, is called with each match of Pattern; only matches it accepts are replaced.
+	Verify func(match string) bool
+	// Replacement is used instead of MaskedValue when not empty.
+	Replacement string
+}
+
+// MaskKeys returns a rule that redacts values of the given attribute keys
+func MaskKeys(keys ...string) MaskRule {
+	return MaskRule{Keys: keys}
+}
+
+// MaskPattern returns a rule that redacts parts of string values matching the pattern
+func MaskPattern(pattern *regexp.Regexp) MaskRule {
+	return MaskRule{Pattern: pattern}
+}
+
+// cardNumberPattern matches 13-19 digit card numbers optionally separated by spaces or dashes
+var cardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// validCardNumber reports whether the digits of s pass the Luhn checksum of card numbers,
+// so IDs and timestamps of the same length are mostly left alone
+func validCardNumber(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return sum%10 == 0
+}
+
+// DefaultMaskRules returns rules covering common secrets: passwords, tokens,
+// authorization headers and payment card numbers
+func DefaultMaskRules() []MaskRule {
+	return []MaskRule{
+		MaskKeys(
+			"password",
+			"secret",
+			"token",
+			"access_token",
+			"refresh_token",
+			"id_token",
+			"api_key",
+			"authorization",
+			"cookie",
+		),
+		{Pattern: cardNumberPattern, Verify: validCardNumber},
+	}
+}
+
+// MaskingHandler wraps a slog.Handler and redacts sensitive attribute values
+// of every record before passing it to the next handler
+type MaskingHandler struct {
+	next     slog.Handler
+	keys     map[string]string
+	patterns []maskPattern
+}
+
+type maskPattern struct {
+	re          *regexp.Regexp
+	verify      func(match string) bool
+	replacement string
+}
+
+// NewMaskingHandler creates a handler that applies the given rules to all records.
+// If no rules are provided, DefaultMaskRules are used.
+func NewMaskingHandler(next slog.Handler, rules ...MaskRule) *MaskingHandler {
+	if len(rules) == 0 {
+		rules = DefaultMaskRules()
+	}
+
+	h := &MaskingHandler{
+		next: next,
+		keys: make(map[string]string),
+	}
+
+	for _, rule := range rules {
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = MaskedValue
+		}
+		for _, k := range rule.Keys {
+			h.keys[normalizeMaskKey(k)] = replacement
+		}
+		if rule.Pattern != nil {
+			h.patterns = append(h.patterns, maskPattern{re: rule.Pattern, verify: rule.Verify, replacement: replacement})
+		}
+	}
+
+	return h
+}
+
+func (h *MaskingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *MaskingHandler) Handle(ctx context.Context, record slog.Record) error {
+	masked := slog.NewRecord(record.Time, record.Level, h.maskString(record.Message), record.PC)
+
+	record.Attrs(func(a slog.Attr) bool {
+		masked.AddAttrs(h.maskAttr(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, masked)
+}
+
+func (h *MaskingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = h.maskAttr(a)
+	}
+
+	return &MaskingHandler{
+		next:     h.next.WithAttrs(masked),
+		keys:     h.keys,
+		patterns: h.patterns,
+	}
+}
+
+func (h *MaskingHandler) WithGroup(name string) slog.Handler {
+	return &MaskingHandler{
+		next:     h.next.WithGroup(name),
+		keys:     h.keys,
+		patterns: h.patterns,
+	}
+}
+
+// maskAttr redacts a single attribute, descending into groups
+func (h *MaskingHandler) maskAttr(a slog.Attr) slog.Attr {
+	if replacement, ok := h.keys[normalizeMaskKey(a.Key)]; ok {
+		return slog.String(a.Key, replacement)
+	}
+
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		masked := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			masked[i] = h.maskAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(masked...)}
+	case slog.KindString:
+		return slog.String(a.Key, h.maskString(v.String()))
+	case slog.KindAny:
+		return slog.Any(a.Key, h.maskAny(v.Any()))
+	default:
+		return slog.Attr{Key: a.Key, Value: v}
+	}
+}
+
+// maskAny redacts decoded JSON-like payloads (maps and slices) and errors
+func (h *MaskingHandler) maskAny(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(val))
+		for k, item := range val {
+			if replacement, ok := h.keys[normalizeMaskKey(k)]; ok {
+				masked[k] = replacement
+				continue
+			}
+			masked[k] = h.maskAny(item)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(val))
+		for i, item := range val {
+			masked[i] = h.maskAny(item)
+		}
+		return masked
+	case string:
+		return h.maskString(val)
+	case error:
+		return h.maskString(val.Error())
+	default:
+		return v
+	}
+}
+
+func (h *MaskingHandler) maskString(s string) string {
+	for _, p := range h.patterns {
+		if p.verify == nil {
+			s = p.re.ReplaceAllString(s, p.replacement)
+			continue
+		}
+		s = p.re.ReplaceAllStringFunc(s, func(match string) string {
+			if !p.verify(match) {
+				return match
+			}
+			return p.replacement
+		})
+	}
+	return s
+}
+
+// normalizeMaskKey lowercases the key and strips separators so that
+// snake_case, kebab-case and camelCase spellings match the same rule
+func normalizeMaskKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newMaskingTestLogger(buf *bytes.Buffer, rules ...MaskRule) *slog.Logger {
+	return slog.New(NewMaskingHandler(slog.NewJSONHandler(buf, nil), rules...))
+}
+
+func decodeLogLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log line %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestMaskingHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(l *slog.Logger)
+		path     []string
+		expected any
+	}{
+		{
+			name:     "Masks configured key",
+			log:      func(l *slog.Logger) { l.Info("login", "password", "hunter2") },
+			path:     []string{"password"},
+			expected: MaskedValue,
+		},
+		{
+			name:     "Matches camelCase spelling of snake_case key",
+			log:      func(l *slog.Logger) { l.Info("login", "accessToken", "abc") },
+			path:     []string{"accessToken"},
+			expected: MaskedValue,
+		},
+		{
+			name:     "Matches key case-insensitively",
+			log:      func(l *slog.Logger) { l.Info("request", "Authorization", "Bearer abc") },
+			path:     []string{"Authorization"},
+			expected: MaskedValue,
+		},
+		{
+			name:     "Leaves other keys untouched",
+			log:      func(l *slog.Logger) { l.Info("login", "user_id", "42") },
+			path:     []string{"user_id"},
+			expected: "42",
+		},
+		{
+			name: "Masks keys inside groups",
+			log: func(l *slog.Logger) {
+				l.Info("login", slog.Group("credentials", slog.String("password", "hunter2")))
+			},
+			path:     []string{"credentials", "password"},
+			expected: MaskedValue,
+		},
+		{
+			name: "Masks keys inside maps",
+			log: func(l *slog.Logger) {
+				l.Info("payload", "body", map[string]any{"refresh_token": "xyz", "name": "john"})
+			},
+			path:     []string{"body", "refresh_token"},
+			expected: MaskedValue,
+		},
+		{
+			name:     "Masks attributes added with With",
+			log:      func(l *slog.Logger) { l.With("token", "abc").Info("request") },
+			path:     []string{"token"},
+			expected: MaskedValue,
+		},
+		{
+			name:     "Masks card numbers in string values",
+			log:      func(l *slog.Logger) { l.Info("payment", "note", "card 4111 1111 1111 1111 declined") },
+			path:     []string{"note"},
+			expected: "card " + MaskedValue + " declined",
+		},
+		{
+			name:     "Masks unseparated card numbers",
+			log:      func(l *slog.Logger) { l.Info("payment", "card", "5500000000000004") },
+			path:     []string{"card"},
+			expected: MaskedValue,
+		},
+		{
+			name:     "Keeps unix nano timestamps failing the card checksum",
+			log:      func(l *slog.Logger) { l.Info("event", "ts", "1760659565123456789") },
+			path:     []string{"ts"},
+			expected: "1760659565123456789",
+		},
+		{
+			name:     "Keeps long IDs failing the card checksum",
+			log:      func(l *slog.Logger) { l.Info("order", "note", "order 1854012345678901234 shipped") },
+			path:     []string{"note"},
+			expected: "order 1854012345678901234 shipped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(newMaskingTestLogger(&buf))
+
+			var value any = decodeLogLine(t, &buf)
+			for _, key := range tt.path {
+				value = value.(map[string]any)[key]
+			}
+
+			if value != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestValidCardNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{number: "4111 1111 1111 1111", valid: true},
+		{number: "4111-1111-1111-1112", valid: false},
+		{number: "378282246310005", valid: true},
+		{number: "1760659565123456789", valid: false},
+	}
+
+	for _, tt := range tests {
+		if got := validCardNumber(tt.number); got != tt.valid {
+			t.Errorf("validCardNumber(%q) = %v, want %v", tt.number, got, tt.valid)
+		}
+	}
+}
+
+func TestMaskingHandlerCustomRules(t *testing.T) {
+	var buf bytes.Buffer
+	log := newMaskingTestLogger(&buf,
+		MaskKeys("ssn"),
+		MaskRule{Pattern: regexp.MustCompile(`[\w.]+@[\w.]+`), Replacement: "<email>"},
+	)
+
+	log.Info("user registered", "ssn", "123-45-6789", "contact", "john@example.com", "password", "kept")
+
+	entry := decodeLogLine(t, &buf)
+	if entry["ssn"] != MaskedValue {
+		t.Errorf("expected ssn to be masked, got %v", entry["ssn"])
+	}
+	if entry["contact"] != "<email>" {
+		t.Errorf("expected contact to be replaced, got %v", entry["contact"])
+	}
+	// Custom rules replace the defaults
+	if entry["password"] != "kept" {
+		t.Errorf("expected password to be kept with custom rules, got %v", entry["password"])
+	}
+	if strings.Contains(buf.String(), "john@example.com") {
+		t.Errorf("email leaked into output: %s", buf.String())
+	}
+}
@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rshelekhov/golib/observability/scope"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// metricsMeterName is the instrumentation scope of MetricsHandler metrics
+const metricsMeterName = "github.com/rshelekhov/golib/observability/logger"
+
+const (
+	// LoggerNameKey is the attribute that names a logger, e.g. log.With(logger.LoggerNameKey, "payments").
+	// MetricsHandler uses its value as the logger label.
+	LoggerNameKey = "logger"
+
+	// DefaultErrorBurstWindow is the window used when MetricsConfig.ErrorBurstWindow is zero
+	DefaultErrorBurstWindow = time.Minute
+
+	// errorBurstBuckets is the number of buckets the error burst window is split into
+	errorBurstBuckets = 12
+)
+
+// MetricsConfig configures MetricsHandler
+type MetricsConfig struct {
+	// Name is the logger label of records logged without a LoggerNameKey attribute
+	Name string
+	// MeterProvider creates the instruments. Defaults to the global meter provider.
+	MeterProvider metric.MeterProvider
+	// ErrorBurstWindow is the sliding window of the log_errors_recent gauge.
+	// Defaults to DefaultErrorBurstWindow.
+	ErrorBurstWindow time.Duration
+}
+
+// MetricsHandler counts records by level and logger name before passing them to the next handler,
+// so error spikes can be alerted on even when the log pipeline lags. It reports:
+//   - log_records_total{level, logger} - counter of handled records
+//   - log_errors_recent{logger} - gauge of error records within the last ErrorBurstWindow
+type MetricsHandler struct {
+	next     slog.Handler
+	name     string
+	recorder *metricsRecorder
+}
+
+// metricsRecorder holds instruments and error windows shared by a handler and all handlers derived from it
+type metricsRecorder struct {
+	records metric.Int64Counter
+	window  time.Duration
+
+	mu     sync.Mutex
+	errors map[string]*burstWindow
+}
+
+// NewMetricsHandler creates a handler that records metrics for every handled record
+func NewMetricsHandler(next slog.Handler, cfg MetricsConfig) *MetricsHandler {
+	if cfg.MeterProvider == nil {
+		cfg.MeterProvider = otel.GetMeterProvider()
+	}
+	if cfg.ErrorBurstWindow <= 0 {
+		cfg.ErrorBurstWindow = DefaultErrorBurstWindow
+	}
+
+	r := &metricsRecorder{
+		window: cfg.ErrorBurstWindow,
+		errors: make(map[string]*burstWindow),
+	}
+
+	meter := scope.Lookup(metricsMeterName).Meter(cfg.MeterProvider)
+
+	var err error
+	r.records, err = meter.Int64Counter(
+		"log_records_total",
+		metric.WithDescription("Total number of log records by level and logger"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		"log_errors_recent",
+		metric.WithDescription("Number of error log records within the recent window"),
+		metric.WithInt64Callback(r.observeErrors),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &MetricsHandler{
+		next:     next,
+		name:     cfg.Name,
+		recorder: r,
+	}
+}
+
+func (h *MetricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *MetricsHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.recorder.record(ctx, h.name, record)
+	return h.next.Handle(ctx, record)
+}
+
+func (h *MetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	name := h.name
+	for _, a := range attrs {
+		if a.Key == LoggerNameKey {
+			name = a.Value.String()
+		}
+	}
+	return &MetricsHandler{next: h.next.WithAttrs(attrs), name: name, recorder: h.recorder}
+}
+
+func (h *MetricsHandler) WithGroup(name string) slog.Handler {
+	return &MetricsHandler{next: h.next.WithGroup(name), name: h.name, recorder: h.recorder}
+}
+
+func (r *metricsRecorder) record(ctx context.Context, name string, record slog.Record) {
+	if r.records != nil {
+		r.records.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("level", levelLabel(record.Level)),
+			attribute.String("logger", name),
+		))
+	}
+
+	if record.Level < slog.LevelError {
+		return
+	}
+
+	now := record.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.errors[name]
+	if !ok {
+		w = newBurstWindow(r.window)
+		r.errors[name] = w
+	}
+	w.add(now)
+}
+
+func (r *metricsRecorder) observeErrors(_ context.Context, o metric.Int64Observer) error {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, w := range r.errors {
+		o.Observe(w.count(now), metric.WithAttributes(attribute.String("logger", name)))
+	}
+	return nil
+}
+
+// levelLabel maps custom levels to the nearest standard level below them to bound label cardinality
+func levelLabel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// burstWindow counts events within a sliding window split into fixed buckets
+type burstWindow struct {
+	bucket time.Duration
+	counts [errorBurstBuckets]int64
+	starts [errorBurstBuckets]time.Time
+}
+
+func newBurstWindow(window time.Duration) *burstWindow {
+	return &burstWindow{bucket: max(window/errorBurstBuckets, 1)}
+}
+
+func (w *burstWindow) add(now time.Time) {
+	start := now.Truncate(w.bucket)
+	i := int(start.UnixNano()/int64(w.bucket)) % errorBurstBuckets
+	if !w.starts[i].Equal(start) {
+		w.starts[i] = start
+		w.counts[i] = 0
+	}
+	w.counts[i]++
+}
+
+func (w *burstWindow) count(now time.Time) int64 {
+	oldest := now.Truncate(w.bucket).Add(-w.bucket * (errorBurstBuckets - 1))
+
+	var total int64
+	for i, start := range w.starts {
+		if !start.Before(oldest) && !start.After(now) {
+			total += w.counts[i]
+		}
+	}
+	return total
+}
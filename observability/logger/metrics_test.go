@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	log := slog.New(NewMetricsHandler(slog.NewTextHandler(io.Discard, nil), MetricsConfig{
+		Name:          "app",
+		MeterProvider: mp,
+	}))
+	payments := log.With(LoggerNameKey, "payments")
+
+	log.Info("started")
+	log.Error("failed")
+	payments.Error("charge failed")
+	payments.Error("charge failed")
+	payments.Log(context.Background(), slog.LevelWarn+2, "retrying")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("failed to collect metrics: %v", err)
+	}
+
+	records := map[[2]string]int64{}
+	errors := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					level, _ := dp.Attributes.Value(attribute.Key("level"))
+					name, _ := dp.Attributes.Value(attribute.Key("logger"))
+					records[[2]string{level.AsString(), name.AsString()}] = dp.Value
+				}
+			case metricdata.Gauge[int64]:
+				for _, dp := range data.DataPoints {
+					name, _ := dp.Attributes.Value(attribute.Key("logger"))
+					errors[name.AsString()] = dp.Value
+				}
+			}
+		}
+	}
+
+	expected := map[[2]string]int64{
+		{"info", "app"}:       1,
+		{"error", "app"}:      1,
+		{"error", "payments"}: 2,
+		{"warn", "payments"}:  1,
+	}
+	for key, want := range expected {
+		if records[key] != want {
+			t.Errorf("expected log_records_total%v = %d, got %d", key, want, records[key])
+		}
+	}
+
+	if errors["app"] != 1 || errors["payments"] != 2 {
+		t.Errorf("expected recent errors app=1 payments=2, got %v", errors)
+	}
+}
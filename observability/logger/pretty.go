@@ -6,21 +6,31 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"path/filepath"
+	"runtime"
+	"sync"
 
 	"github.com/fatih/color"
 )
 
 type PrettyHandlerOptions struct {
-	AddSource   bool
-	Level       slog.Leveler
+	// AddSource adds the file:line of the log call to the output
+	AddSource bool
+	Level     slog.Leveler
+	// ReplaceAttr has the same semantics as slog.HandlerOptions.ReplaceAttr:
+	// it is called for the built-in time, level, message and source attributes
+	// with nil groups, and for every other non-group attribute with the groups it is nested in
 	ReplaceAttr func(groups []string, attr slog.Attr) slog.Attr
 }
 
 type PrettyHandler struct {
-	out   io.Writer
-	opts  PrettyHandlerOptions
-	attrs []slog.Attr
-	group string
+	out  io.Writer
+	opts PrettyHandlerOptions
+	mu   *sync.Mutex
+	// fields holds attributes added with WithAttrs, already nested into their groups
+	fields map[string]any
+	groups []string
 }
 
 func NewPrettyHandler(out io.Writer, opts *PrettyHandlerOptions) *PrettyHandler {
@@ -28,8 +38,10 @@ func NewPrettyHandler(out io.Writer, opts *PrettyHandlerOptions) *PrettyHandler
 		opts = &PrettyHandlerOptions{}
 	}
 	return &PrettyHandler{
-		out:  out,
-		opts: *opts,
+		out:    out,
+		opts:   *opts,
+		mu:     &sync.Mutex{},
+		fields: make(map[string]any),
 	}
 }
 
@@ -41,71 +53,198 @@ func (h *PrettyHandler) Enabled(_ context.Context, level slog.Level) bool {
 }
 
 func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
-	level := r.Level.String() + ":"
-
-	switch r.Level {
-	case slog.LevelDebug:
-		level = color.MagentaString(level)
-	case slog.LevelInfo:
-		level = color.BlueString(level)
-	case slog.LevelWarn:
-		level = color.YellowString(level)
-	case slog.LevelError:
-		level = color.RedString(level)
+	timeStr := ""
+	if !r.Time.IsZero() {
+		if a, ok := h.replaceBuiltin(slog.Time(slog.TimeKey, r.Time)); ok {
+			if a.Value.Kind() == slog.KindTime {
+				timeStr = a.Value.Time().Format("[15:04:05.000]")
+			} else {
+				timeStr = a.Value.String()
+			}
+		}
 	}
 
-	fields := make(map[string]interface{}, r.NumAttrs())
+	level := ""
+	if a, ok := h.replaceBuiltin(slog.Any(slog.LevelKey, r.Level)); ok {
+		level = a.Value.String() + ":"
+		switch r.Level {
+		case slog.LevelDebug:
+			level = color.MagentaString(level)
+		case slog.LevelInfo:
+			level = color.BlueString(level)
+		case slog.LevelWarn:
+			level = color.YellowString(level)
+		case slog.LevelError:
+			level = color.RedString(level)
+		}
+	}
 
-	r.Attrs(func(a slog.Attr) bool {
-		fields[a.Key] = a.Value.Any()
-		return true
-	})
+	msg := ""
+	if a, ok := h.replaceBuiltin(slog.String(slog.MessageKey, r.Message)); ok {
+		msg = color.CyanString(a.Value.String())
+	}
 
-	for _, a := range h.attrs {
-		fields[a.Key] = a.Value.Any()
+	source := ""
+	if h.opts.AddSource && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ := frames.Next()
+		src := fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		if a, ok := h.replaceBuiltin(slog.String(slog.SourceKey, src)); ok {
+			source = color.HiBlackString(a.Value.String())
+		}
 	}
 
-	var b []byte
-	var err error
+	fields := cloneFields(h.fields)
 
+	if r.NumAttrs() > 0 {
+		target := fields
+		for _, g := range h.groups {
+			target = nestedFields(target, g)
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			h.addAttr(target, h.groups, a)
+			return true
+		})
+	}
+
+	attrs := ""
 	if len(fields) > 0 {
-		b, err = json.MarshalIndent(fields, "", "  ")
+		b, err := json.MarshalIndent(fields, "", "  ")
 		if err != nil {
 			return err
 		}
+		attrs = color.WhiteString(string(b))
 	}
 
-	timeStr := r.Time.Format("[15:05:05.000]")
-	msg := color.CyanString(r.Message)
+	line := ""
+	for _, part := range []string{timeStr, level, source, msg, attrs} {
+		if part == "" {
+			continue
+		}
+		if line != "" {
+			line += " "
+		}
+		line += part
+	}
 
-	fmt.Fprintf(h.out, "%s %s %s %s\n",
-		timeStr,
-		level,
-		msg,
-		color.WhiteString(string(b)),
-	)
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	return nil
+	_, err := fmt.Fprintln(h.out, line)
+	return err
 }
 
 func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newAttrs := make([]slog.Attr, len(h.attrs)+len(attrs))
-	copy(newAttrs, h.attrs)
-	copy(newAttrs[len(h.attrs):], attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+
+	fields := cloneFields(h.fields)
+	target := fields
+	for _, g := range h.groups {
+		target = nestedFields(target, g)
+	}
+	for _, a := range attrs {
+		h.addAttr(target, h.groups, a)
+	}
 
 	return &PrettyHandler{
-		out:   h.out,
-		opts:  h.opts,
-		attrs: newAttrs,
-		group: h.group,
+		out:    h.out,
+		opts:   h.opts,
+		mu:     h.mu,
+		fields: fields,
+		groups: h.groups,
 	}
 }
 
 func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups)+1)
+	copy(groups, h.groups)
+	groups[len(h.groups)] = name
+
 	return &PrettyHandler{
-		out:   h.out,
-		opts:  h.opts,
-		attrs: h.attrs,
-		group: name,
+		out:    h.out,
+		opts:   h.opts,
+		mu:     h.mu,
+		fields: h.fields,
+		groups: groups,
+	}
+}
+
+// replaceBuiltin applies ReplaceAttr to a built-in attribute.
+// It reports false if the attribute was dropped.
+func (h *PrettyHandler) replaceBuiltin(a slog.Attr) (slog.Attr, bool) {
+	if h.opts.ReplaceAttr == nil {
+		return a, true
+	}
+	a = h.opts.ReplaceAttr(nil, a)
+	a.Value = a.Value.Resolve()
+	return a, a.Key != ""
+}
+
+// addAttr stores the attribute in fields, nesting group values and
+// applying ReplaceAttr to non-group attributes
+func (h *PrettyHandler) addAttr(fields map[string]any, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		// Groups with an empty key are inlined, as in slog
+		target := fields
+		nested := groups
+		if a.Key != "" {
+			target = nestedFields(fields, a.Key)
+			nested = append(groups[:len(groups):len(groups)], a.Key)
+		}
+		for _, ga := range attrs {
+			h.addAttr(target, nested, ga)
+		}
+		return
+	}
+
+	if h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Key == "" {
+		return
+	}
+
+	if err, ok := a.Value.Any().(error); ok {
+		fields[a.Key] = err.Error()
+		return
+	}
+	fields[a.Key] = a.Value.Any()
+}
+
+// nestedFields returns the map stored under key, creating it if needed
+func nestedFields(fields map[string]any, key string) map[string]any {
+	if nested, ok := fields[key].(map[string]any); ok {
+		return nested
+	}
+	nested := make(map[string]any)
+	fields[key] = nested
+	return nested
+}
+
+// cloneFields deep-copies nested group maps so that derived handlers and
+// concurrent records never share mutable state
+func cloneFields(fields map[string]any) map[string]any {
+	cloned := maps.Clone(fields)
+	if cloned == nil {
+		cloned = make(map[string]any)
+	}
+	for k, v := range cloned {
+		if nested, ok := v.(map[string]any); ok {
+			cloned[k] = cloneFields(nested)
+		}
 	}
+	return cloned
 }
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// decodePrettyFields extracts the JSON attributes block from a pretty log line
+func decodePrettyFields(t *testing.T, line string) map[string]any {
+	t.Helper()
+
+	start := strings.Index(line, "{")
+	if start < 0 {
+		t.Fatalf("no attributes in log line %q", line)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line[start:]), &fields); err != nil {
+		t.Fatalf("failed to decode attributes of %q: %v", line, err)
+	}
+	return fields
+}
+
+func TestPrettyHandler(t *testing.T) {
+	color.NoColor = true
+
+	t.Run("Nests attributes into groups", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(NewPrettyHandler(&buf, nil))
+
+		log.With("service", "payment").
+			WithGroup("request").With("method", "GET").
+			WithGroup("user").Info("handled", "id", 42)
+
+		fields := decodePrettyFields(t, buf.String())
+		if fields["service"] != "payment" {
+			t.Errorf("expected top-level service attribute, got %v", fields)
+		}
+		request, _ := fields["request"].(map[string]any)
+		if request["method"] != "GET" {
+			t.Errorf("expected request.method, got %v", fields)
+		}
+		user, _ := request["user"].(map[string]any)
+		if user["id"] != float64(42) {
+			t.Errorf("expected request.user.id, got %v", fields)
+		}
+	})
+
+	t.Run("Applies ReplaceAttr with groups", func(t *testing.T) {
+		var buf bytes.Buffer
+		var seen []string
+		log := slog.New(NewPrettyHandler(&buf, &PrettyHandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey || a.Key == "drop" {
+					return slog.Attr{}
+				}
+				if a.Key == "secret" {
+					seen = append(seen, strings.Join(groups, "."))
+					return slog.String(a.Key, "redacted")
+				}
+				return a
+			},
+		}))
+
+		log.Info("login", slog.Group("auth", "secret", "hunter2", "drop", true))
+
+		out := buf.String()
+		if strings.HasPrefix(out, "[") {
+			t.Errorf("expected time to be dropped, got %q", out)
+		}
+		auth, _ := decodePrettyFields(t, out)["auth"].(map[string]any)
+		if auth["secret"] != "redacted" {
+			t.Errorf("expected secret to be replaced, got %v", auth)
+		}
+		if _, ok := auth["drop"]; ok {
+			t.Errorf("expected drop to be removed, got %v", auth)
+		}
+		if len(seen) != 1 || seen[0] != "auth" {
+			t.Errorf("expected ReplaceAttr to receive groups [auth], got %v", seen)
+		}
+	})
+
+	t.Run("Adds source", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(NewPrettyHandler(&buf, &PrettyHandlerOptions{AddSource: true}))
+
+		log.Info("with source")
+
+		if !strings.Contains(buf.String(), "pretty_test.go:") {
+			t.Errorf("expected source location in %q", buf.String())
+		}
+	})
+}
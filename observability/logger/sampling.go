@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultSamplingTick is the sampling window used when SamplingConfig.Tick is zero
+const DefaultSamplingTick = time.Second
+
+// SamplingConfig controls how many records with the same level and message
+// pass through SamplingHandler within each Tick
+type SamplingConfig struct {
+	// Initial is the number of records logged per key in each tick before sampling starts
+	Initial int
+	// Thereafter logs every Thereafter-th record once Initial is exceeded.
+	// Zero drops all records above Initial.
+	Thereafter int
+	// Tick is the sampling window. Defaults to DefaultSamplingTick.
+	Tick time.Duration
+	// MinLevel disables sampling for records at or above this level.
+	// Defaults to slog.LevelWarn, so warnings and errors are always kept.
+	MinLevel slog.Leveler
+	// OnDropped, if set, is called for every record dropped by the sampler
+	OnDropped func(ctx context.Context, record slog.Record)
+}
+
+// SamplingHandler drops repetitive records so hot-path logs don't overwhelm
+// the exporter. Records are keyed by level and message.
+type SamplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+type samplingKey struct {
+	level   slog.Level
+	message string
+}
+
+// sampler holds counters shared by a handler and all handlers derived from it
+type sampler struct {
+	cfg SamplingConfig
+
+	mu       sync.Mutex
+	resetAt  time.Time
+	counters map[samplingKey]int
+}
+
+// NewSamplingHandler creates a handler that samples records before passing them to next
+func NewSamplingHandler(next slog.Handler, cfg SamplingConfig) *SamplingHandler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = DefaultSamplingTick
+	}
+	if cfg.MinLevel == nil {
+		cfg.MinLevel = slog.LevelWarn
+	}
+
+	return &SamplingHandler{
+		next: next,
+		sampler: &sampler{
+			cfg:      cfg,
+			counters: make(map[samplingKey]int),
+		},
+	}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.sampler.allow(record) {
+		if h.sampler.cfg.OnDropped != nil {
+			h.sampler.cfg.OnDropped(ctx, record)
+		}
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+// allow reports whether the record should be logged
+func (s *sampler) allow(record slog.Record) bool {
+	if record.Level >= s.cfg.MinLevel.Level() {
+		return true
+	}
+
+	now := record.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Counters are reset every tick, which also bounds the map size
+	if !now.Before(s.resetAt) {
+		clear(s.counters)
+		s.resetAt = now.Add(s.cfg.Tick)
+	}
+
+	key := samplingKey{level: record.Level, message: record.Message}
+	s.counters[key]++
+	n := s.counters[key]
+
+	if n <= s.cfg.Initial {
+		return true
+	}
+	if s.cfg.Thereafter <= 0 {
+		return false
+	}
+	return (n-s.cfg.Initial)%s.cfg.Thereafter == 0
+}
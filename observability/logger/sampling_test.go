@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	dropped := 0
+	log := slog.New(NewSamplingHandler(slog.NewTextHandler(&buf, nil), SamplingConfig{
+		Initial:    2,
+		Thereafter: 3,
+		Tick:       time.Hour,
+		OnDropped:  func(context.Context, slog.Record) { dropped++ },
+	}))
+
+	for i := 0; i < 10; i++ {
+		log.Info("hot path")
+		log.With("attempt", i).Error("always logged")
+	}
+	log.Info("other message")
+
+	// 2 initial records + every 3rd of the remaining 8
+	if got := strings.Count(buf.String(), "hot path"); got != 4 {
+		t.Errorf("expected 4 sampled records, got %d", got)
+	}
+	if dropped != 6 {
+		t.Errorf("expected 6 dropped records, got %d", dropped)
+	}
+	if got := strings.Count(buf.String(), "always logged"); got != 10 {
+		t.Errorf("expected errors not to be sampled, got %d", got)
+	}
+	if !strings.Contains(buf.String(), "other message") {
+		t.Error("expected other messages to be counted separately")
+	}
+}
+
+func TestSamplingHandlerResetsEveryTick(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewSamplingHandler(slog.NewTextHandler(&buf, nil), SamplingConfig{Initial: 1, Tick: time.Second})
+
+	start := time.Now()
+	for _, ts := range []time.Time{start, start.Add(time.Millisecond), start.Add(2 * time.Second)} {
+		if err := handler.Handle(context.Background(), slog.NewRecord(ts, slog.LevelInfo, "tick", 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := strings.Count(buf.String(), "tick"); got != 2 {
+		t.Errorf("expected one record per tick, got %d", got)
+	}
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys used for trace correlation in plain slog output
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// traceContextHandler adds trace_id and span_id of the active span to every record.
+// The OTel bridge does this on its own, so it is used only for plain slog handlers.
+type traceContextHandler struct {
+	handler slog.Handler
+}
+
+// NewTraceContextHandler wraps a handler so that records logged within an active span
+// include its trace_id and span_id attributes
+func NewTraceContextHandler(handler slog.Handler) slog.Handler {
+	return &traceContextHandler{handler: handler}
+}
+
+func (h *traceContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *traceContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		record = record.Clone()
+		record.AddAttrs(
+			slog.String(TraceIDKey, sc.TraceID().String()),
+			slog.String(SpanIDKey, sc.SpanID().String()),
+		)
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *traceContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceContextHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *traceContextHandler) WithGroup(name string) slog.Handler {
+	return &traceContextHandler{handler: h.handler.WithGroup(name)}
+}
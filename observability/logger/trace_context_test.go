@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanContext returns a context with a valid remote span context
+func spanContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	return trace.ContextWithRemoteSpanContext(context.Background(), sc), sc
+}
+
+func TestTraceContextHandler(t *testing.T) {
+	ctx, sc := spanContext(t)
+
+	t.Run("adds trace and span ids within a span", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(NewTraceContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+		logger.InfoContext(ctx, "traced")
+		entry := decodeLogLine(t, &buf)
+		if entry[TraceIDKey] != sc.TraceID().String() {
+			t.Errorf("expected %s=%s, got %v", TraceIDKey, sc.TraceID(), entry[TraceIDKey])
+		}
+		if entry[SpanIDKey] != sc.SpanID().String() {
+			t.Errorf("expected %s=%s, got %v", SpanIDKey, sc.SpanID(), entry[SpanIDKey])
+		}
+	})
+
+	t.Run("leaves records outside a span unchanged", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(NewTraceContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+		logger.InfoContext(context.Background(), "untraced")
+		entry := decodeLogLine(t, &buf)
+		if _, ok := entry[TraceIDKey]; ok {
+			t.Errorf("expected no %s, got %v", TraceIDKey, entry[TraceIDKey])
+		}
+		if _, ok := entry[SpanIDKey]; ok {
+			t.Errorf("expected no %s, got %v", SpanIDKey, entry[SpanIDKey])
+		}
+	})
+
+	t.Run("keeps adding ids after WithAttrs", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(NewTraceContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "api")
+
+		logger.InfoContext(ctx, "traced")
+		entry := decodeLogLine(t, &buf)
+		if entry["component"] != "api" {
+			t.Errorf("expected component=api, got %v", entry["component"])
+		}
+		if entry[TraceIDKey] != sc.TraceID().String() {
+			t.Errorf("expected %s=%s, got %v", TraceIDKey, sc.TraceID(), entry[TraceIDKey])
+		}
+	})
+
+	t.Run("delegates Enabled", func(t *testing.T) {
+		handler := NewTraceContextHandler(slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		if handler.Enabled(ctx, slog.LevelInfo) {
+			t.Error("expected info to be disabled")
+		}
+		if !handler.Enabled(ctx, slog.LevelWarn) {
+			t.Error("expected warn to be enabled")
+		}
+	})
+}
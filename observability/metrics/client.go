@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	httpClientRequestsCounter  metric.Int64Counter
+	httpClientLatencyHistogram metric.Float64Histogram
+	initHTTPClientMetricsOnce  sync.Once
+	initHTTPClientMetricsErr   error
+)
+
+func initHTTPClientMetrics() error {
+	initHTTPClientMetricsOnce.Do(func() {
+		inst := newInstruments()
+		httpClientRequestsCounter = inst.int64Counter(
+			"http_client_requests_total",
+			metric.WithDescription("Total number of outgoing HTTP requests."),
+		)
+		httpClientLatencyHistogram = inst.float64Histogram(
+			"http_client_request_duration_seconds",
+			metric.WithDescription("Outgoing HTTP request latency in seconds, until response headers are received."),
+		)
+		initHTTPClientMetricsErr = inst.err()
+	})
+	return initHTTPClientMetricsErr
+}
+
+type clientRouteKey struct{}
+
+// ContextWithClientRoute sets the route template of an outgoing request, e.g. "/users/{id}",
+// used as the route label by ClientTransport. Without it the label is empty to keep cardinality bounded.
+func ContextWithClientRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, clientRouteKey{}, route)
+}
+
+// ClientTransport is an http.RoundTripper recording metrics of outgoing requests:
+//   - http_client_requests_total{method, host, route, status} - status is "error" when no response was received
+//   - http_client_request_duration_seconds{method, host, route}
+type ClientTransport struct {
+	base http.RoundTripper
+}
+
+// NewClientTransport wraps base with request metrics. A nil base means http.DefaultTransport.
+//
+//	client := &http.Client{Transport: metrics.NewClientTransport(nil)}
+func NewClientTransport(base http.RoundTripper) *ClientTransport {
+	initHTTPClientMetrics()
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &ClientTransport{base: base}
+}
+
+func (t *ClientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	ctx := req.Context()
+	route, _ := ctx.Value(clientRouteKey{}).(string)
+	attrs := []attribute.KeyValue{
+		attribute.String("method", req.Method),
+		attribute.String("host", req.URL.Host),
+		attribute.String("route", route),
+	}
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	httpClientRequestsCounter.Add(ctx, 1, metric.WithAttributes(attrs...), metric.WithAttributes(attribute.String("status", status)))
+	httpClientLatencyHistogram.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
+	return resp, err
+}
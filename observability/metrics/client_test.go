@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// roundTripperFunc is an http.RoundTripper backed by a function
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// The client instruments are created once, so all transport checks share one meter provider
+func TestClientTransport(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/users/") {
+			_, _ = w.Write([]byte("ok"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client := &http.Client{Transport: NewClientTransport(nil)}
+	get := func(ctx context.Context, url string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do(%s) error = %v", url, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	ctx := ContextWithClientRoute(context.Background(), "/users/{id}")
+	get(ctx, server.URL+"/users/1")
+	get(ctx, server.URL+"/users/2")
+	get(context.Background(), server.URL+"/missing")
+
+	// A transport error is recorded with the error status
+	failing := &http.Client{Transport: NewClientTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://unreachable.test/users/3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing.Do(req); err == nil {
+		t.Fatal("expected transport error")
+	}
+
+	data := collectMetrics(t, reader)
+	requests, ok := data["http_client_requests_total"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("expected request counter, got %+v", data["http_client_requests_total"])
+	}
+	counts := make(map[string]int64)
+	for _, dp := range requests.DataPoints {
+		key := strings.Join([]string{
+			attrValue(dp.Attributes, "method"),
+			attrValue(dp.Attributes, "host"),
+			attrValue(dp.Attributes, "route"),
+			attrValue(dp.Attributes, "status"),
+		}, " ")
+		counts[key] = dp.Value
+	}
+	want := map[string]int64{
+		"GET " + host + " /users/{id} 200":        2,
+		"GET " + host + "  404":                   1,
+		"POST unreachable.test /users/{id} error": 1,
+	}
+	if len(counts) != len(want) {
+		t.Errorf("expected %d series, got %v", len(want), counts)
+	}
+	for key, n := range want {
+		if counts[key] != n {
+			t.Errorf("expected %d requests for %q, got %d (all: %v)", n, key, counts[key], counts)
+		}
+	}
+
+	latency, ok := data["http_client_request_duration_seconds"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("expected latency histogram, got %+v", data["http_client_request_duration_seconds"])
+	}
+	histograms := make(map[string]uint64)
+	for _, dp := range latency.DataPoints {
+		if _, ok := dp.Attributes.Value("status"); ok {
+			t.Errorf("expected no status on latency, got %v", dp.Attributes.ToSlice())
+		}
+		histograms[attrValue(dp.Attributes, "method")+" "+attrValue(dp.Attributes, "route")] = dp.Count
+	}
+	for key, n := range map[string]uint64{"GET /users/{id}": 2, "GET ": 1, "POST /users/{id}": 1} {
+		if histograms[key] != n {
+			t.Errorf("expected %d latencies for %q, got %d (all: %v)", n, key, histograms[key], histograms)
+		}
+	}
+}
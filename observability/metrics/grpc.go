@@ -4,40 +4,61 @@ import (
 	"context"
 	"time"
 	"sync"
-	"log"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
 	grpcRequestsCounter metric.Int64Counter
 	grpcLatencyHistogram metric.Float64Histogram
+	grpcRequestSizeHistogram metric.Int64Histogram
+	grpcResponseSizeHistogram metric.Int64Histogram
+	grpcInFlightCounter metric.Int64UpDownCounter
+	grpcPanicsCounter metric.Int64Counter
 	initGRPCMetricsOnce sync.Once
+	initGRPCMetricsErr error
 )
 
-func initGRPCMetrics() {
+// messageSizeBuckets are the bucket boundaries of message size histograms, 64B to 16MiB
+var messageSizeBuckets = []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216}
+
+func initGRPCMetrics() error {
 	initGRPCMetricsOnce.Do(func() {
-		meter := OtelMeter()
-		var err error
-		
-		grpcRequestsCounter, err = meter.Int64Counter(
+		inst := newInstruments()
+		grpcRequestsCounter = inst.int64Counter(
 			"grpc_server_requests_total",
 			metric.WithDescription("Total number of gRPC requests received."),
 		)
-		if err != nil {
-			log.Fatalf("failed to create grpc_server_requests_total counter: %v", err)
-		}
-		
-		grpcLatencyHistogram, err = meter.Float64Histogram(
+		grpcLatencyHistogram = inst.float64Histogram(
 			"grpc_server_handling_seconds",
 			metric.WithDescription("gRPC request handling duration in seconds."),
 		)
-		if err != nil {
-			log.Fatalf("failed to create grpc_server_handling_seconds histogram: %v", err)
-		}
+		grpcRequestSizeHistogram = inst.int64Histogram(
+			"grpc_server_request_size_bytes",
+			metric.WithDescription("Size of gRPC request messages in bytes."),
+			metric.WithUnit("By"),
+			metric.WithExplicitBucketBoundaries(messageSizeBuckets...),
+		)
+		grpcResponseSizeHistogram = inst.int64Histogram(
+			"grpc_server_response_size_bytes",
+			metric.WithDescription("Size of gRPC response messages in bytes."),
+			metric.WithUnit("By"),
+			metric.WithExplicitBucketBoundaries(messageSizeBuckets...),
+		)
+		grpcInFlightCounter = inst.int64UpDownCounter(
+			"grpc_server_in_flight_requests",
+			metric.WithDescription("Number of gRPC requests currently being handled."),
+		)
+		grpcPanicsCounter = inst.int64Counter(
+			"grpc_server_panics_total",
+			metric.WithDescription("Total number of panics in gRPC handlers."),
+		)
+		initGRPCMetricsErr = inst.err()
 	})
+	return initGRPCMetricsErr
 }
 
 // UnaryServerInterceptor returns grpc.UnaryServerInterceptor for otel metrics
@@ -50,9 +71,21 @@ func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 		start := time.Now()
+		service, method := splitMethod(info.FullMethod)
+		methodAttrs := metric.WithAttributes(
+			attribute.String("service", service),
+			attribute.String("method", method),
+		)
+		grpcInFlightCounter.Add(ctx, 1, methodAttrs)
+		defer grpcInFlightCounter.Add(ctx, -1, methodAttrs)
+		defer countPanic(ctx, methodAttrs)
+		recordMessageSize(ctx, grpcRequestSizeHistogram, req, methodAttrs)
+
 		resp, err := handler(ctx, req)
+		if err == nil {
+			recordMessageSize(ctx, grpcResponseSizeHistogram, resp, methodAttrs)
+		}
 		code := status.Code(err).String()
-		service, method := splitMethod(info.FullMethod)
 		grpcRequestsCounter.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("service", service),
 			attribute.String("method", method),
@@ -76,9 +109,17 @@ func StreamServerInterceptor() grpc.StreamServerInterceptor {
 		handler grpc.StreamHandler,
 	) error {
 		start := time.Now()
-		err := handler(srv, ss)
-		code := status.Code(err).String()
 		service, method := splitMethod(info.FullMethod)
+		methodAttrs := metric.WithAttributes(
+			attribute.String("service", service),
+			attribute.String("method", method),
+		)
+		grpcInFlightCounter.Add(ss.Context(), 1, methodAttrs)
+		defer grpcInFlightCounter.Add(ss.Context(), -1, methodAttrs)
+		defer countPanic(ss.Context(), methodAttrs)
+
+		err := handler(srv, &sizedServerStream{ServerStream: ss, attrs: methodAttrs})
+		code := status.Code(err).String()
 		grpcRequestsCounter.Add(ss.Context(), 1, metric.WithAttributes(
 			attribute.String("service", service),
 			attribute.String("method", method),
@@ -92,6 +133,44 @@ func StreamServerInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
+// countPanic counts a panic of the handler and panics again, leaving the conversion
+// to codes.Internal to the recovery interceptor
+func countPanic(ctx context.Context, attrs metric.MeasurementOption) {
+	if rec := recover(); rec != nil {
+		grpcPanicsCounter.Add(ctx, 1, attrs)
+		panic(rec)
+	}
+}
+
+// sizedServerStream records the size of every streamed message
+type sizedServerStream struct {
+	grpc.ServerStream
+	attrs metric.MeasurementOption
+}
+
+func (s *sizedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		recordMessageSize(s.Context(), grpcRequestSizeHistogram, m, s.attrs)
+	}
+	return err
+}
+
+func (s *sizedServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		recordMessageSize(s.Context(), grpcResponseSizeHistogram, m, s.attrs)
+	}
+	return err
+}
+
+// recordMessageSize records the encoded size of protobuf messages
+func recordMessageSize(ctx context.Context, h metric.Int64Histogram, m interface{}, attrs metric.MeasurementOption) {
+	if msg, ok := m.(proto.Message); ok {
+		h.Record(ctx, int64(proto.Size(msg)), attrs)
+	}
+}
+
 func splitMethod(fullMethod string) (service, method string) {
 	// fullMethod: /package.service/method
 	if len(fullMethod) == 0 || fullMethod[0] != '/' {
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// The gRPC instruments are created once, so all interceptor checks share one meter provider
+func TestUnaryServerInterceptor(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	ctx := context.Background()
+	collect := func() map[string]metricdata.Aggregation {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &rm); err != nil {
+			t.Fatalf("collect: %v", err)
+		}
+		data := make(map[string]metricdata.Aggregation)
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				data[m.Name] = m.Data
+			}
+		}
+		return data
+	}
+	inFlight := func(data map[string]metricdata.Aggregation) int64 {
+		sum, ok := data["grpc_server_in_flight_requests"].(metricdata.Sum[int64])
+		if !ok || len(sum.DataPoints) != 1 {
+			t.Fatalf("expected one in-flight data point, got %+v", data["grpc_server_in_flight_requests"])
+		}
+		return sum.DataPoints[0].Value
+	}
+
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Echo/Say"}
+	req := wrapperspb.String("hello")
+	resp := wrapperspb.String("hello, world")
+
+	_, err := interceptor(ctx, req, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		if n := inFlight(collect()); n != 1 {
+			t.Errorf("expected 1 in-flight request, got %d", n)
+		}
+		return resp, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := collect()
+	if n := inFlight(data); n != 0 {
+		t.Errorf("expected no in-flight requests, got %d", n)
+	}
+	for name, want := range map[string]int64{
+		"grpc_server_request_size_bytes":  7,  // tag, length and "hello"
+		"grpc_server_response_size_bytes": 14, // tag, length and "hello, world"
+	} {
+		hist, ok := data[name].(metricdata.Histogram[int64])
+		if !ok || len(hist.DataPoints) != 1 {
+			t.Fatalf("expected one %s data point, got %+v", name, data[name])
+		}
+		if dp := hist.DataPoints[0]; dp.Count != 1 || dp.Sum != want {
+			t.Errorf("%s: expected one message of %d bytes, got count %d sum %d", name, want, dp.Count, dp.Sum)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate to the recovery interceptor")
+			}
+		}()
+		_, _ = interceptor(ctx, req, info, func(context.Context, interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	}()
+
+	data = collect()
+	panics, ok := data["grpc_server_panics_total"].(metricdata.Sum[int64])
+	if !ok || len(panics.DataPoints) != 1 || panics.DataPoints[0].Value != 1 {
+		t.Fatalf("expected one panic, got %+v", data["grpc_server_panics_total"])
+	}
+	if method, _ := panics.DataPoints[0].Attributes.Value("method"); method.AsString() != "Say" {
+		t.Errorf("expected method=Say, got %q", method.AsString())
+	}
+	if n := inFlight(data); n != 0 {
+		t.Errorf("expected no in-flight requests after panic, got %d", n)
+	}
+}
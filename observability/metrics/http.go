@@ -5,7 +5,6 @@ import (
 	"strconv"
 	"time"
 	"sync"
-	"log"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -15,37 +14,27 @@ var (
 	httpLatencyHistogram metric.Float64Histogram
 	httpPanicsCounter metric.Int64Counter
 	initHTTPMetricsOnce sync.Once
+	initHTTPMetricsErr error
 )
 
-func initHTTPMetrics() {
+func initHTTPMetrics() error {
 	initHTTPMetricsOnce.Do(func() {
-		meter := OtelMeter()
-		var err error
-		
-		httpRequestsCounter, err = meter.Int64Counter(
+		inst := newInstruments()
+		httpRequestsCounter = inst.int64Counter(
 			"http_requests_total",
 			metric.WithDescription("Total number of HTTP requests."),
 		)
-		if err != nil {
-			log.Fatalf("failed to create http_requests_total counter: %v", err)
-		}
-		
-		httpLatencyHistogram, err = meter.Float64Histogram(
+		httpLatencyHistogram = inst.float64Histogram(
 			"http_request_duration_seconds",
 			metric.WithDescription("HTTP request latency in seconds."),
 		)
-		if err != nil {
-			log.Fatalf("failed to create http_request_duration_seconds histogram: %v", err)
-		}
-		
-		httpPanicsCounter, err = meter.Int64Counter(
+		httpPanicsCounter = inst.int64Counter(
 			"http_panics_total",
 			metric.WithDescription("Total number of panics in HTTP handlers."),
 		)
-		if err != nil {
-			log.Fatalf("failed to create http_panics_total counter: %v", err)
-		}
+		initHTTPMetricsErr = inst.err()
 	})
+	return initHTTPMetricsErr
 }
 
 type statusRecorder struct {
@@ -59,31 +48,39 @@ func (r *statusRecorder) WriteHeader(code int) {
 }
 
 // Middleware returns http.Handler with otel-metrics
-func Middleware(next http.Handler) http.Handler {
+func Middleware(next http.Handler, opts ...HTTPOption) http.Handler {
 	initHTTPMetrics()
+	o := newHTTPOptions(opts)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if o.isExcluded(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		start := time.Now()
 		rec := &statusRecorder{ResponseWriter: w, status: 200}
-		ctx := r.Context()
+		ctx, route := withRouteHolder(r.Context())
+		r = r.WithContext(ctx)
 		defer func() {
 			if rec := recover(); rec != nil {
 				httpPanicsCounter.Add(ctx, 1, metric.WithAttributes(
 					attribute.String("method", r.Method),
-					attribute.String("path", r.URL.Path),
+					attribute.String("path", routeLabel(r, route)),
 				))
 				panic(rec)
 			}
 		}()
 		next.ServeHTTP(rec, r)
 		status := strconv.Itoa(rec.status)
+		path := routeLabel(r, route)
+		extra := o.attributes(r, rec.status)
 		httpRequestsCounter.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("method", r.Method),
-			attribute.String("path", r.URL.Path),
+			attribute.String("path", path),
 			attribute.String("status", status),
-		))
+		), metric.WithAttributes(extra...))
 		httpLatencyHistogram.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
 			attribute.String("method", r.Method),
-			attribute.String("path", r.URL.Path),
-		))
+			attribute.String("path", path),
+		), metric.WithAttributes(extra...))
 	})
 }
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HTTPOption configures Middleware
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	excluded    map[string]struct{}
+	extractors  []func(*http.Request) []attribute.KeyValue
+	statusClass bool
+}
+
+// WithExcludedPaths skips requests to the given URL paths, e.g. "/healthz" and "/metrics"
+func WithExcludedPaths(paths ...string) HTTPOption {
+	return func(o *httpOptions) {
+		for _, p := range paths {
+			o.excluded[p] = struct{}{}
+		}
+	}
+}
+
+// WithRequestAttributes adds attributes derived from the request to request count and duration metrics,
+// e.g. a tenant ID from a header. Keep their cardinality bounded.
+func WithRequestAttributes(fn func(r *http.Request) []attribute.KeyValue) HTTPOption {
+	return func(o *httpOptions) {
+		o.extractors = append(o.extractors, fn)
+	}
+}
+
+// WithStatusClass adds a status_class attribute ("2xx", "4xx", "5xx", ...) to request count and duration metrics
+func WithStatusClass() HTTPOption {
+	return func(o *httpOptions) {
+		o.statusClass = true
+	}
+}
+
+func newHTTPOptions(opts []HTTPOption) httpOptions {
+	o := httpOptions{excluded: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o httpOptions) isExcluded(r *http.Request) bool {
+	_, ok := o.excluded[r.URL.Path]
+	return ok
+}
+
+// attributes returns the configured extra attributes of a handled request
+func (o httpOptions) attributes(r *http.Request, status int) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if o.statusClass {
+		attrs = append(attrs, attribute.String("status_class", statusClass(status)))
+	}
+	for _, fn := range o.extractors {
+		attrs = append(attrs, fn(r)...)
+	}
+	return attrs
+}
+
+// statusClass returns the class of an HTTP status code, e.g. "4xx" for 404
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectMetrics returns the metrics of the reader by name
+func collectMetrics(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	data := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data[m.Name] = m.Data
+		}
+	}
+	return data
+}
+
+// attrValue returns the value of an attribute of a data point, or "" if it is missing
+func attrValue(set attribute.Set, key string) string {
+	v, ok := set.Value(attribute.Key(key))
+	if !ok {
+		return ""
+	}
+	return v.Emit()
+}
+
+// The HTTP instruments are created once, so all middleware checks share one meter provider
+func TestMiddlewareOptions(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}),
+		WithExcludedPaths("/healthz", "/metrics"),
+		WithStatusClass(),
+		WithRequestAttributes(func(r *http.Request) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("tenant", r.Header.Get("X-Tenant"))}
+		}),
+	)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	for _, path := range []string{"/healthz", "/metrics", "/orders", "/orders", "/missing"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Tenant", "acme")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	data := collectMetrics(t, reader)
+	requests, ok := data["http_requests_total"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("expected request counter, got %+v", data["http_requests_total"])
+	}
+
+	counts := make(map[string]int64)
+	for _, dp := range requests.DataPoints {
+		path := attrValue(dp.Attributes, "path")
+		if path == "/healthz" || path == "/metrics" {
+			t.Errorf("expected excluded path %s not to be recorded", path)
+		}
+		if tenant := attrValue(dp.Attributes, "tenant"); tenant != "acme" {
+			t.Errorf("expected tenant attribute acme on %s, got %q", path, tenant)
+		}
+		counts[path+" "+attrValue(dp.Attributes, "status")+" "+attrValue(dp.Attributes, "status_class")] = dp.Value
+	}
+	for key, want := range map[string]int64{"/orders 200 2xx": 2, "/missing 404 4xx": 1} {
+		if counts[key] != want {
+			t.Errorf("expected %d requests for %q, got %d (all: %v)", want, key, counts[key], counts)
+		}
+	}
+
+	latency, ok := data["http_request_duration_seconds"].(metricdata.Histogram[float64])
+	if !ok || len(latency.DataPoints) != 2 {
+		t.Fatalf("expected latency of two paths, got %+v", data["http_request_duration_seconds"])
+	}
+	for _, dp := range latency.DataPoints {
+		if attrValue(dp.Attributes, "tenant") != "acme" || attrValue(dp.Attributes, "status_class") == "" {
+			t.Errorf("expected extra attributes on latency, got %v", dp.Attributes.ToSlice())
+		}
+	}
+}
+
+func TestHTTPOptions(t *testing.T) {
+	o := newHTTPOptions([]HTTPOption{WithExcludedPaths("/healthz"), WithStatusClass()})
+
+	tests := []struct {
+		path     string
+		excluded bool
+	}{
+		{path: "/healthz", excluded: true},
+		{path: "/healthz/", excluded: false},
+		{path: "/orders", excluded: false},
+	}
+	for _, tt := range tests {
+		if got := o.isExcluded(httptest.NewRequest(http.MethodGet, tt.path, nil)); got != tt.excluded {
+			t.Errorf("isExcluded(%s) = %v, want %v", tt.path, got, tt.excluded)
+		}
+	}
+
+	for status, want := range map[int]string{200: "2xx", 301: "3xx", 404: "4xx", 503: "5xx"} {
+		attrs := o.attributes(httptest.NewRequest(http.MethodGet, "/orders", nil), status)
+		if len(attrs) != 1 || attrs[0].Value.AsString() != want {
+			t.Errorf("attributes(%d) = %v, want status_class %s", status, attrs, want)
+		}
+	}
+
+	if attrs := newHTTPOptions(nil).attributes(httptest.NewRequest(http.MethodGet, "/", nil), 200); len(attrs) != 0 {
+		t.Errorf("expected no extra attributes by default, got %v", attrs)
+	}
+}
@@ -2,46 +2,74 @@ package metrics
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"net/http"
 	"time"
 
 	promclient "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rshelekhov/golib/observability/scope"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"google.golang.org/grpc/credentials"
 )
 
 type ExporterType string
 
 const (
-	ExporterPrometheus ExporterType = "prometheus"
-	ExporterOTLP       ExporterType = "otlp"
+	ExporterPrometheus  ExporterType = "prometheus"
+	ExporterOTLP        ExporterType = "otlp"
+	ExporterPushgateway ExporterType = "pushgateway"
+)
+
+type OTLPTransportType string
+
+const (
+	OTLPTransportGRPC OTLPTransportType = "grpc"
+	OTLPTransportHTTP OTLPTransportType = "http"
 )
 
 type Config struct {
-	ServiceName    string
-	ServiceVersion string
-	Env            string
-	ExporterType   ExporterType
-	OTLPEndpoint   string        // Used only when ExporterType is ExporterOTLP
-	PushInterval   time.Duration // Used for OTLP exporter, defaults to 30s
-	OTLPInsecure   bool          // If true, uses insecure OTLP connection
+	ServiceName       string
+	ServiceVersion    string
+	Env               string
+	ExporterType      ExporterType
+	OTLPEndpoint      string            // Used only when ExporterType is ExporterOTLP
+	OTLPTransportType OTLPTransportType // "grpc" (default) or "http", used only when ExporterType is ExporterOTLP
+	PushInterval      time.Duration     // Used for OTLP and Pushgateway exporters, defaults to 30s
+	OTLPInsecure      bool              // If true, uses insecure OTLP connection
+	OTLPHeaders       map[string]string // Headers sent with every export request, e.g. collector auth
+	OTLPTLSConfig     *tls.Config       // Custom TLS config (CA bundle, client certificate). Takes precedence over OTLPInsecure
+
+	// Pushgateway settings, used only when ExporterType is ExporterPushgateway.
+	// Metrics are pushed every PushInterval and a final time on MeterProvider shutdown.
+	PushgatewayURL      string            // e.g. "http://pushgateway:9091"
+	PushgatewayJob      string            // Job label, defaults to ServiceName
+	PushgatewayGrouping map[string]string // Grouping labels in addition to the job, e.g. {"instance": hostname}
+
+	// Resource overrides the resource built from service name, version and environment
+	Resource *resource.Resource
 }
 
 // Init initializes OpenTelemetry MeterProvider with the specified exporter
 func Init(ctx context.Context, cfg Config) (*sdkmetric.MeterProvider, http.Handler, error) {
 	// Create resource
-	res := resource.NewWithAttributes(
-		resource.Default().SchemaURL(),
-		semconv.ServiceName(cfg.ServiceName),
-		semconv.ServiceVersion(cfg.ServiceVersion),
-		semconv.DeploymentEnvironment(cfg.Env),
-	)
+	res := cfg.Resource
+	if res == nil {
+		res = resource.NewWithAttributes(
+			resource.Default().SchemaURL(),
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			semconv.DeploymentEnvironment(cfg.Env),
+		)
+	}
 
 	var provider *sdkmetric.MeterProvider
 	var handler http.Handler
@@ -49,7 +77,9 @@ func Init(ctx context.Context, cfg Config) (*sdkmetric.MeterProvider, http.Handl
 
 	switch cfg.ExporterType {
 	case ExporterOTLP:
-		provider, err = initOTLP(ctx, res, cfg.OTLPEndpoint, cfg.PushInterval, cfg.OTLPInsecure)
+		provider, err = initOTLP(ctx, res, cfg)
+	case ExporterPushgateway:
+		provider, err = initPushgateway(res, cfg)
 	default: // ExporterPrometheus or empty
 		provider, handler, err = initPrometheus(res)
 	}
@@ -86,20 +116,13 @@ func initPrometheus(res *resource.Resource) (*sdkmetric.MeterProvider, http.Hand
 	return provider, handler, nil
 }
 
-func initOTLP(ctx context.Context, res *resource.Resource, endpoint string, interval time.Duration, insecure bool) (*sdkmetric.MeterProvider, error) {
-	// Create OTLP exporter with configurable TLS
-	opts := []otlpmetricgrpc.Option{
-		otlpmetricgrpc.WithEndpoint(endpoint),
-	}
-	if insecure {
-		opts = append(opts, otlpmetricgrpc.WithInsecure())
-	}
-
-	exporter, err := otlpmetricgrpc.New(ctx, opts...)
+func initOTLP(ctx context.Context, res *resource.Resource, cfg Config) (*sdkmetric.MeterProvider, error) {
+	exporter, err := newOTLPExporter(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	interval := cfg.PushInterval
 	if interval == 0 {
 		interval = 30 * time.Second
 	}
@@ -119,7 +142,56 @@ func initOTLP(ctx context.Context, res *resource.Resource, endpoint string, inte
 	return provider, nil
 }
 
-// OtelMeter returns global otel.Meter
+// newOTLPExporter creates an OTLP exporter for the configured transport with configurable TLS
+func newOTLPExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	switch cfg.OTLPTransportType {
+	case OTLPTransportHTTP:
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(cfg.OTLPEndpoint),
+		}
+		switch {
+		case cfg.OTLPTLSConfig != nil:
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(cfg.OTLPTLSConfig))
+		case cfg.OTLPInsecure:
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(cfg.OTLPHeaders) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.OTLPHeaders))
+		}
+
+		exporter, err := otlpmetrichttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp http metric exporter: %w", err)
+		}
+		return exporter, nil
+	case OTLPTransportGRPC, "":
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(cfg.OTLPEndpoint),
+		}
+		switch {
+		case cfg.OTLPTLSConfig != nil:
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(cfg.OTLPTLSConfig)))
+		case cfg.OTLPInsecure:
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if len(cfg.OTLPHeaders) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.OTLPHeaders))
+		}
+
+		exporter, err := otlpmetricgrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp grpc metric exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("invalid otlp transport type: %s", cfg.OTLPTransportType)
+	}
+}
+
+// meterName is the instrumentation scope of metrics, see the scope package
+const meterName = "github.com/rshelekhov/golib/observability/metrics"
+
+// OtelMeter returns a meter of the metrics instrumentation scope from the global provider
 func OtelMeter() metric.Meter {
-	return otel.GetMeterProvider().Meter("github.com/rshelekhov/golib/observability/metrics")
+	return scope.Meter(meterName)
 }
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewOTLPExporter(t *testing.T) {
+	ctx := context.Background()
+
+	for _, transport := range []OTLPTransportType{"", OTLPTransportGRPC, OTLPTransportHTTP} {
+		exporter, err := newOTLPExporter(ctx, Config{
+			OTLPEndpoint:      "localhost:4317",
+			OTLPTransportType: transport,
+			OTLPInsecure:      true,
+		})
+		if err != nil {
+			t.Fatalf("newOTLPExporter(%q) error = %v", transport, err)
+		}
+		_ = exporter.Shutdown(ctx)
+	}
+
+	_, err := newOTLPExporter(ctx, Config{OTLPEndpoint: "localhost:4317", OTLPTransportType: "udp"})
+	if err == nil || !strings.Contains(err.Error(), "invalid otlp transport type: udp") {
+		t.Errorf("expected invalid transport error, got %v", err)
+	}
+}
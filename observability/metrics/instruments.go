@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// InitInstruments creates the instruments of the HTTP middleware, the HTTP client transport and
+// the gRPC interceptors. They are otherwise created on first use; instruments that fail to be
+// created are replaced with no-ops and the errors are reported to the OpenTelemetry error handler.
+// Call it after Init to fail fast on a misconfigured meter provider.
+func InitInstruments() error {
+	return errors.Join(initHTTPMetrics(), initHTTPClientMetrics(), initGRPCMetrics())
+}
+
+// MustInitInstruments is like InitInstruments but panics if an instrument can't be created
+func MustInitInstruments() {
+	if err := InitInstruments(); err != nil {
+		panic(err)
+	}
+}
+
+// instruments creates instruments with the package meter, collecting errors.
+// Instruments that can't be created are replaced with no-ops, so callers never get nil.
+type instruments struct {
+	meter metric.Meter
+	errs  []error
+}
+
+func newInstruments() *instruments {
+	return &instruments{meter: OtelMeter()}
+}
+
+func (i *instruments) int64Counter(name string, opts ...metric.Int64CounterOption) metric.Int64Counter {
+	c, err := i.meter.Int64Counter(name, opts...)
+	if err != nil || c == nil {
+		i.fail(name, err)
+		return noop.Int64Counter{}
+	}
+	return c
+}
+
+func (i *instruments) int64UpDownCounter(name string, opts ...metric.Int64UpDownCounterOption) metric.Int64UpDownCounter {
+	c, err := i.meter.Int64UpDownCounter(name, opts...)
+	if err != nil || c == nil {
+		i.fail(name, err)
+		return noop.Int64UpDownCounter{}
+	}
+	return c
+}
+
+func (i *instruments) int64Histogram(name string, opts ...metric.Int64HistogramOption) metric.Int64Histogram {
+	h, err := i.meter.Int64Histogram(name, opts...)
+	if err != nil || h == nil {
+		i.fail(name, err)
+		return noop.Int64Histogram{}
+	}
+	return h
+}
+
+func (i *instruments) float64Histogram(name string, opts ...metric.Float64HistogramOption) metric.Float64Histogram {
+	h, err := i.meter.Float64Histogram(name, opts...)
+	if err != nil || h == nil {
+		i.fail(name, err)
+		return noop.Float64Histogram{}
+	}
+	return h
+}
+
+func (i *instruments) fail(name string, err error) {
+	if err == nil {
+		err = errors.New("meter returned no instrument")
+	}
+	i.errs = append(i.errs, fmt.Errorf("failed to create %s: %w", name, err))
+}
+
+// err returns the collected errors, also reporting them to the OpenTelemetry error handler
+func (i *instruments) err() error {
+	err := errors.Join(i.errs...)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return err
+}
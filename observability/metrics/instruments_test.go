@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestInstrumentsCollectErrors(t *testing.T) {
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider())
+	defer otel.SetMeterProvider(prev)
+
+	var handled []error
+	prevHandler := otel.GetErrorHandler()
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	defer otel.SetErrorHandler(prevHandler)
+
+	inst := newInstruments()
+	if c := inst.int64Counter("test_valid_total"); c == nil {
+		t.Fatal("expected a counter")
+	}
+	if err := inst.err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if h := inst.float64Histogram("1 invalid name"); h == nil {
+		t.Fatal("expected a no-op histogram for an invalid name")
+	}
+	err := inst.err()
+	if err == nil || !strings.Contains(err.Error(), "failed to create 1 invalid name") {
+		t.Errorf("expected instrument creation error, got %v", err)
+	}
+	if len(handled) != 1 {
+		t.Errorf("expected the error to be reported to the otel handler once, got %d", len(handled))
+	}
+}
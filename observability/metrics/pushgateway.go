@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func initPushgateway(res *resource.Resource, cfg Config) (*sdkmetric.MeterProvider, error) {
+	if cfg.PushgatewayURL == "" {
+		return nil, errors.New("pushgateway url is required")
+	}
+
+	registry := promclient.NewRegistry()
+	exporter, err := prometheus.New(
+		prometheus.WithRegisterer(registry),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job := cfg.PushgatewayJob
+	if job == "" {
+		job = cfg.ServiceName
+	}
+	pusher := push.New(cfg.PushgatewayURL, job).Gatherer(registry)
+	for name, value := range cfg.PushgatewayGrouping {
+		pusher = pusher.Grouping(name, value)
+	}
+
+	reader := newPushgatewayReader(exporter, pusher, cfg.PushInterval)
+
+	// Create MeterProvider
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(reader),
+	)
+
+	return provider, nil
+}
+
+// pushgatewayReader is a Prometheus exporter pushing its registry to a Pushgateway periodically,
+// on ForceFlush and a final time on Shutdown, so short-lived jobs don't lose their last values
+type pushgatewayReader struct {
+	sdkmetric.Reader
+	pusher *push.Pusher
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func newPushgatewayReader(exporter sdkmetric.Reader, pusher *push.Pusher, interval time.Duration) *pushgatewayReader {
+	r := &pushgatewayReader{
+		Reader: exporter,
+		pusher: pusher,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+	go r.run(interval)
+	return r
+}
+
+func (r *pushgatewayReader) run(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.push(context.Background()); err != nil {
+				otel.Handle(err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *pushgatewayReader) push(ctx context.Context) error {
+	if err := r.pusher.PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics to pushgateway: %w", err)
+	}
+	return nil
+}
+
+// ForceFlush pushes the current metrics
+func (r *pushgatewayReader) ForceFlush(ctx context.Context) error {
+	return r.push(ctx)
+}
+
+// Shutdown stops periodic pushes, pushes the final metrics and shuts the exporter down
+func (r *pushgatewayReader) Shutdown(ctx context.Context) error {
+	select {
+	case <-r.stop:
+		return r.Reader.Shutdown(ctx)
+	default:
+		close(r.stop)
+	}
+	<-r.done
+
+	return errors.Join(r.push(ctx), r.Reader.Shutdown(ctx))
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestPushgatewayFinalPush(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		method string
+		path   string
+		body   string
+	)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		method, path, body = r.Method, r.URL.Path, string(data)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	prev := otel.GetMeterProvider()
+	defer otel.SetMeterProvider(prev)
+
+	ctx := context.Background()
+	provider, handler, err := Init(ctx, Config{
+		ServiceName:         "migrations",
+		ExporterType:        ExporterPushgateway,
+		PushInterval:        time.Hour,
+		PushgatewayURL:      gateway.URL,
+		PushgatewayGrouping: map[string]string{"instance": "job-1"},
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if handler != nil {
+		t.Error("expected no metrics handler for pushgateway")
+	}
+
+	counter, err := OtelMeter().Int64Counter("test_migrations_applied")
+	if err != nil {
+		t.Fatalf("create counter: %v", err)
+	}
+	counter.Add(ctx, 3)
+
+	if err := provider.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if method != http.MethodPut {
+		t.Errorf("method = %q, want PUT", method)
+	}
+	if want := "/metrics/job/migrations/instance/job-1"; path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if !strings.Contains(body, "test_migrations_applied") {
+		t.Error("expected the counter in the pushed metrics")
+	}
+}
+
+func TestPushgatewayRequiresURL(t *testing.T) {
+	if _, _, err := Init(context.Background(), Config{ExporterType: ExporterPushgateway}); err == nil {
+		t.Error("expected an error without pushgateway url")
+	}
+}
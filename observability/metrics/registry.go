@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// registry caches instruments by name, so declaring the same metric twice returns the same instrument
+var registry = struct {
+	sync.Mutex
+	entries map[string]registryEntry
+}{entries: make(map[string]registryEntry)}
+
+type registryEntry struct {
+	kind   string
+	labels []string
+	value  any
+}
+
+// register returns the instrument registered under name or creates it.
+// It panics if name is already used by an instrument of another kind or with other labels.
+func register[T any](kind, name string, labels []string, create func(desc descriptor) T) T {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if e, ok := registry.entries[name]; ok {
+		if e.kind != kind || !slices.Equal(e.labels, labels) {
+			panic(fmt.Sprintf("metrics: %s already registered as %s with labels %v", name, e.kind, e.labels))
+		}
+		return e.value.(T)
+	}
+
+	value := create(descriptor{name: name, labels: slices.Clone(labels)})
+	registry.entries[name] = registryEntry{kind: kind, labels: slices.Clone(labels), value: value}
+	return value
+}
+
+// descriptor holds the metadata shared by registered instruments
+type descriptor struct {
+	name        string
+	description string
+	labels      []string
+}
+
+// attributes pairs label names with values. It reports an error through otel.Handle
+// and returns false if their number differs.
+func (d descriptor) attributes(values []string) (metric.MeasurementOption, bool) {
+	if len(values) != len(d.labels) {
+		otel.Handle(fmt.Errorf("metrics: %s expects %d label values %v, got %d", d.name, len(d.labels), d.labels, len(values)))
+		return nil, false
+	}
+	attrs := make([]attribute.KeyValue, len(values))
+	for i, v := range values {
+		attrs[i] = attribute.String(d.labels[i], v)
+	}
+	return metric.WithAttributes(attrs...), true
+}
+
+// Counter is a monotonic counter with fixed label names, created on first use
+type Counter struct {
+	descriptor
+	once sync.Once
+	inst metric.Int64Counter
+}
+
+// NewCounter returns the counter registered under name, creating it if needed.
+// Label values are passed positionally when recording:
+//
+//	var ordersTotal = metrics.NewCounter("orders_total", "Total number of orders.", "status")
+//	ordersTotal.Inc(ctx, "paid")
+func NewCounter(name, description string, labels ...string) *Counter {
+	return register("counter", name, labels, func(d descriptor) *Counter {
+		d.description = description
+		return &Counter{descriptor: d}
+	})
+}
+
+// Inc increases the counter by one
+func (c *Counter) Inc(ctx context.Context, labelValues ...string) {
+	c.Add(ctx, 1, labelValues...)
+}
+
+// Add increases the counter by n
+func (c *Counter) Add(ctx context.Context, n int64, labelValues ...string) {
+	c.once.Do(func() {
+		var err error
+		c.inst, err = OtelMeter().Int64Counter(c.name, metric.WithDescription(c.description))
+		if err != nil {
+			otel.Handle(err)
+		}
+	})
+	if attrs, ok := c.attributes(labelValues); ok && c.inst != nil {
+		c.inst.Add(ctx, n, attrs)
+	}
+}
+
+// UpDownCounter is a counter that can go up and down, e.g. in-flight jobs, created on first use
+type UpDownCounter struct {
+	descriptor
+	once sync.Once
+	inst metric.Int64UpDownCounter
+}
+
+// NewUpDownCounter returns the up-down counter registered under name, creating it if needed
+func NewUpDownCounter(name, description string, labels ...string) *UpDownCounter {
+	return register("updowncounter", name, labels, func(d descriptor) *UpDownCounter {
+		d.description = description
+		return &UpDownCounter{descriptor: d}
+	})
+}
+
+// Inc increases the counter by one
+func (c *UpDownCounter) Inc(ctx context.Context, labelValues ...string) {
+	c.Add(ctx, 1, labelValues...)
+}
+
+// Dec decreases the counter by one
+func (c *UpDownCounter) Dec(ctx context.Context, labelValues ...string) {
+	c.Add(ctx, -1, labelValues...)
+}
+
+// Add changes the counter by n
+func (c *UpDownCounter) Add(ctx context.Context, n int64, labelValues ...string) {
+	c.once.Do(func() {
+		var err error
+		c.inst, err = OtelMeter().Int64UpDownCounter(c.name, metric.WithDescription(c.description))
+		if err != nil {
+			otel.Handle(err)
+		}
+	})
+	if attrs, ok := c.attributes(labelValues); ok && c.inst != nil {
+		c.inst.Add(ctx, n, attrs)
+	}
+}
+
+// Histogram records a distribution of values, e.g. durations in seconds, created on first use
+type Histogram struct {
+	descriptor
+	once sync.Once
+	inst metric.Float64Histogram
+}
+
+// NewHistogram returns the histogram registered under name, creating it if needed
+func NewHistogram(name, description string, labels ...string) *Histogram {
+	return register("histogram", name, labels, func(d descriptor) *Histogram {
+		d.description = description
+		return &Histogram{descriptor: d}
+	})
+}
+
+// Observe records a value
+func (h *Histogram) Observe(ctx context.Context, value float64, labelValues ...string) {
+	h.once.Do(func() {
+		var err error
+		h.inst, err = OtelMeter().Float64Histogram(h.name, metric.WithDescription(h.description))
+		if err != nil {
+			otel.Handle(err)
+		}
+	})
+	if attrs, ok := h.attributes(labelValues); ok && h.inst != nil {
+		h.inst.Record(ctx, value, attrs)
+	}
+}
+
+// Gauge records the current value of something, e.g. a queue length, created on first use
+type Gauge struct {
+	descriptor
+	once sync.Once
+	inst metric.Float64Gauge
+}
+
+// NewGauge returns the gauge registered under name, creating it if needed
+func NewGauge(name, description string, labels ...string) *Gauge {
+	return register("gauge", name, labels, func(d descriptor) *Gauge {
+		d.description = description
+		return &Gauge{descriptor: d}
+	})
+}
+
+// Set records the current value
+func (g *Gauge) Set(ctx context.Context, value float64, labelValues ...string) {
+	g.once.Do(func() {
+		var err error
+		g.inst, err = OtelMeter().Float64Gauge(g.name, metric.WithDescription(g.description))
+		if err != nil {
+			otel.Handle(err)
+		}
+	})
+	if attrs, ok := g.attributes(labelValues); ok && g.inst != nil {
+		g.inst.Record(ctx, value, attrs)
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRegistry(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(prev)
+
+	ctx := context.Background()
+
+	orders := NewCounter("test_orders_total", "Orders.", "status")
+	if again := NewCounter("test_orders_total", "Orders.", "status"); again != orders {
+		t.Error("expected the registered counter to be reused")
+	}
+
+	orders.Inc(ctx, "paid")
+	orders.Add(ctx, 2, "paid")
+	orders.Inc(ctx) // wrong number of label values, dropped
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 {
+		t.Fatalf("expected one counter data point, got %+v", rm.ScopeMetrics[0].Metrics[0].Data)
+	}
+	if dp := sum.DataPoints[0]; dp.Value != 3 {
+		t.Errorf("expected 3 orders, got %d", dp.Value)
+	}
+	if status, _ := sum.DataPoints[0].Attributes.Value("status"); status.AsString() != "paid" {
+		t.Errorf("expected status=paid, got %q", status.AsString())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when reusing a name for another kind")
+		}
+	}()
+	NewGauge("test_orders_total", "Orders.", "status")
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+type routeKey struct{}
+
+// withRouteHolder returns a context where routers can report the matched route template
+func withRouteHolder(ctx context.Context) (context.Context, *atomic.Pointer[string]) {
+	holder := &atomic.Pointer[string]{}
+	return context.WithValue(ctx, routeKey{}, holder), holder
+}
+
+// SetRoute reports the route template matched for the request, e.g. "/users/{id}".
+// Middleware uses it as the path label instead of the raw URL path to keep cardinality bounded.
+// Router adapters call it once routing is done; it is a no-op outside Middleware.
+func SetRoute(ctx context.Context, route string) {
+	if holder, ok := ctx.Value(routeKey{}).(*atomic.Pointer[string]); ok && route != "" {
+		holder.Store(&route)
+	}
+}
+
+// routeLabel returns the route reported with SetRoute, the http.ServeMux pattern
+// or the raw URL path, in that order
+func routeLabel(r *http.Request, holder *atomic.Pointer[string]) string {
+	if route := holder.Load(); route != nil {
+		return *route
+	}
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return r.URL.Path
+}
@@ -3,11 +3,13 @@ package observability
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
 	"github.com/rshelekhov/golib/observability/logger"
 	"github.com/rshelekhov/golib/observability/metrics"
+	"github.com/rshelekhov/golib/observability/profiling"
 	"github.com/rshelekhov/golib/observability/tracing"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -20,6 +22,7 @@ type Observability struct {
 	LoggerProvider *sdklog.LoggerProvider
 	MeterProvider  *sdkmetric.MeterProvider
 	TracerProvider *sdktrace.TracerProvider
+	Profiler       *profiling.Profiler
 }
 
 // Init initializes observability with automatic exporter selection
@@ -27,6 +30,16 @@ func Init(ctx context.Context, cfg Config) (*Observability, error) {
 	// Determine if we should use OTLP based on configuration
 	useOTLP := cfg.OTLPEndpoint != "" && cfg.Env != EnvLocal
 
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg, err := cfg.otlpTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure OTLP TLS: %w", err)
+	}
+
 	// Initialize logger
 	loggerCfg := logger.Config{
 		ServiceName:    cfg.ServiceName,
@@ -34,14 +47,25 @@ func Init(ctx context.Context, cfg Config) (*Observability, error) {
 		Env:            cfg.Env,
 		Level:          cfg.LogLevel,
 		OTLPInsecure:   cfg.OTLPInsecure,
+		OTLPHeaders:    cfg.OTLPHeaders,
+		OTLPTLSConfig:  tlsCfg,
+		ConsoleOutput:  cfg.ConsoleLogs,
+		JSONOutput:     cfg.JSONLogs,
+		Resource:       res,
+		Batch:          cfg.LogBatch,
+		Spool:          cfg.Spool,
 	}
 	if useOTLP {
 		loggerCfg.Endpoint = cfg.OTLPEndpoint
+		loggerCfg.OTLPTransportType = logger.OTLPTransportType(cfg.OTLPTransportType)
 	}
 	loggerProvider, log, err := logger.Init(ctx, loggerCfg)
 	if err != nil {
 		return nil, err
 	}
+	if attrs := cfg.Ownership.logAttrs(); len(attrs) > 0 {
+		log = log.With(attrs...)
+	}
 
 	// Initialize tracing
 	tracingCfg := tracing.Config{
@@ -49,6 +73,14 @@ func Init(ctx context.Context, cfg Config) (*Observability, error) {
 		ServiceVersion: cfg.ServiceVersion,
 		Env:            cfg.Env,
 		OTLPInsecure:   cfg.OTLPInsecure,
+		OTLPHeaders:    cfg.OTLPHeaders,
+		OTLPTLSConfig:  tlsCfg,
+		Resource:       res,
+		Batch:          cfg.TraceBatch,
+		Propagators:    cfg.Propagators,
+		Redaction:      cfg.SpanRedaction,
+		Sampler:        cfg.TraceSampler,
+		Spool:          cfg.Spool,
 	}
 	if useOTLP {
 		tracingCfg.ExporterType = tracing.ExporterOTLP
@@ -73,10 +105,14 @@ func Init(ctx context.Context, cfg Config) (*Observability, error) {
 			ServiceVersion: cfg.ServiceVersion,
 			Env:            cfg.Env,
 			OTLPInsecure:   cfg.OTLPInsecure,
+			OTLPHeaders:    cfg.OTLPHeaders,
+			OTLPTLSConfig:  tlsCfg,
+			Resource:       res,
 		}
 		if useOTLP {
 			metricsCfg.ExporterType = metrics.ExporterOTLP
 			metricsCfg.OTLPEndpoint = cfg.OTLPEndpoint
+			metricsCfg.OTLPTransportType = metrics.OTLPTransportType(cfg.OTLPTransportType)
 		} else {
 			metricsCfg.ExporterType = metrics.ExporterPrometheus
 		}
@@ -86,12 +122,28 @@ func Init(ctx context.Context, cfg Config) (*Observability, error) {
 		}
 	}
 
+	var profiler *profiling.Profiler
+	if cfg.EnableProfiling {
+		profilingCfg := cfg.Profiling
+		profilingCfg.ServiceName = cfg.ServiceName
+		profilingCfg.ServiceVersion = cfg.ServiceVersion
+		profilingCfg.Env = cfg.Env
+		if profilingCfg.Logger == nil {
+			profilingCfg.Logger = log
+		}
+		profiler, err = profiling.Init(ctx, profilingCfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Observability{
 		Logger:         log,
 		MetricsHandler: metricsHandler,
 		LoggerProvider: loggerProvider,
 		MeterProvider:  meterProvider,
 		TracerProvider: tracerProvider,
+		Profiler:       profiler,
 	}, nil
 }
 
@@ -99,6 +151,12 @@ func Init(ctx context.Context, cfg Config) (*Observability, error) {
 func (o *Observability) Shutdown(ctx context.Context) error {
 	var errs []error
 
+	if o.Profiler != nil {
+		if err := o.Profiler.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if o.TracerProvider != nil {
 		if err := o.TracerProvider.Shutdown(ctx); err != nil {
 			errs = append(errs, err)
@@ -123,6 +181,35 @@ func (o *Observability) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// ForceFlush exports all buffered spans, metrics and logs without shutting
+// the providers down. Use it in short-lived binaries or before risky operations.
+func (o *Observability) ForceFlush(ctx context.Context) error {
+	var errs []error
+
+	if o.TracerProvider != nil {
+		if err := o.TracerProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if o.MeterProvider != nil {
+		if err := o.MeterProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if o.LoggerProvider != nil {
+		if err := o.LoggerProvider.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
 // HTTPMetricsMiddleware returns http.Handler with otel metrics
 func HTTPMetricsMiddleware(next http.Handler) http.Handler {
 	return metrics.Middleware(next)
@@ -0,0 +1,97 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestForceFlush(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	obs := &Observability{TracerProvider: tp}
+	defer obs.Shutdown(context.Background())
+
+	_, span := tp.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	if err := obs.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+	if got := len(exporter.GetSpans()); got != 1 {
+		t.Fatalf("expected 1 exported span, got %d", got)
+	}
+
+	// Providers keep working after a flush
+	_, span = tp.Tracer("test").Start(context.Background(), "after_flush")
+	span.End()
+	if err := obs.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+	if got := len(exporter.GetSpans()); got != 2 {
+		t.Errorf("expected 2 exported spans, got %d", got)
+	}
+}
+
+func TestInitJSONLogs(t *testing.T) {
+	// JSON output applies only to environments without an OTLP exporter
+	RegisterEnvironment("json-test", EnvDefaults{LogLevel: slog.LevelInfo})
+
+	cfg, err := NewConfig(ConfigParams{
+		Env:            "json-test",
+		ServiceName:    "test-service",
+		ServiceVersion: "1.0.0",
+		JSONLogs:       true,
+	})
+	if err != nil {
+		t.Fatalf("NewConfig failed: %v", err)
+	}
+	if !cfg.JSONLogs {
+		t.Fatal("expected JSONLogs to be copied from params")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	obs, err := Init(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	obs.Logger.Info("json record")
+	if err := obs.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	os.Stdout = stdout
+	_ = w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.Contains(line, "json record") {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("expected a JSON line, got %q: %v", line, err)
+		}
+		if record["service.name"] != "test-service" {
+			t.Errorf("expected service.name attribute, got %v", record["service.name"])
+		}
+		return
+	}
+	t.Fatalf("record not found in output %q", out)
+}
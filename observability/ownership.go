@@ -0,0 +1,49 @@
+package observability
+
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Resource attribute keys of ownership metadata
+const (
+	TeamKey           = attribute.Key("service.team")
+	DomainKey         = attribute.Key("service.domain")
+	TierKey           = attribute.Key("service.tier")
+	DeploymentRingKey = attribute.Key("deployment.ring")
+)
+
+// Ownership describes who owns a service, so alerts can be routed by team or domain.
+// Non-empty fields are added to the resource of all signals and to every log record.
+type Ownership struct {
+	Team           string // e.g. "payments"
+	Domain         string // e.g. "checkout"
+	Tier           string // Criticality, e.g. "tier-1"
+	DeploymentRing string // e.g. "canary", "ring-1"
+}
+
+// Attributes returns the non-empty ownership fields as resource attributes
+func (o Ownership) Attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, kv := range []attribute.KeyValue{
+		TeamKey.String(o.Team),
+		DomainKey.String(o.Domain),
+		TierKey.String(o.Tier),
+		DeploymentRingKey.String(o.DeploymentRing),
+	} {
+		if kv.Value.AsString() != "" {
+			attrs = append(attrs, kv)
+		}
+	}
+	return attrs
+}
+
+// logAttrs returns the non-empty ownership fields as log attributes
+func (o Ownership) logAttrs() []any {
+	var attrs []any
+	for _, kv := range o.Attributes() {
+		attrs = append(attrs, slog.String(string(kv.Key), kv.Value.AsString()))
+	}
+	return attrs
+}
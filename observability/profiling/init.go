@@ -0,0 +1,180 @@
+// Package profiling exposes pprof endpoints and pushes continuous profiles
+// to Pyroscope-compatible servers (Pyroscope, Grafana Cloud Profiles).
+package profiling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/grafana/pyroscope-go"
+)
+
+type ProfileType = pyroscope.ProfileType
+
+// Profile types pushed to the server. DefaultProfileTypes covers CPU and memory.
+const (
+	ProfileCPU           = pyroscope.ProfileCPU
+	ProfileInuseObjects  = pyroscope.ProfileInuseObjects
+	ProfileAllocObjects  = pyroscope.ProfileAllocObjects
+	ProfileInuseSpace    = pyroscope.ProfileInuseSpace
+	ProfileAllocSpace    = pyroscope.ProfileAllocSpace
+	ProfileGoroutines    = pyroscope.ProfileGoroutines
+	ProfileMutexCount    = pyroscope.ProfileMutexCount
+	ProfileMutexDuration = pyroscope.ProfileMutexDuration
+	ProfileBlockCount    = pyroscope.ProfileBlockCount
+	ProfileBlockDuration = pyroscope.ProfileBlockDuration
+)
+
+var DefaultProfileTypes = pyroscope.DefaultProfileTypes
+
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	Env            string
+
+	// PprofAddr, if set, serves net/http/pprof handlers on this address, e.g. "localhost:6060"
+	PprofAddr string
+
+	// PushEndpoint, if set, pushes continuous profiles to this Pyroscope-compatible server,
+	// e.g. "http://pyroscope:4040"
+	PushEndpoint      string
+	BasicAuthUser     string
+	BasicAuthPassword string
+	TenantID          string            // Tenant of multi-tenant servers
+	Headers           map[string]string // Headers sent with every push request
+	Tags              map[string]string // Extra labels added to service_name, service_version and deployment_environment
+	ProfileTypes      []ProfileType     // Defaults to DefaultProfileTypes
+	UploadRate        time.Duration     // Defaults to 15s
+
+	// Sampling rates required by the mutex and block profile types, see
+	// runtime.SetMutexProfileFraction and runtime.SetBlockProfileRate. Zero keeps the runtime settings.
+	MutexProfileFraction int
+	BlockProfileRate     int
+
+	// Logger receives push errors. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Profiler runs the configured pprof endpoint and profile pusher
+type Profiler struct {
+	server *http.Server
+	pusher *pyroscope.Profiler
+}
+
+// Init starts the pprof endpoint and continuous profiling according to cfg
+func Init(_ context.Context, cfg Config) (*Profiler, error) {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.MutexProfileFraction > 0 {
+		runtime.SetMutexProfileFraction(cfg.MutexProfileFraction)
+	}
+	if cfg.BlockProfileRate > 0 {
+		runtime.SetBlockProfileRate(cfg.BlockProfileRate)
+	}
+
+	p := &Profiler{}
+
+	if cfg.PushEndpoint != "" {
+		tags := map[string]string{
+			"service_version":        cfg.ServiceVersion,
+			"deployment_environment": cfg.Env,
+		}
+		for k, v := range cfg.Tags {
+			tags[k] = v
+		}
+
+		pusher, err := pyroscope.Start(pyroscope.Config{
+			ApplicationName:   cfg.ServiceName,
+			ServerAddress:     cfg.PushEndpoint,
+			BasicAuthUser:     cfg.BasicAuthUser,
+			BasicAuthPassword: cfg.BasicAuthPassword,
+			TenantID:          cfg.TenantID,
+			HTTPHeaders:       cfg.Headers,
+			Tags:              tags,
+			ProfileTypes:      cfg.ProfileTypes,
+			UploadRate:        cfg.UploadRate,
+			Logger:            logger{cfg.Logger},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start profile pusher: %w", err)
+		}
+		p.pusher = pusher
+	}
+
+	if cfg.PprofAddr != "" {
+		lis, err := net.Listen("tcp", cfg.PprofAddr)
+		if err != nil {
+			_ = p.Shutdown(context.Background())
+			return nil, fmt.Errorf("failed to listen on pprof address: %w", err)
+		}
+
+		p.server = &http.Server{
+			Handler:           Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := p.server.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+				cfg.Logger.Error("pprof server error", slog.Any("error", err))
+			}
+		}()
+	}
+
+	return p, nil
+}
+
+// Handler returns the net/http/pprof handlers under /debug/pprof/,
+// for mounting on an existing internal server
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Shutdown stops the pprof endpoint and uploads the remaining profiles
+func (p *Profiler) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	if p.server != nil {
+		if err := p.server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.pusher != nil {
+		if err := p.pusher.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// logger adapts slog to the pyroscope logger interface. Info and debug
+// messages are logged at debug level, as they are emitted on every upload.
+type logger struct {
+	log *slog.Logger
+}
+
+func (l logger) Infof(format string, args ...any) {
+	l.log.Debug(fmt.Sprintf(format, args...), slog.String("component", "pyroscope"))
+}
+
+func (l logger) Debugf(format string, args ...any) {
+	l.log.Debug(fmt.Sprintf(format, args...), slog.String("component", "pyroscope"))
+}
+
+func (l logger) Errorf(format string, args ...any) {
+	l.log.Error(fmt.Sprintf(format, args...), slog.String("component", "pyroscope"))
+}
@@ -0,0 +1,30 @@
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestInitPprofEndpoint(t *testing.T) {
+	p, err := Init(context.Background(), Config{PprofAddr: "127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if p.server == nil {
+		t.Fatal("expected pprof server to be started")
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
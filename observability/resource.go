@@ -0,0 +1,98 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Environment variables read by K8sDetector. They are usually populated
+// from the Downward API in the pod spec.
+const (
+	EnvK8sPodName   = "K8S_POD_NAME"
+	EnvK8sPodUID    = "K8S_POD_UID"
+	EnvK8sNamespace = "K8S_NAMESPACE_NAME"
+	EnvK8sNodeName  = "K8S_NODE_NAME"
+)
+
+// k8sNamespaceFile is mounted into every pod with a service account token
+const k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// newResource builds the resource shared by the logger, tracer and meter providers.
+// Attributes from OTEL_RESOURCE_ATTRIBUTES and detectors are merged first,
+// so service identity and explicit ResourceAttributes take precedence.
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	instanceID := cfg.ServiceInstanceID
+	if instanceID == "" {
+		instanceID = uuid.NewString()
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironment(cfg.Env),
+		semconv.ServiceInstanceID(instanceID),
+	}
+	attrs = append(attrs, cfg.Ownership.Attributes()...)
+	attrs = append(attrs, cfg.ResourceAttributes...)
+
+	opts := []resource.Option{resource.WithFromEnv()}
+	opts = append(opts, cfg.ResourceOptions...)
+	opts = append(opts, resource.WithAttributes(attrs...))
+
+	res, err := resource.New(ctx, opts...)
+	// Detectors that could not find all attributes (e.g. container ID outside a container)
+	// still return what they found
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	return res, nil
+}
+
+// K8sDetector returns a detector for Kubernetes pod attributes.
+// Pod name, UID and node name are read from K8S_POD_NAME, K8S_POD_UID and K8S_NODE_NAME;
+// the namespace from K8S_NAMESPACE_NAME or the service account mount.
+// Outside Kubernetes it returns an empty resource.
+func K8sDetector() resource.Detector {
+	return k8sDetector{}
+}
+
+type k8sDetector struct{}
+
+func (k8sDetector) Detect(context.Context) (*resource.Resource, error) {
+	var attrs []attribute.KeyValue
+
+	if v := os.Getenv(EnvK8sPodName); v != "" {
+		attrs = append(attrs, semconv.K8SPodName(v))
+	}
+	if v := os.Getenv(EnvK8sPodUID); v != "" {
+		attrs = append(attrs, semconv.K8SPodUID(v))
+	}
+	if v := os.Getenv(EnvK8sNodeName); v != "" {
+		attrs = append(attrs, semconv.K8SNodeName(v))
+	}
+
+	namespace := os.Getenv(EnvK8sNamespace)
+	if namespace == "" {
+		if b, err := os.ReadFile(k8sNamespaceFile); err == nil {
+			namespace = strings.TrimSpace(string(b))
+		}
+	}
+	if namespace != "" {
+		attrs = append(attrs, semconv.K8SNamespaceName(namespace))
+	}
+
+	if len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+	// Schemaless, so it merges with detectors using other semconv versions
+	return resource.NewSchemaless(attrs...), nil
+}
@@ -0,0 +1,71 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Setenv(EnvK8sPodName, "api-7d9f")
+	t.Setenv(EnvK8sNamespace, "payments")
+
+	cfg, err := NewConfig(ConfigParams{
+		Env:            EnvLocal,
+		ServiceName:    "api",
+		ServiceVersion: "1.2.3",
+		Ownership:      Ownership{Team: "payments", DeploymentRing: "canary"},
+	},
+		WithResourceAttributes(attribute.String("team", "core")),
+		WithResourceDetectors(K8sDetector()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+
+	res, err := newResource(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected resource error: %v", err)
+	}
+
+	expected := map[attribute.Key]string{
+		"service.name":       "api",
+		"service.version":    "1.2.3",
+		"team":               "core",
+		"k8s.pod.name":       "api-7d9f",
+		"k8s.namespace.name": "payments",
+		"service.team":       "payments",
+		"deployment.ring":    "canary",
+	}
+	for key, want := range expected {
+		if got, ok := res.Set().Value(key); !ok || got.AsString() != want {
+			t.Errorf("expected %s=%s, got %v", key, want, got.AsString())
+		}
+	}
+	if id, ok := res.Set().Value("service.instance.id"); !ok || id.AsString() == "" {
+		t.Error("expected generated service.instance.id")
+	}
+	if _, ok := res.Set().Value(DomainKey); ok {
+		t.Error("expected empty ownership fields to be omitted")
+	}
+}
+
+func TestNewResourceServiceInstanceID(t *testing.T) {
+	t.Setenv(EnvK8sPodName, "pod-1")
+
+	cfg := Config{ServiceName: "api", ServiceInstanceID: "pod-1", ResourceOptions: []resource.Option{resource.WithHost(), resource.WithDetectors(K8sDetector())}}
+
+	res, err := newResource(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected resource error: %v", err)
+	}
+
+	if id, _ := res.Set().Value("service.instance.id"); id.AsString() != "pod-1" {
+		t.Errorf("expected configured instance ID, got %q", id.AsString())
+	}
+	if _, ok := res.Set().Value("host.name"); !ok {
+		t.Error("expected host detector attributes")
+	}
+}
@@ -0,0 +1,176 @@
+// Package scope resolves the OpenTelemetry instrumentation scopes of tracers and meters,
+// so backends grouping telemetry by scope attribute it to the component that produced it.
+//
+// Every golib observability package, and every tracer or meter obtained from
+// observability.Observability, looks up its scope by a key, usually an import path.
+// Without registration the scope is named after the key and versioned after the module
+// containing it in the build info of the binary. Register overrides both, or adds scopes
+// for the components of an application:
+//
+//	func init() {
+//		scope.Register("github.com/rshelekhov/golib/observability/metrics", scope.Scope{Name: "orders/metrics"})
+//		scope.Register("billing", scope.Scope{Version: "2.3.0"})
+//	}
+//
+//	tracer := obs.Tracer("billing")
+package scope
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Scope is an instrumentation scope
+type Scope struct {
+	// Name of the scope. Defaults to the key it is looked up by.
+	Name string
+	// Version of the scope. Defaults to the version of the module containing the key
+	// in the build info of the binary, if the key is an import path.
+	Version string
+	// SchemaURL of the semantic conventions used by the instrumentation
+	SchemaURL string
+	// Attributes identifying the scope, e.g. the component of a multi-module binary
+	Attributes []attribute.KeyValue
+}
+
+var (
+	scopesMu sync.RWMutex
+	scopes   = make(map[string]Scope)
+)
+
+// Register sets the scope looked up by key. Empty Name and Version are defaulted by Lookup;
+// registering an existing key replaces its scope. It is meant to be called during program
+// initialization, as instrumentation may keep the tracers and meters it creates.
+func Register(key string, s Scope) {
+	if key == "" {
+		panic("scope: key is required")
+	}
+
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+	scopes[key] = s
+}
+
+// Lookup returns the scope registered for key, with defaults applied
+func Lookup(key string) Scope {
+	scopesMu.RLock()
+	s := scopes[key]
+	scopesMu.RUnlock()
+	return s.withDefaults(key)
+}
+
+// Registered returns the registered scopes by key, with defaults applied
+func Registered() map[string]Scope {
+	scopesMu.RLock()
+	defer scopesMu.RUnlock()
+
+	registered := make(map[string]Scope, len(scopes))
+	for key, s := range scopes {
+		registered[key] = s.withDefaults(key)
+	}
+	return registered
+}
+
+func (s Scope) withDefaults(key string) Scope {
+	if s.Name == "" {
+		s.Name = key
+	}
+	if s.Version == "" {
+		s.Version = moduleVersion(key)
+	}
+	return s
+}
+
+// TracerOptions returns the options setting the version, schema URL and attributes of a tracer
+func (s Scope) TracerOptions() []trace.TracerOption {
+	var opts []trace.TracerOption
+	if s.Version != "" {
+		opts = append(opts, trace.WithInstrumentationVersion(s.Version))
+	}
+	if s.SchemaURL != "" {
+		opts = append(opts, trace.WithSchemaURL(s.SchemaURL))
+	}
+	if len(s.Attributes) > 0 {
+		opts = append(opts, trace.WithInstrumentationAttributes(s.Attributes...))
+	}
+	return opts
+}
+
+// MeterOptions returns the options setting the version, schema URL and attributes of a meter
+func (s Scope) MeterOptions() []metric.MeterOption {
+	var opts []metric.MeterOption
+	if s.Version != "" {
+		opts = append(opts, metric.WithInstrumentationVersion(s.Version))
+	}
+	if s.SchemaURL != "" {
+		opts = append(opts, metric.WithSchemaURL(s.SchemaURL))
+	}
+	if len(s.Attributes) > 0 {
+		opts = append(opts, metric.WithInstrumentationAttributes(s.Attributes...))
+	}
+	return opts
+}
+
+// Tracer returns a tracer of the scope from tp; options are applied after the ones of the scope
+func (s Scope) Tracer(tp trace.TracerProvider, opts ...trace.TracerOption) trace.Tracer {
+	return tp.Tracer(s.Name, append(s.TracerOptions(), opts...)...)
+}
+
+// Meter returns a meter of the scope from mp; options are applied after the ones of the scope
+func (s Scope) Meter(mp metric.MeterProvider, opts ...metric.MeterOption) metric.Meter {
+	return mp.Meter(s.Name, append(s.MeterOptions(), opts...)...)
+}
+
+// Tracer returns a tracer of the scope of key from the global provider
+func Tracer(key string) trace.Tracer {
+	return Lookup(key).Tracer(otel.GetTracerProvider())
+}
+
+// Meter returns a meter of the scope of key from the global provider
+func Meter(key string) metric.Meter {
+	return Lookup(key).Meter(otel.GetMeterProvider())
+}
+
+// buildModules returns the versions of the modules of the binary by path
+var buildModules = sync.OnceValue(func() map[string]string {
+	modules := make(map[string]string)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return modules
+	}
+
+	add := func(m *debug.Module) {
+		if m.Replace != nil && m.Replace.Version != "" {
+			modules[m.Path] = m.Replace.Version
+			return
+		}
+		// Modules of the workspace and local replacements have no version
+		if m.Version != "" && m.Version != "(devel)" {
+			modules[m.Path] = m.Version
+		}
+	}
+	add(&info.Main)
+	for _, dep := range info.Deps {
+		add(dep)
+	}
+	return modules
+})
+
+// moduleVersion returns the version of the module containing the package path,
+// or an empty string if it isn't known
+func moduleVersion(path string) string {
+	var version string
+	longest := -1
+	for module, v := range buildModules() {
+		if (path == module || strings.HasPrefix(path, module+"/")) && len(module) > longest {
+			version, longest = v, len(module)
+		}
+	}
+	return version
+}
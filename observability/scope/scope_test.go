@@ -0,0 +1,61 @@
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestScope(t *testing.T) {
+	if s := Lookup("github.com/acme/orders/internal/billing"); s.Name != "github.com/acme/orders/internal/billing" {
+		t.Errorf("Lookup() name = %q, want the key", s.Name)
+	}
+
+	Register("billing", Scope{
+		Version:    "2.3.0",
+		SchemaURL:  "https://opentelemetry.io/schemas/1.26.0",
+		Attributes: []attribute.KeyValue{attribute.String("component", "billing")},
+	})
+	t.Cleanup(func() {
+		scopesMu.Lock()
+		delete(scopes, "billing")
+		scopesMu.Unlock()
+	})
+
+	if _, ok := Registered()["billing"]; !ok {
+		t.Error("Registered() misses billing")
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_, span := Lookup("billing").Tracer(tp).Start(context.Background(), "charge")
+	span.End()
+
+	got := recorder.Ended()[0].InstrumentationScope()
+	if got.Name != "billing" || got.Version != "2.3.0" || got.SchemaURL == "" {
+		t.Errorf("span scope = %+v, want billing 2.3.0 with schema URL", got)
+	}
+	if v, ok := got.Attributes.Value("component"); !ok || v.AsString() != "billing" {
+		t.Errorf("span scope attributes = %v, want component=billing", got.Attributes.ToSlice())
+	}
+}
+
+func TestModuleVersion(t *testing.T) {
+	modules := buildModules()
+	modules["example.com/lib"] = "v1.2.0"
+	modules["example.com/lib/v2"] = "v2.0.1"
+
+	for path, want := range map[string]string{
+		"example.com/lib":          "v1.2.0",
+		"example.com/lib/tracing":  "v1.2.0",
+		"example.com/lib/v2/trace": "v2.0.1",
+		"example.com/library":      "",
+	} {
+		if got := moduleVersion(path); got != want {
+			t.Errorf("moduleVersion(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package spool
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// encodeLogs converts records to OTLP, grouped by resource and instrumentation scope
+func encodeLogs(records []sdklog.Record) *logspb.LogsData {
+	type scopeKey struct {
+		res   *resource.Resource
+		scope instrumentation.Scope
+	}
+
+	data := &logspb.LogsData{}
+	resourceLogs := make(map[*resource.Resource]*logspb.ResourceLogs)
+	scopeLogs := make(map[scopeKey]*logspb.ScopeLogs)
+
+	for i := range records {
+		r := &records[i]
+
+		res := r.Resource()
+		rl, ok := resourceLogs[res]
+		if !ok {
+			rl = &logspb.ResourceLogs{Resource: &resourcepb.Resource{}}
+			if res != nil {
+				rl.Resource.Attributes = attrsToProto(res.Attributes())
+				rl.SchemaUrl = res.SchemaURL()
+			}
+			resourceLogs[res] = rl
+			data.ResourceLogs = append(data.ResourceLogs, rl)
+		}
+
+		scope := r.InstrumentationScope()
+		key := scopeKey{res: res, scope: scope}
+		sl, ok := scopeLogs[key]
+		if !ok {
+			sl = &logspb.ScopeLogs{
+				Scope: &commonpb.InstrumentationScope{
+					Name:       scope.Name,
+					Version:    scope.Version,
+					Attributes: attrsToProto(scope.Attributes.ToSlice()),
+				},
+				SchemaUrl: scope.SchemaURL,
+			}
+			scopeLogs[key] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+
+		sl.LogRecords = append(sl.LogRecords, recordToProto(r))
+	}
+
+	return data
+}
+
+func recordToProto(r *sdklog.Record) *logspb.LogRecord {
+	lr := &logspb.LogRecord{
+		TimeUnixNano:           unixNano(r.Timestamp()),
+		ObservedTimeUnixNano:   unixNano(r.ObservedTimestamp()),
+		SeverityNumber:         logspb.SeverityNumber(r.Severity()),
+		SeverityText:           r.SeverityText(),
+		Body:                   valueToProto(r.Body()),
+		DroppedAttributesCount: uint32(r.DroppedAttributes()),
+		Flags:                  uint32(r.TraceFlags()),
+		EventName:              r.EventName(),
+	}
+	if traceID := r.TraceID(); traceID.IsValid() {
+		lr.TraceId = traceID[:]
+	}
+	if spanID := r.SpanID(); spanID.IsValid() {
+		lr.SpanId = spanID[:]
+	}
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		lr.Attributes = append(lr.Attributes, &commonpb.KeyValue{Key: kv.Key, Value: valueToProto(kv.Value)})
+		return true
+	})
+	return lr
+}
+
+// decodeLogs rebuilds records from OTLP. Records are emitted through a LoggerProvider
+// per resource, as that is the only way to set the resource and scope of a record.
+func decodeLogs(data *logspb.LogsData) []sdklog.Record {
+	c := &recordCollector{}
+
+	for _, rl := range data.GetResourceLogs() {
+		res := resource.NewWithAttributes(rl.GetSchemaUrl(), attrsFromProto(rl.GetResource().GetAttributes())...)
+		provider := sdklog.NewLoggerProvider(
+			sdklog.WithResource(res),
+			sdklog.WithProcessor(c),
+			sdklog.WithAttributeCountLimit(-1),
+			sdklog.WithAttributeValueLengthLimit(-1),
+		)
+
+		for _, sl := range rl.GetScopeLogs() {
+			logger := provider.Logger(
+				sl.GetScope().GetName(),
+				log.WithInstrumentationVersion(sl.GetScope().GetVersion()),
+				log.WithSchemaURL(sl.GetSchemaUrl()),
+				log.WithInstrumentationAttributes(attrsFromProto(sl.GetScope().GetAttributes())...),
+			)
+			for _, lr := range sl.GetLogRecords() {
+				emitRecord(logger, lr)
+			}
+		}
+	}
+
+	return c.records
+}
+
+func emitRecord(logger log.Logger, lr *logspb.LogRecord) {
+	var r log.Record
+	r.SetTimestamp(fromUnixNano(lr.GetTimeUnixNano()))
+	r.SetObservedTimestamp(fromUnixNano(lr.GetObservedTimeUnixNano()))
+	r.SetSeverity(log.Severity(lr.GetSeverityNumber()))
+	r.SetSeverityText(lr.GetSeverityText())
+	r.SetBody(valueFromProto(lr.GetBody()))
+	r.SetEventName(lr.GetEventName())
+	for _, kv := range lr.GetAttributes() {
+		r.AddAttributes(log.KeyValue{Key: kv.GetKey(), Value: valueFromProto(kv.GetValue())})
+	}
+
+	// The SDK takes trace and span IDs from the span context
+	var sc trace.SpanContextConfig
+	copy(sc.TraceID[:], lr.GetTraceId())
+	copy(sc.SpanID[:], lr.GetSpanId())
+	sc.TraceFlags = trace.TraceFlags(lr.GetFlags())
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(sc))
+
+	logger.Emit(ctx, r)
+}
+
+// recordCollector is a processor that keeps emitted records
+type recordCollector struct {
+	records []sdklog.Record
+}
+
+func (c *recordCollector) OnEmit(_ context.Context, r *sdklog.Record) error {
+	c.records = append(c.records, r.Clone())
+	return nil
+}
+
+func (c *recordCollector) Shutdown(context.Context) error   { return nil }
+func (c *recordCollector) ForceFlush(context.Context) error { return nil }
+
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func fromUnixNano(n uint64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(n))
+}
+
+func valueToProto(v log.Value) *commonpb.AnyValue {
+	switch v.Kind() {
+	case log.KindBool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case log.KindInt64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case log.KindFloat64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case log.KindString:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case log.KindBytes:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v.AsBytes()}}
+	case log.KindSlice:
+		values := make([]*commonpb.AnyValue, 0, len(v.AsSlice()))
+		for _, item := range v.AsSlice() {
+			values = append(values, valueToProto(item))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+	case log.KindMap:
+		kvs := make([]*commonpb.KeyValue, 0, len(v.AsMap()))
+		for _, kv := range v.AsMap() {
+			kvs = append(kvs, &commonpb.KeyValue{Key: kv.Key, Value: valueToProto(kv.Value)})
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: kvs}}}
+	default:
+		return nil
+	}
+}
+
+func valueFromProto(v *commonpb.AnyValue) log.Value {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		return log.BoolValue(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return log.Int64Value(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return log.Float64Value(v.DoubleValue)
+	case *commonpb.AnyValue_StringValue:
+		return log.StringValue(v.StringValue)
+	case *commonpb.AnyValue_BytesValue:
+		return log.BytesValue(v.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]log.Value, 0, len(v.ArrayValue.GetValues()))
+		for _, item := range v.ArrayValue.GetValues() {
+			values = append(values, valueFromProto(item))
+		}
+		return log.SliceValue(values...)
+	case *commonpb.AnyValue_KvlistValue:
+		kvs := make([]log.KeyValue, 0, len(v.KvlistValue.GetValues()))
+		for _, kv := range v.KvlistValue.GetValues() {
+			kvs = append(kvs, log.KeyValue{Key: kv.GetKey(), Value: valueFromProto(kv.GetValue())})
+		}
+		return log.MapValue(kvs...)
+	default:
+		return log.Value{}
+	}
+}
+
+func attrsToProto(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	kvs := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		kvs = append(kvs, &commonpb.KeyValue{Key: string(kv.Key), Value: attrValueToProto(kv.Value)})
+	}
+	return kvs
+}
+
+func attrValueToProto(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return valueToProto(log.BoolValue(v.AsBool()))
+	case attribute.INT64:
+		return valueToProto(log.Int64Value(v.AsInt64()))
+	case attribute.FLOAT64:
+		return valueToProto(log.Float64Value(v.AsFloat64()))
+	case attribute.STRING:
+		return valueToProto(log.StringValue(v.AsString()))
+	case attribute.BOOLSLICE:
+		return sliceToProto(v.AsBoolSlice(), log.BoolValue)
+	case attribute.INT64SLICE:
+		return sliceToProto(v.AsInt64Slice(), log.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return sliceToProto(v.AsFloat64Slice(), log.Float64Value)
+	case attribute.STRINGSLICE:
+		return sliceToProto(v.AsStringSlice(), log.StringValue)
+	default:
+		return nil
+	}
+}
+
+func sliceToProto[T any](items []T, value func(T) log.Value) *commonpb.AnyValue {
+	values := make([]log.Value, 0, len(items))
+	for _, item := range items {
+		values = append(values, value(item))
+	}
+	return valueToProto(log.SliceValue(values...))
+}
+
+// attrsFromProto converts OTLP attributes back to resource and scope attributes.
+// Arrays take the type of their first element.
+func attrsFromProto(kvs []*commonpb.KeyValue) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		key := attribute.Key(kv.GetKey())
+		switch v := kv.GetValue().GetValue().(type) {
+		case *commonpb.AnyValue_BoolValue:
+			attrs = append(attrs, key.Bool(v.BoolValue))
+		case *commonpb.AnyValue_IntValue:
+			attrs = append(attrs, key.Int64(v.IntValue))
+		case *commonpb.AnyValue_DoubleValue:
+			attrs = append(attrs, key.Float64(v.DoubleValue))
+		case *commonpb.AnyValue_StringValue:
+			attrs = append(attrs, key.String(v.StringValue))
+		case *commonpb.AnyValue_ArrayValue:
+			attrs = append(attrs, arrayAttr(key, v.ArrayValue.GetValues()))
+		}
+	}
+	return attrs
+}
+
+func arrayAttr(key attribute.Key, values []*commonpb.AnyValue) attribute.KeyValue {
+	if len(values) == 0 {
+		return key.StringSlice(nil)
+	}
+
+	switch values[0].GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		items := make([]bool, 0, len(values))
+		for _, v := range values {
+			items = append(items, v.GetBoolValue())
+		}
+		return key.BoolSlice(items)
+	case *commonpb.AnyValue_IntValue:
+		items := make([]int64, 0, len(values))
+		for _, v := range values {
+			items = append(items, v.GetIntValue())
+		}
+		return key.Int64Slice(items)
+	case *commonpb.AnyValue_DoubleValue:
+		items := make([]float64, 0, len(values))
+		for _, v := range values {
+			items = append(items, v.GetDoubleValue())
+		}
+		return key.Float64Slice(items)
+	default:
+		items := make([]string, 0, len(values))
+		for _, v := range values {
+			items = append(items, v.GetStringValue())
+		}
+		return key.StringSlice(items)
+	}
+}
@@ -0,0 +1,71 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// LogExporter is a log exporter that spools records the collector didn't accept
+// and replays them in the background. Records are stored as OTLP LogsData protobuf.
+type LogExporter struct {
+	next    sdklog.Exporter
+	spooler *spooler
+}
+
+var _ sdklog.Exporter = (*LogExporter)(nil)
+
+// NewLogExporter wraps a log exporter, e.g. otlploggrpc.New, with a disk spool and starts
+// replaying records spooled by a previous process. Disable the retries of the wrapped exporter
+// so failed batches are spooled right away instead of blocking the batch processor.
+func NewLogExporter(next sdklog.Exporter, cfg Config) (*LogExporter, error) {
+	e := &LogExporter{next: next}
+
+	s, err := newSpooler(signalLogs, cfg, e.replay)
+	if err != nil {
+		return nil, err
+	}
+	e.spooler = s
+	s.start()
+	return e, nil
+}
+
+// Export exports records with the wrapped exporter and spools them if the export fails
+func (e *LogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.next.Export(ctx, records)
+	if err == nil || len(records) == 0 {
+		return err
+	}
+
+	data, mErr := proto.Marshal(encodeLogs(records))
+	if mErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to encode log records: %w", mErr))
+	}
+	if sErr := e.spooler.store(ctx, data, len(records)); sErr != nil {
+		return errors.Join(err, sErr)
+	}
+	return nil
+}
+
+// Shutdown stops the replay and shuts down the wrapped exporter. Spooled records stay on disk.
+func (e *LogExporter) Shutdown(ctx context.Context) error {
+	e.spooler.stop()
+	return e.next.Shutdown(ctx)
+}
+
+// ForceFlush flushes the wrapped exporter
+func (e *LogExporter) ForceFlush(ctx context.Context) error {
+	return e.next.ForceFlush(ctx)
+}
+
+func (e *LogExporter) replay(ctx context.Context, data []byte) error {
+	var logs logspb.LogsData
+	if err := proto.Unmarshal(data, &logs); err != nil {
+		return fmt.Errorf("%w: %w", errCorrupt, err)
+	}
+	return e.next.Export(ctx, decodeLogs(&logs))
+}
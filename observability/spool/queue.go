@@ -0,0 +1,194 @@
+package spool
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	batchExt = ".otlp"
+	tempExt  = ".tmp"
+)
+
+var (
+	errBatchTooLarge = errors.New("batch exceeds spool size")
+	errSpoolFull     = errors.New("spool is full")
+)
+
+// entry is a batch stored on disk. Its file name holds the sequence number and the item count,
+// so the queue can be restored and dropped items counted without reading the files.
+type entry struct {
+	seq   uint64
+	items int
+	size  int64
+}
+
+func (e entry) name() string {
+	return fmt.Sprintf("%020d-%d%s", e.seq, e.items, batchExt)
+}
+
+func parseEntry(name string) (entry, bool) {
+	base, ok := strings.CutSuffix(name, batchExt)
+	if !ok {
+		return entry{}, false
+	}
+	seqStr, itemsStr, ok := strings.Cut(base, "-")
+	if !ok {
+		return entry{}, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return entry{}, false
+	}
+	items, err := strconv.Atoi(itemsStr)
+	if err != nil {
+		return entry{}, false
+	}
+	return entry{seq: seq, items: items}, true
+}
+
+// queue is a bounded FIFO of batches, one file per batch
+type queue struct {
+	dir      string
+	maxBytes int64
+	policy   DropPolicy
+
+	mu      sync.Mutex
+	entries []entry
+	size    int64
+	nextSeq uint64
+}
+
+// openQueue creates dir if needed and restores batches left by a previous process
+func openQueue(dir string, maxBytes int64, policy DropPolicy) (*queue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	q := &queue{dir: dir, maxBytes: maxBytes, policy: policy}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(f.Name(), tempExt) {
+			// Leftover of an interrupted write
+			_ = os.Remove(filepath.Join(dir, f.Name()))
+			continue
+		}
+		e, ok := parseEntry(f.Name())
+		if !ok {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		e.size = info.Size()
+		q.entries = append(q.entries, e)
+		q.size += e.size
+		q.nextSeq = max(q.nextSeq, e.seq+1)
+	}
+	slices.SortFunc(q.entries, func(a, b entry) int { return cmp.Compare(a.seq, b.seq) })
+
+	return q, nil
+}
+
+// push stores a batch of items. When the queue is full, the oldest batches are evicted
+// or the new batch is rejected with errSpoolFull, depending on the drop policy.
+// It returns the number of evicted items.
+func (q *queue) push(data []byte, items int) (int, error) {
+	size := int64(len(data))
+	if size > q.maxBytes {
+		return 0, errBatchTooLarge
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	evicted := 0
+	for q.size+size > q.maxBytes {
+		if q.policy == DropNewest {
+			return 0, errSpoolFull
+		}
+		oldest := q.entries[0]
+		if err := q.removeLocked(oldest); err != nil {
+			return evicted, err
+		}
+		evicted += oldest.items
+	}
+
+	e := entry{seq: q.nextSeq, items: items, size: size}
+	path := filepath.Join(q.dir, e.name())
+	if err := os.WriteFile(path+tempExt, data, 0o600); err != nil {
+		return evicted, fmt.Errorf("failed to write spool batch: %w", err)
+	}
+	if err := os.Rename(path+tempExt, path); err != nil {
+		_ = os.Remove(path + tempExt)
+		return evicted, fmt.Errorf("failed to write spool batch: %w", err)
+	}
+
+	q.nextSeq++
+	q.entries = append(q.entries, e)
+	q.size += size
+	return evicted, nil
+}
+
+// peek returns the oldest batch without removing it
+func (q *queue) peek() (entry, []byte, bool, error) {
+	q.mu.Lock()
+	if len(q.entries) == 0 {
+		q.mu.Unlock()
+		return entry{}, nil, false, nil
+	}
+	e := q.entries[0]
+	q.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(q.dir, e.name()))
+	if err != nil {
+		return e, nil, true, fmt.Errorf("failed to read spool batch: %w", err)
+	}
+	return e, data, true, nil
+}
+
+// remove deletes a batch. It reports false if the batch was already evicted.
+func (q *queue) remove(e entry) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !slices.Contains(q.entries, e) {
+		return false, nil
+	}
+	return true, q.removeLocked(e)
+}
+
+func (q *queue) removeLocked(e entry) error {
+	i := slices.Index(q.entries, e)
+	if i < 0 {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(q.dir, e.name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove spool batch: %w", err)
+	}
+	q.entries = slices.Delete(q.entries, i, i+1)
+	q.size -= e.size
+	return nil
+}
+
+// stats returns the number of stored batches and their total size in bytes
+func (q *queue) stats() (int, int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries), q.size
+}
@@ -0,0 +1,287 @@
+// Package spool buffers OTLP spans and logs on disk while the collector is unreachable
+// and replays them once it recovers, so a collector outage doesn't lose telemetry.
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rshelekhov/golib/observability/scope"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of spool metrics
+const meterName = "github.com/rshelekhov/golib/observability/spool"
+
+const (
+	// DefaultMaxBytes is the spool size per signal used when Config.MaxBytes is zero
+	DefaultMaxBytes = 100 << 20
+	// DefaultReplayInterval is the replay interval used when Config.ReplayInterval is zero
+	DefaultReplayInterval = 5 * time.Second
+	// DefaultReplayTimeout is the replay timeout used when Config.ReplayTimeout is zero
+	DefaultReplayTimeout = 30 * time.Second
+)
+
+// DropPolicy decides which batches are dropped when the spool is full
+type DropPolicy string
+
+const (
+	// DropOldest evicts the oldest batches to make room for new ones
+	DropOldest DropPolicy = "oldest"
+	// DropNewest keeps the spooled batches and drops new ones
+	DropNewest DropPolicy = "newest"
+)
+
+const (
+	signalTraces = "traces"
+	signalLogs   = "logs"
+)
+
+// Reasons of dropped items
+const (
+	dropReasonFull    = "full"
+	dropReasonSize    = "too_large"
+	dropReasonCorrupt = "corrupt"
+	dropReasonWrite   = "write_error"
+)
+
+// Config configures the disk spool. Each signal is spooled to its own subdirectory of Dir.
+type Config struct {
+	// Dir is the spool directory. It should survive restarts, e.g. a persistent volume,
+	// so telemetry spooled before a restart is replayed after it.
+	Dir string
+	// MaxBytes bounds the spool size per signal. Defaults to DefaultMaxBytes.
+	MaxBytes int64
+	// DropPolicy decides what is dropped when the spool is full. Defaults to DropOldest.
+	DropPolicy DropPolicy
+	// ReplayInterval is how often spooled batches are replayed. Defaults to DefaultReplayInterval.
+	ReplayInterval time.Duration
+	// ReplayTimeout bounds the export of a single replayed batch. Defaults to DefaultReplayTimeout.
+	ReplayTimeout time.Duration
+}
+
+func (c Config) withDefaults() (Config, error) {
+	if c.Dir == "" {
+		return c, errors.New("spool directory is required")
+	}
+	if c.MaxBytes <= 0 {
+		c.MaxBytes = DefaultMaxBytes
+	}
+	switch c.DropPolicy {
+	case "":
+		c.DropPolicy = DropOldest
+	case DropOldest, DropNewest:
+	default:
+		return c, fmt.Errorf("invalid spool drop policy: %s", c.DropPolicy)
+	}
+	if c.ReplayInterval <= 0 {
+		c.ReplayInterval = DefaultReplayInterval
+	}
+	if c.ReplayTimeout <= 0 {
+		c.ReplayTimeout = DefaultReplayTimeout
+	}
+	return c, nil
+}
+
+// spooler stores failed batches of a signal and replays them in the background with send
+type spooler struct {
+	signal string
+	cfg    Config
+	queue  *queue
+	send   func(ctx context.Context, data []byte) error
+
+	spooled  metric.Int64Counter
+	replayed metric.Int64Counter
+	dropped  metric.Int64Counter
+	attrs    metric.MeasurementOption
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newSpooler(signal string, cfg Config, send func(ctx context.Context, data []byte) error) (*spooler, error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := openQueue(filepath.Join(cfg.Dir, signal), cfg.MaxBytes, cfg.DropPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &spooler{
+		signal: signal,
+		cfg:    cfg,
+		queue:  q,
+		send:   send,
+		attrs:  metric.WithAttributes(attribute.String("signal", signal)),
+		done:   make(chan struct{}),
+	}
+	s.initMetrics()
+	return s, nil
+}
+
+func (s *spooler) initMetrics() {
+	meter := scope.Meter(meterName)
+
+	var err error
+	s.spooled, err = meter.Int64Counter(
+		"telemetry_spool_spooled_total",
+		metric.WithDescription("Total number of spans and log records written to the spool after a failed export"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	s.replayed, err = meter.Int64Counter(
+		"telemetry_spool_replayed_total",
+		metric.WithDescription("Total number of spooled spans and log records exported after recovery"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	s.dropped, err = meter.Int64Counter(
+		"telemetry_spool_dropped_total",
+		metric.WithDescription("Total number of spans and log records dropped by the spool"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		"telemetry_spool_size_bytes",
+		metric.WithDescription("Size of spooled batches on disk"),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			_, size := s.queue.stats()
+			o.Observe(size, s.attrs)
+			return nil
+		}),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// store spools a batch that failed to export. It returns an error if the batch was dropped.
+func (s *spooler) store(ctx context.Context, data []byte, items int) error {
+	evicted, err := s.queue.push(data, items)
+	s.drop(ctx, evicted, dropReasonFull)
+
+	switch {
+	case err == nil:
+		if s.spooled != nil {
+			s.spooled.Add(ctx, int64(items), s.attrs)
+		}
+		return nil
+	case errors.Is(err, errSpoolFull):
+		s.drop(ctx, items, dropReasonFull)
+	case errors.Is(err, errBatchTooLarge):
+		s.drop(ctx, items, dropReasonSize)
+	default:
+		s.drop(ctx, items, dropReasonWrite)
+	}
+	return fmt.Errorf("failed to spool %s: %w", s.signal, err)
+}
+
+func (s *spooler) drop(ctx context.Context, items int, reason string) {
+	if items == 0 || s.dropped == nil {
+		return
+	}
+	s.dropped.Add(ctx, int64(items), metric.WithAttributes(
+		attribute.String("signal", s.signal),
+		attribute.String("reason", reason),
+	))
+}
+
+// start begins replaying spooled batches every ReplayInterval
+func (s *spooler) start() {
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+// stop ends the replay loop. Batches still spooled are replayed after the next start.
+func (s *spooler) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+	})
+}
+
+func (s *spooler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.ReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.replay()
+		}
+	}
+}
+
+// replay exports spooled batches oldest first until the spool is empty or an export fails
+func (s *spooler) replay() {
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		e, data, ok, err := s.queue.peek()
+		if !ok {
+			return
+		}
+		if err == nil {
+			err = s.export(data)
+			if err != nil && !errors.Is(err, errCorrupt) {
+				// The collector is still unreachable, retry on the next tick
+				return
+			}
+		}
+
+		removed, rmErr := s.queue.remove(e)
+		if rmErr != nil {
+			otel.Handle(rmErr)
+			return
+		}
+		if !removed {
+			// Evicted while being replayed
+			continue
+		}
+		if err != nil {
+			otel.Handle(fmt.Errorf("failed to replay spooled %s: %w", s.signal, err))
+			s.drop(context.Background(), e.items, dropReasonCorrupt)
+			continue
+		}
+		if s.replayed != nil {
+			s.replayed.Add(context.Background(), int64(e.items), s.attrs)
+		}
+	}
+}
+
+func (s *spooler) export(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ReplayTimeout)
+	defer cancel()
+	return s.send(ctx, data)
+}
+
+// errCorrupt marks a spooled batch that can't be decoded and is dropped instead of retried
+var errCorrupt = errors.New("corrupt spool batch")
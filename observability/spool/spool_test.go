@@ -0,0 +1,256 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+var errUnavailable = errors.New("collector unavailable")
+
+func TestQueue(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := openQueue(dir, 10, DropOldest)
+	if err != nil {
+		t.Fatalf("openQueue() error = %v", err)
+	}
+	for i, data := range []string{"aaaa", "bbbb", "cccc"} {
+		evicted, err := q.push([]byte(data), i+1)
+		if err != nil {
+			t.Fatalf("push(%q) error = %v", data, err)
+		}
+		if want := map[int]int{2: 1}[i]; evicted != want {
+			t.Errorf("push(%q) evicted = %d, want %d", data, evicted, want)
+		}
+	}
+	if _, err := q.push(make([]byte, 11), 1); !errors.Is(err, errBatchTooLarge) {
+		t.Errorf("push() of oversized batch error = %v, want %v", err, errBatchTooLarge)
+	}
+
+	// A reopened queue continues where the previous one stopped
+	q, err = openQueue(dir, 10, DropNewest)
+	if err != nil {
+		t.Fatalf("openQueue() error = %v", err)
+	}
+	if n, size := q.stats(); n != 2 || size != 8 {
+		t.Errorf("stats() = %d, %d, want 2, 8", n, size)
+	}
+	if _, err := q.push([]byte("dddd"), 1); !errors.Is(err, errSpoolFull) {
+		t.Errorf("push() to full queue error = %v, want %v", err, errSpoolFull)
+	}
+
+	e, data, ok, err := q.peek()
+	if err != nil || !ok || string(data) != "bbbb" || e.items != 2 {
+		t.Fatalf("peek() = %+v, %q, %v, %v, want oldest batch bbbb", e, data, ok, err)
+	}
+	if removed, err := q.remove(e); !removed || err != nil {
+		t.Fatalf("remove() = %v, %v", removed, err)
+	}
+	if _, err := q.push([]byte("dddd"), 1); err != nil {
+		t.Errorf("push() after remove error = %v", err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Errorf("spool directory has %d files, want 2", len(files))
+	}
+}
+
+type fakeTraceClient struct {
+	mu       sync.Mutex
+	down     bool
+	uploaded int
+}
+
+func (c *fakeTraceClient) Start(context.Context) error { return nil }
+func (c *fakeTraceClient) Stop(context.Context) error  { return nil }
+
+func (c *fakeTraceClient) UploadTraces(_ context.Context, rs []*tracepb.ResourceSpans) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return errUnavailable
+	}
+	c.uploaded += countSpans(rs)
+	return nil
+}
+
+func TestTraceClient(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(prev) })
+
+	next := &fakeTraceClient{down: true}
+	c, err := NewTraceClient(next, Config{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewTraceClient() error = %v", err)
+	}
+
+	spans := []*tracepb.ResourceSpans{{
+		ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{Name: "a"}, {Name: "b"}}}},
+	}}
+	if err := c.UploadTraces(context.Background(), spans); err != nil {
+		t.Fatalf("UploadTraces() during outage error = %v, want spooled", err)
+	}
+
+	// Replay keeps batches while the collector is down
+	c.spooler.replay()
+	if n, _ := c.spooler.queue.stats(); n != 1 {
+		t.Fatalf("spooled batches = %d, want 1", n)
+	}
+
+	next.down = false
+	c.spooler.replay()
+	if n, _ := c.spooler.queue.stats(); n != 0 {
+		t.Errorf("spooled batches after recovery = %d, want 0", n)
+	}
+	if next.uploaded != 2 {
+		t.Errorf("uploaded spans = %d, want 2", next.uploaded)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	for _, name := range []string{"telemetry_spool_spooled_total", "telemetry_spool_replayed_total"} {
+		if got := counterValue(rm, name); got != 2 {
+			t.Errorf("%s = %d, want 2", name, got)
+		}
+	}
+}
+
+func TestTraceClientDropsCorruptBatches(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, signalTraces), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := entry{seq: 1, items: 3}
+	if err := os.WriteFile(filepath.Join(dir, signalTraces, corrupt.name()), []byte{0xff, 0xff}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewTraceClient(&fakeTraceClient{}, Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewTraceClient() error = %v", err)
+	}
+	c.spooler.replay()
+	if n, _ := c.spooler.queue.stats(); n != 0 {
+		t.Errorf("spooled batches = %d, want corrupt batch dropped", n)
+	}
+}
+
+type fakeLogExporter struct {
+	err      error
+	exported []sdklog.Record
+}
+
+func (e *fakeLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.exported = append(e.exported, records...)
+	return nil
+}
+
+func (e *fakeLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *fakeLogExporter) ForceFlush(context.Context) error { return nil }
+
+func TestLogExporter(t *testing.T) {
+	// Build records the way the SDK does
+	c := &recordCollector{}
+	res := resource.NewSchemaless(attribute.String("service.name", "orders"))
+	provider := sdklog.NewLoggerProvider(sdklog.WithResource(res), sdklog.WithProcessor(c))
+	logger := provider.Logger("orders", log.WithInstrumentationVersion("v1.2.0"))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	var r log.Record
+	r.SetSeverity(log.SeverityError)
+	r.SetBody(log.StringValue("payment failed"))
+	r.AddAttributes(
+		log.Int("attempt", 3),
+		log.Map("order", log.String("id", "o-1"), log.Slice("items", log.StringValue("sku-1"))),
+	)
+	logger.Emit(trace.ContextWithSpanContext(context.Background(), sc), r)
+
+	next := &fakeLogExporter{err: errUnavailable}
+	exp, err := NewLogExporter(next, Config{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewLogExporter() error = %v", err)
+	}
+	t.Cleanup(func() { _ = exp.Shutdown(context.Background()) })
+
+	if err := exp.Export(context.Background(), c.records); err != nil {
+		t.Fatalf("Export() during outage error = %v, want spooled", err)
+	}
+
+	next.err = nil
+	exp.spooler.replay()
+	if len(next.exported) != 1 {
+		t.Fatalf("replayed %d records, want 1", len(next.exported))
+	}
+
+	got, want := next.exported[0], c.records[0]
+	if !got.Timestamp().Equal(want.Timestamp()) || !got.ObservedTimestamp().Equal(want.ObservedTimestamp()) {
+		t.Errorf("timestamps = %v, %v, want %v, %v", got.Timestamp(), got.ObservedTimestamp(), want.Timestamp(), want.ObservedTimestamp())
+	}
+	if got.Severity() != log.SeverityError || !got.Body().Equal(want.Body()) {
+		t.Errorf("severity, body = %v, %v, want %v, %v", got.Severity(), got.Body(), log.SeverityError, want.Body())
+	}
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || got.TraceFlags() != sc.TraceFlags() {
+		t.Errorf("trace context = %s/%s/%s, want %s/%s/%s", got.TraceID(), got.SpanID(), got.TraceFlags(), sc.TraceID(), sc.SpanID(), sc.TraceFlags())
+	}
+	if got.AttributesLen() != 2 {
+		t.Errorf("attributes = %d, want 2", got.AttributesLen())
+	}
+	got.WalkAttributes(func(kv log.KeyValue) bool {
+		want.WalkAttributes(func(w log.KeyValue) bool {
+			if w.Key == kv.Key && !w.Equal(kv) {
+				t.Errorf("attribute %s = %v, want %v", kv.Key, kv.Value, w.Value)
+			}
+			return true
+		})
+		return true
+	})
+	if v, ok := got.Resource().Set().Value("service.name"); !ok || v.AsString() != "orders" {
+		t.Errorf("resource service.name = %v, want orders", v)
+	}
+	if scope := got.InstrumentationScope(); scope.Name != "orders" || scope.Version != "v1.2.0" {
+		t.Errorf("scope = %+v, want orders v1.2.0", scope)
+	}
+}
+
+func counterValue(rm metricdata.ResourceMetrics, name string) int64 {
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// TraceClient is an OTLP trace client that spools batches the collector didn't accept
+// and replays them in the background. Batches are stored as OTLP TracesData protobuf.
+type TraceClient struct {
+	next    otlptrace.Client
+	spooler *spooler
+}
+
+var _ otlptrace.Client = (*TraceClient)(nil)
+
+// NewTraceClient wraps an OTLP trace client, e.g. otlptracegrpc.NewClient, with a disk spool.
+// Disable the retries of the wrapped client so failed batches are spooled right away
+// instead of blocking the batch span processor.
+func NewTraceClient(next otlptrace.Client, cfg Config) (*TraceClient, error) {
+	c := &TraceClient{next: next}
+
+	s, err := newSpooler(signalTraces, cfg, c.replay)
+	if err != nil {
+		return nil, err
+	}
+	c.spooler = s
+	return c, nil
+}
+
+// Start starts the wrapped client and the replay of spooled batches
+func (c *TraceClient) Start(ctx context.Context) error {
+	if err := c.next.Start(ctx); err != nil {
+		return err
+	}
+	c.spooler.start()
+	return nil
+}
+
+// Stop stops the replay and the wrapped client. Spooled batches stay on disk.
+func (c *TraceClient) Stop(ctx context.Context) error {
+	c.spooler.stop()
+	return c.next.Stop(ctx)
+}
+
+// UploadTraces uploads spans with the wrapped client and spools them if the upload fails
+func (c *TraceClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	err := c.next.UploadTraces(ctx, protoSpans)
+	if err == nil {
+		return nil
+	}
+
+	data, mErr := proto.Marshal(&tracepb.TracesData{ResourceSpans: protoSpans})
+	if mErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to encode spans: %w", mErr))
+	}
+	if sErr := c.spooler.store(ctx, data, countSpans(protoSpans)); sErr != nil {
+		return errors.Join(err, sErr)
+	}
+	return nil
+}
+
+func (c *TraceClient) replay(ctx context.Context, data []byte) error {
+	var traces tracepb.TracesData
+	if err := proto.Unmarshal(data, &traces); err != nil {
+		return fmt.Errorf("%w: %w", errCorrupt, err)
+	}
+	return c.next.UploadTraces(ctx, traces.ResourceSpans)
+}
+
+func countSpans(resourceSpans []*tracepb.ResourceSpans) int {
+	n := 0
+	for _, rs := range resourceSpans {
+		for _, ss := range rs.GetScopeSpans() {
+			n += len(ss.GetSpans())
+		}
+	}
+	return n
+}
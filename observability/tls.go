@@ -0,0 +1,54 @@
+package observability
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LoadTLSConfig builds a TLS config for OTLP exporters from PEM files.
+// caFile adds a CA bundle to verify the collector; certFile and keyFile set a
+// client certificate for mutual TLS. Empty paths are skipped, so LoadTLSConfig("", "", "")
+// returns a config using the system roots.
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("both client certificate and key files are required for mutual TLS")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// otlpTLSConfig returns the TLS config for exporters: the explicit config if set,
+// otherwise one loaded from the configured files, or nil to use OTLPInsecure defaults
+func (c Config) otlpTLSConfig() (*tls.Config, error) {
+	if c.OTLPTLSConfig != nil {
+		return c.OTLPTLSConfig, nil
+	}
+	if c.OTLPCAFile == "" && c.OTLPCertFile == "" && c.OTLPKeyFile == "" {
+		return nil, nil
+	}
+	return LoadTLSConfig(c.OTLPCAFile, c.OTLPCertFile, c.OTLPKeyFile)
+}
@@ -0,0 +1,66 @@
+package observability
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	invalidCA := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(invalidCA, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		ca, cert, key   string
+		expectedErr     bool
+		expectedRootCAs bool
+	}{
+		{name: "System roots without files"},
+		{name: "Missing CA file", ca: filepath.Join(dir, "missing.pem"), expectedErr: true},
+		{name: "CA file without certificates", ca: invalidCA, expectedErr: true},
+		{name: "Client certificate without key", cert: invalidCA, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadTLSConfig(tt.ca, tt.cert, tt.key)
+			if tt.expectedErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (cfg.RootCAs != nil) != tt.expectedRootCAs {
+				t.Errorf("unexpected RootCAs %v", cfg.RootCAs)
+			}
+		})
+	}
+}
+
+func TestConfigOTLPTLSConfig(t *testing.T) {
+	cfg, err := NewConfig(ConfigParams{
+		Env:               EnvProd,
+		ServiceName:       "api",
+		ServiceVersion:    "1.0.0",
+		OTLPEndpoint:      "otlp.example.com:443",
+		OTLPTransportType: "grpc",
+	}, WithOTLPHeaders(map[string]string{"authorization": "Basic abc"}))
+	if err != nil {
+		t.Fatalf("unexpected config error: %v", err)
+	}
+
+	tlsCfg, err := cfg.otlpTLSConfig()
+	if err != nil || tlsCfg != nil {
+		t.Errorf("expected no TLS config without files, got %v, %v", tlsCfg, err)
+	}
+	if cfg.OTLPHeaders["authorization"] != "Basic abc" {
+		t.Errorf("expected headers to be set, got %v", cfg.OTLPHeaders)
+	}
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      BatchConfig
+		wantOpts int
+		wantErr  string
+	}{
+		{name: "zero values keep the SDK defaults", cfg: BatchConfig{}, wantOpts: 0},
+		{
+			name: "all settings",
+			cfg: BatchConfig{
+				MaxQueueSize:       16384,
+				BatchTimeout:       2 * time.Second,
+				ExportTimeout:      10 * time.Second,
+				MaxExportBatchSize: 2048,
+			},
+			wantOpts: 4,
+		},
+		{name: "batch size within the default queue", cfg: BatchConfig{MaxExportBatchSize: defaultBatchQueueSize}, wantOpts: 1},
+		{name: "batch size above the default queue", cfg: BatchConfig{MaxExportBatchSize: defaultBatchQueueSize + 1}, wantErr: "exceeds max queue size 2048"},
+		{name: "batch size above the queue", cfg: BatchConfig{MaxQueueSize: 100, MaxExportBatchSize: 200}, wantErr: "exceeds max queue size 100"},
+		{name: "negative queue size", cfg: BatchConfig{MaxQueueSize: -1}, wantErr: "must not be negative"},
+		{name: "negative timeout", cfg: BatchConfig{BatchTimeout: -time.Second}, wantErr: "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := len(tt.cfg.options()); got != tt.wantOpts {
+				t.Errorf("expected %d processor options, got %d", tt.wantOpts, got)
+			}
+		})
+	}
+}
+
+func TestInitRejectsInvalidBatchConfig(t *testing.T) {
+	_, err := Init(context.Background(), Config{
+		ServiceName:  "test-service",
+		ExporterType: ExporterStdout,
+		Batch:        BatchConfig{MaxQueueSize: 10, MaxExportBatchSize: 20},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid span batch config") {
+		t.Fatalf("expected invalid batch config error, got %v", err)
+	}
+}
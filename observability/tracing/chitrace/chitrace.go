@@ -0,0 +1,39 @@
+// Package chitrace provides tracing middleware for the chi router
+// that names spans after route templates, e.g. "GET /users/{id}"
+package chitrace
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rshelekhov/golib/observability/tracing"
+)
+
+// Middleware traces requests handled by a chi router:
+//
+//	r := chi.NewRouter()
+//	r.Use(chitrace.Middleware())
+//
+// The route is known only after routing, so the span is renamed when the handler returns.
+func Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracing.StartHTTPServerSpan(r, "")
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				if rctx := chi.RouteContext(ctx); rctx != nil {
+					tracing.SetRoute(ctx, r.Method, rctx.RoutePattern())
+				}
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				tracing.EndHTTPServerSpan(span, status)
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
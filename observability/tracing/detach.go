@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type originKey struct{}
+
+// Detach returns a context for background work that outlives the request, e.g. a fire-and-forget goroutine.
+// It keeps the values of ctx (baggage, logger settings, force sampling) but drops its cancellation,
+// deadline and current span, so spans started from it begin a new trace instead of extending the request one.
+// The request span is remembered for Link:
+//
+//	go func(ctx context.Context) {
+//		ctx, span := tracing.StartSpan(ctx, "send_receipt", tracing.Link(ctx))
+//		defer span.End()
+//		...
+//	}(tracing.Detach(ctx))
+func Detach(ctx context.Context) context.Context {
+	origin := trace.SpanContextFromContext(ctx)
+	ctx = context.WithoutCancel(ctx)
+	ctx = trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+	if origin.IsValid() {
+		ctx = context.WithValue(ctx, originKey{}, origin)
+	}
+	return ctx
+}
+
+// Link returns a span start option linking the new span to the originating span:
+// the request span of a context returned by Detach, or the current span of ctx otherwise,
+// e.g. a span context extracted from queue message headers.
+// Without such a span it adds no link.
+func Link(ctx context.Context, attrs ...Attribute) SpanStartOption {
+	origin, ok := ctx.Value(originKey{}).(trace.SpanContext)
+	if !ok {
+		origin = trace.SpanContextFromContext(ctx)
+	}
+	if !origin.IsValid() {
+		return trace.WithLinks()
+	}
+	return trace.WithLinks(trace.Link{SpanContext: origin, Attributes: attrs})
+}
+
+// StartDetached detaches ctx (see Detach) and starts a new root span linked to the originating span
+func StartDetached(ctx context.Context, name string, opts ...SpanStartOption) (context.Context, trace.Span) {
+	detached := Detach(ctx)
+	opts = append(opts, trace.WithNewRoot(), Link(detached))
+	return StartSpan(detached, name, opts...)
+}
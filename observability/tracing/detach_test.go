@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartDetached(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	reqCtx, reqSpan := tp.Tracer("test").Start(reqCtx, "request")
+
+	ctx, span := StartDetached(reqCtx, "background")
+	cancel()
+	reqSpan.End()
+	span.End()
+
+	if ctx.Err() != nil {
+		t.Error("expected detached context to outlive request cancellation")
+	}
+
+	bg := exporter.GetSpans()[1]
+	if bg.Parent.IsValid() {
+		t.Error("expected background span to be a root span")
+	}
+	if bg.SpanContext.TraceID() == reqSpan.SpanContext().TraceID() {
+		t.Error("expected background span in a new trace")
+	}
+	if len(bg.Links) != 1 || bg.Links[0].SpanContext.SpanID() != reqSpan.SpanContext().SpanID() {
+		t.Errorf("expected link to request span, got %+v", bg.Links)
+	}
+}
+
+func TestLinkWithoutSpan(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	_, span := tp.Tracer("test").Start(context.Background(), "job", Link(context.Background()))
+	defer span.End()
+
+	if links := span.(sdktrace.ReadOnlySpan).Links(); len(links) != 0 {
+		t.Errorf("expected no links, got %d", len(links))
+	}
+}
@@ -0,0 +1,34 @@
+// Package echotrace provides tracing middleware for the echo framework
+// that names spans after route templates, e.g. "GET /users/:id"
+package echotrace
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/rshelekhov/golib/observability/tracing"
+)
+
+// Middleware traces requests handled by an echo server:
+//
+//	e := echo.New()
+//	e.Use(echotrace.Middleware())
+//
+// Errors returned by handlers are recorded on the span and passed to the echo error handler,
+// so the span gets the final response status.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, span := tracing.StartHTTPServerSpan(c.Request(), c.Path())
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				span.RecordError(err)
+				// Write the error response now to know its status; the error handler skips committed responses
+				c.Error(err)
+			}
+
+			tracing.EndHTTPServerSpan(span, c.Response().Status)
+			return err
+		}
+	}
+}
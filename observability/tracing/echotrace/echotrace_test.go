@@ -0,0 +1,49 @@
+package echotrace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/users/:id", func(echo.Context) error {
+		return echo.ErrNotFound
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", rec.Code)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name != "GET /users/:id" {
+		t.Errorf("expected span named after route template, got %q", spans[0].Name)
+	}
+
+	var status attribute.Value
+	for _, kv := range spans[0].Attributes {
+		if kv.Key == "http.response.status_code" {
+			status = kv.Value
+		}
+	}
+	if status.AsInt64() != http.StatusNotFound {
+		t.Errorf("expected status code attribute 404, got %v", status.Emit())
+	}
+}
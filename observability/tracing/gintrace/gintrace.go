@@ -0,0 +1,30 @@
+// Package gintrace provides tracing middleware for the gin framework
+// that names spans after route templates, e.g. "GET /users/:id"
+package gintrace
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rshelekhov/golib/observability/tracing"
+)
+
+// Middleware traces requests handled by a gin engine:
+//
+//	r := gin.New()
+//	r.Use(gintrace.Middleware())
+//
+// Errors added with c.Error are recorded on the span.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartHTTPServerSpan(c.Request, c.FullPath())
+		c.Request = c.Request.WithContext(ctx)
+
+		defer func() {
+			for _, err := range c.Errors {
+				span.RecordError(err.Err)
+			}
+			tracing.EndHTTPServerSpan(span, c.Writer.Status())
+		}()
+
+		c.Next()
+	}
+}
@@ -2,16 +2,21 @@ package tracing
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/rshelekhov/golib/observability/spool"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc/credentials"
 )
 
 type ExporterType string
@@ -36,43 +41,151 @@ type Config struct {
 	OTLPEndpoint      string            // Used only when ExporterType is ExporterOTLP
 	OTLPTransportType OTLPTransportType // "grpc" or "http", used only when ExporterType is ExporterOTLP
 	OTLPInsecure      bool              // If true, uses insecure OTLP connection
+	OTLPHeaders       map[string]string // Headers sent with every export request, e.g. collector auth
+	OTLPTLSConfig     *tls.Config       // Custom TLS config (CA bundle, client certificate). Takes precedence over OTLPInsecure
+
+	// Resource overrides the resource built from service name, version and environment
+	Resource *resource.Resource
+
+	// Batch tunes the batch span processor
+	Batch BatchConfig
+
+	// Propagators sets the context propagation formats, e.g. []string{"w3c", "b3multi"}
+	// for services behind Istio/Envoy or talking to Zipkin-instrumented services.
+	// Defaults to DefaultPropagators.
+	Propagators []string
+
+	// Redaction, if set, strips or hashes sensitive span attributes before export
+	Redaction *RedactionConfig
+
+	// Sampler decides which traces are recorded. Defaults to ParentBased(AlwaysSample).
+	// It is wrapped with NewForceSampler, so force-sampled requests are always kept.
+	Sampler sdktrace.Sampler
+
+	// Spool, if set, buffers spans on disk while the OTLP collector is unreachable.
+	// Used only when ExporterType is ExporterOTLP.
+	Spool *spool.Config
+}
+
+// BatchConfig configures the batch span processor. Zero values keep the SDK defaults
+// (queue of 2048 spans, 5s batch timeout, 30s export timeout, batches of 512 spans).
+type BatchConfig struct {
+	// MaxQueueSize is the maximum number of spans buffered before new ones are dropped
+	MaxQueueSize int
+	// BatchTimeout is the maximum delay before a batch is exported
+	BatchTimeout time.Duration
+	// ExportTimeout is the maximum duration of a single export
+	ExportTimeout time.Duration
+	// MaxExportBatchSize is the maximum number of spans in one export. Must not exceed MaxQueueSize.
+	MaxExportBatchSize int
+}
+
+// defaultBatchQueueSize is the queue size of the SDK batch span processor
+const defaultBatchQueueSize = 2048
+
+// Validate reports negative settings and batches larger than the queue
+func (c BatchConfig) Validate() error {
+	if c.MaxQueueSize < 0 || c.BatchTimeout < 0 || c.ExportTimeout < 0 || c.MaxExportBatchSize < 0 {
+		return errors.New("batch settings must not be negative")
+	}
+	queueSize := c.MaxQueueSize
+	if queueSize == 0 {
+		queueSize = defaultBatchQueueSize
+	}
+	if c.MaxExportBatchSize > queueSize {
+		return fmt.Errorf("max export batch size %d exceeds max queue size %d", c.MaxExportBatchSize, queueSize)
+	}
+	return nil
+}
+
+func (c BatchConfig) options() []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if c.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(c.MaxQueueSize))
+	}
+	if c.BatchTimeout > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(c.BatchTimeout))
+	}
+	if c.ExportTimeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(c.ExportTimeout))
+	}
+	if c.MaxExportBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(c.MaxExportBatchSize))
+	}
+	return opts
 }
 
 // Init initializes OpenTelemetry TracerProvider
 func Init(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	if err := cfg.Batch.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid span batch config: %w", err)
+	}
+
+	propagator, err := NewPropagator(cfg.Propagators...)
+	if err != nil {
+		return nil, err
+	}
+
 	var exporter sdktrace.SpanExporter
-	var err error
 
 	switch cfg.ExporterType {
 	case ExporterOTLP:
+		var client otlptrace.Client
+
 		switch cfg.OTLPTransportType {
 		case OTLPTransportHTTP:
 			opts := []otlptracehttp.Option{
 				otlptracehttp.WithEndpoint(cfg.OTLPEndpoint),
 			}
-			if cfg.OTLPInsecure {
+			switch {
+			case cfg.OTLPTLSConfig != nil:
+				opts = append(opts, otlptracehttp.WithTLSClientConfig(cfg.OTLPTLSConfig))
+			case cfg.OTLPInsecure:
 				opts = append(opts, otlptracehttp.WithInsecure())
 			}
-
-			exporter, err = otlptracehttp.New(ctx, opts...)
-			if err != nil {
-				return nil, fmt.Errorf("create otlp http exporter: %w", err)
+			if len(cfg.OTLPHeaders) > 0 {
+				opts = append(opts, otlptracehttp.WithHeaders(cfg.OTLPHeaders))
+			}
+			if cfg.Spool != nil {
+				// Failed batches are spooled and replayed instead of retried in memory
+				opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}))
 			}
+
+			client = otlptracehttp.NewClient(opts...)
 		case OTLPTransportGRPC:
 			opts := []otlptracegrpc.Option{
 				otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
 			}
-			if cfg.OTLPInsecure {
+			switch {
+			case cfg.OTLPTLSConfig != nil:
+				opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(cfg.OTLPTLSConfig)))
+			case cfg.OTLPInsecure:
 				opts = append(opts, otlptracegrpc.WithInsecure())
 			}
-
-			exporter, err = otlptracegrpc.New(ctx, opts...)
-			if err != nil {
-				return nil, fmt.Errorf("create otlp grpc exporter: %w", err)
+			if len(cfg.OTLPHeaders) > 0 {
+				opts = append(opts, otlptracegrpc.WithHeaders(cfg.OTLPHeaders))
 			}
+			if cfg.Spool != nil {
+				// Failed batches are spooled and replayed instead of retried in memory
+				opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{Enabled: false}))
+			}
+
+			client = otlptracegrpc.NewClient(opts...)
 		default:
 			return nil, fmt.Errorf("invalid otlp transport type: %s", cfg.OTLPTransportType)
 		}
+
+		if cfg.Spool != nil {
+			client, err = spool.NewTraceClient(client, *cfg.Spool)
+			if err != nil {
+				return nil, fmt.Errorf("create span spool: %w", err)
+			}
+		}
+
+		exporter, err = otlptrace.New(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp %s exporter: %w", cfg.OTLPTransportType, err)
+		}
 	default:
 		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
 		if err != nil {
@@ -81,27 +194,33 @@ func Init(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
 	}
 
 	// Create resource
-	res := resource.NewWithAttributes(
-		resource.Default().SchemaURL(),
-		semconv.ServiceName(cfg.ServiceName),
-		semconv.ServiceVersion(cfg.ServiceVersion),
-		semconv.DeploymentEnvironment(cfg.Env),
-	)
+	res := cfg.Resource
+	if res == nil {
+		res = resource.NewWithAttributes(
+			resource.Default().SchemaURL(),
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			semconv.DeploymentEnvironment(cfg.Env),
+		)
+	}
 
 	// Create TracerProvider
+	processor := sdktrace.NewBatchSpanProcessor(exporter, cfg.Batch.options()...)
+	if cfg.Redaction != nil {
+		processor = NewRedactingProcessor(processor, *cfg.Redaction)
+	}
+
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSpanProcessor(processor),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(NewForceSampler(cfg.Sampler)),
 	)
 
 	// Set global TracerProvider
 	otel.SetTracerProvider(tp)
 
 	// Set global TextMapPropagator
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
+	otel.SetTextMapPropagator(propagator)
 
 	return tp, nil
 }
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Propagator names accepted in Config.Propagators
+const (
+	PropagatorW3C          = "w3c"          // W3C Trace Context and Baggage
+	PropagatorTraceContext = "tracecontext" // W3C Trace Context only
+	PropagatorBaggage      = "baggage"      // W3C Baggage only
+	PropagatorB3Single     = "b3single"     // Zipkin B3 single header (Istio, Envoy)
+	PropagatorB3Multi      = "b3multi"      // Zipkin B3 multiple X-B3-* headers
+	PropagatorJaeger       = "jaeger"       // Jaeger uber-trace-id header
+)
+
+// DefaultPropagators are used when Config.Propagators is empty
+var DefaultPropagators = []string{PropagatorW3C}
+
+// NewPropagator returns a composite propagator for the given names.
+// Context is injected in all formats and extracted from the first one present.
+// "b3" is accepted as an alias of "b3single", as in OTEL_PROPAGATORS.
+func NewPropagator(names ...string) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case PropagatorW3C:
+			propagators = append(propagators, propagation.TraceContext{}, propagation.Baggage{})
+		case PropagatorTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case PropagatorBaggage:
+			propagators = append(propagators, propagation.Baggage{})
+		case PropagatorB3Single, "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case PropagatorB3Multi:
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case PropagatorJaeger:
+			propagators = append(propagators, jaeger.Jaeger{})
+		default:
+			return nil, fmt.Errorf("unsupported propagator: %s", name)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
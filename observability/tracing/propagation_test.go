@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewPropagator(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	tests := []struct {
+		names   []string
+		headers []string
+	}{
+		{names: nil, headers: []string{"traceparent"}},
+		{names: []string{PropagatorB3Single}, headers: []string{"b3"}},
+		{names: []string{PropagatorB3Multi}, headers: []string{"x-b3-traceid", "x-b3-spanid"}},
+		{names: []string{PropagatorJaeger}, headers: []string{"uber-trace-id"}},
+		{names: []string{PropagatorW3C, PropagatorB3Multi}, headers: []string{"traceparent", "x-b3-traceid"}},
+	}
+
+	for _, tt := range tests {
+		p, err := NewPropagator(tt.names...)
+		if err != nil {
+			t.Fatalf("NewPropagator(%v) failed: %v", tt.names, err)
+		}
+
+		carrier := propagation.MapCarrier{}
+		p.Inject(ctx, carrier)
+		for _, h := range tt.headers {
+			if carrier.Get(h) == "" {
+				t.Errorf("NewPropagator(%v): expected %s header, got %v", tt.names, h, carrier)
+			}
+		}
+
+		extracted := trace.SpanContextFromContext(p.Extract(context.Background(), carrier))
+		if extracted.TraceID() != sc.TraceID() {
+			t.Errorf("NewPropagator(%v): expected trace ID %s, got %s", tt.names, sc.TraceID(), extracted.TraceID())
+		}
+	}
+
+	if _, err := NewPropagator("xray"); err == nil {
+		t.Error("expected error for unsupported propagator")
+	}
+}
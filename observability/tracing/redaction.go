@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactAction defines how a matched attribute is redacted
+type RedactAction int
+
+const (
+	// RedactDrop removes the attribute
+	RedactDrop RedactAction = iota
+	// RedactHash replaces the value with its SHA-256 hex digest, so equal values stay correlatable
+	RedactHash
+)
+
+// RedactionRule redacts attributes with the given keys
+type RedactionRule struct {
+	Keys   []string
+	Action RedactAction
+}
+
+// RedactionConfig configures the redacting span processor
+type RedactionConfig struct {
+	Rules []RedactionRule
+	// StripURLQuery removes query strings from url.full, http.url and http.target values and drops url.query
+	StripURLQuery bool
+	// HashKey, if set, makes RedactHash use HMAC-SHA256, so short values can't be recovered by brute force
+	HashKey []byte
+}
+
+// DefaultRedactionConfig hashes database statements and strips URL query strings
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Rules: []RedactionRule{
+			{Keys: []string{"db.statement", "db.query.text"}, Action: RedactHash},
+		},
+		StripURLQuery: true,
+	}
+}
+
+// urlKeys hold full URLs or request targets with query strings
+var urlKeys = map[attribute.Key]struct{}{
+	"url.full":    {},
+	"http.url":    {},
+	"http.target": {},
+}
+
+// RedactingProcessor redacts attributes of ended spans and their events
+// before passing them to the next processor, e.g. the batch processor of an exporter
+type RedactingProcessor struct {
+	next          sdktrace.SpanProcessor
+	actions       map[attribute.Key]RedactAction
+	stripURLQuery bool
+	hashKey       []byte
+}
+
+var _ sdktrace.SpanProcessor = (*RedactingProcessor)(nil)
+
+// NewRedactingProcessor wraps next with attribute redaction
+func NewRedactingProcessor(next sdktrace.SpanProcessor, cfg RedactionConfig) *RedactingProcessor {
+	p := &RedactingProcessor{
+		next:          next,
+		actions:       make(map[attribute.Key]RedactAction),
+		stripURLQuery: cfg.StripURLQuery,
+		hashKey:       cfg.HashKey,
+	}
+	for _, rule := range cfg.Rules {
+		for _, k := range rule.Keys {
+			p.actions[attribute.Key(k)] = rule.Action
+		}
+	}
+	return p
+}
+
+func (p *RedactingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *RedactingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.next.OnEnd(&redactedSpan{ReadOnlySpan: s, processor: p})
+}
+
+func (p *RedactingProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *RedactingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// redact returns the attributes with rules applied. The input slice is not modified.
+func (p *RedactingProcessor) redact(attrs []attribute.KeyValue) []attribute.KeyValue {
+	redacted := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if action, ok := p.actions[kv.Key]; ok {
+			if action == RedactHash {
+				redacted = append(redacted, kv.Key.String(p.hash(kv.Value.Emit())))
+			}
+			continue
+		}
+
+		if p.stripURLQuery {
+			if kv.Key == "url.query" {
+				continue
+			}
+			if _, ok := urlKeys[kv.Key]; ok && kv.Value.Type() == attribute.STRING {
+				kv = kv.Key.String(stripQuery(kv.Value.AsString()))
+			}
+		}
+
+		redacted = append(redacted, kv)
+	}
+	return redacted
+}
+
+func (p *RedactingProcessor) hash(value string) string {
+	if len(p.hashKey) > 0 {
+		mac := hmac.New(sha256.New, p.hashKey)
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func stripQuery(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
+
+// redactedSpan overrides attributes of a finished span
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	processor *RedactingProcessor
+}
+
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return s.processor.redact(s.ReadOnlySpan.Attributes())
+}
+
+func (s *redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, len(events))
+	for i, e := range events {
+		e.Attributes = s.processor.redact(e.Attributes)
+		redacted[i] = e
+	}
+	return redacted
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRedactingProcessor(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := DefaultRedactionConfig()
+	cfg.Rules = append(cfg.Rules, RedactionRule{Keys: []string{"user.email"}, Action: RedactDrop})
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+		NewRedactingProcessor(sdktrace.NewSimpleSpanProcessor(exporter), cfg),
+	))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "query")
+	span.SetAttributes(
+		attribute.String("db.statement", "SELECT * FROM users WHERE email = 'john@example.com'"),
+		attribute.String("url.full", "https://api.example.com/users?token=secret"),
+		attribute.String("url.query", "token=secret"),
+		attribute.String("user.email", "john@example.com"),
+		attribute.String("user.id", "42"),
+	)
+	span.AddEvent("retry", trace.WithAttributes(attribute.String("user.email", "john@example.com")))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := map[attribute.Key]string{}
+	for _, kv := range spans[0].Attributes {
+		attrs[kv.Key] = kv.Value.AsString()
+	}
+
+	sum := sha256.Sum256([]byte("SELECT * FROM users WHERE email = 'john@example.com'"))
+	expected := map[attribute.Key]string{
+		"db.statement": hex.EncodeToString(sum[:]),
+		"url.full":     "https://api.example.com/users",
+		"user.id":      "42",
+	}
+	for key, want := range expected {
+		if attrs[key] != want {
+			t.Errorf("expected %s=%q, got %q", key, want, attrs[key])
+		}
+	}
+	for _, key := range []attribute.Key{"url.query", "user.email"} {
+		if _, ok := attrs[key]; ok {
+			t.Errorf("expected %s to be dropped", key)
+		}
+	}
+	if len(spans[0].Events[0].Attributes) != 0 {
+		t.Errorf("expected event attributes to be redacted, got %v", spans[0].Events[0].Attributes)
+	}
+}
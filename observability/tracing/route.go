@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rshelekhov/golib/observability/metrics"
+	"github.com/rshelekhov/golib/observability/scope"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartHTTPServerSpan starts a server span for the request, continuing the remote trace
+// found in its headers. The span is named "METHOD route", or "METHOD" when the route
+// is not known before routing; call SetRoute once it is matched.
+// Router adapters use it instead of HTTPMiddleware to name spans after route templates.
+func StartHTTPServerSpan(r *http.Request, route string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	name := r.Method
+	attrs := []Attribute{
+		semconv.HTTPRequestMethodKey.String(r.Method),
+		semconv.URLPath(r.URL.Path),
+	}
+	if route != "" {
+		name += " " + route
+		attrs = append(attrs, semconv.HTTPRoute(route))
+	}
+
+	ctx, span := scope.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+	metrics.SetRoute(ctx, route)
+	return ctx, span
+}
+
+// SetRoute names the server span in ctx after the matched route template, e.g. "GET /users/{id}",
+// and reports the route to metrics.Middleware
+func SetRoute(ctx context.Context, method, route string) {
+	if route == "" {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetName(method + " " + route)
+	span.SetAttributes(semconv.HTTPRoute(route))
+	metrics.SetRoute(ctx, route)
+}
+
+// EndHTTPServerSpan records the response status and ends the span.
+// 5xx responses set the span status to codes.Error.
+func EndHTTPServerSpan(span trace.Span, status int) {
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type forceSampleKey struct{}
+
+// ContextWithForceSample marks ctx so that spans started from it are sampled
+// regardless of the configured sampler and the parent's sampling decision.
+// It takes effect only with a ForceSampler, which Init always installs.
+func ContextWithForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+// IsForceSampled reports whether ctx was marked with ContextWithForceSample
+func IsForceSampled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	forced, _ := ctx.Value(forceSampleKey{}).(bool)
+	return forced
+}
+
+// NewForceSampler returns a sampler that samples spans started from a context marked with
+// ContextWithForceSample and delegates every other decision to base.
+// A nil base defaults to ParentBased(AlwaysSample).
+func NewForceSampler(base sdktrace.Sampler) sdktrace.Sampler {
+	if base == nil {
+		base = sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+	return forceSampler{base: base}
+}
+
+type forceSampler struct {
+	base sdktrace.Sampler
+}
+
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if IsForceSampled(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s forceSampler) Description() string {
+	return "ForceSampler{" + s.base.Description() + "}"
+}
@@ -2,8 +2,9 @@ package tracing
 
 import (
 	"context"
+	"sync/atomic"
 
-	"go.opentelemetry.io/otel"
+	"github.com/rshelekhov/golib/observability/scope"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -24,13 +25,13 @@ const tracerName = "github.com/rshelekhov/golib/observability/tracing"
 
 // StartSpan creates a span with an arbitrary name
 func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	tracer := otel.Tracer(tracerName)
+	tracer := scope.Tracer(tracerName)
 	return tracer.Start(ctx, name, opts...)
 }
 
 // SpanFromHTTP creates a span for an HTTP request
 func SpanFromHTTP(ctx context.Context, method, path string) (context.Context, trace.Span) {
-	tracer := otel.Tracer(tracerName)
+	tracer := scope.Tracer(tracerName)
 	return tracer.Start(ctx, method+" "+path,
 		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(
@@ -42,7 +43,7 @@ func SpanFromHTTP(ctx context.Context, method, path string) (context.Context, tr
 
 // SpanFromGRPC creates a span for a gRPC method
 func SpanFromGRPC(ctx context.Context, method string) (context.Context, trace.Span) {
-	tracer := otel.Tracer(tracerName)
+	tracer := scope.Tracer(tracerName)
 	return tracer.Start(ctx, method,
 		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(
@@ -54,13 +55,27 @@ func SpanFromGRPC(ctx context.Context, method string) (context.Context, trace.Sp
 
 // OutgoingSpan creates a span for outgoing calls (DB, external, etc)
 func OutgoingSpan(ctx context.Context, name string, spanKind SpanKind, attrs ...Attribute) (context.Context, trace.Span) {
-	tracer := otel.Tracer(tracerName)
+	tracer := scope.Tracer(tracerName)
 	return tracer.Start(ctx, name,
 		trace.WithSpanKind(spanKind),
 		trace.WithAttributes(attrs...),
 	)
 }
 
+// ErrorHook is called by RecordError for every recorded error, e.g. to forward it to an error tracker
+type ErrorHook func(span trace.Span, err error)
+
+var errorHook atomic.Pointer[ErrorHook]
+
+// SetErrorHook sets the hook called by RecordError. A nil hook removes it.
+func SetErrorHook(hook ErrorHook) {
+	if hook == nil {
+		errorHook.Store(nil)
+		return
+	}
+	errorHook.Store(&hook)
+}
+
 // RecordError records the provided error on the span and sets the span status to codes.Error.
 // It is safe to call with nil span or error.
 func RecordError(span trace.Span, err error) {
@@ -70,6 +85,10 @@ func RecordError(span trace.Span, err error) {
 
 	span.RecordError(err)
 	span.SetStatus(codes.Error, err.Error())
+
+	if hook := errorHook.Load(); hook != nil {
+		(*hook)(span, err)
+	}
 }
 
 // EndSpanOnError records the error on the span and ends the span.
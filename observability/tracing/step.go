@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys of step events
+const (
+	StepDurationKey = attribute.Key("step.duration_ms")
+	StepErrorKey    = attribute.Key("step.error")
+)
+
+// Step marks a business phase inside the current span without creating a child span.
+// The returned function records a span event named after the step, timestamped at
+// the step start, with its duration in milliseconds:
+//
+//	defer tracing.Step(ctx, "validate_order")()
+func Step(ctx context.Context, name string, attrs ...Attribute) func() {
+	start := time.Now()
+	return func() {
+		recordStep(ctx, name, start, nil, attrs)
+	}
+}
+
+// Timed runs fn as a step of the current span (see Step) and returns its error.
+// A failed step records the error message in the step.error attribute;
+// the span status is left to the caller.
+func Timed(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...Attribute) error {
+	start := time.Now()
+	err := fn(ctx)
+	recordStep(ctx, name, start, err, attrs)
+	return err
+}
+
+func recordStep(ctx context.Context, name string, start time.Time, err error, attrs []Attribute) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	eventAttrs := make([]Attribute, 0, len(attrs)+2)
+	eventAttrs = append(eventAttrs, attrs...)
+	eventAttrs = append(eventAttrs, StepDurationKey.Float64(float64(time.Since(start).Microseconds())/1000))
+	if err != nil {
+		eventAttrs = append(eventAttrs, StepErrorKey.String(err.Error()))
+	}
+
+	span.AddEvent(name, trace.WithTimestamp(start), trace.WithAttributes(eventAttrs...))
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStep(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "checkout")
+
+	done := Step(ctx, "validate_order", String("order.id", "42"))
+	time.Sleep(2 * time.Millisecond)
+	done()
+
+	errPayment := errors.New("card declined")
+	err := Timed(ctx, "charge", func(context.Context) error { return errPayment })
+	if !errors.Is(err, errPayment) {
+		t.Errorf("expected Timed to return fn error, got %v", err)
+	}
+	span.End()
+
+	events := exporter.GetSpans()[0].Events
+	if len(events) != 2 {
+		t.Fatalf("expected 2 step events, got %d", len(events))
+	}
+
+	attrs := map[string]any{}
+	for _, kv := range events[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if events[0].Name != "validate_order" || attrs["order.id"] != "42" {
+		t.Errorf("unexpected step event %+v", events[0])
+	}
+	if d, _ := attrs[string(StepDurationKey)].(float64); d < 2 {
+		t.Errorf("expected duration of at least 2ms, got %v", attrs[string(StepDurationKey)])
+	}
+
+	var stepErr string
+	for _, kv := range events[1].Attributes {
+		if kv.Key == StepErrorKey {
+			stepErr = kv.Value.AsString()
+		}
+	}
+	if events[1].Name != "charge" || stepErr != "card declined" {
+		t.Errorf("unexpected failed step event %+v", events[1])
+	}
+}
@@ -0,0 +1,12 @@
+package privacy
+
+import "time"
+
+const (
+	// SubjectPlaceholder is replaced with the subject ID in key patterns and object prefixes
+	SubjectPlaceholder = "{subject}"
+	// DefaultStepTimeout is the default timeout of one handler
+	DefaultStepTimeout = 5 * time.Minute
+	// DefaultProgressTable is the default table of PostgresProgress
+	DefaultProgressTable = "privacy_erasures"
+)
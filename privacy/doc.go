@@ -0,0 +1,12 @@
+// Package privacy coordinates "right to be forgotten" requests.
+//
+// Modules owning personal data register erasure handlers in a Registry under a
+// name, e.g. "orders" or "avatars". For an erasure request, an Orchestrator runs
+// every handler with the subject ID and persists the outcome of each step in a
+// ProgressStore, so an interrupted or failed request resumes with the remaining
+// steps. Handlers must therefore be idempotent. Completed requests are reported
+// to an Auditor as the audit trail of the erasure.
+//
+// PostgresEraser, MongoEraser, RedisEraser and S3Eraser implement handlers for
+// the stores of the db packages.
+package privacy
@@ -0,0 +1,118 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PostgresEraser returns a handler running the statements in order, each with the subject ID as $1,
+// e.g. "DELETE FROM addresses WHERE user_id = $1"
+func PostgresEraser(db PostgresAPI, statements ...string) Handler {
+	return HandlerFunc(func(ctx context.Context, subjectID string) error {
+		for _, stmt := range statements {
+			if _, err := db.Exec(ctx, stmt, subjectID); err != nil {
+				return fmt.Errorf("failed to erase rows: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// MongoAPI is the subset of the mongo connection used by MongoEraser
+type MongoAPI interface {
+	DeleteMany(ctx context.Context, collection string, filter any, opts ...*mongooptions.DeleteOptions) (*mongo.DeleteResult, error)
+}
+
+// MongoEraser returns a handler deleting the documents of collection whose field equals the subject ID
+func MongoEraser(db MongoAPI, collection, field string) Handler {
+	return HandlerFunc(func(ctx context.Context, subjectID string) error {
+		if _, err := db.DeleteMany(ctx, collection, bson.D{{Key: field, Value: subjectID}}); err != nil {
+			return fmt.Errorf("failed to erase documents: %w", err)
+		}
+		return nil
+	})
+}
+
+// RedisEraser returns a handler deleting the keys matching the patterns, in which SubjectPlaceholder
+// is replaced with the subject ID, e.g. "session:{subject}" or "cart:{subject}:*". Patterns without
+// wildcards are deleted directly, the others are scanned; with a cluster client only one node is scanned.
+func RedisEraser(client redis.Cmdable, patterns ...string) Handler {
+	return HandlerFunc(func(ctx context.Context, subjectID string) error {
+		for _, pattern := range patterns {
+			// A pattern without the subject would erase the data of everyone
+			if !strings.Contains(pattern, SubjectPlaceholder) {
+				return fmt.Errorf("key pattern %q has no %s placeholder", pattern, SubjectPlaceholder)
+			}
+			if !strings.ContainsAny(pattern, "*?[") {
+				key := strings.ReplaceAll(pattern, SubjectPlaceholder, subjectID)
+				if err := client.Del(ctx, key).Err(); err != nil {
+					return fmt.Errorf("failed to erase key: %w", err)
+				}
+				continue
+			}
+			// Wildcards in the subject ID must not match the keys of other subjects
+			pattern = strings.ReplaceAll(pattern, SubjectPlaceholder, globEscaper.Replace(subjectID))
+
+			iter := client.Scan(ctx, 0, pattern, 0).Iterator()
+			var keys []string
+			for iter.Next(ctx) {
+				keys = append(keys, iter.Val())
+			}
+			if err := iter.Err(); err != nil {
+				return fmt.Errorf("failed to scan keys: %w", err)
+			}
+			if len(keys) > 0 {
+				if err := client.Del(ctx, keys...).Err(); err != nil {
+					return fmt.Errorf("failed to erase keys: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// globEscaper escapes the special characters of Redis glob patterns
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// S3API is the subset of the s3 connection used by S3Eraser
+type S3API interface {
+	ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+	DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+}
+
+// S3Eraser returns a handler deleting the objects of bucket under prefix, in which SubjectPlaceholder
+// is replaced with the subject ID, e.g. "avatars/{subject}/"
+func S3Eraser(client S3API, bucket, prefix string) Handler {
+	return HandlerFunc(func(ctx context.Context, subjectID string) error {
+		if !strings.Contains(prefix, SubjectPlaceholder) {
+			return fmt.Errorf("object prefix %q has no %s placeholder", prefix, SubjectPlaceholder)
+		}
+		prefix := strings.ReplaceAll(prefix, SubjectPlaceholder, subjectID)
+
+		input := &s3.ListObjectsV2Input{Bucket: aws.String(bucket), Prefix: aws.String(prefix)}
+		for {
+			out, err := client.ListObjectsV2(ctx, input)
+			if err != nil {
+				return fmt.Errorf("failed to list objects: %w", err)
+			}
+			for _, obj := range out.Contents {
+				_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: obj.Key})
+				if err != nil {
+					return fmt.Errorf("failed to erase object %s: %w", aws.StringValue(obj.Key), err)
+				}
+			}
+			if !aws.BoolValue(out.IsTruncated) {
+				return nil
+			}
+			input.ContinuationToken = out.NextContinuationToken
+		}
+	})
+}
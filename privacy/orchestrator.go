@@ -0,0 +1,188 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Auditor records completed erasure requests, the audit trail proving that the data was erased
+type Auditor interface {
+	Audit(ctx context.Context, e *Erasure) error
+}
+
+// AuditorFunc adapts a function to Auditor
+type AuditorFunc func(ctx context.Context, e *Erasure) error
+
+// Audit calls f
+func (f AuditorFunc) Audit(ctx context.Context, e *Erasure) error {
+	return f(ctx, e)
+}
+
+// LogAuditor writes completed erasure requests to the logger
+func LogAuditor(logger *slog.Logger) Auditor {
+	return AuditorFunc(func(ctx context.Context, e *Erasure) error {
+		handlers := make([]string, len(e.Steps))
+		for i, s := range e.Steps {
+			handlers[i] = s.Handler
+		}
+		logger.InfoContext(ctx, "erasure completed",
+			"erasure_id", e.ID,
+			"subject_id", e.SubjectID,
+			"requested_at", e.RequestedAt,
+			"completed_at", e.CompletedAt,
+			"handlers", handlers,
+		)
+		return nil
+	})
+}
+
+// options holds configuration for Orchestrator
+type options struct {
+	stepTimeout time.Duration
+	auditor     Auditor
+	logger      *slog.Logger
+}
+
+// Option is a function that configures Orchestrator options.
+type Option func(opts *options)
+
+// WithStepTimeout limits the run of one handler (default: DefaultStepTimeout).
+func WithStepTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.stepTimeout = timeout
+	}
+}
+
+// WithAuditor sets the auditor of completed requests (default: LogAuditor of the logger).
+func WithAuditor(auditor Auditor) Option {
+	return func(opts *options) {
+		opts.auditor = auditor
+	}
+}
+
+// WithLogger sets the logger of failed steps and of the default auditor.
+func WithLogger(logger *slog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
+func defaultOptions() *options {
+	return &options{
+		stepTimeout: DefaultStepTimeout,
+		logger:      slog.Default(),
+	}
+}
+
+// Orchestrator runs the registered handlers for erasure requests
+type Orchestrator struct {
+	registry *Registry
+	progress ProgressStore
+	opts     *options
+	now      func() time.Time
+}
+
+// NewOrchestrator creates an Orchestrator of the handlers of registry, persisting progress in progress
+func NewOrchestrator(registry *Registry, progress ProgressStore, opts ...Option) *Orchestrator {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.auditor == nil {
+		o.auditor = LogAuditor(o.logger)
+	}
+
+	return &Orchestrator{registry: registry, progress: progress, opts: o, now: time.Now}
+}
+
+// Erase runs the erasure request id for the subject. A new request gets a step for every registered handler;
+// a known one resumes with its pending and failed steps, so Erase can be retried until the request completes.
+// Every step runs, even after a failed one, and its outcome is saved before the next one starts.
+// Completed requests are audited; the returned error joins the errors of failed steps.
+func (o *Orchestrator) Erase(ctx context.Context, id, subjectID string) (*Erasure, error) {
+	if id == "" || subjectID == "" {
+		return nil, errors.New("missing erasure request or subject ID")
+	}
+
+	e, err := o.progress.Load(ctx, id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		e = &Erasure{ID: id, SubjectID: subjectID, RequestedAt: o.now().UTC()}
+		for _, name := range o.registry.Names() {
+			e.Steps = append(e.Steps, Step{Handler: name, Status: StepPending})
+		}
+		if err := o.progress.Save(ctx, e); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case e.SubjectID != subjectID:
+		return nil, fmt.Errorf("erasure request %s is for another subject", id)
+	}
+
+	if !e.CompletedAt.IsZero() {
+		return e, nil
+	}
+
+	var errs []error
+	for i := range e.Steps {
+		step := &e.Steps[i]
+		if step.Status == StepCompleted {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return e, err
+		}
+
+		if err := o.runStep(ctx, e.SubjectID, step); err != nil {
+			errs = append(errs, err)
+			o.opts.logger.ErrorContext(ctx, "erasure step failed",
+				"erasure_id", e.ID, "handler", step.Handler, "attempts", step.Attempts, "error", err)
+		}
+		if err := o.progress.Save(ctx, e); err != nil {
+			return e, err
+		}
+	}
+	if len(errs) > 0 {
+		return e, errors.Join(errs...)
+	}
+
+	e.CompletedAt = o.now().UTC()
+	if err := o.progress.Save(ctx, e); err != nil {
+		return e, err
+	}
+	if err := o.opts.auditor.Audit(ctx, e); err != nil {
+		return e, fmt.Errorf("failed to audit erasure: %w", err)
+	}
+	return e, nil
+}
+
+// Status returns the progress of the request id, or ErrNotFound
+func (o *Orchestrator) Status(ctx context.Context, id string) (*Erasure, error) {
+	return o.progress.Load(ctx, id)
+}
+
+func (o *Orchestrator) runStep(ctx context.Context, subjectID string, step *Step) error {
+	step.Attempts++
+
+	h, ok := o.registry.lookup(step.Handler)
+	if !ok {
+		step.Status, step.Error = StepFailed, "handler not registered"
+		return fmt.Errorf("handler %q is not registered", step.Handler)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.opts.stepTimeout)
+	defer cancel()
+	if err := h.Erase(ctx, subjectID); err != nil {
+		step.Status, step.Error = StepFailed, err.Error()
+		return fmt.Errorf("handler %q: %w", step.Handler, err)
+	}
+
+	step.Status, step.Error, step.CompletedAt = StepCompleted, "", o.now().UTC()
+	return nil
+}
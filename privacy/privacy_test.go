@@ -0,0 +1,111 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestOrchestrator(t *testing.T) {
+	var calls []string
+	handler := func(name string, err *error) Handler {
+		return HandlerFunc(func(_ context.Context, subjectID string) error {
+			calls = append(calls, name+":"+subjectID)
+			return *err
+		})
+	}
+	var ordersErr, avatarsErr error
+	avatarsErr = errors.New("bucket unavailable")
+
+	registry := NewRegistry()
+	registry.MustRegister("avatars", handler("avatars", &avatarsErr))
+	registry.MustRegister("orders", handler("orders", &ordersErr))
+	if err := registry.Register("orders", handler("orders", &ordersErr)); err == nil {
+		t.Error("Register() duplicate error = nil")
+	}
+
+	var audited []*Erasure
+	progress := NewMemoryProgress()
+	o := NewOrchestrator(registry, progress, WithAuditor(AuditorFunc(func(_ context.Context, e *Erasure) error {
+		audited = append(audited, e)
+		return nil
+	})))
+
+	// A failed step doesn't stop the others and is saved as failed
+	e, err := o.Erase(context.Background(), "req-1", "user-42")
+	if err == nil || !strings.Contains(err.Error(), "bucket unavailable") {
+		t.Fatalf("Erase() error = %v, want the avatars failure", err)
+	}
+	saved, err := o.Status(context.Background(), "req-1")
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if saved.Steps[0].Status != StepFailed || saved.Steps[1].Status != StepCompleted || !saved.CompletedAt.IsZero() {
+		t.Errorf("saved steps = %+v, want avatars failed and orders completed", saved.Steps)
+	}
+	if len(audited) != 0 {
+		t.Error("incomplete erasure audited")
+	}
+
+	// Retrying resumes with the failed step only
+	avatarsErr = nil
+	calls = nil
+	e, err = o.Erase(context.Background(), "req-1", "user-42")
+	if err != nil {
+		t.Fatalf("Erase() retry error = %v", err)
+	}
+	if strings.Join(calls, ",") != "avatars:user-42" {
+		t.Errorf("retry calls = %v, want avatars only", calls)
+	}
+	if e.CompletedAt.IsZero() || e.Steps[0].Attempts != 2 || len(audited) != 1 {
+		t.Errorf("erasure = %+v, audited %d, want completed after 2 attempts and audited once", e, len(audited))
+	}
+
+	// Completed requests are not run again
+	calls = nil
+	if _, err := o.Erase(context.Background(), "req-1", "user-42"); err != nil || len(calls) != 0 {
+		t.Errorf("Erase() completed request = %v with calls %v", err, calls)
+	}
+	if _, err := o.Erase(context.Background(), "req-1", "user-7"); err == nil {
+		t.Error("Erase() with another subject error = nil")
+	}
+}
+
+type fakeS3 struct {
+	keys    []string
+	deleted []string
+}
+
+func (f *fakeS3) ListObjectsV2(_ context.Context, input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+	out := &s3.ListObjectsV2Output{IsTruncated: aws.Bool(false)}
+	for _, key := range f.keys {
+		if strings.HasPrefix(key, *input.Prefix) {
+			out.Contents = append(out.Contents, &s3.Object{Key: aws.String(key)})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeS3) DeleteObject(_ context.Context, input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	f.deleted = append(f.deleted, *input.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestS3Eraser(t *testing.T) {
+	client := &fakeS3{keys: []string{"avatars/42/a.png", "avatars/42/b.png", "avatars/420/a.png"}}
+	if err := S3Eraser(client, "media", "avatars/{subject}/").Erase(context.Background(), "42"); err != nil {
+		t.Fatalf("Erase() error = %v", err)
+	}
+	if got := strings.Join(client.deleted, ","); got != "avatars/42/a.png,avatars/42/b.png" {
+		t.Errorf("deleted = %s", got)
+	}
+
+	// Prefixes without the subject would erase everyone's objects
+	if err := S3Eraser(client, "media", "avatars/").Erase(context.Background(), "42"); err == nil {
+		t.Error("Erase() without placeholder error = nil")
+	}
+}
@@ -0,0 +1,169 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound is returned by ProgressStore.Load for unknown erasure requests
+var ErrNotFound = errors.New("erasure request not found")
+
+// StepStatus is the state of one handler of an erasure request
+type StepStatus string
+
+const (
+	// StepPending is a step that hasn't run yet
+	StepPending StepStatus = "pending"
+	// StepCompleted is a step whose handler succeeded
+	StepCompleted StepStatus = "completed"
+	// StepFailed is a step whose last attempt failed; it runs again when the request is resumed
+	StepFailed StepStatus = "failed"
+)
+
+// Step is the progress of one handler
+type Step struct {
+	Handler     string     `json:"handler"`
+	Status      StepStatus `json:"status"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	CompletedAt time.Time  `json:"completed_at,omitzero"`
+}
+
+// Erasure is the progress of an erasure request
+type Erasure struct {
+	ID          string    `json:"id"`
+	SubjectID   string    `json:"subject_id"`
+	RequestedAt time.Time `json:"requested_at"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	Steps       []Step    `json:"steps"`
+}
+
+// ProgressStore persists the progress of erasure requests
+type ProgressStore interface {
+	// Load returns the progress of the request, or ErrNotFound
+	Load(ctx context.Context, id string) (*Erasure, error)
+	// Save creates or replaces the progress of the request
+	Save(ctx context.Context, e *Erasure) error
+}
+
+// MemoryProgress keeps progress in memory, for tests and single-process tools
+type MemoryProgress struct {
+	mu       sync.Mutex
+	erasures map[string][]byte
+}
+
+// NewMemoryProgress creates an empty MemoryProgress
+func NewMemoryProgress() *MemoryProgress {
+	return &MemoryProgress{erasures: make(map[string][]byte)}
+}
+
+// Load returns a copy of the progress of the request
+func (m *MemoryProgress) Load(_ context.Context, id string) (*Erasure, error) {
+	m.mu.Lock()
+	data, ok := m.erasures[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	var e Erasure
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("failed to decode erasure: %w", err)
+	}
+	return &e, nil
+}
+
+// Save stores a copy of the progress of the request
+func (m *MemoryProgress) Save(_ context.Context, e *Erasure) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode erasure: %w", err)
+	}
+	m.mu.Lock()
+	m.erasures[e.ID] = data
+	m.mu.Unlock()
+	return nil
+}
+
+// PostgresAPI is the subset of the pgxv5 connection used by PostgresProgress and PostgresEraser
+type PostgresAPI interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// PostgresProgress persists progress as JSON rows of a table, which also keeps the audit trail
+// of subjects erased, when and by which handlers
+type PostgresProgress struct {
+	db    PostgresAPI
+	table string
+}
+
+// NewPostgresProgress creates a PostgresProgress storing requests in table (default: DefaultProgressTable)
+func NewPostgresProgress(db PostgresAPI, table string) *PostgresProgress {
+	if table == "" {
+		table = DefaultProgressTable
+	}
+	return &PostgresProgress{db: db, table: pgx.Identifier(strings.Split(table, ".")).Sanitize()}
+}
+
+// Init creates the table if it doesn't exist
+func (p *PostgresProgress) Init(ctx context.Context) error {
+	_, err := p.db.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id text PRIMARY KEY,
+	subject_id text NOT NULL,
+	requested_at timestamptz NOT NULL,
+	completed_at timestamptz,
+	progress jsonb NOT NULL
+)`, p.table))
+	if err != nil {
+		return fmt.Errorf("failed to create erasure table: %w", err)
+	}
+	return nil
+}
+
+// Load returns the progress of the request
+func (p *PostgresProgress) Load(ctx context.Context, id string) (*Erasure, error) {
+	var data []byte
+	err := p.db.QueryRow(ctx, fmt.Sprintf("SELECT progress FROM %s WHERE id = $1", p.table), id).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load erasure: %w", err)
+	}
+
+	var e Erasure
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("failed to decode erasure: %w", err)
+	}
+	return &e, nil
+}
+
+// Save upserts the progress of the request
+func (p *PostgresProgress) Save(ctx context.Context, e *Erasure) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode erasure: %w", err)
+	}
+	var completedAt *time.Time
+	if !e.CompletedAt.IsZero() {
+		completedAt = &e.CompletedAt
+	}
+
+	_, err = p.db.Exec(ctx, fmt.Sprintf(`INSERT INTO %s (id, subject_id, requested_at, completed_at, progress)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE SET completed_at = EXCLUDED.completed_at, progress = EXCLUDED.progress`, p.table),
+		e.ID, e.SubjectID, e.RequestedAt, completedAt, data)
+	if err != nil {
+		return fmt.Errorf("failed to save erasure: %w", err)
+	}
+	return nil
+}
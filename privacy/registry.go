@@ -0,0 +1,88 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Handler erases the data of a subject held by one module. Erase must be idempotent,
+// as steps failed or interrupted before their outcome was saved run again.
+type Handler interface {
+	Erase(ctx context.Context, subjectID string) error
+}
+
+// HandlerFunc adapts a function to Handler
+type HandlerFunc func(ctx context.Context, subjectID string) error
+
+// Erase calls f
+func (f HandlerFunc) Erase(ctx context.Context, subjectID string) error {
+	return f(ctx, subjectID)
+}
+
+type namedHandler struct {
+	name    string
+	handler Handler
+}
+
+// Registry holds the erasure handlers of the modules of a service
+type Registry struct {
+	mu       sync.RWMutex
+	handlers []namedHandler
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a handler under a unique name. Handlers run in the order they are registered,
+// so register the handlers of dependent data, e.g. files referenced by rows, first.
+func (r *Registry) Register(name string, h Handler) error {
+	if name == "" {
+		return errors.New("missing handler name")
+	}
+	if h == nil {
+		return fmt.Errorf("handler %q is nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, nh := range r.handlers {
+		if nh.name == name {
+			return fmt.Errorf("duplicate handler %q", name)
+		}
+	}
+	r.handlers = append(r.handlers, namedHandler{name: name, handler: h})
+	return nil
+}
+
+// MustRegister is like Register but panics on error
+func (r *Registry) MustRegister(name string, h Handler) {
+	if err := r.Register(name, h); err != nil {
+		panic(err)
+	}
+}
+
+// Names returns the names of the registered handlers in order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.handlers))
+	for i, nh := range r.handlers {
+		names[i] = nh.name
+	}
+	return names
+}
+
+func (r *Registry) lookup(name string) (Handler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, nh := range r.handlers {
+		if nh.name == name {
+			return nh.handler, true
+		}
+	}
+	return nil, false
+}
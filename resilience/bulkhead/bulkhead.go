@@ -0,0 +1,174 @@
+// Package bulkhead limits the number of concurrent calls to a resource, so that one slow dependency
+// or one class of traffic can't exhaust the goroutines, connections or memory shared with others.
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of the bulkhead metrics
+const meterName = "github.com/rshelekhov/golib/resilience/bulkhead"
+
+// ErrFull is returned when all slots are taken and the wait queue is full or the wait timed out
+var ErrFull = errors.New("bulkhead is full")
+
+// Bulkhead admits up to a fixed number of concurrent calls. Calls over the limit wait for a free slot
+// if the wait queue has room, otherwise they are rejected with ErrFull.
+type Bulkhead struct {
+	name          string
+	slots         chan struct{}
+	waiting       chan struct{}
+	maxWaitTime   time.Duration
+	meterProvider metric.MeterProvider
+
+	inFlight metric.Int64UpDownCounter
+	rejected metric.Int64Counter
+	attrs    metric.MeasurementOption
+}
+
+// Option configures the Bulkhead
+type Option func(*Bulkhead)
+
+// WithMaxWaiting sets how many calls may wait for a free slot (default: 0, calls over the limit are rejected)
+func WithMaxWaiting(n int) Option {
+	return func(b *Bulkhead) {
+		if n > 0 {
+			b.waiting = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithMaxWaitTime limits how long a call waits for a free slot. Zero means until the context is done.
+func WithMaxWaitTime(d time.Duration) Option {
+	return func(b *Bulkhead) {
+		b.maxWaitTime = d
+	}
+}
+
+// WithMeterProvider sets the provider used to create metrics.
+// The global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(b *Bulkhead) {
+		b.meterProvider = mp
+	}
+}
+
+// New creates a bulkhead admitting maxConcurrent calls at once. The name is used as the "bulkhead" metric attribute.
+func New(name string, maxConcurrent int, opts ...Option) *Bulkhead {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	b := &Bulkhead{
+		name:          name,
+		slots:         make(chan struct{}, maxConcurrent),
+		meterProvider: otel.GetMeterProvider(),
+		attrs:         metric.WithAttributes(attribute.String("bulkhead", name)),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	meter := b.meterProvider.Meter(meterName)
+
+	var err error
+	b.inFlight, err = meter.Int64UpDownCounter(
+		"bulkhead_in_flight",
+		metric.WithDescription("Number of calls holding a bulkhead slot."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	b.rejected, err = meter.Int64Counter(
+		"bulkhead_rejected_total",
+		metric.WithDescription("Total number of calls rejected by a full bulkhead."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return b
+}
+
+// Name returns the name of the bulkhead
+func (b *Bulkhead) Name() string {
+	return b.name
+}
+
+// Capacity returns the maximum number of concurrent calls
+func (b *Bulkhead) Capacity() int {
+	return cap(b.slots)
+}
+
+// InFlight returns the number of calls holding a slot
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// TryAcquire takes a slot without waiting. A successful call must be followed by Release.
+func (b *Bulkhead) TryAcquire(ctx context.Context) bool {
+	select {
+	case b.slots <- struct{}{}:
+		b.inFlight.Add(ctx, 1, b.attrs)
+		return true
+	default:
+		return false
+	}
+}
+
+// Acquire takes a slot, waiting for one if the wait queue has room. It returns ErrFull
+// when the call is rejected and the context error when ctx is done while waiting.
+// A successful call must be followed by Release.
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	if b.TryAcquire(ctx) {
+		return nil
+	}
+
+	// Join the wait queue, or reject if it is full
+	select {
+	case b.waiting <- struct{}{}:
+		defer func() { <-b.waiting }()
+	default:
+		b.rejected.Add(ctx, 1, b.attrs)
+		return ErrFull
+	}
+
+	var timeout <-chan time.Time
+	if b.maxWaitTime > 0 {
+		timer := time.NewTimer(b.maxWaitTime)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		b.inFlight.Add(ctx, 1, b.attrs)
+		return nil
+	case <-timeout:
+		b.rejected.Add(ctx, 1, b.attrs)
+		return ErrFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire or TryAcquire
+func (b *Bulkhead) Release(ctx context.Context) {
+	<-b.slots
+	b.inFlight.Add(ctx, -1, b.attrs)
+}
+
+// Do runs fn holding a slot
+func (b *Bulkhead) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := b.Acquire(ctx); err != nil {
+		return err
+	}
+	defer b.Release(ctx)
+	return fn(ctx)
+}
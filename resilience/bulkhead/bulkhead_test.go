@@ -0,0 +1,67 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBulkhead(t *testing.T) {
+	ctx := context.Background()
+	b := New("test", 1, WithMaxWaiting(1), WithMaxWaitTime(time.Second))
+
+	if err := b.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// The second call waits, the third is rejected
+	acquired := make(chan error, 1)
+	go func() { acquired <- b.Acquire(ctx) }()
+	waitFor(t, func() bool { return len(b.waiting) == 1 })
+
+	if err := b.Acquire(ctx); !errors.Is(err, ErrFull) {
+		t.Errorf("Acquire() with full queue error = %v, want ErrFull", err)
+	}
+
+	b.Release(ctx)
+	if err := <-acquired; err != nil {
+		t.Errorf("waiting Acquire() error = %v", err)
+	}
+	if b.InFlight() != 1 {
+		t.Errorf("InFlight() = %d, want 1", b.InFlight())
+	}
+	b.Release(ctx)
+}
+
+func TestBulkheadWaitLimits(t *testing.T) {
+	ctx := context.Background()
+	b := New("test", 1, WithMaxWaiting(1), WithMaxWaitTime(10*time.Millisecond))
+	if !b.TryAcquire(ctx) {
+		t.Fatal("TryAcquire() = false")
+	}
+	defer b.Release(ctx)
+
+	if err := b.Acquire(ctx); !errors.Is(err, ErrFull) {
+		t.Errorf("Acquire() after max wait time error = %v, want ErrFull", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	b = New("test", 1, WithMaxWaiting(1))
+	_ = b.TryAcquire(ctx)
+	if err := b.Acquire(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire() with canceled context error = %v, want context.Canceled", err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,214 @@
+// Package fallback runs a primary step and falls back to the next tiers when it fails,
+// formalizing cache-then-db-then-default patterns.
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of the fallback metrics
+const meterName = "github.com/rshelekhov/golib/resilience/fallback"
+
+// Step is one tier of a fallback chain
+type Step[T any] struct {
+	// Name identifies the step in errors and metrics, e.g. "cache" or "db"
+	Name string
+	// Timeout bounds the step. Zero means the step is only bounded by the parent context.
+	Timeout time.Duration
+	// Fn produces the value
+	Fn func(ctx context.Context) (T, error)
+}
+
+// Static returns a step that always succeeds with the given value,
+// typically the last tier serving a safe default
+func Static[T any](name string, value T) Step[T] {
+	return Step[T]{
+		Name: name,
+		Fn: func(context.Context) (T, error) {
+			return value, nil
+		},
+	}
+}
+
+// Classifier reports whether the chain should fall through to the next step after err.
+// Returning false stops the chain and returns err, e.g. for authoritative "not found" answers.
+type Classifier func(err error) bool
+
+// StepError is the failure of a single step
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e StepError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Step, e.Err)
+}
+
+func (e StepError) Unwrap() error {
+	return e.Err
+}
+
+// Error is returned when every step of the chain failed
+type Error struct {
+	Chain string
+	Steps []StepError
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Steps))
+	for i, s := range e.Steps {
+		parts[i] = s.Error()
+	}
+	return fmt.Sprintf("fallback chain %s: all steps failed: %s", e.Chain, strings.Join(parts, "; "))
+}
+
+// Unwrap allows errors.Is and errors.As to match errors of any step
+func (e *Error) Unwrap() []error {
+	errs := make([]error, len(e.Steps))
+	for i, s := range e.Steps {
+		errs[i] = s
+	}
+	return errs
+}
+
+// Chain holds the settings shared by all executions of a fallback chain.
+// Steps are passed to Run, so one Chain can be reused for calls with different arguments.
+type Chain struct {
+	name          string
+	classify      Classifier
+	meterProvider metric.MeterProvider
+
+	served   metric.Int64Counter
+	failures metric.Int64Counter
+}
+
+// Option configures the Chain
+type Option func(*Chain)
+
+// WithClassifier sets which errors fall through to the next step.
+// By default every error does.
+func WithClassifier(classify Classifier) Option {
+	return func(c *Chain) {
+		c.classify = classify
+	}
+}
+
+// WithMeterProvider sets the provider used to create metrics.
+// The global provider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *Chain) {
+		c.meterProvider = mp
+	}
+}
+
+// NewChain creates a fallback chain. The name is used as the "chain" metric attribute.
+func NewChain(name string, opts ...Option) *Chain {
+	c := &Chain{
+		name:          name,
+		classify:      func(error) bool { return true },
+		meterProvider: otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	meter := c.meterProvider.Meter(meterName)
+
+	var err error
+	c.served, err = meter.Int64Counter(
+		"fallback_served_total",
+		metric.WithDescription("Total number of fallback chain executions by the step that served the result."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	c.failures, err = meter.Int64Counter(
+		"fallback_step_failures_total",
+		metric.WithDescription("Total number of failed fallback chain steps."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return c
+}
+
+// Run executes primary and, while steps fail with errors the classifier lets through,
+// the fallbacks in order. It returns the first successful value.
+// If the parent context is done, Run stops and returns its error.
+func Run[T any](ctx context.Context, c *Chain, primary Step[T], fallbacks ...Step[T]) (T, error) {
+	steps := append([]Step[T]{primary}, fallbacks...)
+
+	var zero T
+	var failed []StepError
+
+	for tier, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		value, err := runStep(ctx, step)
+		if err == nil {
+			c.served.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("chain", c.name),
+				attribute.String("step", step.Name),
+				attribute.Int("tier", tier),
+			))
+			return value, nil
+		}
+
+		c.failures.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("chain", c.name),
+			attribute.String("step", step.Name),
+		))
+
+		// The step may have failed because the caller gave up
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return zero, ctxErr
+		}
+
+		failed = append(failed, StepError{Step: step.Name, Err: err})
+		if !c.classify(err) {
+			return zero, err
+		}
+	}
+
+	c.served.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("chain", c.name),
+		attribute.String("step", "none"),
+		attribute.Int("tier", -1),
+	))
+	return zero, &Error{Chain: c.name, Steps: failed}
+}
+
+// Do runs the steps with a default chain: every error falls through
+// and metrics use the "default" chain name
+func Do[T any](ctx context.Context, primary Step[T], fallbacks ...Step[T]) (T, error) {
+	return Run(ctx, defaultChain, primary, fallbacks...)
+}
+
+var defaultChain = NewChain("default")
+
+func runStep[T any](ctx context.Context, step Step[T]) (T, error) {
+	if step.Fn == nil {
+		var zero T
+		return zero, errors.New("step has no function")
+	}
+
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	return step.Fn(ctx)
+}
@@ -0,0 +1,100 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errNotFound = errors.New("not found")
+
+func failingStep(name string, err error) Step[string] {
+	return Step[string]{
+		Name: name,
+		Fn: func(context.Context) (string, error) {
+			return "", err
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	slowCache := Step[string]{
+		Name:    "cache",
+		Timeout: 10 * time.Millisecond,
+		Fn: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+
+	tests := []struct {
+		name     string
+		chain    *Chain
+		steps    []Step[string]
+		expected string
+		check    func(t *testing.T, err error)
+	}{
+		{
+			name:     "Falls through timed out and failed steps",
+			chain:    NewChain("test"),
+			steps:    []Step[string]{slowCache, failingStep("db", errors.New("connection refused")), Static("default", "guest")},
+			expected: "guest",
+		},
+		{
+			name:  "Stops on errors rejected by the classifier",
+			chain: NewChain("test", WithClassifier(func(err error) bool { return !errors.Is(err, errNotFound) })),
+			steps: []Step[string]{failingStep("db", errNotFound), Static("default", "guest")},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, errNotFound) {
+					t.Errorf("expected not found error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "Reports all step errors when every step fails",
+			chain: NewChain("test"),
+			steps: []Step[string]{failingStep("cache", errors.New("miss")), failingStep("db", errNotFound)},
+			check: func(t *testing.T, err error) {
+				var chainErr *Error
+				if !errors.As(err, &chainErr) || len(chainErr.Steps) != 2 {
+					t.Fatalf("expected chain error with 2 steps, got %v", err)
+				}
+				if !errors.Is(err, errNotFound) {
+					t.Errorf("expected step errors to be unwrapped, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := Run(context.Background(), tt.chain, tt.steps[0], tt.steps[1:]...)
+			if tt.check != nil {
+				tt.check(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestDoStopsOnCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	_, err := Do(ctx, Step[int]{Name: "db", Fn: func(context.Context) (int, error) {
+		called = true
+		return 1, nil
+	}})
+
+	if !errors.Is(err, context.Canceled) || called {
+		t.Errorf("expected context error without calling steps, got %v (called %v)", err, called)
+	}
+}
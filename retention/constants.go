@@ -0,0 +1,10 @@
+package retention
+
+import "time"
+
+const (
+	// DefaultBatchSize is the default number of records purged by one statement or request
+	DefaultBatchSize = 1000
+	// DefaultInterval is the default interval between runs of Enforcer.Run
+	DefaultInterval = time.Hour
+)
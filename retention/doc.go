@@ -0,0 +1,15 @@
+// Package retention enforces data retention policies across stores.
+//
+// Services declare policies: which table, collection, key prefix or object prefix
+// holds the data, how long it is kept and whether expired records are deleted or
+// archived. An Enforcer applies them periodically in batches, so a large backlog
+// doesn't lock tables or flood the store, and writes an audit log of every run with
+// the number of purged records. In dry-run mode expired records are only counted.
+//
+// Stores adapt the databases of the db packages:
+//
+//   - PostgresStore deletes rows older than a timestamp column, or moves them to an archive table
+//   - MongoStore deletes documents older than a date field, or moves them to an archive collection
+//   - RedisStore deletes keys not accessed for the max age, or renames them under an archive prefix
+//   - S3Store deletes objects last modified before the max age, or copies them under an archive prefix
+package retention
@@ -0,0 +1,107 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAPI is the subset of the mongo connection used by MongoStore
+type MongoAPI interface {
+	Find(ctx context.Context, collection string, filter any, opts ...*mongooptions.FindOptions) (*mongo.Cursor, error)
+	InsertMany(ctx context.Context, collection string, documents []any, opts ...*mongooptions.InsertManyOptions) (*mongo.InsertManyResult, error)
+	DeleteMany(ctx context.Context, collection string, filter any, opts ...*mongooptions.DeleteOptions) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, collection string, filter any, opts ...*mongooptions.CountOptions) (int64, error)
+}
+
+// MongoStore purges documents whose TimeField date is older than the max age.
+// Targets are collection names. Archived documents keep their _id, so a batch interrupted
+// between the insert and the delete fails on duplicate keys until the archived copies are removed.
+type MongoStore struct {
+	db MongoAPI
+}
+
+// NewMongoStore creates a MongoStore of the database
+func NewMongoStore(db MongoAPI) *MongoStore {
+	return &MongoStore{db: db}
+}
+
+// Validate checks that the policy has a time field
+func (s *MongoStore) Validate(p Policy) error {
+	return checkTimeField(p)
+}
+
+// Purge deletes or archives expired documents, reading and deleting one batch at a time
+func (s *MongoStore) Purge(ctx context.Context, req Request) (int64, error) {
+	p := req.Policy
+	filter := bson.D{{Key: p.TimeField, Value: bson.D{{Key: "$lt", Value: req.Before}}}}
+
+	if req.DryRun {
+		count, err := s.db.CountDocuments(ctx, p.Target, filter)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count expired documents: %w", err)
+		}
+		return count, nil
+	}
+
+	findOpts := mongooptions.Find().SetLimit(int64(req.BatchSize))
+	if p.Action == ActionDelete {
+		findOpts.SetProjection(bson.D{{Key: "_id", Value: 1}})
+	}
+
+	var purged int64
+	for {
+		docs, err := s.findBatch(ctx, p.Target, filter, findOpts)
+		if err != nil {
+			return purged, err
+		}
+		if len(docs) == 0 {
+			return purged, nil
+		}
+
+		ids := make(bson.A, 0, len(docs))
+		archived := make([]any, 0, len(docs))
+		for _, doc := range docs {
+			ids = append(ids, doc.Lookup("_id"))
+			archived = append(archived, doc)
+		}
+
+		if p.Action == ActionArchive {
+			if _, err := s.db.InsertMany(ctx, p.ArchiveTo, archived); err != nil {
+				return purged, fmt.Errorf("failed to archive expired documents: %w", err)
+			}
+		}
+		res, err := s.db.DeleteMany(ctx, p.Target, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+		if err != nil {
+			return purged, fmt.Errorf("failed to delete expired documents: %w", err)
+		}
+		purged += res.DeletedCount
+
+		if len(docs) < req.BatchSize {
+			return purged, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+	}
+}
+
+func (s *MongoStore) findBatch(ctx context.Context, collection string, filter any, opts *mongooptions.FindOptions) ([]bson.Raw, error) {
+	cursor, err := s.db.Find(ctx, collection, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.Raw
+	for cursor.Next(ctx) {
+		docs = append(docs, append(bson.Raw(nil), cursor.Current...))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expired documents: %w", err)
+	}
+	return docs, nil
+}
@@ -0,0 +1,84 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Action is what happens to expired records
+type Action string
+
+const (
+	// ActionDelete deletes expired records
+	ActionDelete Action = "delete"
+	// ActionArchive moves expired records to Policy.ArchiveTo in the same store
+	ActionArchive Action = "archive"
+)
+
+// Policy declares how long the data of a target is kept
+type Policy struct {
+	// Name identifies the policy in audit logs (default: "<store>/<target>")
+	Name string
+	// Store is the name of the store holding the data, as given to NewEnforcer
+	Store string
+	// Target is the table, collection, key prefix or object prefix holding the data
+	Target string
+	// TimeField is the column or field with the record time, for PostgresStore and MongoStore
+	TimeField string
+	// MaxAge is how long records are kept
+	MaxAge time.Duration
+	// Action is what happens to expired records (default: ActionDelete)
+	Action Action
+	// ArchiveTo is the table, collection, key prefix or object prefix receiving archived records
+	ArchiveTo string
+}
+
+func (p *Policy) validate() error {
+	if p.Name == "" {
+		p.Name = p.Store + "/" + p.Target
+	}
+	if p.Action == "" {
+		p.Action = ActionDelete
+	}
+
+	switch {
+	case p.Store == "":
+		return errors.New("missing store")
+	case p.Target == "":
+		return errors.New("missing target")
+	case p.MaxAge <= 0:
+		return errors.New("max age must be positive")
+	}
+
+	switch p.Action {
+	case ActionDelete:
+	case ActionArchive:
+		if p.ArchiveTo == "" {
+			return errors.New("archive action requires ArchiveTo")
+		}
+		if p.ArchiveTo == p.Target {
+			return errors.New("archive is the target")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", p.Action)
+	}
+	return nil
+}
+
+// checkPrefixes rejects archive prefixes under the target prefix, whose records would be archived again on every run
+func checkPrefixes(p Policy) error {
+	if p.Action == ActionArchive && strings.HasPrefix(p.ArchiveTo, p.Target) {
+		return fmt.Errorf("archive prefix %q is under target prefix %q", p.ArchiveTo, p.Target)
+	}
+	return nil
+}
+
+// checkTimeField requires the time field of stores filtering records by a column or field
+func checkTimeField(p Policy) error {
+	if p.TimeField == "" {
+		return errors.New("missing time field")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgresAPI is the subset of the pgxv5 connection used by PostgresStore
+type PostgresAPI interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// PostgresStore purges rows whose TimeField column is older than the max age.
+// Targets are table names, optionally schema-qualified. Archive tables must have
+// the columns of the target in the same order, e.g. created with
+// CREATE TABLE events_archive (LIKE events).
+type PostgresStore struct {
+	db PostgresAPI
+}
+
+// NewPostgresStore creates a PostgresStore of the database
+func NewPostgresStore(db PostgresAPI) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Validate checks that the policy has a time field
+func (s *PostgresStore) Validate(p Policy) error {
+	return checkTimeField(p)
+}
+
+// Purge deletes or archives expired rows, one statement per batch
+func (s *PostgresStore) Purge(ctx context.Context, req Request) (int64, error) {
+	p := req.Policy
+	table := quoteIdent(p.Target)
+	expired := fmt.Sprintf("%s < $1", pgx.Identifier{p.TimeField}.Sanitize())
+
+	if req.DryRun {
+		var count int64
+		err := s.db.QueryRow(ctx, fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", table, expired), req.Before).Scan(&count)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count expired rows: %w", err)
+		}
+		return count, nil
+	}
+
+	// ctid locates the rows of the batch without relying on a primary key
+	query := fmt.Sprintf("DELETE FROM %s WHERE ctid = ANY(ARRAY(SELECT ctid FROM %s WHERE %s LIMIT $2))", table, table, expired)
+	if p.Action == ActionArchive {
+		query = fmt.Sprintf("WITH moved AS (%s RETURNING *) INSERT INTO %s SELECT * FROM moved", query, quoteIdent(p.ArchiveTo))
+	}
+
+	var purged int64
+	for {
+		tag, err := s.db.Exec(ctx, query, req.Before, req.BatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge expired rows: %w", err)
+		}
+		purged += tag.RowsAffected()
+		if tag.RowsAffected() < int64(req.BatchSize) {
+			return purged, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+	}
+}
+
+// quoteIdent quotes a table name, keeping the schema qualification
+func quoteIdent(name string) string {
+	return pgx.Identifier(strings.Split(name, ".")).Sanitize()
+}
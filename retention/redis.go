@@ -0,0 +1,120 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore purges keys under the Target prefix that weren't accessed for the max age,
+// as Redis keeps no creation time. Idle times are only tracked with an LRU or no eviction
+// policy, and reset by every read. Archived keys are renamed under the ArchiveTo prefix,
+// keeping their TTL. With a cluster client only the keys of one node are scanned.
+type RedisStore struct {
+	client redis.Cmdable
+}
+
+// NewRedisStore creates a RedisStore of the client, e.g. the Client() of the redis connection
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Validate checks that the archive prefix is outside the target prefix
+func (s *RedisStore) Validate(p Policy) error {
+	return checkPrefixes(p)
+}
+
+// Purge scans the keys of the prefix in batches and deletes or renames the expired ones
+func (s *RedisStore) Purge(ctx context.Context, req Request) (int64, error) {
+	p := req.Policy
+	maxIdle := time.Since(req.Before)
+
+	var purged int64
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, p.Target+"*", int64(req.BatchSize)).Result()
+		if err != nil {
+			return purged, fmt.Errorf("failed to scan keys: %w", err)
+		}
+
+		expired, err := s.expired(ctx, keys, maxIdle)
+		if err != nil {
+			return purged, err
+		}
+		if req.DryRun {
+			purged += int64(len(expired))
+		} else if len(expired) > 0 {
+			n, err := s.purge(ctx, p, expired)
+			purged += n
+			if err != nil {
+				return purged, err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return purged, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+	}
+}
+
+// expired returns the keys idle for at least maxIdle
+func (s *RedisStore) expired(ctx context.Context, keys []string, maxIdle time.Duration) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.ObjectIdleTime(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read idle times: %w", err)
+	}
+
+	var expired []string
+	for i, cmd := range cmds {
+		// Keys deleted since the scan have no idle time
+		if idle, err := cmd.Result(); err == nil && idle >= maxIdle {
+			expired = append(expired, keys[i])
+		}
+	}
+	return expired, nil
+}
+
+func (s *RedisStore) purge(ctx context.Context, p Policy, keys []string) (int64, error) {
+	if p.Action == ActionDelete {
+		n, err := s.client.Del(ctx, keys...).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete expired keys: %w", err)
+		}
+		return n, nil
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.StatusCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Rename(ctx, key, p.ArchiveTo+strings.TrimPrefix(key, p.Target))
+	}
+	_, _ = pipe.Exec(ctx)
+
+	var renamed int64
+	var firstErr error
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to archive expired key: %w", err)
+			}
+			continue
+		}
+		renamed++
+	}
+	return renamed, firstErr
+}
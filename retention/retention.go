@@ -0,0 +1,196 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Store purges expired records of a database
+type Store interface {
+	// Validate checks that the store supports the policy
+	Validate(p Policy) error
+	// Purge deletes or archives the records of the policy older than req.Before in batches of req.BatchSize,
+	// or only counts them in dry-run mode, and returns their number. Records purged before an error are counted.
+	Purge(ctx context.Context, req Request) (int64, error)
+}
+
+// Request is a purge of the expired records of a policy
+type Request struct {
+	Policy    Policy
+	Before    time.Time
+	BatchSize int
+	DryRun    bool
+}
+
+// Result is the outcome of enforcing a policy
+type Result struct {
+	Policy   string
+	Action   Action
+	DryRun   bool
+	Before   time.Time
+	Purged   int64
+	Duration time.Duration
+	Err      error
+}
+
+// options holds configuration for Enforcer
+type options struct {
+	batchSize int
+	interval  time.Duration
+	dryRun    bool
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// Option is a function that configures Enforcer options.
+type Option func(opts *options)
+
+// WithBatchSize sets the number of records purged by one statement or request (default: DefaultBatchSize).
+func WithBatchSize(size int) Option {
+	return func(opts *options) {
+		opts.batchSize = size
+	}
+}
+
+// WithInterval sets the interval between runs of Run (default: DefaultInterval).
+func WithInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.interval = interval
+	}
+}
+
+// WithDryRun only counts expired records, e.g. to review new policies before they delete anything.
+func WithDryRun(enable bool) Option {
+	return func(opts *options) {
+		opts.dryRun = enable
+	}
+}
+
+// WithLogger sets the logger receiving the audit log.
+func WithLogger(logger *slog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
+func defaultOptions() *options {
+	return &options{
+		batchSize: DefaultBatchSize,
+		interval:  DefaultInterval,
+		logger:    slog.Default(),
+		now:       time.Now,
+	}
+}
+
+// Enforcer applies retention policies to stores
+type Enforcer struct {
+	stores   map[string]Store
+	policies []Policy
+	opts     *options
+}
+
+// NewEnforcer creates an Enforcer of the policies, whose stores are looked up by name in stores
+func NewEnforcer(stores map[string]Store, policies []Policy, opts ...Option) (*Enforcer, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.batchSize <= 0 {
+		return nil, errors.New("batch size must be positive")
+	}
+	if o.interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	names := make(map[string]struct{}, len(policies))
+	validated := make([]Policy, 0, len(policies))
+	for i, p := range policies {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy %d: %w", i, err)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate policy %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		store, ok := stores[p.Store]
+		if !ok {
+			return nil, fmt.Errorf("policy %q: unknown store %q", p.Name, p.Store)
+		}
+		if err := store.Validate(p); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", p.Name, err)
+		}
+		validated = append(validated, p)
+	}
+
+	return &Enforcer{stores: stores, policies: validated, opts: o}, nil
+}
+
+// RunOnce enforces every policy once, in order. A failed policy doesn't stop the rest;
+// the returned error joins the errors of all failed policies.
+func (e *Enforcer) RunOnce(ctx context.Context) ([]Result, error) {
+	results := make([]Result, 0, len(e.policies))
+	var errs []error
+	for _, p := range e.policies {
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+		res := e.enforce(ctx, p)
+		results = append(results, res)
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("policy %q: %w", p.Name, res.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Run enforces the policies right away and then every interval until ctx is done, logging failures.
+// It fits app.WithWorker.
+func (e *Enforcer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.opts.interval)
+	defer ticker.Stop()
+	for {
+		_, _ = e.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Enforcer) enforce(ctx context.Context, p Policy) Result {
+	start := e.opts.now()
+	res := Result{Policy: p.Name, Action: p.Action, DryRun: e.opts.dryRun, Before: start.Add(-p.MaxAge)}
+
+	res.Purged, res.Err = e.stores[p.Store].Purge(ctx, Request{
+		Policy:    p,
+		Before:    res.Before,
+		BatchSize: e.opts.batchSize,
+		DryRun:    e.opts.dryRun,
+	})
+	res.Duration = time.Since(start)
+
+	attrs := []any{
+		"policy", p.Name,
+		"store", p.Store,
+		"target", p.Target,
+		"action", p.Action,
+		"dry_run", res.DryRun,
+		"before", res.Before,
+		"purged", res.Purged,
+		"duration", res.Duration,
+	}
+	if res.Err != nil {
+		e.opts.logger.ErrorContext(ctx, "retention policy failed", append(attrs, "error", res.Err)...)
+	} else {
+		e.opts.logger.InfoContext(ctx, "retention policy enforced", attrs...)
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeStore struct {
+	requests []Request
+	purged   int64
+	err      error
+}
+
+func (s *fakeStore) Validate(Policy) error { return nil }
+
+func (s *fakeStore) Purge(_ context.Context, req Request) (int64, error) {
+	s.requests = append(s.requests, req)
+	return s.purged, s.err
+}
+
+func TestEnforcer(t *testing.T) {
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	events := &fakeStore{purged: 42}
+	uploads := &fakeStore{purged: 3, err: errors.New("access denied")}
+	var logs bytes.Buffer
+
+	e, err := NewEnforcer(
+		map[string]Store{"postgres": events, "s3": uploads},
+		[]Policy{
+			{Store: "s3", Target: "uploads/tmp/", MaxAge: 24 * time.Hour},
+			{Name: "events", Store: "postgres", Target: "events", TimeField: "created_at", MaxAge: 90 * 24 * time.Hour,
+				Action: ActionArchive, ArchiveTo: "events_archive"},
+		},
+		WithBatchSize(500),
+		WithDryRun(true),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewEnforcer() error = %v", err)
+	}
+	e.opts.now = func() time.Time { return now }
+
+	results, err := e.RunOnce(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `policy "s3/uploads/tmp/": access denied`) {
+		t.Errorf("RunOnce() error = %v, want the failed S3 policy", err)
+	}
+
+	// A failed policy doesn't stop the rest
+	if len(results) != 2 {
+		t.Fatalf("RunOnce() results = %d, want 2", len(results))
+	}
+	if results[0].Purged != 3 || results[0].Err == nil {
+		t.Errorf("S3 result = %+v, want 3 purged before the error", results[0])
+	}
+	if results[1].Purged != 42 || results[1].Err != nil || results[1].Action != ActionArchive {
+		t.Errorf("events result = %+v, want 42 archived", results[1])
+	}
+
+	req := events.requests[0]
+	if want := now.Add(-90 * 24 * time.Hour); !req.Before.Equal(want) {
+		t.Errorf("Before = %v, want %v", req.Before, want)
+	}
+	if req.BatchSize != 500 || !req.DryRun {
+		t.Errorf("request = %+v, want batch size 500 in dry-run mode", req)
+	}
+
+	for _, want := range []string{
+		`msg="retention policy enforced" policy=events store=postgres target=events action=archive dry_run=true`,
+		`msg="retention policy failed" policy=s3/uploads/tmp/`,
+		"purged=42",
+	} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("audit log = %s, want %s", logs.String(), want)
+		}
+	}
+}
+
+func TestNewEnforcerErrors(t *testing.T) {
+	stores := map[string]Store{
+		"fake":  &fakeStore{},
+		"redis": NewRedisStore(nil),
+		"pg":    NewPostgresStore(nil),
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+	}{
+		{name: "missing max age", policy: Policy{Store: "fake", Target: "events"}},
+		{name: "unknown store", policy: Policy{Store: "mysql", Target: "events", MaxAge: time.Hour}},
+		{name: "unknown action", policy: Policy{Store: "fake", Target: "events", MaxAge: time.Hour, Action: "truncate"}},
+		{name: "archive without target", policy: Policy{Store: "fake", Target: "events", MaxAge: time.Hour, Action: ActionArchive}},
+		{name: "missing time field", policy: Policy{Store: "pg", Target: "events", MaxAge: time.Hour}},
+		{name: "archive under target", policy: Policy{Store: "redis", Target: "session:", MaxAge: time.Hour,
+			Action: ActionArchive, ArchiveTo: "session:archive:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEnforcer(stores, []Policy{tt.policy}); err == nil {
+				t.Error("NewEnforcer() error = nil")
+			}
+		})
+	}
+}
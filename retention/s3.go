@@ -0,0 +1,97 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3API is the subset of the s3 connection used by S3Store
+type S3API interface {
+	ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+	CopyObject(ctx context.Context, input *s3.CopyObjectInput) (*s3.CopyObjectOutput, error)
+	DeleteObject(ctx context.Context, input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+}
+
+// S3Store purges objects under the Target prefix last modified before the max age.
+// Archived objects are copied under the ArchiveTo prefix of the same bucket, then deleted;
+// prefer bucket lifecycle rules where the provider supports them.
+type S3Store struct {
+	client S3API
+	bucket string
+}
+
+// NewS3Store creates an S3Store of the bucket
+func NewS3Store(client S3API, bucket string) *S3Store {
+	return &S3Store{client: client, bucket: bucket}
+}
+
+// Validate checks that the archive prefix is outside the target prefix
+func (s *S3Store) Validate(p Policy) error {
+	return checkPrefixes(p)
+}
+
+// Purge lists the objects of the prefix in pages of the batch size and deletes or archives the expired ones
+func (s *S3Store) Purge(ctx context.Context, req Request) (int64, error) {
+	p := req.Policy
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(s.bucket),
+		Prefix:  aws.String(p.Target),
+		MaxKeys: aws.Int64(int64(req.BatchSize)),
+	}
+
+	var purged int64
+	for {
+		out, err := s.client.ListObjectsV2(ctx, input)
+		if err != nil {
+			return purged, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		for _, obj := range out.Contents {
+			if !aws.TimeValue(obj.LastModified).Before(req.Before) {
+				continue
+			}
+			if !req.DryRun {
+				if err := s.purge(ctx, p, aws.StringValue(obj.Key)); err != nil {
+					return purged, err
+				}
+			}
+			purged++
+		}
+
+		if !aws.BoolValue(out.IsTruncated) {
+			return purged, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func (s *S3Store) purge(ctx context.Context, p Policy, key string) error {
+	if p.Action == ActionArchive {
+		source := (&url.URL{Path: s.bucket + "/" + key}).EscapedPath()
+		_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(s.bucket),
+			CopySource: aws.String(source),
+			Key:        aws.String(p.ArchiveTo + strings.TrimPrefix(key, p.Target)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive object %s: %w", key, err)
+		}
+	}
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	return nil
+}
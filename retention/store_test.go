@@ -0,0 +1,112 @@
+package retention
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type fakePostgres struct {
+	queries  []string
+	affected []string
+}
+
+func (f *fakePostgres) QueryRow(context.Context, string, ...any) pgx.Row { return nil }
+
+func (f *fakePostgres) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	f.queries = append(f.queries, sql)
+	tag := f.affected[0]
+	f.affected = f.affected[1:]
+	return pgconn.NewCommandTag(tag), nil
+}
+
+func TestPostgresStore(t *testing.T) {
+	db := &fakePostgres{affected: []string{"INSERT 0 100", "INSERT 0 100", "INSERT 0 7"}}
+	purged, err := NewPostgresStore(db).Purge(context.Background(), Request{
+		Policy:    Policy{Target: "audit.events", TimeField: "created_at", Action: ActionArchive, ArchiveTo: "audit.events_archive"},
+		Before:    time.Now(),
+		BatchSize: 100,
+	})
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if purged != 207 || len(db.queries) != 3 {
+		t.Errorf("Purge() = %d in %d batches, want 207 in 3", purged, len(db.queries))
+	}
+
+	want := `WITH moved AS (DELETE FROM "audit"."events" WHERE ctid = ANY(ARRAY(SELECT ctid FROM "audit"."events" ` +
+		`WHERE "created_at" < $1 LIMIT $2)) RETURNING *) INSERT INTO "audit"."events_archive" SELECT * FROM moved`
+	if db.queries[0] != want {
+		t.Errorf("query = %s, want %s", db.queries[0], want)
+	}
+}
+
+type fakeS3 struct {
+	objects []*s3.Object
+	copied  []string
+	deleted []string
+}
+
+func (f *fakeS3) ListObjectsV2(_ context.Context, input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+	start := 0
+	if input.ContinuationToken != nil {
+		for i, obj := range f.objects {
+			if *obj.Key == *input.ContinuationToken {
+				start = i
+			}
+		}
+	}
+	end := min(start+int(*input.MaxKeys), len(f.objects))
+	out := &s3.ListObjectsV2Output{Contents: f.objects[start:end], IsTruncated: aws.Bool(end < len(f.objects))}
+	if end < len(f.objects) {
+		out.NextContinuationToken = f.objects[end].Key
+	}
+	return out, nil
+}
+
+func (f *fakeS3) CopyObject(_ context.Context, input *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+	f.copied = append(f.copied, *input.CopySource+" -> "+*input.Key)
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObject(_ context.Context, input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	f.deleted = append(f.deleted, *input.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestS3Store(t *testing.T) {
+	before := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	object := func(key string, age time.Duration) *s3.Object {
+		return &s3.Object{Key: aws.String(key), LastModified: aws.Time(before.Add(-age))}
+	}
+	client := &fakeS3{objects: []*s3.Object{
+		object("reports/2025/a b.csv", time.Hour),
+		object("reports/2025/b.csv", -time.Hour),
+		object("reports/2025/c.csv", 24*time.Hour),
+	}}
+	store := NewS3Store(client, "data")
+	policy := Policy{Target: "reports/", Action: ActionArchive, ArchiveTo: "archive/reports/"}
+
+	purged, err := store.Purge(context.Background(), Request{Policy: policy, Before: before, BatchSize: 2, DryRun: true})
+	if err != nil || purged != 2 || len(client.deleted) != 0 {
+		t.Errorf("dry-run Purge() = %d, %v with %d deleted, want 2 counted", purged, err, len(client.deleted))
+	}
+
+	purged, err = store.Purge(context.Background(), Request{Policy: policy, Before: before, BatchSize: 2})
+	if err != nil || purged != 2 {
+		t.Fatalf("Purge() = %d, %v, want 2", purged, err)
+	}
+	want := "data/reports/2025/a%20b.csv -> archive/reports/2025/a b.csv,data/reports/2025/c.csv -> archive/reports/2025/c.csv"
+	if got := strings.Join(client.copied, ","); got != want {
+		t.Errorf("copied = %s, want %s", got, want)
+	}
+	if got := strings.Join(client.deleted, ","); got != "reports/2025/a b.csv,reports/2025/c.csv" {
+		t.Errorf("deleted = %s", got)
+	}
+}
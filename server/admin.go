@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rshelekhov/golib/observability/blackbox"
+	"github.com/rshelekhov/golib/observability/profiling"
+	"google.golang.org/grpc/health"
+)
+
+// redacted replaces the values of sensitive fields in config dumps
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are substrings of the config keys whose values are redacted, compared case-insensitively
+var sensitiveKeys = []string{"password", "secret", "token", "key", "credential", "dsn", "private"}
+
+// newAdminServer creates the server of the admin port with the operational endpoints:
+// the probes, metrics, pprof, build info, the config dump and the debug handlers
+func newAdminServer(options *Options, liveness *health.Server, ready *readiness) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", HealthHandler(liveness))
+	mux.HandleFunc("/readyz", ReadinessHandler(ready))
+	mux.Handle("/debug/pprof/", profiling.Handler())
+	mux.HandleFunc("/debug/buildinfo", buildInfoHandler(options.buildInfoOrDefault()))
+	mux.HandleFunc("/debug/config", ConfigHandler(map[string]any{
+		"server": options.dump(),
+		"config": options.adminConfig,
+	}))
+
+	if options.observability != nil && options.observability.MetricsHandler != nil {
+		mux.Handle(metricsPath, options.observability.MetricsHandler)
+	}
+	if len(options.dependencies) > 0 {
+		mux.HandleFunc("/debug/dependencies", DependenciesHandler(options.dependencies...))
+	}
+	if options.blackBox != nil {
+		mux.Handle("/debug/blackbox", blackbox.Handler(options.blackBox))
+	}
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", options.adminPort),
+		Handler:           mux,
+		ReadHeaderTimeout: options.httpReadHeaderTimeout,
+		IdleTimeout:       options.httpIdleTimeout,
+	}
+}
+
+// ConfigHandler creates an HTTP handler returning cfg as JSON, with the values of keys
+// that look sensitive, e.g. "password", "api_key" or "DSN", redacted. Secrets under
+// other names must be excluded from cfg, e.g. with `json:"-"`.
+func ConfigHandler(cfg any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dump, err := redactConfig(cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(dump)
+	}
+}
+
+// redactConfig converts cfg to its JSON representation with sensitive values redacted
+func redactConfig(cfg any) (any, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var dump any
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return redact(dump), nil
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			if isSensitive(k) && value != nil && value != "" {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redact(value)
+		}
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return v
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// dump returns the server configuration for the config dump
+func (o *Options) dump() map[string]any {
+	return map[string]any{
+		"grpc_port":                o.grpcPort,
+		"http_port":                o.httpPort,
+		"admin_port":               o.adminPort,
+		"reflection":               o.enableReflection,
+		"channelz":                 o.enableChannelz,
+		"single_port":              o.singlePort,
+		"grpc_web":                 o.grpcWeb,
+		"shutdown_timeout":         o.shutdownTimeout.String(),
+		"max_recv_msg_size":        o.maxRecvMsgSize,
+		"max_send_msg_size":        o.maxSendMsgSize,
+		"max_concurrent_streams":   o.maxConcurrentStreams,
+		"http_read_timeout":        o.httpReadTimeout.String(),
+		"http_read_header_timeout": o.httpReadHeaderTimeout.String(),
+		"http_write_timeout":       o.httpWriteTimeout.String(),
+		"http_idle_timeout":        o.httpIdleTimeout.String(),
+		"health_check_interval":    o.healthCheckInterval.String(),
+		"graceful_restart":         o.gracefulRestart,
+		"authentication":           o.authFunc != nil,
+		"rate_limit":               o.rateLimiter != nil,
+		"validation":               o.validation,
+		"build_info":               o.buildInfo != nil,
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminPort(t *testing.T) {
+	type database struct {
+		Host     string `json:"host"`
+		Password string `json:"password"`
+	}
+	cfg := struct {
+		Database database `json:"database"`
+		APIKeys  []string `json:"api_keys"`
+		Region   string   `json:"region"`
+	}{
+		Database: database{Host: "db.internal", Password: "hunter2"},
+		APIKeys:  []string{"k1"},
+		Region:   "eu-west-1",
+	}
+
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithAdminPort(9090),
+		WithAdminConfig(cfg),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	get := func(h http.Handler, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get(app.adminServer.Handler, "/debug/config")
+	if strings.Contains(rec.Body.String(), "hunter2") || strings.Contains(rec.Body.String(), "k1") {
+		t.Errorf("config dump leaks secrets: %s", rec.Body)
+	}
+	var dump struct {
+		Server map[string]any `json:"server"`
+		Config struct {
+			Database map[string]string `json:"database"`
+			Region   string            `json:"region"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &dump); err != nil {
+		t.Fatalf("config dump error = %v", err)
+	}
+	if dump.Config.Database["host"] != "db.internal" || dump.Config.Region != "eu-west-1" || dump.Server["admin_port"] != float64(9090) {
+		t.Errorf("config dump = %s", rec.Body)
+	}
+
+	var info BuildInfo
+	if err := json.Unmarshal(get(app.adminServer.Handler, "/debug/buildinfo").Body.Bytes(), &info); err != nil || info.GoVersion == "" {
+		t.Errorf("build info = %+v, error = %v", info, err)
+	}
+
+	for _, path := range []string{"/debug/pprof/", "/healthz"} {
+		if rec := get(app.adminServer.Handler, path); rec.Code == http.StatusNotFound {
+			t.Errorf("GET %s on admin port = 404", path)
+		}
+	}
+	if rec := get(app.httpServer.Handler, "/debug/config"); rec.Code == http.StatusOK {
+		t.Error("GET /debug/config on HTTP port = 200")
+	}
+
+	if _, err := NewApp(context.Background(), WithHTTPPort(8080), WithAdminPort(8080)); err == nil {
+		t.Error("NewApp() with admin port of the HTTP server error = nil")
+	}
+}
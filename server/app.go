@@ -2,16 +2,21 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rshelekhov/golib/observability/blackbox"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	channelzservice "google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
@@ -22,9 +27,19 @@ type App struct {
 	options     *Options
 	grpcServer  *grpc.Server
 	httpServer  *http.Server
+	adminServer *http.Server
 	healthCheck *health.Server
+	liveness    *health.Server
+	readiness   *readiness
 	mux         *runtime.ServeMux
 	httpMux     *http.ServeMux
+	upgrader    *upgrader
+	services    []GRPCProvider
+	started     []GRPCProvider
+
+	draining          *atomic.Bool
+	stopCallbacksOnce sync.Once
+	shutdownHooksOnce sync.Once
 }
 
 // GRPCProvider is an interface for any service that can register with gRPC
@@ -55,15 +70,67 @@ func NewApp(ctx context.Context, opts ...Option) (*App, error) {
 		opt(options)
 	}
 
-	if options.grpcPort <= 0 {
-		return nil, fmt.Errorf("gRPC port must be specified and be greater than 0")
+	if !options.singlePort && !options.grpcEnabled() {
+		return nil, fmt.Errorf("gRPC port must be specified and be greater than 0, or a gRPC listener or socket set")
+	}
+	if options.singlePort && !options.httpEnabled() {
+		return nil, fmt.Errorf("single port mode requires the HTTP port to be specified")
+	}
+	if options.singlePort && (len(options.grpcListeners) > 0 || len(options.grpcSockets) > 0) {
+		return nil, fmt.Errorf("single port mode serves gRPC on the HTTP listeners, use HTTP listeners and sockets instead")
+	}
+	if options.adminPort > 0 && (options.adminPort == options.grpcPort || options.adminPort == options.httpPort) {
+		return nil, fmt.Errorf("admin port %d is already used by the gRPC or HTTP server", options.adminPort)
 	}
 
 	var httpServer *http.Server
 	var httpMux *http.ServeMux
 	var gwMux *runtime.ServeMux
 
+	upg, err := newUpgrader(options.reusePort)
+	if err != nil {
+		return nil, err
+	}
+
+	// The service is not ready until Run has warmed it up
 	healthCheck := health.NewServer()
+	healthCheck.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	liveness := health.NewServer()
+	ready := newReadiness(options.healthChecks)
+
+	// Authenticate and limit calls before the configured interceptors, but after observability records them,
+	// and validate the requests of authenticated callers
+	if options.validation {
+		options.installValidation()
+	}
+	if options.authFunc != nil {
+		options.installAuth()
+	}
+	if options.rateLimiter != nil {
+		options.installRateLimit()
+	}
+
+	// Recover from panics of everything but observability, which records the failed requests
+	if options.recovery {
+		options.installRecovery()
+	}
+
+	// Reject new streams while draining; observability records the rejections
+	draining := new(atomic.Bool)
+	options.streamInterceptors = append([]grpc.StreamServerInterceptor{
+		drainingStreamInterceptor(draining),
+	}, options.streamInterceptors...)
+
+	// Wire observability first, so that other interceptors and middleware can use it
+	if options.observability != nil {
+		options.installObservability()
+	}
+
+	// Record requests last, so that recovery and tracing interceptors wrap the recorder
+	if options.blackBox != nil {
+		options.unaryInterceptors = append(options.unaryInterceptors, blackbox.UnaryServerInterceptor(options.blackBox))
+		options.streamInterceptors = append(options.streamInterceptors, blackbox.StreamServerInterceptor(options.blackBox))
+	}
 
 	serverOpts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(options.unaryInterceptors...),
@@ -72,6 +139,7 @@ func NewApp(ctx context.Context, opts ...Option) (*App, error) {
 	if options.statsHandler != nil {
 		serverOpts = append(serverOpts, grpc.StatsHandler(options.statsHandler))
 	}
+	serverOpts = append(serverOpts, options.grpcServerOpts()...)
 
 	// Create gRPC server with interceptors
 	grpcServer := grpc.NewServer(serverOpts...)
@@ -79,80 +147,213 @@ func NewApp(ctx context.Context, opts ...Option) (*App, error) {
 	// Register health check service
 	healthpb.RegisterHealthServer(grpcServer, healthCheck)
 
+	// Register dependencies status service
+	if len(options.dependencies) > 0 {
+		RegisterDependenciesServer(grpcServer, options.dependencies...)
+	}
+
+	// Register version service
+	if options.buildInfo != nil {
+		RegisterVersionServer(grpcServer, *options.buildInfo)
+	}
+
 	// Enable reflection for development tools
 	if options.enableReflection {
 		reflection.Register(grpcServer)
 	}
 
+	// Enable channelz for debugging connections
+	if options.enableChannelz {
+		channelzservice.RegisterChannelzServiceToServer(grpcServer)
+	}
+
 	// Create HTTP server for gRPC-Gateway if port is specified
-	if options.httpPort > 0 {
+	if options.httpEnabled() {
 		// Create HTTP mux for gRPC-Gateway
-		gwMux = runtime.NewServeMux(options.muxOptions...)
+		muxOptions := options.muxOptions
+		if len(options.responseTransformers) > 0 {
+			muxOptions = append(muxOptions, runtime.WithMiddlewares(
+				responseTransformMiddleware(options.responseTransformers, options.logger),
+			))
+		}
+		if options.observability != nil {
+			muxOptions = append(muxOptions, runtime.WithMiddlewares(gatewayRouteMiddleware))
+		}
+		if options.rateLimiter != nil {
+			muxOptions = append(muxOptions, runtime.WithMiddlewares(gatewayRateLimitMiddleware(options.rateLimiter)))
+		}
+		gwMux = runtime.NewServeMux(muxOptions...)
 
 		// Create main HTTP mux for both gRPC-Gateway and other HTTP handlers
 		httpMux = http.NewServeMux()
 
-		// Register health check endpoints
-		WithHealthEndpoints(httpMux, healthCheck)
+		// Register health check endpoints. Liveness doesn't depend on health checks,
+		// so a failing database makes the service unready but doesn't get it restarted.
+		httpMux.HandleFunc("/healthz", HealthHandler(liveness))
+		httpMux.HandleFunc("/readyz", ReadinessHandler(ready))
+
+		// Register version endpoint
+		if options.buildInfo != nil {
+			httpMux.HandleFunc("GET /version", VersionHandler(*options.buildInfo))
+		}
+
+		// Serve Prometheus metrics, unless the admin port serves them
+		admin := options.adminPort > 0
+		if !admin && options.observability != nil && options.observability.MetricsHandler != nil {
+			httpMux.Handle(metricsPath, options.observability.MetricsHandler)
+		}
 
-		// Handle gRPC-Gateway requests
-		httpMux.Handle("/", gwMux)
+		// Register dependencies status endpoint
+		if !admin && len(options.dependencies) > 0 {
+			httpMux.HandleFunc("/debug/dependencies", DependenciesHandler(options.dependencies...))
+		}
+
+		// Handle gRPC-Gateway requests, recording them without the probes
+		if options.blackBox != nil {
+			if !admin {
+				httpMux.Handle("/debug/blackbox", blackbox.Handler(options.blackBox))
+			}
+			httpMux.Handle("/", blackbox.Middleware(options.blackBox)(gwMux))
+		} else {
+			httpMux.Handle("/", gwMux)
+		}
+
+		// Register handlers served next to the gateway; more specific patterns take precedence over it
+		for _, h := range options.httpHandlers {
+			if options.rateLimiter != nil && h.handler != nil {
+				h.handler = options.rateLimiter.Handler(h.handler)
+			}
+			if err := h.register(httpMux); err != nil {
+				return nil, err
+			}
+		}
+
+		// Serve gRPC-Web calls of browsers behind the HTTP middleware, e.g. CORS
+		var handler http.Handler = httpMux
+		if options.grpcWeb {
+			handler = grpcWebHandler(grpcServer, httpMux)
+		}
 
 		// Create HTTP server with configured mux
 		httpServer = &http.Server{
-			Addr:    fmt.Sprintf(":%d", options.httpPort),
-			Handler: options.wrapHTTPHandler(httpMux),
+			Addr:              fmt.Sprintf(":%d", options.httpPort),
+			Handler:           options.wrapHTTPHandler(handler),
+			ReadTimeout:       options.httpReadTimeout,
+			ReadHeaderTimeout: options.httpReadHeaderTimeout,
+			WriteTimeout:      options.httpWriteTimeout,
+			IdleTimeout:       options.httpIdleTimeout,
 		}
+
+		// Route gRPC requests to the gRPC server before the HTTP middleware,
+		// accepting HTTP/2 without TLS as gRPC clients use it
+		if options.singlePort {
+			httpServer.Handler = grpcRoutingHandler(grpcServer, httpServer.Handler)
+			httpServer.Protocols = new(http.Protocols)
+			httpServer.Protocols.SetHTTP1(true)
+			httpServer.Protocols.SetUnencryptedHTTP2(true)
+		}
+	}
+
+	var adminServer *http.Server
+	if options.adminPort > 0 {
+		adminServer = newAdminServer(options, liveness, ready)
 	}
 
 	return &App{
 		options:     options,
 		grpcServer:  grpcServer,
 		httpServer:  httpServer,
+		adminServer: adminServer,
 		healthCheck: healthCheck,
+		liveness:    liveness,
+		readiness:   ready,
 		mux:         gwMux,
 		httpMux:     httpMux,
+		upgrader:    upg,
+		draining:    draining,
 	}, nil
 }
 
-// Run starts the application servers and blocks until shutdown
-func (a *App) Run(ctx context.Context, service GRPCProvider) error {
-	// Set health check to serving
-	a.healthCheck.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-
-	// Create error group for concurrent server management
-	g, ctx := errgroup.WithContext(ctx)
+// Run starts the services registered with Register and the given ones, then the application
+// servers, and blocks until shutdown
+func (a *App) Run(ctx context.Context, services ...GRPCProvider) error {
+	a.Register(services...)
+	if len(a.services) == 0 {
+		return errors.New("no services to run")
+	}
 
-	// Start gRPC server
-	a.startGRPCServer(g, service)
+	// Warm up before accepting traffic; until then the service is not ready
+	if err := a.warmup(ctx); err != nil {
+		return err
+	}
 
 	// Register readiness checks if available
-	if a.httpMux != nil && a.httpServer != nil {
+	for _, service := range a.services {
 		if readinessProvider, ok := service.(ReadinessProvider); ok {
-			readinessChecks := readinessProvider.ReadinessChecks()
-			a.httpMux.HandleFunc("/readyz", ReadinessHandler(readinessChecks...))
+			a.readiness.add(readinessProvider.ReadinessChecks()...)
 		}
 	}
 
+	// Start background work of the services before serving
+	if err := a.startServices(ctx); err != nil {
+		return err
+	}
+
+	// Set health check to serving unless a health check fails, and keep it up to date
+	a.readiness.serving.Store(true)
+	failing := make(map[string]bool)
+	a.updateHealth(ctx, failing)
+
+	healthCtx, stopHealth := context.WithCancel(ctx)
+	defer stopHealth()
+	go a.watchHealth(healthCtx, failing)
+
+	// Create error group for concurrent server management
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Start gRPC server
+	if err := a.startGRPCServer(ctx, g); err != nil {
+		a.stopServices(ctx)
+		return err
+	}
+
 	// Start HTTP server if initialized
 	if a.httpServer != nil && a.mux != nil {
-		// Check if service implements HTTPProvider
-		if httpProvider, ok := service.(HTTPProvider); ok {
-			if err := a.startHTTPServer(ctx, g, httpProvider); err != nil {
-				return err
+		if err := a.startHTTPServer(ctx, g); err != nil {
+			a.grpcServer.Stop()
+			a.stopServices(ctx)
+			return err
+		}
+	}
+
+	// Start admin server if configured
+	if a.adminServer != nil {
+		if err := a.startAdminServer(ctx, g); err != nil {
+			a.grpcServer.Stop()
+			if a.httpServer != nil {
+				_ = a.httpServer.Close()
 			}
-		} else {
-			// Start HTTP server without registering HTTP handlers
-			g.Go(func() error {
-				a.options.logger.Info("starting HTTP server", "port", a.options.httpPort)
-				if err := a.httpServer.ListenAndServe(); err != http.ErrServerClosed {
-					return fmt.Errorf("HTTP server error: %w", err)
-				}
-				return nil
-			})
+			a.stopServices(ctx)
+			return err
 		}
 	}
 
+	// Notify the parent process that listeners are served after a graceful restart
+	if a.upgrader.isChild() {
+		a.options.logger.Info("taking over from previous process")
+	}
+	if err := a.upgrader.ready(); err != nil {
+		a.options.logger.Error("failed to notify previous process", "error", err)
+	}
+
+	// Run the start callbacks once the servers listen; a failure stops the application
+	if err := a.runStartCallbacks(ctx); err != nil {
+		a.options.logger.Error("start callback failed, shutting down", "error", err)
+		a.shutdown(false)
+		_ = g.Wait()
+		return fmt.Errorf("start callback: %w", err)
+	}
+
 	// Handle graceful shutdown
 	a.handleGracefulShutdown(ctx, g)
 
@@ -160,37 +361,77 @@ func (a *App) Run(ctx context.Context, service GRPCProvider) error {
 }
 
 // startGRPCServer initializes and starts the gRPC server
-func (a *App) startGRPCServer(g *errgroup.Group, service GRPCProvider) {
-	// Register service with gRPC server
-	service.RegisterGRPC(a.grpcServer)
-
-	// Start gRPC server
-	g.Go(func() error {
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", a.options.grpcPort))
-		if err != nil {
-			return fmt.Errorf("failed to listen on gRPC port: %w", err)
-		}
+func (a *App) startGRPCServer(ctx context.Context, g *errgroup.Group) error {
+	// Register services with gRPC server
+	for _, service := range a.services {
+		service.RegisterGRPC(a.grpcServer)
+	}
 
-		a.options.logger.Info("starting gRPC server", "port", a.options.grpcPort)
-		if err := a.grpcServer.Serve(lis); err != nil {
-			return fmt.Errorf("gRPC server error: %w", err)
-		}
+	// In single port mode the HTTP server serves gRPC requests
+	if a.options.singlePort {
 		return nil
-	})
+	}
+
+	listeners, err := a.listeners(ctx, "grpc", a.options.grpcPort, a.options.grpcSockets, a.options.grpcListeners)
+	if err != nil {
+		return err
+	}
+
+	// Start gRPC server on every listener
+	for _, lis := range listeners {
+		g.Go(func() error {
+			a.options.logger.Info("starting gRPC server", "address", lis.Addr().String())
+			if err := a.grpcServer.Serve(lis); err != nil {
+				return fmt.Errorf("gRPC server error: %w", err)
+			}
+			return nil
+		})
+	}
+
+	return nil
 }
 
 // startHTTPServer initializes and starts the HTTP server
-func (a *App) startHTTPServer(ctx context.Context, g *errgroup.Group, provider HTTPProvider) error {
-	// Register HTTP handlers
-	if err := provider.RegisterHTTP(ctx, a.mux); err != nil {
-		return fmt.Errorf("failed to register HTTP handlers: %w", err)
+func (a *App) startHTTPServer(ctx context.Context, g *errgroup.Group) error {
+	// Register HTTP handlers of services implementing HTTPProvider
+	for _, service := range a.services {
+		if provider, ok := service.(HTTPProvider); ok {
+			if err := provider.RegisterHTTP(ctx, a.mux); err != nil {
+				return fmt.Errorf("failed to register HTTP handlers: %w", err)
+			}
+		}
+	}
+
+	listeners, err := a.listeners(ctx, "http", a.options.httpPort, a.options.httpSockets, a.options.httpListeners)
+	if err != nil {
+		return err
+	}
+
+	// Start HTTP server on every listener
+	for _, lis := range listeners {
+		g.Go(func() error {
+			a.options.logger.Info("starting HTTP server", "address", lis.Addr().String(), "single_port", a.options.singlePort)
+			if err := a.httpServer.Serve(lis); err != http.ErrServerClosed {
+				return fmt.Errorf("HTTP server error: %w", err)
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// startAdminServer starts the admin server with the operational endpoints
+func (a *App) startAdminServer(ctx context.Context, g *errgroup.Group) error {
+	lis, err := a.upgrader.listen(ctx, "admin", a.adminServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on admin port: %w", err)
 	}
 
-	// Start HTTP server
 	g.Go(func() error {
-		a.options.logger.Info("starting HTTP server", "port", a.options.httpPort)
-		if err := a.httpServer.ListenAndServe(); err != http.ErrServerClosed {
-			return fmt.Errorf("HTTP server error: %w", err)
+		a.options.logger.Info("starting admin server", "port", a.options.adminPort)
+		if err := a.adminServer.Serve(lis); err != http.ErrServerClosed {
+			return fmt.Errorf("admin server error: %w", err)
 		}
 		return nil
 	})
@@ -198,29 +439,88 @@ func (a *App) startHTTPServer(ctx context.Context, g *errgroup.Group, provider H
 	return nil
 }
 
-// handleGracefulShutdown manages graceful shutdown on signals or context done
+// grpcRoutingHandler sends gRPC requests to grpcServer and everything else to next
+func grpcRoutingHandler(grpcServer *grpc.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		if r.ProtoMajor == 2 && strings.HasPrefix(contentType, grpcContentType) && !strings.HasPrefix(contentType, grpcWebContentType) {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleGracefulShutdown manages graceful shutdown on signals or context done.
+// With graceful restart enabled, SIGHUP hands the listeners over to a new
+// process and shuts this one down once the new process is ready.
 func (a *App) handleGracefulShutdown(ctx context.Context, g *errgroup.Group) {
 	g.Go(func() error {
 		// Create signal channel for shutdown
 		sigCh := make(chan os.Signal, 1)
 		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigCh)
 
-		select {
-		case s := <-sigCh:
-			a.options.logger.Info("received signal, shutting down", "signal", s.String())
-		case <-ctx.Done():
-			a.options.logger.Info("context done, shutting down")
+		upgradeCh := make(chan os.Signal, 1)
+		if a.options.gracefulRestart {
+			signal.Notify(upgradeCh, syscall.SIGHUP)
+			defer signal.Stop(upgradeCh)
 		}
 
-		a.Shutdown()
-		return nil
+		for {
+			select {
+			case s := <-sigCh:
+				a.options.logger.Info("received signal, shutting down", "signal", s.String())
+			case <-upgradeCh:
+				if err := a.Upgrade(ctx); err != nil {
+					a.options.logger.Error("graceful restart failed, continuing to serve", "error", err)
+					continue
+				}
+				a.options.logger.Info("new process is ready, draining connections")
+				a.shutdown(false)
+				return nil
+			case <-ctx.Done():
+				a.options.logger.Info("context done, shutting down")
+			}
+
+			a.Shutdown()
+			return nil
+		}
 	})
 }
 
-// Shutdown gracefully stops the application servers
+// Upgrade starts a new instance of the current binary that inherits the listeners
+// and waits until it is ready to serve. The caller is responsible for shutting down
+// the current App afterwards; Run does this automatically on SIGHUP when
+// graceful restart is enabled. Observability providers should be flushed after
+// Run returns, so telemetry of the draining phase is not lost.
+func (a *App) Upgrade(ctx context.Context) error {
+	a.options.logger.Info("starting graceful restart")
+	return a.upgrader.upgrade(ctx, a.options.upgradeTimeout)
+}
+
+// Shutdown gracefully stops the application servers: it runs the stop callbacks, drains
+// for the drain delay, and stops the servers once in-flight requests finish
 func (a *App) Shutdown() {
-	// Set health check to not serving
-	a.healthCheck.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	a.shutdown(true)
+}
+
+// shutdown stops the application, waiting for the drain delay if wait is set
+func (a *App) shutdown(wait bool) {
+	a.stopCallbacksOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.options.shutdownTimeout)
+		defer cancel()
+		a.runStopCallbacks(ctx)
+	})
+
+	a.Drain()
+	if wait {
+		a.waitDrain()
+	}
+
+	// Set health check to not serving; health checks can't change it anymore
+	a.healthCheck.Shutdown()
+	a.liveness.Shutdown()
 
 	// Create a timeout context for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), a.options.shutdownTimeout)
@@ -250,4 +550,17 @@ func (a *App) Shutdown() {
 			a.options.logger.Info("gRPC server stopped gracefully")
 		}
 	}
+
+	// Stop the services and release resources once the listeners have drained
+	a.shutdownHooksOnce.Do(func() {
+		a.stopServices(ctx)
+		a.runShutdownHooks(ctx)
+	})
+
+	// Keep the metrics and pprof available until the end of the shutdown
+	if a.adminServer != nil {
+		if err := a.adminServer.Shutdown(ctx); err != nil {
+			a.options.logger.Error("error shutting down admin server", "error", err)
+		}
+	}
 }
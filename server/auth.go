@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/rshelekhov/golib/middleware/auth"
+	"google.golang.org/grpc"
+)
+
+// installAuth prepends the authentication interceptors and middleware to the configured ones
+func (o *Options) installAuth() {
+	o.unaryInterceptors = append([]grpc.UnaryServerInterceptor{
+		auth.UnaryServerInterceptor(o.authFunc, o.authOptions...),
+	}, o.unaryInterceptors...)
+	o.streamInterceptors = append([]grpc.StreamServerInterceptor{
+		auth.StreamServerInterceptor(o.authFunc, o.authOptions...),
+	}, o.streamInterceptors...)
+
+	// Probes and scrapers don't carry credentials
+	httpOpts := append([]auth.Option{auth.WithExempt(untracedPaths...)}, o.authOptions...)
+	o.httpMiddleware = append([]func(http.Handler) http.Handler{
+		auth.HTTPMiddleware(o.authFunc, httpOpts...),
+	}, o.httpMiddleware...)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rshelekhov/golib/middleware/auth"
+)
+
+func TestWithAuthFunc(t *testing.T) {
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithAuthFunc(auth.APIKeys(map[string]string{"secret": "billing"})),
+		WithHTTPHandler("/hook", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		path, key string
+		want      int
+	}{
+		{"/hook", "", http.StatusUnauthorized},
+		{"/hook", "secret", http.StatusOK},
+		{"/healthz", "", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set(auth.APIKeyHeader, tt.key)
+		}
+		rec := httptest.NewRecorder()
+		app.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("GET %s with key %q = %d, want %d", tt.path, tt.key, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rshelekhov/golib/observability/blackbox"
+)
+
+func TestBlackBox(t *testing.T) {
+	rec := blackbox.New()
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithBlackBox(rec),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	for _, path := range []string{"/healthz", "/v1/orders/1", "/debug/blackbox"} {
+		app.httpMux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Probes and the admin endpoint are not recorded
+	entries := rec.Snapshot()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v, want only the gateway request", entries)
+	}
+	if entries[0].Route != "GET /v1/orders/1" || entries[0].Status != http.StatusNotFound {
+		t.Errorf("entry = %s %d, want GET /v1/orders/1 404", entries[0].Route, entries[0].Status)
+	}
+}
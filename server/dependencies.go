@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DependencyState is the state of a dependency
+type DependencyState string
+
+const (
+	DependencyUp       DependencyState = "up"
+	DependencyDegraded DependencyState = "degraded"
+	DependencyDown     DependencyState = "down"
+)
+
+// DefaultDependencyTimeout is the timeout of a single dependency check
+const DefaultDependencyTimeout = 2 * time.Second
+
+// DependencyStatus describes the state of a dependency for incident triage
+type DependencyStatus struct {
+	Name  string          `json:"name"`
+	Kind  string          `json:"kind,omitempty"` // e.g. "postgres", "redis", "circuit_breaker"
+	State DependencyState `json:"state"`
+	// LastError is the most recent error and LastErrorAt its time, possibly from an earlier check
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+	// Latency is the duration of the last check
+	Latency   time.Duration  `json:"-"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Details   map[string]any `json:"details,omitempty"` // e.g. pool stats or breaker counters
+}
+
+// MarshalJSON encodes Latency as a duration string, e.g. "12.5ms"
+func (s DependencyStatus) MarshalJSON() ([]byte, error) {
+	type status DependencyStatus
+	return json.Marshal(struct {
+		status
+		Latency string `json:"latency,omitempty"`
+	}{status: status(s), Latency: latencyString(s.Latency)})
+}
+
+func latencyString(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// Dependency reports the status of a dependency: a database, a downstream service, a circuit breaker, etc.
+type Dependency interface {
+	DependencyStatus(ctx context.Context) DependencyStatus
+}
+
+// DependencyFunc adapts a function to Dependency, e.g. to map circuit breaker states:
+//
+//	server.DependencyFunc(func(ctx context.Context) server.DependencyStatus {
+//		state := server.DependencyUp
+//		if cb.State() == gobreaker.StateOpen {
+//			state = server.DependencyDown
+//		}
+//		return server.DependencyStatus{Name: "payments", Kind: "circuit_breaker", State: state}
+//	})
+type DependencyFunc func(ctx context.Context) DependencyStatus
+
+func (f DependencyFunc) DependencyStatus(ctx context.Context) DependencyStatus {
+	return f(ctx)
+}
+
+// DependencyOption configures a DependencyCheck
+type DependencyOption func(*DependencyCheck)
+
+// WithDependencyTimeout sets the check timeout (default: DefaultDependencyTimeout)
+func WithDependencyTimeout(timeout time.Duration) DependencyOption {
+	return func(d *DependencyCheck) {
+		d.timeout = timeout
+	}
+}
+
+// WithDependencyDetails adds details to the status, e.g. connection pool stats
+func WithDependencyDetails(details func() map[string]any) DependencyOption {
+	return func(d *DependencyCheck) {
+		d.details = details
+	}
+}
+
+// DependencyCheck is a Dependency running a check on every status request.
+// It measures the check latency and remembers the last error.
+type DependencyCheck struct {
+	name    string
+	kind    string
+	check   ReadinessCheck
+	timeout time.Duration
+	details func() map[string]any
+
+	mu          sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// NewDependencyCheck creates a dependency checked with check, e.g. a database ping
+func NewDependencyCheck(name, kind string, check ReadinessCheck, opts ...DependencyOption) *DependencyCheck {
+	d := &DependencyCheck{
+		name:    name,
+		kind:    kind,
+		check:   check,
+		timeout: DefaultDependencyTimeout,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *DependencyCheck) DependencyStatus(ctx context.Context) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := d.check.Check(ctx)
+
+	status := DependencyStatus{
+		Name:      d.name,
+		Kind:      d.kind,
+		State:     DependencyUp,
+		Latency:   time.Since(start),
+		CheckedAt: start,
+	}
+	if d.details != nil {
+		status.Details = d.details()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil {
+		status.State = DependencyDown
+		d.lastError = err.Error()
+		d.lastErrorAt = start
+	}
+	status.LastError = d.lastError
+	status.LastErrorAt = d.lastErrorAt
+
+	return status
+}
+
+// DependencyReport is the response of the dependencies endpoint
+type DependencyReport struct {
+	// State is the worst state of all dependencies
+	State        DependencyState    `json:"state"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// CollectDependencies queries all dependencies concurrently
+func CollectDependencies(ctx context.Context, deps ...Dependency) DependencyReport {
+	report := DependencyReport{
+		State:        DependencyUp,
+		Dependencies: make([]DependencyStatus, len(deps)),
+	}
+
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dependencies[i] = dep.DependencyStatus(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range report.Dependencies {
+		switch {
+		case s.State == DependencyDown:
+			report.State = DependencyDown
+		case s.State == DependencyDegraded && report.State == DependencyUp:
+			report.State = DependencyDegraded
+		}
+	}
+	return report
+}
+
+// DependenciesHandler creates an HTTP handler returning the status of all dependencies as JSON.
+// It always responds with 200 OK: it is meant for triage, use ReadinessHandler for probes.
+func DependenciesHandler(deps ...Dependency) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := CollectDependencies(r.Context(), deps...)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
+
+// DependenciesServiceName is the gRPC service registered by RegisterDependenciesServer.
+// Its List method takes google.protobuf.Empty and returns the report as google.protobuf.Struct.
+const DependenciesServiceName = "golib.server.Dependencies"
+
+// RegisterDependenciesServer registers a gRPC service returning the status of all dependencies
+func RegisterDependenciesServer(s grpc.ServiceRegistrar, deps ...Dependency) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: DependenciesServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "List",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, _ any) (any, error) {
+					return dependencyReportStruct(CollectDependencies(ctx, deps...))
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{FullMethod: "/" + DependenciesServiceName + "/List"}
+				return interceptor(ctx, in, info, handler)
+			},
+		}},
+	}, struct{}{})
+}
+
+func dependencyReportStruct(report DependencyReport) (*structpb.Struct, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	out := new(structpb.Struct)
+	if err := protojson.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type checkFunc func(ctx context.Context) error
+
+func (f checkFunc) Check(ctx context.Context) error { return f(ctx) }
+
+func TestDependenciesHandler(t *testing.T) {
+	failing := true
+	db := NewDependencyCheck("users-db", "postgres", checkFunc(func(context.Context) error {
+		if failing {
+			return errors.New("connection refused")
+		}
+		return nil
+	}), WithDependencyDetails(func() map[string]any { return map[string]any{"idle_conns": 3} }))
+
+	breaker := DependencyFunc(func(context.Context) DependencyStatus {
+		return DependencyStatus{Name: "payments", Kind: "circuit_breaker", State: DependencyDegraded}
+	})
+
+	handler := DependenciesHandler(db, breaker)
+
+	get := func() map[string]any {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/debug/dependencies", nil))
+
+		var report map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("decode report: %v", err)
+		}
+		return report
+	}
+
+	report := get()
+	if report["state"] != string(DependencyDown) {
+		t.Errorf("expected overall state down, got %v", report["state"])
+	}
+
+	failing = false
+	report = get()
+	if report["state"] != string(DependencyDegraded) {
+		t.Errorf("expected overall state degraded, got %v", report["state"])
+	}
+
+	dbStatus := report["dependencies"].([]any)[0].(map[string]any)
+	if dbStatus["state"] != string(DependencyUp) || dbStatus["last_error"] != "connection refused" {
+		t.Errorf("expected recovered dependency to keep its last error, got %v", dbStatus)
+	}
+	if dbStatus["latency"] == nil || dbStatus["details"] == nil {
+		t.Errorf("expected latency and details, got %v", dbStatus)
+	}
+}
+
+func TestDependencyReportStruct(t *testing.T) {
+	report := CollectDependencies(context.Background(), DependencyFunc(func(context.Context) DependencyStatus {
+		return DependencyStatus{Name: "cache", State: DependencyUp}
+	}))
+
+	s, err := dependencyReportStruct(report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.Fields["state"].GetStringValue(); got != string(DependencyUp) {
+		t.Errorf("expected state up, got %q", got)
+	}
+}
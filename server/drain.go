@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// drainExempt are the gRPC services whose streams are accepted while draining,
+// so health watchers see NOT_SERVING instead of an error
+var drainExempt = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// Drain puts the application into the draining state before shutdown. Readiness fails, so
+// Kubernetes and load balancers stop routing traffic to it; new streams are rejected with
+// Unavailable, so streaming clients reconnect to other instances; HTTP keep-alive connections
+// are closed after their current request. Unary calls and HTTP requests are still served,
+// as some are routed before the load balancers notice. Shutdown drains before stopping the servers;
+// Drain is called directly to start draining earlier, e.g. from a preStop hook endpoint.
+func (a *App) Drain() {
+	if !a.draining.CompareAndSwap(false, true) {
+		return
+	}
+
+	a.options.logger.Info("draining")
+	a.readiness.serving.Store(false)
+	a.healthCheck.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if a.httpServer != nil {
+		a.httpServer.SetKeepAlivesEnabled(false)
+	}
+}
+
+// Draining reports whether the application is draining
+func (a *App) Draining() bool {
+	return a.draining.Load()
+}
+
+// waitDrain waits for the drain delay after draining, so load balancers stop routing
+// traffic before the servers stop accepting connections
+func (a *App) waitDrain() {
+	if a.options.drainDelay <= 0 {
+		return
+	}
+
+	a.options.logger.Info("waiting for load balancers to stop routing traffic", "delay", a.options.drainDelay)
+	time.Sleep(a.options.drainDelay)
+}
+
+// drainingStreamInterceptor rejects new streams while draining
+func drainingStreamInterceptor(draining *atomic.Bool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if draining.Load() && !isDrainExempt(info.FullMethod) {
+			return status.Error(codes.Unavailable, "server is draining")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func isDrainExempt(fullMethod string) bool {
+	for _, prefix := range drainExempt {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lifecycleCallback is a function called when the application starts or stops serving
+type lifecycleCallback func(ctx context.Context) error
+
+// runStartCallbacks runs the OnStart callbacks in registration order, stopping at the first error
+func (a *App) runStartCallbacks(ctx context.Context) error {
+	for _, fn := range a.options.onStart {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStopCallbacks runs the OnStop callbacks in registration order. A failed callback
+// doesn't stop the others.
+func (a *App) runStopCallbacks(ctx context.Context) {
+	for _, fn := range a.options.onStop {
+		if err := fn(ctx); err != nil {
+			a.options.logger.Error("stop callback failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrain(t *testing.T) {
+	var started, stopped atomic.Bool
+	app, err := NewApp(context.Background(),
+		WithGRPCPort(0),
+		WithGRPCListener(newLocalListener(t)),
+		WithHTTPListener(newLocalListener(t)),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithOnStart(func(ctx context.Context) error { started.Store(true); return nil }),
+		WithOnStop(func(ctx context.Context) error { stopped.Store(true); return nil }),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, noopService{}) }()
+
+	readyz := func() int {
+		rec := httptest.NewRecorder()
+		app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for (!started.Load() || readyz() != http.StatusOK) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !started.Load() {
+		t.Fatal("OnStart callback not called")
+	}
+
+	app.Drain()
+	if !app.Draining() || readyz() != http.StatusServiceUnavailable {
+		t.Errorf("readyz while draining = %d, want 503", readyz())
+	}
+
+	interceptor := drainingStreamInterceptor(app.draining)
+	handler := func(any, grpc.ServerStream) error { return nil }
+	if err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: "/orders.v1.OrderService/Watch"}, handler); status.Code(err) != codes.Unavailable {
+		t.Errorf("new stream while draining = %v, want Unavailable", err)
+	}
+	if err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, handler); err != nil {
+		t.Errorf("health watch while draining = %v, want nil", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if !stopped.Load() {
+		t.Error("OnStop callback not called")
+	}
+}
+
+func newLocalListener(t *testing.T) net.Listener {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	return lis
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/textproto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+// ForwardHeaders returns an incoming header matcher passing the given HTTP headers to gRPC metadata
+// under their own names, e.g. "X-Request-ID" as "x-request-id", besides the headers forwarded
+// by runtime.DefaultHeaderMatcher
+func ForwardHeaders(headers ...string) runtime.HeaderMatcherFunc {
+	forward := make(map[string]struct{}, len(headers))
+	for _, h := range headers {
+		forward[textproto.CanonicalMIMEHeaderKey(h)] = struct{}{}
+	}
+	return func(key string) (string, bool) {
+		if _, ok := forward[textproto.CanonicalMIMEHeaderKey(key)]; ok {
+			return key, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+// ErrorEnvelope is the body written by ErrorEnvelopeHandler
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes a failed gateway request
+type ErrorBody struct {
+	// Code is the gRPC status code name, e.g. "NOT_FOUND"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Details are the status details marshaled by the gateway marshaler, with their "@type"
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// ErrorEnvelopeHandler returns a gateway error handler writing errors as
+// {"error": {"code": "NOT_FOUND", "message": "...", "details": [...]}}, with the HTTP status
+// mapped from the gRPC code like the default handler. Headers and trailers are forwarded as by default.
+// Envelope wraps error bodies too, so use one or the other.
+func ErrorEnvelopeHandler() runtime.ErrorHandlerFunc {
+	return func(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		runtime.DefaultHTTPErrorHandler(ctx, mux, errorEnvelopeMarshaler{Marshaler: marshaler}, w, r, err)
+	}
+}
+
+// errorEnvelopeMarshaler marshals statuses as ErrorEnvelope and everything else with the wrapped marshaler
+type errorEnvelopeMarshaler struct {
+	runtime.Marshaler
+}
+
+func (m errorEnvelopeMarshaler) ContentType(v any) string {
+	if _, ok := v.(*spb.Status); ok {
+		return "application/json"
+	}
+	return m.Marshaler.ContentType(v)
+}
+
+func (m errorEnvelopeMarshaler) Marshal(v any) ([]byte, error) {
+	st, ok := v.(*spb.Status)
+	if !ok {
+		return m.Marshaler.Marshal(v)
+	}
+
+	body := ErrorBody{Code: code.Code(st.GetCode()).String(), Message: st.GetMessage()}
+	for _, detail := range st.GetDetails() {
+		b, err := m.Marshaler.Marshal(detail)
+		if err != nil {
+			return nil, err
+		}
+		body.Details = append(body.Details, b)
+	}
+	return json.Marshal(ErrorEnvelope{Error: body})
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestGatewayOptions(t *testing.T) {
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithGatewayJSON(protojson.MarshalOptions{UseProtoNames: true}, protojson.UnmarshalOptions{DiscardUnknown: true}),
+		WithIncomingHeaderMatcher(ForwardHeaders("X-Request-ID")),
+		WithGatewayErrorHandler(ErrorEnvelopeHandler()),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	var forwarded string
+	err = app.mux.HandlePath(http.MethodGet, "/v1/orders/{id}", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx, err := runtime.AnnotateContext(r.Context(), app.mux, r, "/orders.v1.Orders/Get")
+		if err != nil {
+			t.Fatalf("AnnotateContext() error = %v", err)
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		forwarded = strings.Join(md.Get("x-request-id"), ",")
+
+		_, outbound := runtime.MarshalerForRequest(app.mux, r)
+		st, _ := status.New(codes.NotFound, "order not found").WithDetails(&errdetails.ResourceInfo{ResourceName: "orders/1"})
+		runtime.HTTPError(ctx, app.mux, outbound, w, r, st.Err())
+	})
+	if err != nil {
+		t.Fatalf("HandlePath() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/1", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	app.httpMux.ServeHTTP(rec, req)
+
+	if forwarded != "req-1" {
+		t.Errorf("forwarded x-request-id = %q, want req-1", forwarded)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+	want := `{"error":{"code":"NOT_FOUND","message":"order not found","details":[{"@type":"type.googleapis.com/google.rpc.ResourceInfo","resource_name":"orders/1"}]}}`
+	var body bytes.Buffer
+	if err := json.Compact(&body, rec.Body.Bytes()); err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if body.String() != want {
+		t.Errorf("body = %s, want %s", body.String(), want)
+	}
+
+	// Messages use proto field names
+	_, outbound := runtime.MarshalerForRequest(app.mux, req)
+	b, err := outbound.Marshal(&descriptorpb.FieldDescriptorProto{JsonName: proto.String("orderId")})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(b), `"json_name"`) {
+		t.Errorf("Marshal() = %s, want proto field names", b)
+	}
+}
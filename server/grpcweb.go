@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	grpcContentType        = "application/grpc"
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+
+	// grpcWebTrailerFlag marks the frame carrying the trailers at the end of a gRPC-Web response body
+	grpcWebTrailerFlag = 0x80
+)
+
+// isGRPCWebRequest reports whether r is a gRPC-Web call, e.g. of a browser client
+func isGRPCWebRequest(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), grpcWebContentType)
+}
+
+// grpcWebHandler translates gRPC-Web calls to gRPC and serves them with grpcServer,
+// passing everything else to next. Browsers can call the services over HTTP/1.1 or HTTP/2
+// without a proxy; the calls go through the gRPC interceptors like native ones.
+func grpcWebHandler(grpcServer *grpc.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isGRPCWebRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		text := strings.HasPrefix(contentType, grpcWebTextContentType)
+
+		// The gRPC server only serves HTTP/2 requests with a gRPC content type, e.g. application/grpc+proto
+		req := r.Clone(r.Context())
+		req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+		if text {
+			req.Header.Set("Content-Type", grpcContentType+strings.TrimPrefix(contentType, grpcWebTextContentType))
+			req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+			req.ContentLength = -1
+			req.Header.Del("Content-Length")
+		} else {
+			req.Header.Set("Content-Type", grpcContentType+strings.TrimPrefix(contentType, grpcWebContentType))
+		}
+
+		resp := &grpcWebResponse{w: w, header: make(http.Header), text: text}
+		grpcServer.ServeHTTP(resp, req)
+		resp.finish()
+	})
+}
+
+// grpcWebResponse writes a gRPC response in the gRPC-Web format: the trailers are sent
+// as the last frame of the body, and the body is base64 encoded for text clients
+type grpcWebResponse struct {
+	w           http.ResponseWriter
+	header      http.Header
+	text        bool
+	wroteHeader bool
+}
+
+func (r *grpcWebResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *grpcWebResponse) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+
+	// Trailers are declared by the Trailer header and written after the body
+	h := r.w.Header()
+	for k, vv := range r.header {
+		if k == "Trailer" || strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+		h[k] = vv
+	}
+
+	contentType := grpcWebContentType
+	if r.text {
+		contentType = grpcWebTextContentType
+	}
+	h.Set("Content-Type", contentType+strings.TrimPrefix(r.header.Get("Content-Type"), grpcContentType))
+	r.w.WriteHeader(code)
+}
+
+func (r *grpcWebResponse) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	if !r.text {
+		return r.w.Write(b)
+	}
+
+	// Every write is encoded on its own, so streamed messages can be flushed;
+	// gRPC-Web clients decode concatenated padded chunks
+	if _, err := io.WriteString(r.w, base64.StdEncoding.EncodeToString(b)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (r *grpcWebResponse) Flush() {
+	r.WriteHeader(http.StatusOK)
+	_ = http.NewResponseController(r.w).Flush()
+}
+
+func (r *grpcWebResponse) Unwrap() http.ResponseWriter {
+	return r.w
+}
+
+// finish writes the trailers frame: the declared trailers and the ones set with http.TrailerPrefix,
+// as lowercase "key: value" lines
+func (r *grpcWebResponse) finish() {
+	trailers := make(http.Header)
+	for _, k := range r.header.Values("Trailer") {
+		if vv := r.header.Values(k); len(vv) > 0 {
+			trailers[strings.ToLower(k)] = vv
+		}
+	}
+	for k, vv := range r.header {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			trailers[strings.ToLower(name)] = vv
+		}
+	}
+
+	keys := make([]string, 0, len(trailers))
+	for k := range trailers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var block bytes.Buffer
+	for _, k := range keys {
+		for _, v := range trailers[k] {
+			block.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+block.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(block.Len()))
+	_, _ = r.Write(append(frame, block.Bytes()...))
+	r.Flush()
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCWeb(t *testing.T) {
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithGRPCWeb(true),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	msg, _ := proto.Marshal(&healthpb.HealthCheckRequest{})
+	frame := append([]byte{0, 0, 0, 0, 0}, msg...)
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+
+	for _, tt := range []struct {
+		contentType, path string
+		body              []byte
+		wantStatus        string
+	}{
+		{"application/grpc-web+proto", "/grpc.health.v1.Health/Check", frame, "grpc-status: 0"},
+		{"application/grpc-web-text", "/grpc.health.v1.Health/Check", []byte(base64.StdEncoding.EncodeToString(frame)), "grpc-status: 0"},
+		{"application/grpc-web+proto", "/orders.v1.OrderService/GetOrder", frame, "grpc-status: 12"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		rec := httptest.NewRecorder()
+		app.httpServer.Handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+			t.Errorf("%s %s content type = %q, want %q", tt.contentType, tt.path, got, tt.contentType)
+		}
+
+		body := rec.Body.Bytes()
+		if tt.contentType == "application/grpc-web-text" {
+			body = decodeChunks(t, rec.Body.String())
+		}
+
+		// The last frame carries the trailers
+		var trailers string
+		for len(body) >= 5 {
+			n := binary.BigEndian.Uint32(body[1:5])
+			if body[0]&0x80 != 0 {
+				trailers = string(body[5 : 5+n])
+			}
+			body = body[5+n:]
+		}
+		if !strings.Contains(trailers, tt.wantStatus+"\r\n") {
+			t.Errorf("%s %s trailers = %q, want %q", tt.contentType, tt.path, trailers, tt.wantStatus)
+		}
+	}
+}
+
+// decodeChunks decodes concatenated padded base64 chunks
+func decodeChunks(t *testing.T, s string) []byte {
+	t.Helper()
+
+	var out []byte
+	for s != "" {
+		end := strings.Index(s, "=")
+		for end >= 0 && end+1 < len(s) && s[end+1] == '=' {
+			end++
+		}
+		chunk := s
+		if end >= 0 {
+			chunk, s = s[:end+1], s[end+1:]
+		} else {
+			s = ""
+		}
+		b, err := base64.StdEncoding.DecodeString(chunk)
+		if err != nil {
+			t.Fatalf("DecodeString() error = %v", err)
+		}
+		out = append(out, b...)
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// httpHandler is a handler served at a pattern of the HTTP mux
+type httpHandler struct {
+	pattern string
+	handler http.Handler
+}
+
+// register adds the handler to mux, reporting invalid and conflicting patterns,
+// e.g. "/" or "/healthz", as errors instead of the panics of http.ServeMux
+func (h httpHandler) register(mux *http.ServeMux) (err error) {
+	if h.handler == nil {
+		return fmt.Errorf("HTTP handler for %q is nil", h.pattern)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to register HTTP handler for %q: %v", h.pattern, r)
+		}
+	}()
+	mux.Handle(h.pattern, h.handler)
+	return nil
+}
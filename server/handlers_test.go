@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	debug := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(logger),
+		WithHTTPHandler("/debug/", debug),
+		WithHTTPHandler("POST /webhooks/stripe", debug),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/debug/pprof/", http.StatusTeapot},
+		{http.MethodPost, "/webhooks/stripe", http.StatusTeapot},
+		{http.MethodGet, "/webhooks/stripe", http.StatusNotFound}, // served by the gateway
+		{http.MethodGet, "/healthz", http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+	}
+
+	// Conflicting patterns fail instead of panicking
+	for _, pattern := range []string{"/healthz", "/", "debug"} {
+		if _, err := NewApp(context.Background(), WithHTTPPort(8080), WithLogger(logger), WithHTTPHandler(pattern, debug)); err == nil {
+			t.Errorf("NewApp() with pattern %q error = nil", pattern)
+		}
+	}
+}
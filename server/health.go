@@ -2,12 +2,22 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// DefaultHealthCheckInterval is how often health checks update the gRPC health status
+const DefaultHealthCheckInterval = 10 * time.Second
+
+var errNotServing = errors.New("not serving")
+
 // HealthHandler creates an HTTP handler for health checks that uses the gRPC health check service
 func HealthHandler(healthCheck *health.Server) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -53,3 +63,115 @@ func WithHealthEndpoints(mux *http.ServeMux, healthCheck *health.Server, readine
 	// Ready probe - is the service ready to receive traffic?
 	mux.HandleFunc("/readyz", ReadinessHandler(readinessChecks...))
 }
+
+// HealthCheckFunc adapts a function to ReadinessCheck
+type HealthCheckFunc func(ctx context.Context) error
+
+func (f HealthCheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// namedCheck is a check registered with WithHealthCheck. Its name is the gRPC health service
+// reporting the check and prefixes its errors.
+type namedCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+func (c namedCheck) Check(ctx context.Context) error {
+	if err := c.check.Check(ctx); err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	return nil
+}
+
+// readiness is the readiness of an App: it is ready while serving and all checks pass
+type readiness struct {
+	serving atomic.Bool
+
+	mu     sync.RWMutex
+	checks []ReadinessCheck
+}
+
+func newReadiness(checks []namedCheck) *readiness {
+	r := &readiness{}
+	for _, c := range checks {
+		r.checks = append(r.checks, c)
+	}
+	return r
+}
+
+func (r *readiness) add(checks ...ReadinessCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, checks...)
+}
+
+func (r *readiness) all() []ReadinessCheck {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.checks
+}
+
+func (r *readiness) Check(ctx context.Context) error {
+	if !r.serving.Load() {
+		return errNotServing
+	}
+	for _, check := range r.all() {
+		if err := check.Check(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchHealth runs updateHealth every health check interval until ctx is done
+func (a *App) watchHealth(ctx context.Context, failing map[string]bool) {
+	ticker := time.NewTicker(a.options.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.updateHealth(ctx, failing)
+		}
+	}
+}
+
+// updateHealth runs the readiness checks and reflects their results in the gRPC health service:
+// the overall status ("") and the status of every named check. failing tracks failed checks
+// between runs, so only changes are logged.
+func (a *App) updateHealth(ctx context.Context, failing map[string]bool) {
+	overall := healthpb.HealthCheckResponse_SERVING
+
+	for i, check := range a.readiness.all() {
+		checkCtx, cancel := context.WithTimeout(ctx, DefaultDependencyTimeout)
+		err := check.Check(checkCtx)
+		cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = status
+		}
+
+		name := fmt.Sprintf("#%d", i)
+		if c, ok := check.(namedCheck); ok {
+			name = c.name
+			a.healthCheck.SetServingStatus(name, status)
+		}
+		switch {
+		case err != nil && !failing[name]:
+			a.options.logger.Warn("health check failed", "check", name, "error", err)
+		case err == nil && failing[name]:
+			a.options.logger.Info("health check recovered", "check", name)
+		}
+		failing[name] = err != nil
+	}
+
+	if a.readiness.serving.Load() {
+		a.healthCheck.SetServingStatus("", overall)
+	}
+}
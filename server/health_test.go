@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthChecks(t *testing.T) {
+	var dbErr error
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithHealthCheck("db", func(ctx context.Context) error { return dbErr }),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		app.httpMux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+	grpcStatus := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := app.healthCheck.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return healthpb.HealthCheckResponse_UNKNOWN
+		}
+		return resp.Status
+	}
+
+	// Not ready before Run, but alive
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before Run = %d, want 503", code)
+	}
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz before Run = %d, want 200", code)
+	}
+	if got := grpcStatus(""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("gRPC status before Run = %v, want NOT_SERVING", got)
+	}
+
+	app.readiness.serving.Store(true)
+	failing := make(map[string]bool)
+	app.updateHealth(context.Background(), failing)
+	if code := probe("/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz = %d, want 200", code)
+	}
+	if got := grpcStatus("db"); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("gRPC status of db = %v, want SERVING", got)
+	}
+
+	// A failing check flips readiness but not liveness
+	dbErr = errors.New("connection refused")
+	app.updateHealth(context.Background(), failing)
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz with failing check = %d, want 503", code)
+	}
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz with failing check = %d, want 200", code)
+	}
+	for _, service := range []string{"", "db"} {
+		if got := grpcStatus(service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("gRPC status of %q with failing check = %v, want NOT_SERVING", service, got)
+		}
+	}
+
+	dbErr = nil
+	app.updateHealth(context.Background(), failing)
+	if got := grpcStatus(""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("gRPC status after recovery = %v, want SERVING", got)
+	}
+
+	app.Shutdown()
+	app.updateHealth(context.Background(), failing)
+	if got := grpcStatus(""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("gRPC status after Shutdown = %v, want NOT_SERVING", got)
+	}
+	if code := probe("/healthz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/healthz after Shutdown = %d, want 503", code)
+	}
+}
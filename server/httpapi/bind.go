@@ -0,0 +1,301 @@
+package httpapi
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct tags recognized by Bind
+const (
+	TagQuery  = "query"
+	TagPath   = "path"
+	TagHeader = "header"
+	TagForm   = "form"
+)
+
+// bindSources lists tags in the order they are applied
+var bindSources = []string{TagPath, TagQuery, TagHeader, TagForm}
+
+// BindError describes request parameters that could not be bound or validated
+type BindError struct {
+	Status int
+	Fields []FieldProblem
+	Err    error
+}
+
+func (e *BindError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid request parameters: %v", e.Err)
+	}
+
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Message)
+	}
+	return "invalid request parameters: " + strings.Join(msgs, "; ")
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// Problem converts the error into a problem details response
+func (e *BindError) Problem() Problem {
+	p := NewProblem(e.Status, "invalid request parameters")
+	p.Errors = e.Fields
+	if len(p.Errors) == 0 && e.Err != nil {
+		p.Errors = []FieldProblem{{Message: e.Err.Error()}}
+	}
+	return p
+}
+
+type bindOptions struct {
+	pathParams map[string]string
+	validate   bool
+}
+
+// BindOption configures Bind
+type BindOption func(*bindOptions)
+
+// WithPathParams provides path parameters extracted by the router,
+// e.g. the pathParams argument of runtime.ServeMux.HandlePath.
+// Without this option path values are read with http.Request.PathValue.
+func WithPathParams(params map[string]string) BindOption {
+	return func(o *bindOptions) {
+		o.pathParams = params
+	}
+}
+
+// WithValidation enables or disables calling Validate() after binding (default: enabled)
+func WithValidation(enable bool) BindOption {
+	return func(o *bindOptions) {
+		o.validate = enable
+	}
+}
+
+// Bind populates the struct pointed to by dst from path, query, header and form values
+// according to its `path`, `query`, `header` and `form` struct tags.
+// Slice fields collect repeated values. After binding, dst.Validate() is called
+// if dst implements it. All failures are reported as *BindError.
+//
+//	type ListUsersRequest struct {
+//	    OrgID    string   `path:"org_id"`
+//	    Limit    int      `query:"limit"`
+//	    Tags     []string `query:"tag"`
+//	    TenantID string   `header:"X-Tenant-ID"`
+//	}
+func Bind(r *http.Request, dst any, opts ...BindOption) error {
+	options := &bindOptions{validate: true}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return &BindError{
+			Status: http.StatusInternalServerError,
+			Err:    errors.New("bind target must be a non-nil pointer to a struct"),
+		}
+	}
+
+	if hasFormTags(rv.Elem().Type()) {
+		if err := r.ParseForm(); err != nil {
+			return &BindError{Status: http.StatusBadRequest, Err: fmt.Errorf("parse form: %w", err)}
+		}
+	}
+
+	var problems []FieldProblem
+	bindStruct(r, options, rv.Elem(), &problems)
+	if len(problems) > 0 {
+		return &BindError{Status: http.StatusBadRequest, Fields: problems}
+	}
+
+	if options.validate {
+		if v, ok := dst.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return &BindError{Status: http.StatusBadRequest, Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+// BindOrProblem binds the request and, on failure, writes a problem+json response.
+// It returns false if the handler should stop.
+func BindOrProblem(w http.ResponseWriter, r *http.Request, dst any, opts ...BindOption) bool {
+	if err := Bind(r, dst, opts...); err != nil {
+		WriteError(w, err)
+		return false
+	}
+	return true
+}
+
+func bindStruct(r *http.Request, options *bindOptions, v reflect.Value, problems *[]FieldProblem) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		// Descend into embedded structs to support shared parameter sets
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			bindStruct(r, options, fv, problems)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, source := range bindSources {
+			name, ok := field.Tag.Lookup(source)
+			if !ok || name == "-" {
+				continue
+			}
+			name = strings.Split(name, ",")[0]
+
+			values := lookupValues(r, options, source, name)
+			if len(values) == 0 {
+				continue
+			}
+
+			if err := setField(fv, values); err != nil {
+				*problems = append(*problems, FieldProblem{
+					Field:   name,
+					Message: fmt.Sprintf("%s parameter %q: %v", source, name, err),
+				})
+			}
+		}
+	}
+}
+
+func hasFormTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup(TagForm); ok {
+			return true
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && hasFormTags(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupValues(r *http.Request, options *bindOptions, source, name string) []string {
+	switch source {
+	case TagPath:
+		if options.pathParams != nil {
+			if v, ok := options.pathParams[name]; ok {
+				return []string{v}
+			}
+			return nil
+		}
+		if v := r.PathValue(name); v != "" {
+			return []string{v}
+		}
+		return nil
+	case TagQuery:
+		return r.URL.Query()[name]
+	case TagHeader:
+		return r.Header.Values(name)
+	case TagForm:
+		return r.PostForm[name]
+	default:
+		return nil
+	}
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// setField converts the raw values into the field type
+func setField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, raw := range values {
+			if err := setValue(slice.Index(i), raw); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setValue(fv, values[0])
+}
+
+func setValue(fv reflect.Value, raw string) error {
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setValue(ptr.Elem(), raw); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) && fv.Type() != timeType {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch fv.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case timeType:
+		tm, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("invalid RFC 3339 time %q", raw)
+		}
+		fv.Set(reflect.ValueOf(tm))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type pageParams struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+type listUsersRequest struct {
+	pageParams
+	OrgID    string        `path:"org_id"`
+	Tags     []string      `query:"tag"`
+	Active   *bool         `query:"active"`
+	Since    time.Time     `query:"since"`
+	Timeout  time.Duration `query:"timeout"`
+	TenantID string        `header:"X-Tenant-ID"`
+}
+
+func (r *listUsersRequest) Validate() error {
+	if r.Limit > 100 {
+		return errors.New("limit must not exceed 100")
+	}
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet,
+		"/orgs/acme/users?limit=10&offset=20&tag=a&tag=b&active=true&since=2025-01-02T03:04:05Z&timeout=5s", nil)
+	r.Header.Set("X-Tenant-ID", "tenant-1")
+
+	var req listUsersRequest
+	if err := Bind(r, &req, WithPathParams(map[string]string{"org_id": "acme"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if req.OrgID != "acme" || req.TenantID != "tenant-1" {
+		t.Errorf("unexpected path/header values: %+v", req)
+	}
+	if req.Limit != 10 || req.Offset != 20 {
+		t.Errorf("unexpected embedded values: %+v", req.pageParams)
+	}
+	if len(req.Tags) != 2 || req.Tags[1] != "b" {
+		t.Errorf("unexpected tags: %v", req.Tags)
+	}
+	if req.Active == nil || !*req.Active {
+		t.Errorf("expected active to be true")
+	}
+	if !req.Since.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) || req.Timeout != 5*time.Second {
+		t.Errorf("unexpected time values: %v %v", req.Since, req.Timeout)
+	}
+}
+
+func TestBindPathValue(t *testing.T) {
+	mux := http.NewServeMux()
+
+	var req listUsersRequest
+	mux.HandleFunc("GET /orgs/{org_id}/users", func(w http.ResponseWriter, r *http.Request) {
+		if err := Bind(r, &req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orgs/acme/users", nil))
+
+	if req.OrgID != "acme" {
+		t.Errorf("expected org_id acme, got %q", req.OrgID)
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	form := url.Values{"name": {"john"}, "age": {"42"}}
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var req struct {
+		Name string `form:"name"`
+		Age  uint8  `form:"age"`
+	}
+	if err := Bind(r, &req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Name != "john" || req.Age != 42 {
+		t.Errorf("unexpected form values: %+v", req)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{
+			name:     "Conversion errors are collected per field",
+			target:   "/users?limit=ten&offset=-",
+			expected: `{"field":"limit","message":"query parameter \"limit\": invalid integer \"ten\""},{"field":"offset"`,
+		},
+		{
+			name:     "Validation errors are reported",
+			target:   "/users?limit=1000",
+			expected: "limit must not exceed 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			var req listUsersRequest
+			if BindOrProblem(w, httptest.NewRequest(http.MethodGet, tt.target, nil), &req) {
+				t.Fatal("expected binding to fail")
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.expected) {
+				t.Errorf("expected body to contain %q, got %s", tt.expected, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the default limit for JSON request bodies
+const DefaultMaxBodyBytes int64 = 1 << 20 // 1 MiB
+
+// DecodeErrorKind classifies JSON decoding failures
+type DecodeErrorKind string
+
+const (
+	DecodeErrorEmptyBody    DecodeErrorKind = "empty_body"
+	DecodeErrorTooLarge     DecodeErrorKind = "too_large"
+	DecodeErrorSyntax       DecodeErrorKind = "syntax"
+	DecodeErrorType         DecodeErrorKind = "type_mismatch"
+	DecodeErrorUnknownField DecodeErrorKind = "unknown_field"
+	DecodeErrorTrailingData DecodeErrorKind = "trailing_data"
+	DecodeErrorRead         DecodeErrorKind = "read"
+)
+
+// DecodeError describes why a request body could not be decoded.
+// Line and Column are 1-based positions in the body, zero when unknown.
+type DecodeError struct {
+	Kind   DecodeErrorKind
+	Status int
+	Field  string
+	Offset int64
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	msg := e.message()
+	if e.Line > 0 {
+		msg = fmt.Sprintf("%s (line %d, column %d)", msg, e.Line, e.Column)
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Problem converts the error into a problem details response
+func (e *DecodeError) Problem() Problem {
+	p := NewProblem(e.Status, "invalid request body")
+	p.Errors = []FieldProblem{{
+		Field:   e.Field,
+		Message: e.message(),
+		Line:    e.Line,
+		Column:  e.Column,
+	}}
+	return p
+}
+
+func (e *DecodeError) message() string {
+	switch e.Kind {
+	case DecodeErrorEmptyBody:
+		return "request body must not be empty"
+	case DecodeErrorTooLarge:
+		return "request body is too large"
+	case DecodeErrorSyntax:
+		return "request body contains malformed JSON"
+	case DecodeErrorType:
+		return fmt.Sprintf("field %q has invalid type", e.Field)
+	case DecodeErrorUnknownField:
+		return fmt.Sprintf("unknown field %q", e.Field)
+	case DecodeErrorTrailingData:
+		return "request body must contain a single JSON value"
+	default:
+		return "failed to read request body"
+	}
+}
+
+type decodeOptions struct {
+	maxBytes              int64
+	disallowUnknownFields bool
+	allowEmpty            bool
+}
+
+// DecodeOption configures JSON decoding
+type DecodeOption func(*decodeOptions)
+
+// WithMaxBytes limits the size of the request body
+func WithMaxBytes(n int64) DecodeOption {
+	return func(o *decodeOptions) {
+		o.maxBytes = n
+	}
+}
+
+// WithDisallowUnknownFields rejects bodies containing fields not present in the target type
+func WithDisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// WithAllowEmptyBody returns the zero value instead of an error for empty bodies
+func WithAllowEmptyBody() DecodeOption {
+	return func(o *decodeOptions) {
+		o.allowEmpty = true
+	}
+}
+
+// DecodeJSON decodes the request body into a value of type T.
+// All failures are reported as *DecodeError with a suggested HTTP status.
+func DecodeJSON[T any](r *http.Request, opts ...DecodeOption) (T, error) {
+	var v T
+
+	options := &decodeOptions{maxBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		if options.allowEmpty {
+			return v, nil
+		}
+		return v, &DecodeError{Kind: DecodeErrorEmptyBody, Status: http.StatusBadRequest}
+	}
+
+	// Read one byte more than allowed to detect oversized bodies without relying on Content-Length
+	body, err := io.ReadAll(io.LimitReader(r.Body, options.maxBytes+1))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		// The body is limited by http.MaxBytesReader, e.g. in server.MaxBodyBytesMiddleware
+		return v, &DecodeError{Kind: DecodeErrorTooLarge, Status: http.StatusRequestEntityTooLarge, Err: err}
+	}
+	if err != nil {
+		return v, &DecodeError{Kind: DecodeErrorRead, Status: http.StatusBadRequest, Err: err}
+	}
+	if int64(len(body)) > options.maxBytes {
+		return v, &DecodeError{Kind: DecodeErrorTooLarge, Status: http.StatusRequestEntityTooLarge}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if options.allowEmpty {
+			return v, nil
+		}
+		return v, &DecodeError{Kind: DecodeErrorEmptyBody, Status: http.StatusBadRequest}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if options.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(&v); err != nil {
+		return v, newDecodeError(body, err)
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return v, withPosition(&DecodeError{
+			Kind:   DecodeErrorTrailingData,
+			Status: http.StatusBadRequest,
+			Offset: dec.InputOffset(),
+		}, body)
+	}
+
+	return v, nil
+}
+
+// DecodeJSONOrProblem decodes the request body and, on failure, writes a
+// problem+json response. It returns false if the handler should stop.
+func DecodeJSONOrProblem[T any](w http.ResponseWriter, r *http.Request, opts ...DecodeOption) (T, bool) {
+	v, err := DecodeJSON[T](r, opts...)
+	if err != nil {
+		WriteError(w, err)
+		return v, false
+	}
+	return v, true
+}
+
+// newDecodeError maps encoding/json errors to DecodeError
+func newDecodeError(body []byte, err error) *DecodeError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return withPosition(&DecodeError{
+			Kind:   DecodeErrorSyntax,
+			Status: http.StatusBadRequest,
+			Offset: syntaxErr.Offset,
+			Err:    err,
+		}, body)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return withPosition(&DecodeError{
+			Kind:   DecodeErrorSyntax,
+			Status: http.StatusBadRequest,
+			Offset: int64(len(body)),
+			Err:    err,
+		}, body)
+	case errors.As(err, &typeErr):
+		return withPosition(&DecodeError{
+			Kind:   DecodeErrorType,
+			Status: http.StatusBadRequest,
+			Field:  typeErr.Field,
+			Offset: typeErr.Offset,
+			Err:    err,
+		}, body)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &DecodeError{
+			Kind:   DecodeErrorUnknownField,
+			Status: http.StatusBadRequest,
+			Field:  field,
+			Err:    err,
+		}
+	default:
+		return &DecodeError{Kind: DecodeErrorSyntax, Status: http.StatusBadRequest, Err: err}
+	}
+}
+
+// withPosition fills line and column from the byte offset
+func withPosition(e *DecodeError, body []byte) *DecodeError {
+	if e.Offset <= 0 || e.Offset > int64(len(body)) {
+		return e
+	}
+
+	before := body[:e.Offset]
+	e.Line = bytes.Count(before, []byte("\n")) + 1
+	e.Column = int(e.Offset) - (bytes.LastIndexByte(before, '\n') + 1)
+	return e
+}
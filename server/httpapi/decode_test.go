@@ -0,0 +1,130 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type createUserRequest struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		opts         []DecodeOption
+		expectedKind DecodeErrorKind
+		expectedLine int
+		expectedCol  int
+		field        string
+	}{
+		{
+			name: "Valid body",
+			body: `{"name":"john","age":42}`,
+		},
+		{
+			name:         "Empty body",
+			body:         "",
+			expectedKind: DecodeErrorEmptyBody,
+		},
+		{
+			name: "Empty body allowed",
+			body: "",
+			opts: []DecodeOption{WithAllowEmptyBody()},
+		},
+		{
+			name:         "Malformed JSON reports position",
+			body:         "{\n  \"name\": \"john\",\n  \"age\": 42,,\n}",
+			expectedKind: DecodeErrorSyntax,
+			expectedLine: 3,
+			expectedCol:  13,
+		},
+		{
+			name:         "Type mismatch reports field",
+			body:         `{"name":"john","age":"old"}`,
+			expectedKind: DecodeErrorType,
+			field:        "age",
+		},
+		{
+			name: "Unknown field allowed by default",
+			body: `{"name":"john","email":"john@example.com"}`,
+		},
+		{
+			name:         "Unknown field rejected in strict mode",
+			body:         `{"name":"john","email":"john@example.com"}`,
+			opts:         []DecodeOption{WithDisallowUnknownFields()},
+			expectedKind: DecodeErrorUnknownField,
+			field:        "email",
+		},
+		{
+			name:         "Trailing data",
+			body:         `{"name":"john"}{"name":"jane"}`,
+			expectedKind: DecodeErrorTrailingData,
+		},
+		{
+			name:         "Body too large",
+			body:         `{"name":"` + strings.Repeat("a", 64) + `"}`,
+			opts:         []DecodeOption{WithMaxBytes(32)},
+			expectedKind: DecodeErrorTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+
+			_, err := DecodeJSON[createUserRequest](r, tt.opts...)
+			if tt.expectedKind == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected *DecodeError, got %v", err)
+			}
+			if decodeErr.Kind != tt.expectedKind {
+				t.Errorf("expected kind %s, got %s", tt.expectedKind, decodeErr.Kind)
+			}
+			if tt.field != "" && decodeErr.Field != tt.field {
+				t.Errorf("expected field %q, got %q", tt.field, decodeErr.Field)
+			}
+			if tt.expectedLine != 0 && (decodeErr.Line != tt.expectedLine || decodeErr.Column != tt.expectedCol) {
+				t.Errorf("expected position %d:%d, got %d:%d", tt.expectedLine, tt.expectedCol, decodeErr.Line, decodeErr.Column)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONOrProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"age":"old"}`))
+	w := httptest.NewRecorder()
+
+	_, ok := DecodeJSONOrProblem[createUserRequest](w, r)
+	if ok {
+		t.Fatal("expected decoding to fail")
+	}
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ContentTypeProblemJSON {
+		t.Errorf("expected content type %s, got %s", ContentTypeProblemJSON, ct)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "age" {
+		t.Errorf("expected field problem for age, got %+v", p.Errors)
+	}
+}
@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ContentTypeProblemJSON is the media type for RFC 7807 problem details
+const ContentTypeProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details response
+type Problem struct {
+	Type     string         `json:"type,omitempty"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []FieldProblem `json:"errors,omitempty"`
+}
+
+// FieldProblem describes a problem with a single request field
+type FieldProblem struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// NewProblem creates a problem with the standard title for the status code
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// WriteProblem writes the problem as application/problem+json
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblemJSON)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// WriteError converts the error into a problem response.
+// Errors returned by this package keep their status code and field details,
+// any other error results in 500 Internal Server Error without exposing its message.
+func WriteError(w http.ResponseWriter, err error) {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		WriteProblem(w, decodeErr.Problem())
+		return
+	}
+
+	var bindErr *BindError
+	if errors.As(err, &bindErr) {
+		WriteProblem(w, bindErr.Problem())
+		return
+	}
+
+	WriteProblem(w, NewProblem(http.StatusInternalServerError, ""))
+}
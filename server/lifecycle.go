@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Starter is an interface for services that start background work, e.g. consumers or workers.
+// Start is called before the servers accept traffic and must not block.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is an interface for services that stop background work. Stop is called once
+// the servers have drained, before the shutdown hooks.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Register adds services to the ones served by Run. Services are registered with the servers,
+// started and checked for readiness in order, and stopped in reverse order.
+func (a *App) Register(services ...GRPCProvider) {
+	a.services = append(a.services, services...)
+}
+
+// startServices starts the services implementing Starter in order. On failure the services
+// already started are stopped.
+func (a *App) startServices(ctx context.Context) error {
+	for _, service := range a.services {
+		if starter, ok := service.(Starter); ok {
+			start := time.Now()
+			if err := starter.Start(ctx); err != nil {
+				a.options.logger.Error("failed to start service", "service", serviceName(service), "error", err)
+				a.stopServices(ctx)
+				return fmt.Errorf("start %s: %w", serviceName(service), err)
+			}
+			a.options.logger.Info("service started", "service", serviceName(service), "duration", time.Since(start))
+		}
+		a.started = append(a.started, service)
+	}
+	return nil
+}
+
+// stopServices stops the started services implementing Stopper in reverse order. A failed service
+// doesn't stop the others; all of them share the deadline of ctx.
+func (a *App) stopServices(ctx context.Context) {
+	started := a.started
+	a.started = nil
+
+	for i := len(started) - 1; i >= 0; i-- {
+		stopper, ok := started[i].(Stopper)
+		if !ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := stopper.Stop(ctx); err != nil {
+			a.options.logger.Error("failed to stop service", "service", serviceName(started[i]), "error", err)
+			continue
+		}
+		a.options.logger.Info("service stopped", "service", serviceName(started[i]), "duration", time.Since(start))
+	}
+}
+
+// serviceName identifies a service in logs by its type
+func serviceName(service GRPCProvider) string {
+	return fmt.Sprintf("%T", service)
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+type lifecycleService struct {
+	noopService
+	name     string
+	startErr error
+	order    *[]string
+}
+
+func (s *lifecycleService) Start(context.Context) error {
+	*s.order = append(*s.order, "start "+s.name)
+	return s.startErr
+}
+
+func (s *lifecycleService) Stop(context.Context) error {
+	*s.order = append(*s.order, "stop "+s.name)
+	return nil
+}
+
+func TestServiceLifecycle(t *testing.T) {
+	var order []string
+	app, err := NewApp(context.Background(),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithShutdownHook("db", func(context.Context) error {
+			order = append(order, "hook db")
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	errFailed := errors.New("failed")
+	app.Register(
+		&lifecycleService{name: "api", order: &order},
+		noopService{},
+		&lifecycleService{name: "workers", order: &order, startErr: errFailed},
+	)
+
+	// A failed service stops the ones already started
+	if err := app.startServices(context.Background()); !errors.Is(err, errFailed) {
+		t.Fatalf("startServices() error = %v, want %v", err, errFailed)
+	}
+	if want := []string{"start api", "start workers", "stop api"}; !slices.Equal(order, want) {
+		t.Errorf("lifecycle = %v, want %v", order, want)
+	}
+
+	order = nil
+	app.services[2].(*lifecycleService).startErr = nil
+	if err := app.startServices(context.Background()); err != nil {
+		t.Fatalf("startServices() error = %v", err)
+	}
+	app.Shutdown()
+	app.Shutdown()
+
+	want := []string{"start api", "start workers", "stop workers", "stop api", "hook db"}
+	if !slices.Equal(order, want) {
+		t.Errorf("lifecycle = %v, want %v", order, want)
+	}
+}
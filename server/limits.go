@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rshelekhov/golib/server/httpapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TimeoutMiddleware limits the time of every HTTP request to d: the request context is canceled and
+// reading the body fails after d, so slow clients can't hold a handler. Gateway requests reaching
+// the deadline fail with 504 Gateway Timeout like gRPC calls limited by TimeoutUnaryInterceptor.
+// Handlers must respect the context; streaming endpoints are cut after d too.
+func TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			// Not supported by every ResponseWriter, e.g. in tests; the context still limits the handler
+			_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(d))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// MaxBodyBytesMiddleware limits HTTP request bodies to n bytes. Requests declaring a larger
+// Content-Length are rejected with 413 Content Too Large; reading beyond n bytes of other requests
+// fails with *http.MaxBytesError. Use WithMaxRecvMsgSize to limit gRPC messages.
+func MaxBodyBytesMiddleware(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				httpapi.WriteProblem(w, httpapi.NewProblem(http.StatusRequestEntityTooLarge,
+					fmt.Sprintf("request body exceeds %d bytes", n)))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TimeoutUnaryInterceptor limits every unary call to d, or to the deadline of the client if it is
+// earlier. Calls failing after the deadline with a plain error, e.g. a wrapped context error,
+// return codes.DeadlineExceeded instead of codes.Unknown.
+func TimeoutUnaryInterceptor(d time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+
+		resp, err := handler(ctx, req)
+		return resp, deadlineError(ctx, err)
+	}
+}
+
+// TimeoutStreamInterceptor limits every stream to d, or to the deadline of the client if it is earlier.
+// Long-lived streams, e.g. subscriptions, should be served by a server without it.
+func TimeoutStreamInterceptor(d time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithTimeout(ss.Context(), d)
+		defer cancel()
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		return deadlineError(ctx, err)
+	}
+}
+
+// deadlineError replaces err with a DeadlineExceeded status if the deadline of ctx has passed
+// and err has no other status code
+func deadlineError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if status.Code(err) != codes.Unknown {
+		return err
+	}
+	return status.Error(codes.DeadlineExceeded, "request timed out")
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	handler := TimeoutMiddleware(time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+}
+
+func TestMaxBodyBytesMiddleware(t *testing.T) {
+	var readErr error
+	handler := MaxBodyBytesMiddleware(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large")))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	// Bodies of unknown length fail when read beyond the limit
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("too large")))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	var maxErr *http.MaxBytesError
+	if !errors.As(readErr, &maxErr) {
+		t.Errorf("read error = %v, want *http.MaxBytesError", readErr)
+	}
+}
+
+func TestTimeoutUnaryInterceptor(t *testing.T) {
+	interceptor := TimeoutUnaryInterceptor(time.Millisecond)
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.Orders/Get"}
+
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"wrapped context error", fmt.Errorf("query: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"status", status.Error(codes.Unavailable, "db down"), codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+				<-ctx.Done()
+				return nil, tt.err
+			})
+			if status.Code(err) != tt.want {
+				t.Errorf("code = %v, want %v", status.Code(err), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+)
+
+// unixAddrPrefix marks the addresses of Unix sockets passed to upgrader.listen
+const unixAddrPrefix = "unix:"
+
+// listeners returns the listeners of a server: the TCP port, if positive, the Unix sockets
+// and the pre-created listeners. Port and socket listeners are handed over by graceful restarts;
+// pre-created listeners belong to the caller.
+func (a *App) listeners(ctx context.Context, name string, port int, sockets []string, listeners []net.Listener) ([]net.Listener, error) {
+	var result []net.Listener
+	closeAll := func() {
+		for _, lis := range result {
+			_ = lis.Close()
+		}
+	}
+
+	if port > 0 {
+		lis, err := a.upgrader.listen(ctx, name, fmt.Sprintf(":%d", port))
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s port: %w", name, err)
+		}
+		result = append(result, lis)
+	}
+
+	for _, path := range sockets {
+		lis, err := a.upgrader.listen(ctx, name+"-"+unixAddrPrefix+path, unixAddrPrefix+path)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to listen on %s socket %s: %w", name, path, err)
+		}
+		result = append(result, lis)
+	}
+
+	return append(result, listeners...), nil
+}
+
+// listenUnix listens on the Unix socket at path, replacing the socket file left by a previous run.
+// The file is kept when the listener is closed, so a process taking the socket over
+// in a graceful restart keeps serving it.
+func listenUnix(ctx context.Context, path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	return lis, nil
+}
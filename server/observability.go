@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rshelekhov/golib/middleware/logging"
+	"github.com/rshelekhov/golib/middleware/requestid"
+	"github.com/rshelekhov/golib/observability"
+	"github.com/rshelekhov/golib/observability/metrics"
+	"github.com/rshelekhov/golib/observability/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+)
+
+// metricsPath is the path of the Prometheus metrics endpoint
+const metricsPath = "/metrics"
+
+// untracedPaths are the HTTP paths without traces and request metrics
+var untracedPaths = []string{"/healthz", "/readyz", metricsPath}
+
+// ObservabilityUnaryInterceptor injects obs into the context of every unary call
+func ObservabilityUnaryInterceptor(obs *observability.Observability) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(observability.Inject(ctx, obs), req)
+	}
+}
+
+// ObservabilityStreamInterceptor injects obs into the context of every stream
+func ObservabilityStreamInterceptor(obs *observability.Observability) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: observability.Inject(ss.Context(), obs)})
+	}
+}
+
+// ObservabilityMiddleware injects obs into the context of every HTTP request
+func ObservabilityMiddleware(obs *observability.Observability) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(observability.Inject(r.Context(), obs)))
+		})
+	}
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// installObservability prepends the observability interceptors and middleware to the configured ones
+func (o *Options) installObservability() {
+	obs := o.observability
+
+	if o.statsHandler == nil {
+		o.statsHandler = tracing.GRPCServerStatsHandler()
+	}
+
+	o.unaryInterceptors = append([]grpc.UnaryServerInterceptor{
+		ObservabilityUnaryInterceptor(obs),
+		requestid.UnaryServerInterceptorFunc(),
+		logging.UnaryServerInterceptor(o.logger),
+		metrics.UnaryServerInterceptor(),
+	}, o.unaryInterceptors...)
+	o.streamInterceptors = append([]grpc.StreamServerInterceptor{
+		ObservabilityStreamInterceptor(obs),
+		requestid.StreamServerInterceptorFunc(),
+		logging.StreamServerInterceptor(o.logger),
+		metrics.StreamServerInterceptor(),
+	}, o.streamInterceptors...)
+	o.httpMiddleware = append([]func(http.Handler) http.Handler{
+		httpTracingMiddleware,
+		func(next http.Handler) http.Handler {
+			return metrics.Middleware(next, metrics.WithExcludedPaths(untracedPaths...))
+		},
+		ObservabilityMiddleware(obs),
+		requestid.HTTPMiddleware(),
+		logging.Middleware(o.logger),
+	}, o.httpMiddleware...)
+}
+
+// httpTracingMiddleware starts a server span named after the method, renamed after the route
+// by gatewayRouteMiddleware once the gateway has matched it
+func httpTracingMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return r.Method }),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			for _, p := range untracedPaths {
+				if r.URL.Path == p {
+					return false
+				}
+			}
+			return true
+		}),
+	)
+}
+
+// gatewayRouteMiddleware names the server span and request metrics after the matched gateway route
+func gatewayRouteMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			tracing.SetRoute(r.Context(), r.Method, normalizePattern(pattern.String()))
+		}
+		next(w, r, pathParams)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rshelekhov/golib/middleware/requestid"
+	"github.com/rshelekhov/golib/observability"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithObservability(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	defer otel.SetTracerProvider(prev)
+
+	var logs bytes.Buffer
+	obs := &observability.Observability{
+		Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		MetricsHandler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("http_requests_total 1\n"))
+		}),
+	}
+	app, err := NewApp(context.Background(), WithHTTPPort(8080), WithObservability(obs))
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	if app.options.statsHandler == nil {
+		t.Error("gRPC tracing stats handler not installed")
+	}
+
+	var injected bool
+	var requestID string
+	err = app.mux.HandlePath(http.MethodGet, "/v1/orders/{id}", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		injected = observability.FromContext(r.Context()) == obs
+		requestID, _ = requestid.FromContext(r.Context())
+	})
+	if err != nil {
+		t.Fatalf("HandlePath() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/orders/1", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	rec := httptest.NewRecorder()
+	app.httpServer.Handler.ServeHTTP(rec, req)
+
+	if !injected {
+		t.Error("observability not injected into the request context")
+	}
+	if requestID != "req-1" {
+		t.Errorf("request ID = %q, want req-1", requestID)
+	}
+	if !strings.Contains(logs.String(), "/v1/orders/1") {
+		t.Errorf("request log = %q, want the request path", logs.String())
+	}
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Name() != "GET /v1/orders/{id}" {
+		var names []string
+		for _, s := range ended {
+			names = append(names, s.Name())
+		}
+		t.Errorf("spans = %v, want [GET /v1/orders/{id}]", names)
+	}
+
+	// Metrics are served without tracing the scrape
+	rec = httptest.NewRecorder()
+	app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "http_requests_total") {
+		t.Errorf("GET /metrics = %d %q", rec.Code, rec.Body.String())
+	}
+	if len(spans.Ended()) != 1 {
+		t.Errorf("spans after scrape = %d, want 1", len(spans.Ended()))
+	}
+}
@@ -1,14 +1,24 @@
 package server
 
 import (
+	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rshelekhov/golib/middleware/auth"
+	"github.com/rshelekhov/golib/middleware/ratelimit"
+	"github.com/rshelekhov/golib/middleware/recovery"
+	"github.com/rshelekhov/golib/middleware/validation"
+	"github.com/rshelekhov/golib/observability"
+	"github.com/rshelekhov/golib/observability/blackbox"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // Options holds all configuration for the application
@@ -17,19 +27,99 @@ type Options struct {
 	grpcPort         int
 	httpPort         int
 	enableReflection bool
+	enableChannelz   bool
+	singlePort       bool
+	grpcWeb          bool
 	shutdownTimeout  time.Duration
 
+	// Unix sockets and pre-created listeners served next to the ports
+	grpcSockets   []string
+	httpSockets   []string
+	grpcListeners []net.Listener
+	httpListeners []net.Listener
+
+	// Admin server with the operational endpoints, and the config it dumps
+	adminPort   int
+	adminConfig any
+
+	// Build info served at /version and by the gRPC version service
+	buildInfo *BuildInfo
+
+	// gRPC connection limits, zero values keep the gRPC defaults
+	keepaliveEnforcement *keepalive.EnforcementPolicy
+	keepaliveParams      *keepalive.ServerParameters
+	maxRecvMsgSize       int
+	maxSendMsgSize       int
+	maxConcurrentStreams uint32
+	grpcServerOptions    []grpc.ServerOption
+
+	// HTTP server timeouts, zero means no timeout
+	httpReadTimeout       time.Duration
+	httpReadHeaderTimeout time.Duration
+	httpWriteTimeout      time.Duration
+	httpIdleTimeout       time.Duration
+
 	// Middleware and interceptors
 	unaryInterceptors  []grpc.UnaryServerInterceptor
 	streamInterceptors []grpc.StreamServerInterceptor
 	muxOptions         []runtime.ServeMuxOption
 	httpMiddleware     []func(http.Handler) http.Handler
 
+	// HTTP handlers served next to the gateway
+	httpHandlers []httpHandler
+
+	// Rate limiting of gRPC calls and HTTP requests
+	rateLimiter *ratelimit.RateLimiter
+
+	// Authentication of gRPC calls and HTTP requests
+	authFunc    auth.Func
+	authOptions []auth.Option
+
+	// Validation of gRPC requests
+	validation        bool
+	validationOptions []validation.Option
+
+	// Recovery from panics in handlers
+	recovery        bool
+	recoveryOptions []recovery.Option
+
 	// Tracing
 	statsHandler stats.Handler
 
+	// Graceful restart
+	gracefulRestart bool
+	upgradeTimeout  time.Duration
+	reusePort       bool
+
 	// Logger
 	logger *slog.Logger
+
+	// Observability injected into request contexts
+	observability *observability.Observability
+
+	// Dependencies reported by the dependencies endpoint
+	dependencies []Dependency
+
+	// Warm-up hooks run before serving traffic
+	warmups []warmupHook
+
+	// Shutdown hooks run after the servers stop
+	shutdownHooks []shutdownHook
+
+	// Lifecycle callbacks and the delay between draining and stopping the servers
+	onStart    []lifecycleCallback
+	onStop     []lifecycleCallback
+	drainDelay time.Duration
+
+	// Transformers of grpc-gateway responses
+	responseTransformers []routeTransformer
+
+	// Recorder of recent requests
+	blackBox *blackbox.Recorder
+
+	// Health checks deciding readiness
+	healthChecks        []namedCheck
+	healthCheckInterval time.Duration
 }
 
 // Option is a function that modifies Options
@@ -38,21 +128,23 @@ type Option func(*Options)
 // defaultOptions returns the default configuration
 func defaultOptions() *Options {
 	return &Options{
-		grpcPort:           9000,
-		httpPort:           0,
-		enableReflection:   true,
-		shutdownTimeout:    time.Second * 10,
-		unaryInterceptors:  []grpc.UnaryServerInterceptor{},
-		streamInterceptors: []grpc.StreamServerInterceptor{},
-		muxOptions:         []runtime.ServeMuxOption{},
-		httpMiddleware:     []func(http.Handler) http.Handler{},
+		grpcPort:            9000,
+		httpPort:            0,
+		enableReflection:    true,
+		shutdownTimeout:     time.Second * 10,
+		upgradeTimeout:      time.Minute,
+		healthCheckInterval: DefaultHealthCheckInterval,
+		unaryInterceptors:   []grpc.UnaryServerInterceptor{},
+		streamInterceptors:  []grpc.StreamServerInterceptor{},
+		muxOptions:          []runtime.ServeMuxOption{},
+		httpMiddleware:      []func(http.Handler) http.Handler{},
 		logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
 		})),
 	}
 }
 
-// WithGRPCPort sets the gRPC server port
+// WithGRPCPort sets the gRPC server port; zero serves gRPC only on the listeners and sockets
 func WithGRPCPort(port int) Option {
 	return func(o *Options) {
 		o.grpcPort = port
@@ -66,13 +158,107 @@ func WithHTTPPort(port int) Option {
 	}
 }
 
-// WithReflection enables/disables gRPC reflection
+// WithGRPCListener serves gRPC on l next to the gRPC port, e.g. a listener of systemd socket activation
+// or a bufconn listener of an in-process test. It can be repeated; with WithGRPCPort(0) only the
+// listeners are served. The server closes l on shutdown, but graceful restarts don't hand it over.
+func WithGRPCListener(l net.Listener) Option {
+	return func(o *Options) {
+		o.grpcListeners = append(o.grpcListeners, l)
+	}
+}
+
+// WithHTTPListener serves the HTTP server on l next to the HTTP port, enabling the HTTP server
+// without a port. It can be repeated and behaves like WithGRPCListener.
+func WithHTTPListener(l net.Listener) Option {
+	return func(o *Options) {
+		o.httpListeners = append(o.httpListeners, l)
+	}
+}
+
+// WithGRPCUnixSocket serves gRPC on the Unix socket at path next to the gRPC port, e.g. for a sidecar.
+// A socket file left by a previous run is replaced. It can be repeated.
+func WithGRPCUnixSocket(path string) Option {
+	return func(o *Options) {
+		o.grpcSockets = append(o.grpcSockets, path)
+	}
+}
+
+// WithHTTPUnixSocket serves the HTTP server on the Unix socket at path next to the HTTP port,
+// enabling the HTTP server without a port. It can be repeated.
+func WithHTTPUnixSocket(path string) Option {
+	return func(o *Options) {
+		o.httpSockets = append(o.httpSockets, path)
+	}
+}
+
+// WithReflection enables/disables gRPC reflection (default: enabled), used by tools like grpcurl.
+// Reflection exposes the whole API schema, so disable it in production builds with config, e.g.
+// server.WithReflection(cfg.Env != "prod").
 func WithReflection(enable bool) Option {
 	return func(o *Options) {
 		o.enableReflection = enable
 	}
 }
 
+// WithChannelz enables/disables the grpc.channelz.v1 service (default: disabled),
+// which reports runtime state of channels, servers and sockets for debugging
+func WithChannelz(enable bool) Option {
+	return func(o *Options) {
+		o.enableChannelz = enable
+	}
+}
+
+// WithSinglePort serves gRPC and the HTTP handlers on the HTTP port, for platforms that expose
+// one port per service, e.g. Cloud Run. Requests are routed by content type: HTTP/2 requests with
+// application/grpc go to the gRPC server, everything else to the HTTP mux. The gRPC port is not used.
+// gRPC clients must connect with HTTP/2 without TLS (h2c) or through a TLS-terminating proxy.
+func WithSinglePort(enable bool) Option {
+	return func(o *Options) {
+		o.singlePort = enable
+	}
+}
+
+// WithAdminPort serves the operational endpoints on an internal port: the probes, /metrics,
+// pprof under /debug/pprof/, build info at /debug/buildinfo, the config dump at /debug/config,
+// and the dependencies and black box endpoints if configured. Metrics and the debug endpoints
+// are then not served on the HTTP port; the probes are served on both.
+// The port must not be reachable from outside the cluster.
+func WithAdminPort(port int) Option {
+	return func(o *Options) {
+		o.adminPort = port
+	}
+}
+
+// WithBuildInfo serves the service name, version, git revision and build time of info at /version
+// and by the golib.server.Version gRPC service, so operators can verify what's deployed.
+// Empty fields are filled from the build info embedded in the binary; values are usually
+// injected at build time, e.g. with -ldflags "-X main.version=v1.2.3 -X main.commit=$(git rev-parse HEAD)".
+// The admin port serves the same info at /debug/buildinfo.
+func WithBuildInfo(info BuildInfo) Option {
+	return func(o *Options) {
+		info = info.withDefaults(ReadBuildInfo())
+		o.buildInfo = &info
+	}
+}
+
+// WithAdminConfig sets the application config dumped at /debug/config of the admin port next to
+// the server options. Values of keys that look sensitive, e.g. "password" or "api_key", are redacted.
+func WithAdminConfig(cfg any) Option {
+	return func(o *Options) {
+		o.adminConfig = cfg
+	}
+}
+
+// WithGRPCWeb serves gRPC-Web calls on the HTTP port, so browser clients can call the services
+// without a proxy such as Envoy. Binary (application/grpc-web) and text (application/grpc-web-text)
+// calls are served over HTTP/1.1 and HTTP/2, going through the HTTP middleware and the gRPC interceptors.
+// Cross-origin clients need CORS middleware allowing the gRPC-Web headers.
+func WithGRPCWeb(enable bool) Option {
+	return func(o *Options) {
+		o.grpcWeb = enable
+	}
+}
+
 // WithShutdownTimeout sets the timeout for graceful shutdown
 func WithShutdownTimeout(timeout time.Duration) Option {
 	return func(o *Options) {
@@ -80,6 +266,101 @@ func WithShutdownTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithKeepaliveEnforcementPolicy sets how often clients may send keepalive pings.
+// Clients pinging more often than policy.MinTime (default: 5m) are disconnected with ENHANCE_YOUR_CALM,
+// so lower it when clients keep idle connections alive through load balancers.
+func WithKeepaliveEnforcementPolicy(policy keepalive.EnforcementPolicy) Option {
+	return func(o *Options) {
+		o.keepaliveEnforcement = &policy
+	}
+}
+
+// WithKeepaliveParams sets the server keepalive pings and connection ages, e.g. MaxConnectionAge
+// to make clients reconnect periodically and spread over new replicas
+func WithKeepaliveParams(params keepalive.ServerParameters) Option {
+	return func(o *Options) {
+		o.keepaliveParams = &params
+	}
+}
+
+// WithMaxRecvMsgSize sets the maximum size of a received gRPC message in bytes (default: 4MB)
+func WithMaxRecvMsgSize(size int) Option {
+	return func(o *Options) {
+		o.maxRecvMsgSize = size
+	}
+}
+
+// WithMaxSendMsgSize sets the maximum size of a sent gRPC message in bytes (default: unlimited)
+func WithMaxSendMsgSize(size int) Option {
+	return func(o *Options) {
+		o.maxSendMsgSize = size
+	}
+}
+
+// WithMaxConcurrentStreams limits the number of concurrent streams, and so calls, of each client connection
+func WithMaxConcurrentStreams(n uint32) Option {
+	return func(o *Options) {
+		o.maxConcurrentStreams = n
+	}
+}
+
+// WithGRPCServerOptions adds gRPC server options not covered by the other options
+func WithGRPCServerOptions(opts ...grpc.ServerOption) Option {
+	return func(o *Options) {
+		o.grpcServerOptions = append(o.grpcServerOptions, opts...)
+	}
+}
+
+// WithHTTPReadTimeout sets the maximum duration for reading an entire HTTP request, including the body
+func WithHTTPReadTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.httpReadTimeout = timeout
+	}
+}
+
+// WithHTTPReadHeaderTimeout sets the maximum duration for reading HTTP request headers
+func WithHTTPReadHeaderTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.httpReadHeaderTimeout = timeout
+	}
+}
+
+// WithHTTPWriteTimeout sets the maximum duration from the end of reading request headers to the end of writing
+// the response. It also cuts gateway server streams, so keep it off for streaming endpoints.
+func WithHTTPWriteTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.httpWriteTimeout = timeout
+	}
+}
+
+// WithHTTPIdleTimeout sets how long idle keep-alive HTTP connections are kept open (default: the read timeout)
+func WithHTTPIdleTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.httpIdleTimeout = timeout
+	}
+}
+
+// grpcServerOpts returns the gRPC server options of the connection settings
+func (o *Options) grpcServerOpts() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if o.keepaliveEnforcement != nil {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(*o.keepaliveEnforcement))
+	}
+	if o.keepaliveParams != nil {
+		opts = append(opts, grpc.KeepaliveParams(*o.keepaliveParams))
+	}
+	if o.maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(o.maxRecvMsgSize))
+	}
+	if o.maxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(o.maxSendMsgSize))
+	}
+	if o.maxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(o.maxConcurrentStreams))
+	}
+	return append(opts, o.grpcServerOptions...)
+}
+
 // WithUnaryInterceptors adds gRPC unary interceptors
 func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
 	return func(o *Options) {
@@ -101,6 +382,41 @@ func WithMuxOptions(options ...runtime.ServeMuxOption) Option {
 	}
 }
 
+// WithGatewayJSON sets how grpc-gateway marshals and unmarshals JSON, e.g. EmitUnpopulated to
+// write fields with default values or UseProtoNames for snake_case field names.
+// google.api.HttpBody responses are still written as is.
+func WithGatewayJSON(marshal protojson.MarshalOptions, unmarshal protojson.UnmarshalOptions) Option {
+	return func(o *Options) {
+		o.muxOptions = append(o.muxOptions, runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{MarshalOptions: marshal, UnmarshalOptions: unmarshal},
+		}))
+	}
+}
+
+// WithIncomingHeaderMatcher sets which HTTP headers grpc-gateway forwards to gRPC metadata,
+// e.g. ForwardHeaders("X-Request-ID") (default: runtime.DefaultHeaderMatcher)
+func WithIncomingHeaderMatcher(matcher runtime.HeaderMatcherFunc) Option {
+	return func(o *Options) {
+		o.muxOptions = append(o.muxOptions, runtime.WithIncomingHeaderMatcher(matcher))
+	}
+}
+
+// WithOutgoingHeaderMatcher sets which gRPC response metadata grpc-gateway writes as HTTP headers
+// (default: all, prefixed with Grpc-Metadata-)
+func WithOutgoingHeaderMatcher(matcher runtime.HeaderMatcherFunc) Option {
+	return func(o *Options) {
+		o.muxOptions = append(o.muxOptions, runtime.WithOutgoingHeaderMatcher(matcher))
+	}
+}
+
+// WithGatewayErrorHandler sets how grpc-gateway writes errors, e.g. ErrorEnvelopeHandler
+// (default: runtime.DefaultHTTPErrorHandler)
+func WithGatewayErrorHandler(handler runtime.ErrorHandlerFunc) Option {
+	return func(o *Options) {
+		o.muxOptions = append(o.muxOptions, runtime.WithErrorHandler(handler))
+	}
+}
+
 // WithHTTPMiddleware adds HTTP middleware
 func WithHTTPMiddleware(middleware ...func(http.Handler) http.Handler) Option {
 	return func(o *Options) {
@@ -108,6 +424,53 @@ func WithHTTPMiddleware(middleware ...func(http.Handler) http.Handler) Option {
 	}
 }
 
+// WithHTTPHandler serves handler at pattern on the HTTP server next to the gateway, e.g. "/debug/pprof/"
+// for a prefix, "/openapi.json" for a path or "POST /webhooks/stripe" for a method and path, following
+// the http.ServeMux pattern syntax. The handlers go through the HTTP middleware like gateway requests.
+func WithHTTPHandler(pattern string, handler http.Handler) Option {
+	return func(o *Options) {
+		o.httpHandlers = append(o.httpHandlers, httpHandler{pattern: pattern, handler: handler})
+	}
+}
+
+// WithRateLimit limits gRPC calls per method and HTTP requests per route, gateway routes
+// and WithHTTPHandler patterns, with rl. Health checks, metrics and reflection are not limited.
+func WithRateLimit(rl *ratelimit.RateLimiter) Option {
+	return func(o *Options) {
+		o.rateLimiter = rl
+	}
+}
+
+// WithAuthFunc authenticates gRPC calls and HTTP requests with fn, e.g. auth.JWT or auth.APIKeys.
+// Health checks, reflection, the probes and /metrics are exempt; options add exempt methods and paths.
+func WithAuthFunc(fn auth.Func, opts ...auth.Option) Option {
+	return func(o *Options) {
+		o.authFunc = fn
+		o.authOptions = opts
+	}
+}
+
+// WithValidation validates gRPC requests, and gateway requests forwarded to the gRPC server, with the methods
+// generated by protoc-gen-validate and the function set by validation.WithValidateFunc, e.g. protovalidate.
+// Invalid requests fail with InvalidArgument and the field violations before reaching the handlers.
+func WithValidation(opts ...validation.Option) Option {
+	return func(o *Options) {
+		o.validation = true
+		o.validationOptions = opts
+	}
+}
+
+// WithRecovery recovers from panics in gRPC handlers, HTTP handlers and the interceptors and middleware
+// after observability, answering with Internal and 500. Panics are logged with their stack, recorded
+// on the span of the request with tracing.RecordError and counted by panics_recovered_total;
+// recovery.WithRepanic(true) crashes instead in development.
+func WithRecovery(opts ...recovery.Option) Option {
+	return func(o *Options) {
+		o.recovery = true
+		o.recoveryOptions = opts
+	}
+}
+
 // WithLogger sets the logger
 func WithLogger(logger *slog.Logger) Option {
 	return func(o *Options) {
@@ -115,6 +478,120 @@ func WithLogger(logger *slog.Logger) Option {
 	}
 }
 
+// WithObservability wires obs into both servers before all other interceptors and middleware:
+// it injects obs into every request context (see observability.FromContext), traces gRPC calls
+// and HTTP requests, records request metrics, propagates request IDs, logs requests with obs.Logger,
+// which also becomes the server logger, and serves obs.MetricsHandler at /metrics.
+// A stats handler set with WithStatsHandler replaces the tracing one.
+func WithObservability(obs *observability.Observability) Option {
+	return func(o *Options) {
+		o.observability = obs
+		if obs != nil && obs.Logger != nil {
+			o.logger = obs.Logger
+		}
+	}
+}
+
+// WithDependencies exposes the status of dependencies at /debug/dependencies (with the HTTP server)
+// and through the golib.server.Dependencies gRPC service
+func WithDependencies(deps ...Dependency) Option {
+	return func(o *Options) {
+		o.dependencies = append(o.dependencies, deps...)
+	}
+}
+
+// WithWarmup adds a hook run before the servers accept traffic, e.g. to prime caches
+// or prepare statements. Hooks run in the order they are added, each limited by timeout
+// (DefaultWarmupTimeout if zero). The service reports ready only after all hooks succeed;
+// Run returns the error of a failed hook.
+func WithWarmup(name string, timeout time.Duration, fn func(ctx context.Context) error) Option {
+	if timeout <= 0 {
+		timeout = DefaultWarmupTimeout
+	}
+	return func(o *Options) {
+		o.warmups = append(o.warmups, warmupHook{name: name, timeout: timeout, fn: fn})
+	}
+}
+
+// WithResponseTransformer adds a transformer applied to grpc-gateway responses of a route after
+// marshaling, e.g. Envelope, Deprecation or StripFields. The route is "*" for all routes or an
+// optional method followed by the path template of the google.api.http annotation:
+// "GET /v1/users/{id}", "/v1/users/{id}" or "/v1/admin/*" for all templates with a prefix.
+// Transformers run in the order they are added.
+func WithResponseTransformer(route string, transformer ResponseTransformer) Option {
+	return func(o *Options) {
+		o.responseTransformers = append(o.responseTransformers, newRouteTransformer(route, transformer))
+	}
+}
+
+// WithBlackBox records the gRPC calls and gateway requests in rec, dumping it when a handler panics,
+// and serves it at /debug/blackbox (with the HTTP server): GET returns the recorded requests and
+// POST dumps them to the recorder's sinks. The interceptors run after those added with
+// WithUnaryInterceptors and WithStreamInterceptors, so recovery and tracing interceptors see its panics
+// and it sees their spans.
+func WithBlackBox(rec *blackbox.Recorder) Option {
+	return func(o *Options) {
+		o.blackBox = rec
+	}
+}
+
+// WithHealthCheck adds a check deciding readiness, e.g. a database ping. Failing checks make
+// /readyz respond with 503 and set the grpc.health.v1 status of the server and of the service
+// named after the check to NOT_SERVING. Checks run on every /readyz request and every
+// health check interval for the gRPC health service.
+func WithHealthCheck(name string, check func(ctx context.Context) error) Option {
+	return func(o *Options) {
+		o.healthChecks = append(o.healthChecks, namedCheck{name: name, check: HealthCheckFunc(check)})
+	}
+}
+
+// WithHealthCheckInterval sets how often health checks update the gRPC health status
+// (default: DefaultHealthCheckInterval)
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		if interval > 0 {
+			o.healthCheckInterval = interval
+		}
+	}
+}
+
+// WithShutdownHook adds a hook run after the servers have stopped, e.g. to close database pools,
+// stop message consumers or flush observability providers. Hooks run once, in the order they are
+// added, within what is left of the shutdown timeout; a failed hook is logged and doesn't stop the rest.
+func WithShutdownHook(name string, fn func(ctx context.Context) error) Option {
+	return func(o *Options) {
+		o.shutdownHooks = append(o.shutdownHooks, shutdownHook{name: name, fn: fn})
+	}
+}
+
+// WithOnStart adds a callback called once the servers listen, e.g. to register the instance
+// in service discovery. Callbacks run in order; an error stops the application and is returned by Run.
+func WithOnStart(fn func(ctx context.Context) error) Option {
+	return func(o *Options) {
+		o.onStart = append(o.onStart, fn)
+	}
+}
+
+// WithOnStop adds a callback called when shutdown begins, before draining, e.g. to deregister
+// the instance from service discovery. Callbacks run in order within the shutdown timeout;
+// errors are logged.
+func WithOnStop(fn func(ctx context.Context) error) Option {
+	return func(o *Options) {
+		o.onStop = append(o.onStop, fn)
+	}
+}
+
+// WithDrainDelay sets how long shutdown keeps serving in the draining state before stopping
+// the servers, so Kubernetes removes the pod from the endpoints and load balancers stop routing
+// traffic to it first. It isn't part of the shutdown timeout and is skipped by graceful restarts,
+// where the new process takes the traffic over. Keep it below terminationGracePeriodSeconds
+// minus the shutdown timeout.
+func WithDrainDelay(d time.Duration) Option {
+	return func(o *Options) {
+		o.drainDelay = d
+	}
+}
+
 // WithStatsHandler sets the stats handler
 func WithStatsHandler(statsHandler stats.Handler) Option {
 	return func(o *Options) {
@@ -122,6 +599,40 @@ func WithStatsHandler(statsHandler stats.Handler) Option {
 	}
 }
 
+// WithGracefulRestart enables zero-downtime binary upgrades on SIGHUP.
+// The new binary inherits the listening sockets and the old process
+// drains and exits once the new one reports readiness.
+func WithGracefulRestart(enable bool) Option {
+	return func(o *Options) {
+		o.gracefulRestart = enable
+	}
+}
+
+// WithUpgradeTimeout sets how long to wait for the new process to become ready (default: 1m)
+func WithUpgradeTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.upgradeTimeout = timeout
+	}
+}
+
+// WithReusePort enables SO_REUSEPORT on listeners so that several processes
+// can accept connections on the same port (Unix only)
+func WithReusePort(enable bool) Option {
+	return func(o *Options) {
+		o.reusePort = enable
+	}
+}
+
+// grpcEnabled reports whether the gRPC server has a port, a socket or a listener to serve
+func (o *Options) grpcEnabled() bool {
+	return o.grpcPort > 0 || len(o.grpcSockets) > 0 || len(o.grpcListeners) > 0
+}
+
+// httpEnabled reports whether the HTTP server has a port, a socket or a listener to serve
+func (o *Options) httpEnabled() bool {
+	return o.httpPort > 0 || len(o.httpSockets) > 0 || len(o.httpListeners) > 0
+}
+
 // wrapHTTPHandler applies all registered HTTP middleware to the handler
 func (o *Options) wrapHTTPHandler(handler http.Handler) http.Handler {
 	// Apply middleware in reverse order (last added is outermost)
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+func TestDebugServices(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		reflection bool
+		channelz   bool
+	}{
+		{name: "defaults", reflection: true},
+		{name: "production", opts: []Option{WithReflection(false)}},
+		{name: "debug", opts: []Option{WithChannelz(true)}, reflection: true, channelz: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))}, tt.opts...)
+			app, err := NewApp(context.Background(), opts...)
+			if err != nil {
+				t.Fatalf("NewApp() error = %v", err)
+			}
+
+			services := app.grpcServer.GetServiceInfo()
+			if _, ok := services["grpc.reflection.v1.ServerReflection"]; ok != tt.reflection {
+				t.Errorf("reflection registered = %v, want %v", ok, tt.reflection)
+			}
+			if _, ok := services["grpc.channelz.v1.Channelz"]; ok != tt.channelz {
+				t.Errorf("channelz registered = %v, want %v", ok, tt.channelz)
+			}
+		})
+	}
+}
+
+func TestConnectionLimits(t *testing.T) {
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithKeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: 10 * time.Second, PermitWithoutStream: true}),
+		WithKeepaliveParams(keepalive.ServerParameters{MaxConnectionAge: time.Hour}),
+		WithMaxRecvMsgSize(64),
+		WithMaxConcurrentStreams(100),
+		WithHTTPReadTimeout(5*time.Second),
+		WithHTTPReadHeaderTimeout(time.Second),
+		WithHTTPWriteTimeout(10*time.Second),
+		WithHTTPIdleTimeout(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	srv := app.httpServer
+	if srv.ReadTimeout != 5*time.Second || srv.ReadHeaderTimeout != time.Second ||
+		srv.WriteTimeout != 10*time.Second || srv.IdleTimeout != time.Minute {
+		t.Errorf("HTTP timeouts = %v/%v/%v/%v, want 5s/1s/10s/1m",
+			srv.ReadTimeout, srv.ReadHeaderTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go func() { _ = app.grpcServer.Serve(lis) }()
+	defer app.grpcServer.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 100)})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Check() with oversized message error = %v, want ResourceExhausted", err)
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rshelekhov/golib/middleware/ratelimit"
+	"google.golang.org/grpc"
+)
+
+// installRateLimit prepends the rate limiting interceptors to the configured ones
+func (o *Options) installRateLimit() {
+	o.unaryInterceptors = append([]grpc.UnaryServerInterceptor{
+		o.rateLimiter.UnaryServerInterceptor(),
+	}, o.unaryInterceptors...)
+	o.streamInterceptors = append([]grpc.StreamServerInterceptor{
+		o.rateLimiter.StreamServerInterceptor(),
+	}, o.streamInterceptors...)
+}
+
+// gatewayRateLimitMiddleware limits gateway requests per route, e.g. "GET /v1/orders/{id}",
+// as http.ServeMux only matches the catch-all pattern of the gateway
+func gatewayRateLimitMiddleware(rl *ratelimit.RateLimiter) runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+				r.Pattern = r.Method + " " + normalizePattern(pattern.String())
+			}
+			rl.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next(w, r, pathParams)
+			})).ServeHTTP(w, r)
+		}
+	}
+}
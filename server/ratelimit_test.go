@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rshelekhov/golib/middleware/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithRateLimit(ratelimit.New(ratelimit.NewMemoryLimiter(), ratelimit.Limit{Rate: 1, Burst: 1})),
+		WithHTTPHandler("/webhooks/", http.NotFoundHandler()),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	noop := func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {}
+	if err := app.mux.HandlePath(http.MethodGet, "/v1/orders/{id}", noop); err != nil {
+		t.Fatalf("HandlePath() error = %v", err)
+	}
+
+	// Requests are limited per route, not per path; probes are not limited
+	for _, tt := range []struct {
+		path string
+		want int
+	}{
+		{"/v1/orders/1", http.StatusOK},
+		{"/v1/orders/2", http.StatusTooManyRequests},
+		{"/webhooks/stripe", http.StatusNotFound},
+		{"/webhooks/github", http.StatusTooManyRequests},
+		{"/healthz", http.StatusOK},
+		{"/healthz", http.StatusOK},
+	} {
+		rec := httptest.NewRecorder()
+		app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+		}
+	}
+}
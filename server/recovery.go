@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/rshelekhov/golib/middleware/recovery"
+	"github.com/rshelekhov/golib/observability/tracing"
+	"google.golang.org/grpc"
+)
+
+// installRecovery prepends the recovery interceptors and middleware to the configured ones
+func (o *Options) installRecovery() {
+	opts := append([]recovery.Option{recovery.WithRecordError(tracing.RecordError)}, o.recoveryOptions...)
+
+	o.unaryInterceptors = append([]grpc.UnaryServerInterceptor{
+		recovery.UnaryServerInterceptor(o.logger, opts...),
+	}, o.unaryInterceptors...)
+	o.streamInterceptors = append([]grpc.StreamServerInterceptor{
+		recovery.StreamServerInterceptor(o.logger, opts...),
+	}, o.streamInterceptors...)
+	o.httpMiddleware = append([]func(http.Handler) http.Handler{
+		recovery.Middleware(o.logger, opts...),
+	}, o.httpMiddleware...)
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithRecovery(t *testing.T) {
+	var logs bytes.Buffer
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithRecovery(),
+		WithHTTPHandler("/panic", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("GET /panic = %d, want 500", rec.Code)
+	}
+	if !strings.Contains(logs.String(), "http server panic recovered") {
+		t.Errorf("panic not logged: %s", logs.String())
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// shutdownHook is a named function releasing a resource after the servers stop
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// runShutdownHooks runs the shutdown hooks in registration order. A failed hook doesn't stop
+// the others; all of them share the deadline of ctx.
+func (a *App) runShutdownHooks(ctx context.Context) {
+	total := len(a.options.shutdownHooks)
+	if total == 0 {
+		return
+	}
+
+	start := time.Now()
+	a.options.logger.Info("running shutdown hooks", "hooks", total)
+
+	for i, hook := range a.options.shutdownHooks {
+		logger := a.options.logger.With("hook", hook.name, "step", fmt.Sprintf("%d/%d", i+1, total))
+
+		hookStart := time.Now()
+		if err := hook.fn(ctx); err != nil {
+			logger.Error("shutdown hook failed", "error", err, "duration", time.Since(hookStart))
+			continue
+		}
+		logger.Info("shutdown hook completed", "duration", time.Since(hookStart))
+	}
+
+	a.options.logger.Info("shutdown hooks completed", "duration", time.Since(start))
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+func TestShutdownHooks(t *testing.T) {
+	var order []string
+	hook := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Errorf("hook %s has no deadline", name)
+			}
+			order = append(order, name)
+			return err
+		}
+	}
+
+	app, err := NewApp(context.Background(),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithShutdownHook("consumers", hook("consumers", nil)),
+		WithShutdownHook("db", hook("db", errors.New("close failed"))),
+		WithShutdownHook("observability", hook("observability", nil)),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	app.Shutdown()
+	app.Shutdown()
+
+	if want := []string{"consumers", "db", "observability"}; !slices.Equal(order, want) {
+		t.Errorf("hooks run = %v, want %v", order, want)
+	}
+}
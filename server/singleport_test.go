@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type noopService struct{}
+
+func (noopService) RegisterGRPC(*grpc.Server) {}
+
+func TestSinglePort(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	port := lis.Addr().(*net.TCPAddr).Port
+	_ = lis.Close()
+
+	app, err := NewApp(context.Background(),
+		WithHTTPPort(port),
+		WithSinglePort(true),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, noopService{}) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	// Wait for the server to start
+	var resp *healthpb.HealthCheckResponse
+	for i := 0; i < 50; i++ {
+		callCtx, callCancel := context.WithTimeout(ctx, time.Second)
+		resp, err = healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		callCancel()
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("gRPC health check error = %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("gRPC status = %v, want SERVING", resp.Status)
+	}
+
+	httpResp, err := http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz error = %v", err)
+	}
+	_ = httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		t.Errorf("GET /healthz = %d, want 200", httpResp.StatusCode)
+	}
+}
+
+func TestSinglePortRequiresHTTPPort(t *testing.T) {
+	if _, err := NewApp(context.Background(), WithSinglePort(true)); err == nil {
+		t.Error("NewApp() error = nil, want error without HTTP port")
+	}
+}
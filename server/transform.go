@@ -0,0 +1,227 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// GatewayResponse is a grpc-gateway response after marshaling, passed to ResponseTransformers
+type GatewayResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// ResponseTransformer mutates a gateway response before it is written to the client
+type ResponseTransformer func(r *http.Request, resp *GatewayResponse) error
+
+// routeTransformer is a transformer registered for a route
+type routeTransformer struct {
+	method    string
+	pattern   string
+	prefix    bool
+	transform ResponseTransformer
+}
+
+// newRouteTransformer parses a route: "*" for all routes, or an optional method followed by
+// the path template of the google.api.http annotation, e.g. "GET /v1/users/{id}".
+// A template ending with "*" matches all templates with that prefix, e.g. "/v1/admin/*".
+func newRouteTransformer(route string, t ResponseTransformer) routeTransformer {
+	rt := routeTransformer{transform: t}
+	if method, pattern, ok := strings.Cut(route, " "); ok {
+		rt.method = method
+		route = strings.TrimSpace(pattern)
+	}
+	rt.pattern, rt.prefix = strings.CutSuffix(normalizePattern(route), "*")
+	return rt
+}
+
+// singleSegmentVar matches variables capturing a single path segment, e.g. "{id=*}"
+var singleSegmentVar = regexp.MustCompile(`\{([^=}]+)=\*\}`)
+
+// normalizePattern writes single segment variables the short way, "{id}", as in annotations.
+// Gateway patterns render them as "{id=*}".
+func normalizePattern(pattern string) string {
+	return singleSegmentVar.ReplaceAllString(pattern, "{$1}")
+}
+
+func (rt routeTransformer) matches(method, pattern string) bool {
+	if rt.method != "" && rt.method != method {
+		return false
+	}
+	if rt.prefix {
+		return strings.HasPrefix(pattern, rt.pattern)
+	}
+	return rt.pattern == pattern
+}
+
+// responseTransformMiddleware applies the transformers matching the route of a gateway request.
+// Responses of matching routes are buffered, so transformers should not be registered for
+// server streaming methods.
+func responseTransformMiddleware(transformers []routeTransformer, logger *slog.Logger) runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			var pattern string
+			if p, ok := runtime.HTTPPattern(r.Context()); ok {
+				pattern = normalizePattern(p.String())
+			}
+
+			var matched []ResponseTransformer
+			for _, rt := range transformers {
+				if rt.matches(r.Method, pattern) {
+					matched = append(matched, rt.transform)
+				}
+			}
+			if len(matched) == 0 {
+				next(w, r, pathParams)
+				return
+			}
+
+			buf := &bufferedResponseWriter{header: w.Header(), status: http.StatusOK}
+			next(buf, r, pathParams)
+
+			resp := &GatewayResponse{StatusCode: buf.status, Header: w.Header(), Body: buf.body.Bytes()}
+			for _, transform := range matched {
+				if err := transform(r, resp); err != nil {
+					logger.ErrorContext(r.Context(), "failed to transform gateway response", "route", pattern, "error", err)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+			}
+
+			w.Header().Set("Content-Length", strconv.Itoa(len(resp.Body)))
+			w.WriteHeader(resp.StatusCode)
+			_, _ = w.Write(resp.Body)
+		}
+	}
+}
+
+// bufferedResponseWriter keeps the status and body in memory. Headers go to the underlying writer's map.
+type bufferedResponseWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
+
+// Flush is a no-op: the body is written after the transformers run
+func (w *bufferedResponseWriter) Flush() {}
+
+// Envelope wraps JSON bodies in a standard envelope: successful responses as {"data": ...}
+// and errors as {"error": ...}
+func Envelope() ResponseTransformer {
+	return func(_ *http.Request, resp *GatewayResponse) error {
+		if !isJSON(resp.Header) {
+			return nil
+		}
+		key := "data"
+		if resp.StatusCode >= http.StatusBadRequest {
+			key = "error"
+		}
+		body := json.RawMessage(resp.Body)
+		if len(bytes.TrimSpace(body)) == 0 {
+			body = json.RawMessage("null")
+		}
+
+		wrapped, err := json.Marshal(map[string]json.RawMessage{key: body})
+		if err != nil {
+			return fmt.Errorf("failed to wrap response: %w", err)
+		}
+		resp.Body = wrapped
+		return nil
+	}
+}
+
+// Deprecation marks responses as deprecated with the Deprecation and Sunset headers
+// and a Link to the migration guide. Zero sunset and empty link are omitted.
+func Deprecation(sunset time.Time, link string) ResponseTransformer {
+	return func(_ *http.Request, resp *GatewayResponse) error {
+		resp.Header.Set("Deprecation", "true")
+		if !sunset.IsZero() {
+			resp.Header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if link != "" {
+			resp.Header.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", link))
+		}
+		return nil
+	}
+}
+
+// StripFields removes internal fields from JSON bodies. Fields are JSON names,
+// nested ones separated by dots, e.g. "internal_notes" or "user.password_hash";
+// fields inside arrays are removed from every element.
+func StripFields(fields ...string) ResponseTransformer {
+	paths := make([][]string, 0, len(fields))
+	for _, f := range fields {
+		paths = append(paths, strings.Split(f, "."))
+	}
+
+	return func(_ *http.Request, resp *GatewayResponse) error {
+		if !isJSON(resp.Header) || len(bytes.TrimSpace(resp.Body)) == 0 {
+			return nil
+		}
+
+		var body any
+		decoder := json.NewDecoder(bytes.NewReader(resp.Body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&body); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		for _, path := range paths {
+			stripField(body, path)
+		}
+
+		stripped, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode response: %w", err)
+		}
+		resp.Body = stripped
+		return nil
+	}
+}
+
+func stripField(v any, path []string) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		if child, ok := v[path[0]]; ok {
+			stripField(child, path[1:])
+		}
+	case []any:
+		for _, item := range v {
+			stripField(item, path)
+		}
+	}
+}
+
+func isJSON(h http.Header) bool {
+	return strings.Contains(h.Get("Content-Type"), "json")
+}
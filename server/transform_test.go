@@ -0,0 +1,79 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+func TestResponseTransformers(t *testing.T) {
+	sunset := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transformers := []routeTransformer{
+		newRouteTransformer("GET /v1/users/{id}", StripFields("password_hash", "roles.internal")),
+		newRouteTransformer("*", Envelope()),
+		newRouteTransformer("/v1/legacy/*", Deprecation(sunset, "https://example.com/migrate")),
+	}
+	mux := runtime.NewServeMux(runtime.WithMiddlewares(
+		responseTransformMiddleware(transformers, slog.New(slog.NewTextHandler(io.Discard, nil))),
+	))
+
+	jsonHandler := func(status int, body string) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}
+	}
+	handle := func(method, pattern string, h runtime.HandlerFunc) {
+		if err := mux.HandlePath(method, pattern, h); err != nil {
+			t.Fatalf("HandlePath(%s) error = %v", pattern, err)
+		}
+	}
+	handle(http.MethodGet, "/v1/users/{id}", jsonHandler(http.StatusOK,
+		`{"id":"1","password_hash":"x","roles":[{"name":"admin","internal":true}]}`))
+	handle(http.MethodGet, "/v1/legacy/orders", jsonHandler(http.StatusNotFound, `{"code":5,"message":"not found"}`))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+		deprecated bool
+	}{
+		{
+			path:       "/v1/users/1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"id":"1","roles":[{"name":"admin"}]}}`,
+		},
+		{
+			path:       "/v1/legacy/orders",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":{"code":5,"message":"not found"}}`,
+			deprecated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+			if got := rec.Header().Get("Deprecation") == "true"; got != tt.deprecated {
+				t.Errorf("deprecated = %v, want %v", got, tt.deprecated)
+			}
+			if tt.deprecated && rec.Header().Get("Sunset") != "Thu, 01 Jan 2026 00:00:00 GMT" {
+				t.Errorf("Sunset = %q", rec.Header().Get("Sunset"))
+			}
+		})
+	}
+}
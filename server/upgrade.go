@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables used to hand listeners over to the new process
+const (
+	envInheritedListeners = "GOLIB_INHERITED_LISTENERS"
+	envUpgradeReadyFD     = "GOLIB_UPGRADE_READY_FD"
+)
+
+// firstInheritedFD is the first file descriptor number of exec.Cmd.ExtraFiles
+const firstInheritedFD = 3
+
+// ErrUpgradeInProgress is returned when an upgrade is requested while another one is running
+var ErrUpgradeInProgress = errors.New("upgrade already in progress")
+
+// upgrader passes listening sockets to a new binary and coordinates
+// the handover between the old and the new process
+type upgrader struct {
+	mu        sync.Mutex
+	inherited map[string]net.Listener
+	listeners map[string]net.Listener
+	names     []string
+	readyFile *os.File
+	upgrading bool
+	reusePort bool
+}
+
+// newUpgrader collects listeners inherited from the parent process, if any
+func newUpgrader(reusePort bool) (*upgrader, error) {
+	u := &upgrader{
+		inherited: make(map[string]net.Listener),
+		listeners: make(map[string]net.Listener),
+		reusePort: reusePort,
+	}
+
+	if names := os.Getenv(envInheritedListeners); names != "" {
+		for i, name := range strings.Split(names, ",") {
+			f := os.NewFile(uintptr(firstInheritedFD+i), name)
+			lis, err := net.FileListener(f)
+			_ = f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to inherit listener %s: %w", name, err)
+			}
+			u.inherited[name] = lis
+		}
+	}
+
+	if fd := os.Getenv(envUpgradeReadyFD); fd != "" {
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", envUpgradeReadyFD, err)
+		}
+		u.readyFile = os.NewFile(uintptr(n), "upgrade-ready")
+	}
+
+	// Do not leak handover state to processes started by the service
+	_ = os.Unsetenv(envInheritedListeners)
+	_ = os.Unsetenv(envUpgradeReadyFD)
+
+	return u, nil
+}
+
+// isChild reports whether the process was started by an upgrade
+func (u *upgrader) isChild() bool {
+	return u.readyFile != nil
+}
+
+// listen returns the inherited listener with the given name or creates a new one
+func (u *upgrader) listen(ctx context.Context, name, addr string) (net.Listener, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	lis, ok := u.inherited[name]
+	if ok {
+		delete(u.inherited, name)
+	} else {
+		var err error
+		if path, ok := strings.CutPrefix(addr, unixAddrPrefix); ok {
+			lis, err = listenUnix(ctx, path)
+		} else {
+			lis, err = listenTCP(ctx, addr, u.reusePort)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u.listeners[name] = lis
+	u.names = append(u.names, name)
+
+	return lis, nil
+}
+
+// ready notifies the parent process that this process serves traffic,
+// so the parent can start draining. It is a no-op for non-upgraded processes.
+func (u *upgrader) ready() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// Listeners that were passed but not used by this version are closed
+	for name, lis := range u.inherited {
+		_ = lis.Close()
+		delete(u.inherited, name)
+	}
+
+	if u.readyFile == nil {
+		return nil
+	}
+
+	_, err := u.readyFile.Write([]byte{1})
+	closeErr := u.readyFile.Close()
+	u.readyFile = nil
+
+	return errors.Join(err, closeErr)
+}
+
+// upgrade starts a new instance of the current binary with the active listeners
+// and waits until it reports readiness. On success the caller is expected to drain and exit.
+func (u *upgrader) upgrade(ctx context.Context, timeout time.Duration) error {
+	u.mu.Lock()
+	if u.upgrading {
+		u.mu.Unlock()
+		return ErrUpgradeInProgress
+	}
+	u.upgrading = true
+
+	files := make([]*os.File, 0, len(u.names)+1)
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+		u.mu.Lock()
+		u.upgrading = false
+		u.mu.Unlock()
+	}()
+
+	for _, name := range u.names {
+		f, err := listenerFile(u.listeners[name])
+		if err != nil {
+			u.mu.Unlock()
+			return fmt.Errorf("failed to get file of listener %s: %w", name, err)
+		}
+		files = append(files, f)
+	}
+	names := strings.Join(u.names, ",")
+	u.mu.Unlock()
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create upgrade pipe: %w", err)
+	}
+	defer readyR.Close()
+	files = append(files, readyW)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		envInheritedListeners+"="+names,
+		envUpgradeReadyFD+"="+strconv.Itoa(firstInheritedFD+len(files)-1),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start new process: %w", err)
+	}
+
+	// Close our copy of the write end, so the read returns EOF if the child exits
+	_ = readyW.Close()
+	files = files[:len(files)-1]
+
+	readyCh := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		if _, err := readyR.Read(buf); err != nil {
+			readyCh <- fmt.Errorf("new process exited before becoming ready: %w", err)
+			return
+		}
+		readyCh <- nil
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-readyCh:
+		if err != nil {
+			_ = cmd.Wait()
+			return err
+		}
+	case <-timer.C:
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("new process did not become ready within %s", timeout)
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return ctx.Err()
+	}
+
+	// The new process is adopted by init once this process exits
+	_ = cmd.Process.Release()
+
+	return nil
+}
+
+// listenTCP creates a TCP listener, optionally with SO_REUSEPORT
+func listenTCP(ctx context.Context, addr string, reusePort bool) (net.Listener, error) {
+	lc := net.ListenConfig{}
+	if reusePort {
+		lc.Control = reusePortControl
+	}
+	return lc.Listen(ctx, "tcp", addr)
+}
+
+// listenerFile returns a duplicated file descriptor of the listener
+func listenerFile(lis net.Listener) (*os.File, error) {
+	fl, ok := lis.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("listener %T does not support file descriptor passing", lis)
+	}
+	return fl.File()
+}
@@ -0,0 +1,191 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envUpgradeTestChild makes the test binary act as the new process of an upgrade
+const envUpgradeTestChild = "GOLIB_UPGRADE_TEST_CHILD"
+
+// childReply is written by the child process to the connection it accepts
+const childReply = "child"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(envUpgradeTestChild); mode != "" {
+		os.Exit(runUpgradeChild(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// runUpgradeChild is the new process of an upgrade:
+//   - serve takes over the http listener, reports readiness and answers one connection
+//   - exit fails before becoming ready
+//   - hang never becomes ready
+func runUpgradeChild(mode string) int {
+	// Never outlive a failed test
+	go func() {
+		time.Sleep(30 * time.Second)
+		os.Exit(3)
+	}()
+
+	switch mode {
+	case "exit":
+		return 1
+	case "hang":
+		select {}
+	}
+
+	u, err := newUpgrader(false)
+	if err != nil {
+		return 2
+	}
+	if _, ok := u.inherited["http"]; !ok {
+		return 2
+	}
+	if os.Getenv(envInheritedListeners) != "" || os.Getenv(envUpgradeReadyFD) != "" {
+		return 2
+	}
+	lis, err := u.listen(context.Background(), "http", "127.0.0.1:0")
+	if err != nil {
+		return 2
+	}
+	if err := u.ready(); err != nil {
+		return 2
+	}
+
+	conn, err := lis.Accept()
+	if err != nil {
+		return 2
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, childReply); err != nil {
+		return 2
+	}
+	return 0
+}
+
+// readFromChild closes the listener of the parent and checks that the child answers on its address
+func readFromChild(t *testing.T, lis net.Listener) {
+	t.Helper()
+
+	addr := lis.Addr().String()
+	if err := lis.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	got, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(got) != childReply {
+		t.Fatalf("reply = %q, want %q", got, childReply)
+	}
+}
+
+func TestInheritedListener(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	f, err := listenerFile(lis)
+	if err != nil {
+		t.Fatalf("listenerFile() error = %v", err)
+	}
+	defer f.Close()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Env = append(os.Environ(),
+		envUpgradeTestChild+"=serve",
+		envInheritedListeners+"=http",
+	)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	readFromChild(t, lis)
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("child error = %v", err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Run("child ready", func(t *testing.T) {
+		t.Setenv(envUpgradeTestChild, "serve")
+
+		u, err := newUpgrader(false)
+		if err != nil {
+			t.Fatalf("newUpgrader() error = %v", err)
+		}
+		if u.isChild() {
+			t.Fatal("isChild() = true for a process not started by an upgrade")
+		}
+		lis, err := u.listen(context.Background(), "http", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen() error = %v", err)
+		}
+
+		if err := u.upgrade(context.Background(), 10*time.Second); err != nil {
+			t.Fatalf("upgrade() error = %v", err)
+		}
+
+		// The parent shuts down once the child is ready, and the child keeps serving the address
+		readFromChild(t, lis)
+	})
+
+	t.Run("child exits", func(t *testing.T) {
+		t.Setenv(envUpgradeTestChild, "exit")
+
+		u, err := newUpgrader(false)
+		if err != nil {
+			t.Fatalf("newUpgrader() error = %v", err)
+		}
+		lis, err := u.listen(context.Background(), "http", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen() error = %v", err)
+		}
+		defer lis.Close()
+
+		err = u.upgrade(context.Background(), 10*time.Second)
+		if err == nil || !strings.Contains(err.Error(), "exited before becoming ready") {
+			t.Fatalf("upgrade() error = %v, want exited before becoming ready", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Setenv(envUpgradeTestChild, "hang")
+
+		u, err := newUpgrader(false)
+		if err != nil {
+			t.Fatalf("newUpgrader() error = %v", err)
+		}
+
+		err = u.upgrade(context.Background(), 200*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "did not become ready") {
+			t.Fatalf("upgrade() error = %v, want did not become ready", err)
+		}
+
+		// A failed upgrade can be retried
+		if u.upgrading {
+			t.Fatal("upgrade still marked in progress")
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package server
+
+import (
+	"github.com/rshelekhov/golib/middleware/validation"
+	"google.golang.org/grpc"
+)
+
+// installValidation prepends the validation interceptors to the configured ones
+func (o *Options) installValidation() {
+	o.unaryInterceptors = append([]grpc.UnaryServerInterceptor{
+		validation.UnaryServerInterceptor(o.validationOptions...),
+	}, o.unaryInterceptors...)
+	o.streamInterceptors = append([]grpc.StreamServerInterceptor{
+		validation.StreamServerInterceptor(o.validationOptions...),
+	}, o.streamInterceptors...)
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// VersionServiceName is the gRPC service registered by RegisterVersionServer.
+// Its Get method takes google.protobuf.Empty and returns the version as google.protobuf.Struct.
+const VersionServiceName = "golib.server.Version"
+
+// BuildInfo describes the binary
+type BuildInfo struct {
+	// Service is the name of the service, e.g. "orders"
+	Service   string `json:"service,omitempty"`
+	GoVersion string `json:"go_version"`
+	Path      string `json:"path,omitempty"`
+	Version   string `json:"version,omitempty"`
+	// Revision, Time and Modified describe the VCS checkout the binary was built from
+	Revision string            `json:"revision,omitempty"`
+	Time     string            `json:"time,omitempty"`
+	Modified bool              `json:"modified,omitempty"`
+	Deps     map[string]string `json:"deps,omitempty"`
+}
+
+// ReadBuildInfo returns the build info embedded in the binary
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{GoVersion: runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.Path = bi.Main.Path
+	info.Version = bi.Main.Version
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+
+	info.Deps = make(map[string]string, len(bi.Deps))
+	for _, dep := range bi.Deps {
+		if dep.Replace != nil {
+			info.Deps[dep.Path] = dep.Replace.Path + " " + dep.Replace.Version
+			continue
+		}
+		info.Deps[dep.Path] = dep.Version
+	}
+	return info
+}
+
+// withDefaults fills the empty fields of info from defaults. Modified is only taken
+// from defaults if the revision is, as it describes the same checkout.
+func (info BuildInfo) withDefaults(defaults BuildInfo) BuildInfo {
+	if info.Service == "" {
+		info.Service = defaults.Service
+	}
+	if info.GoVersion == "" {
+		info.GoVersion = defaults.GoVersion
+	}
+	if info.Path == "" {
+		info.Path = defaults.Path
+	}
+	if info.Version == "" {
+		info.Version = defaults.Version
+	}
+	if info.Revision == "" {
+		info.Revision = defaults.Revision
+		info.Modified = defaults.Modified
+	}
+	if info.Time == "" {
+		info.Time = defaults.Time
+	}
+	if info.Deps == nil {
+		info.Deps = defaults.Deps
+	}
+	return info
+}
+
+// public returns the build info without the dependencies, which are only served on the admin port
+func (info BuildInfo) public() BuildInfo {
+	info.Deps = nil
+	return info
+}
+
+// BuildInfoHandler creates an HTTP handler returning the build info of the binary as JSON
+func BuildInfoHandler() http.HandlerFunc {
+	return buildInfoHandler(ReadBuildInfo())
+}
+
+// VersionHandler creates an HTTP handler returning the service name, version, revision
+// and build time of info as JSON, without the dependencies of the binary
+func VersionHandler(info BuildInfo) http.HandlerFunc {
+	return buildInfoHandler(info.public())
+}
+
+func buildInfoHandler(info BuildInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(info)
+	}
+}
+
+// RegisterVersionServer registers a gRPC service returning the service name, version,
+// revision and build time of info, without the dependencies of the binary
+func RegisterVersionServer(s grpc.ServiceRegistrar, info BuildInfo) {
+	info = info.public()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: VersionServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Get",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(context.Context, any) (any, error) {
+					return buildInfoStruct(info)
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				serverInfo := &grpc.UnaryServerInfo{FullMethod: "/" + VersionServiceName + "/Get"}
+				return interceptor(ctx, in, serverInfo, handler)
+			},
+		}},
+	}, struct{}{})
+}
+
+func buildInfoStruct(info BuildInfo) (*structpb.Struct, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	out := new(structpb.Struct)
+	if err := protojson.Unmarshal(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// buildInfoOrDefault returns the build info set by WithBuildInfo, or the one embedded in the binary
+func (o *Options) buildInfoOrDefault() BuildInfo {
+	if o.buildInfo != nil {
+		return *o.buildInfo
+	}
+	return ReadBuildInfo()
+}
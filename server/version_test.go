@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestVersion(t *testing.T) {
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	app, err := NewApp(context.Background(),
+		WithGRPCPort(0),
+		WithGRPCListener(grpcLis),
+		WithHTTPPort(8080),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithBuildInfo(BuildInfo{Service: "orders", Version: "v1.2.3", Revision: "abc123", Time: "2025-01-02T03:04:05Z"}),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /version = %d, want 200", rec.Code)
+	}
+	var got BuildInfo
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode /version: %v", err)
+	}
+	if got.Service != "orders" || got.Version != "v1.2.3" || got.Revision != "abc123" || got.GoVersion == "" {
+		t.Errorf("GET /version = %+v, want orders v1.2.3 abc123 with Go version", got)
+	}
+	if got.Deps != nil {
+		t.Errorf("GET /version deps = %v, want none", got.Deps)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx, noopService{}) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+
+	conn, err := grpc.NewClient(grpcLis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer conn.Close()
+
+	callCtx, callCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer callCancel()
+	out := new(structpb.Struct)
+	if err := conn.Invoke(callCtx, "/"+VersionServiceName+"/Get", &emptypb.Empty{}, out, grpc.WaitForReady(true)); err != nil {
+		t.Fatalf("%s/Get error = %v", VersionServiceName, err)
+	}
+	if v := out.GetFields()["version"].GetStringValue(); v != "v1.2.3" {
+		t.Errorf("%s/Get version = %q, want v1.2.3", VersionServiceName, v)
+	}
+}
+
+func TestVersionDisabled(t *testing.T) {
+	app, err := NewApp(context.Background(), WithHTTPPort(8080), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	app.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /version without build info = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWarmupTimeout is the timeout of a warm-up hook registered without one
+const DefaultWarmupTimeout = 30 * time.Second
+
+// warmupHook is a named function preparing the service for traffic
+type warmupHook struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+}
+
+// warmup runs the warm-up hooks in registration order and stops at the first failure
+func (a *App) warmup(ctx context.Context) error {
+	total := len(a.options.warmups)
+	if total == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	a.options.logger.Info("warming up", "hooks", total)
+
+	for i, hook := range a.options.warmups {
+		logger := a.options.logger.With("hook", hook.name, "step", fmt.Sprintf("%d/%d", i+1, total))
+		logger.Info("running warm-up hook")
+
+		hookStart := time.Now()
+		hookCtx, cancel := context.WithTimeout(ctx, hook.timeout)
+		err := hook.fn(hookCtx)
+		cancel()
+
+		if err != nil {
+			logger.Error("warm-up hook failed", "error", err, "duration", time.Since(hookStart))
+			return fmt.Errorf("warm-up %s: %w", hook.name, err)
+		}
+		logger.Info("warm-up hook completed", "duration", time.Since(hookStart))
+	}
+
+	a.options.logger.Info("warm-up completed", "duration", time.Since(start))
+	return nil
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestWarmup(t *testing.T) {
+	var order []string
+	hook := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	errFailed := errors.New("failed")
+
+	opts := &Options{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	WithWarmup("first", 0, hook("first"))(opts)
+	WithWarmup("slow", time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})(opts)
+	WithWarmup("never", 0, func(ctx context.Context) error { return errFailed })(opts)
+
+	app := &App{options: opts}
+	err := app.warmup(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("warmup() error = %v, want deadline exceeded", err)
+	}
+	if len(order) != 1 || order[0] != "first" {
+		t.Errorf("hooks run = %v, want [first]", order)
+	}
+	if opts.warmups[0].timeout != DefaultWarmupTimeout {
+		t.Errorf("default timeout = %v, want %v", opts.warmups[0].timeout, DefaultWarmupTimeout)
+	}
+}
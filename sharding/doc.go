@@ -0,0 +1,11 @@
+// Package sharding partitions keys across members (replicas, shards, nodes).
+//
+// Ring implements consistent hashing with bounded loads: keys map to a fixed
+// number of partitions, and partitions are assigned to members on a hash ring
+// so that no member owns more than LoadFactor times the average. Adding or
+// removing a member moves only the partitions it gains or loses, and a
+// rebalance callback reports every move.
+//
+// JumpHash is a stateless alternative for numbered buckets that only grow or
+// shrink at the end, e.g. a fixed list of database shards.
+package sharding
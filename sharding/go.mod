@@ -0,0 +1,3 @@
+module github.com/rshelekhov/golib/sharding
+
+go 1.24.2
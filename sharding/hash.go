@@ -0,0 +1,36 @@
+package sharding
+
+import "hash/fnv"
+
+// Hasher computes a 64-bit hash of a key
+type Hasher func(data []byte) uint64
+
+// DefaultHasher is FNV-1a 64
+func DefaultHasher(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// JumpHash returns the bucket in [0, buckets) for the key using the jump
+// consistent hash by Lamping and Veach. When buckets grows from n to n+1,
+// only 1/(n+1) of the keys move, all of them to the new bucket n.
+// It returns -1 if buckets is not positive.
+func JumpHash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		return -1
+	}
+
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// JumpHashString hashes the key with DefaultHasher and returns its JumpHash bucket
+func JumpHashString(key string, buckets int) int {
+	return JumpHash(DefaultHasher([]byte(key)), buckets)
+}
@@ -0,0 +1,48 @@
+package sharding
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HashTag returns the part of the key between the first '{' and the following '}',
+// or the whole key if there is no non-empty tag. Keys sharing a tag land on the
+// same shard, as in Redis Cluster: "{user:42}:cart" and "{user:42}:orders" both
+// shard by "user:42".
+func HashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+	return key[start+1 : start+1+end]
+}
+
+// KeySegment returns the n-th segment of a key split by sep, or the whole key
+// if there are fewer segments, e.g. KeySegment("tenant-7/orders/15", "/", 0) is "tenant-7"
+func KeySegment(key, sep string, n int) string {
+	parts := strings.SplitN(key, sep, n+2)
+	if n < 0 || n >= len(parts) {
+		return key
+	}
+	return parts[n]
+}
+
+// HeaderKey returns a function extracting the shard key from a request header,
+// e.g. HeaderKey("X-Tenant-ID")
+func HeaderKey(name string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// PathValueKey returns a function extracting the shard key from a path wildcard
+// of the http.ServeMux pattern, e.g. PathValueKey("tenant") for "/tenants/{tenant}/orders"
+func PathValueKey(name string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		return r.PathValue(name)
+	}
+}
@@ -0,0 +1,280 @@
+package sharding
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Defaults for Ring
+const (
+	// DefaultPartitionCount is the number of partitions keys are mapped to.
+	// A prime spreads hashes evenly; it should be well above the expected number of members.
+	DefaultPartitionCount = 271
+
+	// DefaultReplicationFactor is the number of virtual nodes per member on the ring
+	DefaultReplicationFactor = 20
+
+	// DefaultLoadFactor caps the partitions of a member at 1.25 times the average
+	DefaultLoadFactor = 1.25
+)
+
+// ErrNoMembers is returned when a key is located on an empty ring
+var ErrNoMembers = errors.New("sharding: ring has no members")
+
+// Move describes a partition that changed owner during rebalancing.
+// From is empty for partitions that had no owner, To is empty when the ring became empty.
+type Move struct {
+	Partition int
+	From      string
+	To        string
+}
+
+// RebalanceFunc is called after members are added or removed, with all partitions that moved
+type RebalanceFunc func(moves []Move)
+
+// Ring is a consistent hash ring with bounded loads. It is safe for concurrent use.
+type Ring struct {
+	partitionCount    int
+	replicationFactor int
+	loadFactor        float64
+	hasher            Hasher
+	onRebalance       RebalanceFunc
+
+	mu         sync.RWMutex
+	members    map[string]struct{}
+	ring       []uint64          // sorted virtual node hashes
+	ringOwners map[uint64]string // virtual node hash to member
+	partitions []string          // partition to member
+	loads      map[string]int
+}
+
+// Option configures the Ring
+type Option func(*Ring)
+
+// WithPartitionCount sets the number of partitions
+func WithPartitionCount(n int) Option {
+	return func(r *Ring) {
+		r.partitionCount = n
+	}
+}
+
+// WithReplicationFactor sets the number of virtual nodes per member
+func WithReplicationFactor(n int) Option {
+	return func(r *Ring) {
+		r.replicationFactor = n
+	}
+}
+
+// WithLoadFactor sets the maximum load of a member relative to the average.
+// Values closer to 1 give a more even distribution but move more partitions on rebalancing.
+func WithLoadFactor(f float64) Option {
+	return func(r *Ring) {
+		r.loadFactor = f
+	}
+}
+
+// WithHasher sets the hash function used for keys and virtual nodes
+func WithHasher(h Hasher) Option {
+	return func(r *Ring) {
+		r.hasher = h
+	}
+}
+
+// WithRebalanceCallback sets the function called after partitions move between members,
+// e.g. to hand over cached data or restart partition consumers
+func WithRebalanceCallback(fn RebalanceFunc) Option {
+	return func(r *Ring) {
+		r.onRebalance = fn
+	}
+}
+
+// NewRing creates a ring with the given members
+func NewRing(members []string, opts ...Option) *Ring {
+	r := &Ring{
+		partitionCount:    DefaultPartitionCount,
+		replicationFactor: DefaultReplicationFactor,
+		loadFactor:        DefaultLoadFactor,
+		hasher:            DefaultHasher,
+		members:           make(map[string]struct{}),
+		ringOwners:        make(map[uint64]string),
+		loads:             make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.partitionCount <= 0 {
+		r.partitionCount = DefaultPartitionCount
+	}
+	if r.replicationFactor <= 0 {
+		r.replicationFactor = DefaultReplicationFactor
+	}
+	if r.loadFactor < 1 {
+		r.loadFactor = DefaultLoadFactor
+	}
+
+	r.partitions = make([]string, r.partitionCount)
+	for _, m := range members {
+		r.addVirtualNodes(m)
+	}
+	r.distribute()
+
+	return r
+}
+
+// Add adds members to the ring and rebalances partitions
+func (r *Ring) Add(members ...string) {
+	r.update(func() {
+		for _, m := range members {
+			r.addVirtualNodes(m)
+		}
+	})
+}
+
+// Remove removes members from the ring and rebalances partitions
+func (r *Ring) Remove(members ...string) {
+	r.update(func() {
+		for _, m := range members {
+			r.removeVirtualNodes(m)
+		}
+	})
+}
+
+// Members returns the current members in sorted order
+func (r *Ring) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]string, 0, len(r.members))
+	for m := range r.members {
+		members = append(members, m)
+	}
+	slices.Sort(members)
+	return members
+}
+
+// Partition returns the partition of the key
+func (r *Ring) Partition(key string) int {
+	return int(r.hasher([]byte(key)) % uint64(r.partitionCount))
+}
+
+// Locate returns the member owning the key
+func (r *Ring) Locate(key string) (string, error) {
+	return r.PartitionOwner(r.Partition(key))
+}
+
+// PartitionOwner returns the member owning the partition
+func (r *Ring) PartitionOwner(partition int) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.members) == 0 {
+		return "", ErrNoMembers
+	}
+	return r.partitions[partition], nil
+}
+
+// Loads returns the number of partitions owned by each member
+func (r *Ring) Loads() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	loads := make(map[string]int, len(r.loads))
+	for m, l := range r.loads {
+		loads[m] = l
+	}
+	return loads
+}
+
+// MaxLoad returns the maximum number of partitions a member may own
+func (r *Ring) MaxLoad() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.maxLoad()
+}
+
+// update applies a membership change, redistributes partitions
+// and reports moved partitions to the rebalance callback
+func (r *Ring) update(change func()) {
+	r.mu.Lock()
+	previous := slices.Clone(r.partitions)
+	change()
+	r.distribute()
+
+	var moves []Move
+	for p, owner := range r.partitions {
+		if owner != previous[p] {
+			moves = append(moves, Move{Partition: p, From: previous[p], To: owner})
+		}
+	}
+	r.mu.Unlock()
+
+	if r.onRebalance != nil && len(moves) > 0 {
+		r.onRebalance(moves)
+	}
+}
+
+func (r *Ring) addVirtualNodes(member string) {
+	if _, ok := r.members[member]; ok {
+		return
+	}
+	r.members[member] = struct{}{}
+
+	for i := 0; i < r.replicationFactor; i++ {
+		h := r.hasher([]byte(member + "#" + strconv.Itoa(i)))
+		r.ringOwners[h] = member
+		r.ring = append(r.ring, h)
+	}
+	slices.Sort(r.ring)
+}
+
+func (r *Ring) removeVirtualNodes(member string) {
+	if _, ok := r.members[member]; !ok {
+		return
+	}
+	delete(r.members, member)
+
+	r.ring = slices.DeleteFunc(r.ring, func(h uint64) bool {
+		if r.ringOwners[h] == member {
+			delete(r.ringOwners, h)
+			return true
+		}
+		return false
+	})
+}
+
+func (r *Ring) maxLoad() int {
+	if len(r.members) == 0 {
+		return 0
+	}
+	avg := float64(r.partitionCount) / float64(len(r.members))
+	return int(math.Ceil(avg * r.loadFactor))
+}
+
+// distribute assigns every partition to the first member clockwise from the
+// partition hash whose load is below the maximum
+func (r *Ring) distribute() {
+	clear(r.loads)
+	if len(r.members) == 0 {
+		clear(r.partitions)
+		return
+	}
+
+	maxLoad := r.maxLoad()
+	for p := range r.partitions {
+		h := r.hasher([]byte(strconv.Itoa(p)))
+		idx, _ := slices.BinarySearch(r.ring, h)
+
+		for i := 0; i < len(r.ring); i++ {
+			member := r.ringOwners[r.ring[(idx+i)%len(r.ring)]]
+			if r.loads[member] < maxLoad {
+				r.partitions[p] = member
+				r.loads[member]++
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRingBoundedLoads(t *testing.T) {
+	ring := NewRing([]string{"a", "b", "c", "d", "e"})
+
+	total := 0
+	for member, load := range ring.Loads() {
+		if load > ring.MaxLoad() {
+			t.Errorf("member %s owns %d partitions, max %d", member, load, ring.MaxLoad())
+		}
+		total += load
+	}
+	if total != DefaultPartitionCount {
+		t.Errorf("expected all %d partitions to be owned, got %d", DefaultPartitionCount, total)
+	}
+
+	owner, err := ring.Locate("user:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := ring.Locate("user:42"); again != owner {
+		t.Errorf("expected stable owner %s, got %s", owner, again)
+	}
+}
+
+func TestRingRebalance(t *testing.T) {
+	var moves []Move
+	ring := NewRing([]string{"a", "b", "c"}, WithRebalanceCallback(func(m []Move) {
+		moves = m
+	}))
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		before[key], _ = ring.Locate(key)
+	}
+
+	ring.Add("d")
+
+	if len(moves) == 0 {
+		t.Fatal("expected rebalance callback to report moves")
+	}
+	// Most partitions stay where they were
+	if len(moves) > DefaultPartitionCount/2 {
+		t.Errorf("expected a minority of partitions to move, got %d", len(moves))
+	}
+
+	moved := make(map[int]bool, len(moves))
+	for _, m := range moves {
+		moved[m.Partition] = true
+	}
+	for key, owner := range before {
+		now, _ := ring.Locate(key)
+		if now != owner && !moved[ring.Partition(key)] {
+			t.Errorf("key %s moved from %s to %s without a reported move", key, owner, now)
+		}
+	}
+
+	ring.Remove("a", "b", "c", "d")
+	if _, err := ring.Locate("key"); err != ErrNoMembers {
+		t.Errorf("expected ErrNoMembers, got %v", err)
+	}
+}
+
+func TestJumpHash(t *testing.T) {
+	if JumpHash(42, 0) != -1 {
+		t.Error("expected -1 for no buckets")
+	}
+
+	for key := uint64(0); key < 10000; key++ {
+		prev := JumpHash(key, 10)
+		next := JumpHash(key, 11)
+		if prev < 0 || prev >= 10 {
+			t.Fatalf("bucket %d out of range", prev)
+		}
+		// Keys either stay or move to the new bucket
+		if next != prev && next != 10 {
+			t.Fatalf("key %d moved from %d to %d", key, prev, next)
+		}
+	}
+}
+
+func TestHashTag(t *testing.T) {
+	tests := map[string]string{
+		"{user:42}:cart": "user:42",
+		"cart:{42}":      "42",
+		"user:42":        "user:42",
+		"{}:cart":        "{}:cart",
+		"{user:42":       "{user:42",
+	}
+	for key, expected := range tests {
+		if got := HashTag(key); got != expected {
+			t.Errorf("HashTag(%q): expected %q, got %q", key, expected, got)
+		}
+	}
+
+	if got := KeySegment("tenant-7/orders/15", "/", 0); got != "tenant-7" {
+		t.Errorf("expected tenant-7, got %s", got)
+	}
+}
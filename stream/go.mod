@@ -0,0 +1,11 @@
+module github.com/rshelekhov/golib/stream
+
+go 1.24.2
+
+require google.golang.org/grpc v1.74.2
+
+require (
+	golang.org/x/sys v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"io"
+)
+
+// Reader reads data from a stream of chunks, verifying offsets and the checksum.
+// It returns io.EOF when the stream ends.
+type Reader[M any] struct {
+	receiver Receiver[M]
+	codec    Codec[M]
+	opts     options
+
+	pending []byte
+	offset  int64
+	hash    hash.Hash
+	err     error
+}
+
+var _ io.Reader = (*Reader[any])(nil)
+
+// NewReader creates a reader receiving chunks from the stream.
+// WithOffset sets the offset the first chunk is expected at.
+func NewReader[M any](receiver Receiver[M], codec Codec[M], opts ...Option) *Reader[M] {
+	o := newOptions(opts)
+	return &Reader[M]{
+		receiver: receiver,
+		codec:    codec,
+		opts:     o,
+		offset:   o.offset,
+		hash:     sha256.New(),
+	}
+}
+
+// Read implements io.Reader
+func (r *Reader[M]) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.receive()
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// Offset returns the offset of the data received so far.
+// After an interruption, the transfer can be resumed from it.
+func (r *Reader[M]) Offset() int64 {
+	return r.offset - int64(len(r.pending))
+}
+
+// receive reads the next chunk into pending
+func (r *Reader[M]) receive() error {
+	msg, err := r.receiver.Recv()
+	if err != nil {
+		return err
+	}
+
+	chunk := r.codec.Decode(msg)
+	if chunk.Offset != r.offset {
+		return ErrOffsetMismatch
+	}
+
+	if len(chunk.Checksum) > 0 {
+		if r.opts.checksum && !bytes.Equal(chunk.Checksum, r.hash.Sum(nil)) {
+			return ErrChecksumMismatch
+		}
+		return nil
+	}
+
+	r.hash.Write(chunk.Data)
+	r.offset += int64(len(chunk.Data))
+	r.pending = chunk.Data
+
+	if r.opts.progress != nil {
+		r.opts.progress(r.offset)
+	}
+	return nil
+}
+
+// Receive writes all data from the stream to w and returns the number of bytes written
+func Receive[M any](receiver Receiver[M], codec Codec[M], w io.Writer, opts ...Option) (int64, error) {
+	return io.Copy(w, NewReader(receiver, codec, opts...))
+}
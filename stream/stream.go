@@ -0,0 +1,108 @@
+// Package stream adapts io.Reader and io.Writer to gRPC streams of byte chunks.
+//
+// Chunks carry their offset, so the receiver detects lost or reordered data
+// and transfers can be resumed from the last received offset. The sender ends
+// the stream with an empty chunk carrying the SHA-256 checksum of all data it sent.
+//
+// The package does not define protobuf messages: services keep their own chunk
+// message (e.g. `bytes data = 1; int64 offset = 2; bytes checksum = 3;`) and
+// describe it with a Codec.
+package stream
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultChunkSize is the size of data chunks sent by Writer.
+// It stays well below the default 4 MiB gRPC message limit.
+const DefaultChunkSize = 64 * 1024
+
+var (
+	// ErrChecksumMismatch is returned by Reader when the received data does not match the sender's checksum
+	ErrChecksumMismatch = status.Error(codes.DataLoss, "stream: checksum mismatch")
+	// ErrOffsetMismatch is returned by Reader when a chunk does not continue the received data
+	ErrOffsetMismatch = status.Error(codes.DataLoss, "stream: unexpected chunk offset")
+)
+
+// Chunk is the transport-neutral representation of a chunk message
+type Chunk struct {
+	// Offset of Data in the whole payload
+	Offset int64
+	// Data of the chunk. Empty in the final checksum chunk.
+	Data []byte
+	// Checksum is the SHA-256 of all data sent in this stream. Set only in the final chunk.
+	Checksum []byte
+}
+
+// Codec converts between Chunk and a service's chunk message type
+type Codec[M any] struct {
+	Encode func(Chunk) M
+	Decode func(M) Chunk
+}
+
+// Sender is implemented by gRPC client-streaming clients and server-streaming servers
+type Sender[M any] interface {
+	Send(M) error
+}
+
+// Receiver is implemented by gRPC server-streaming clients and client-streaming servers
+type Receiver[M any] interface {
+	Recv() (M, error)
+}
+
+// ProgressFunc is called after every chunk with the offset reached so far
+type ProgressFunc func(offset int64)
+
+type options struct {
+	chunkSize int
+	offset    int64
+	checksum  bool
+	progress  ProgressFunc
+}
+
+// Option configures Writer and Reader
+type Option func(*options)
+
+// WithChunkSize sets the size of sent chunks
+func WithChunkSize(size int) Option {
+	return func(o *options) {
+		o.chunkSize = size
+	}
+}
+
+// WithOffset sets the offset of the first chunk, used to resume an interrupted transfer
+func WithOffset(offset int64) Option {
+	return func(o *options) {
+		o.offset = offset
+	}
+}
+
+// WithChecksum enables or disables the SHA-256 checksum (enabled by default).
+// A Reader verifies the checksum whenever the sender provides one.
+func WithChecksum(enable bool) Option {
+	return func(o *options) {
+		o.checksum = enable
+	}
+}
+
+// WithProgress sets a callback reporting transfer progress
+func WithProgress(fn ProgressFunc) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		chunkSize: DefaultChunkSize,
+		checksum:  true,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.chunkSize <= 0 {
+		o.chunkSize = DefaultChunkSize
+	}
+	return o
+}
@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// pipe is an in-memory stream of chunks
+type pipe struct {
+	chunks []Chunk
+}
+
+func (p *pipe) Send(c Chunk) error {
+	p.chunks = append(p.chunks, c)
+	return nil
+}
+
+func (p *pipe) Recv() (Chunk, error) {
+	if len(p.chunks) == 0 {
+		return Chunk{}, io.EOF
+	}
+	c := p.chunks[0]
+	p.chunks = p.chunks[1:]
+	return c, nil
+}
+
+var identity = Codec[Chunk]{
+	Encode: func(c Chunk) Chunk { return c },
+	Decode: func(c Chunk) Chunk { return c },
+}
+
+func TestSendReceive(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	var progress []int64
+	p := &pipe{}
+	n, err := Send(p, identity, bytes.NewReader(data),
+		WithChunkSize(4096),
+		WithProgress(func(offset int64) { progress = append(progress, offset) }),
+	)
+	if err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("expected %d bytes sent, got %d", len(data), n)
+	}
+	// 3 data chunks and the checksum chunk
+	if len(p.chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(p.chunks))
+	}
+	if progress[len(progress)-1] != int64(len(data)) {
+		t.Errorf("expected final progress %d, got %v", len(data), progress)
+	}
+
+	var out bytes.Buffer
+	if _, err := Receive(p, identity, &out); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Error("received data differs from sent data")
+	}
+}
+
+func TestReceiveResume(t *testing.T) {
+	data := []byte("hello, world")
+
+	p := &pipe{}
+	if _, err := Send(p, identity, bytes.NewReader(data[5:]), WithOffset(5), WithChunkSize(4)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	r := NewReader(p, identity, WithOffset(5))
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if string(rest) != string(data[5:]) {
+		t.Errorf("expected %q, got %q", data[5:], rest)
+	}
+	if r.Offset() != int64(len(data)) {
+		t.Errorf("expected offset %d, got %d", len(data), r.Offset())
+	}
+}
+
+func TestReceiveErrors(t *testing.T) {
+	t.Run("Checksum mismatch", func(t *testing.T) {
+		p := &pipe{}
+		if _, err := Send(p, identity, bytes.NewReader([]byte("payload"))); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+		p.chunks[0].Data[0] = 'P'
+
+		_, err := Receive(p, identity, io.Discard)
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("expected ErrChecksumMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Missing chunk", func(t *testing.T) {
+		p := &pipe{}
+		if _, err := Send(p, identity, bytes.NewReader([]byte("payload")), WithChunkSize(2)); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+		p.chunks = append(p.chunks[:1], p.chunks[2:]...)
+
+		_, err := Receive(p, identity, io.Discard)
+		if !errors.Is(err, ErrOffsetMismatch) {
+			t.Errorf("expected ErrOffsetMismatch, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package stream
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+)
+
+// Writer sends written data as chunks. Close must be called to flush
+// the last chunk and send the checksum; it does not close the gRPC stream.
+type Writer[M any] struct {
+	sender Sender[M]
+	codec  Codec[M]
+	opts   options
+
+	buf    []byte
+	offset int64
+	hash   hash.Hash
+	closed bool
+}
+
+var _ io.WriteCloser = (*Writer[any])(nil)
+
+// NewWriter creates a writer sending chunks to the stream
+func NewWriter[M any](sender Sender[M], codec Codec[M], opts ...Option) *Writer[M] {
+	o := newOptions(opts)
+	return &Writer[M]{
+		sender: sender,
+		codec:  codec,
+		opts:   o,
+		buf:    make([]byte, 0, o.chunkSize),
+		offset: o.offset,
+		hash:   sha256.New(),
+	}
+}
+
+// Write buffers p and sends every full chunk
+func (w *Writer[M]) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), w.opts.chunkSize-len(w.buf))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(w.buf) == w.opts.chunkSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// ReadFrom sends all data from r, allowing io.Copy to skip an intermediate buffer
+func (w *Writer[M]) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for {
+		if len(w.buf) == w.opts.chunkSize {
+			if err := w.flush(); err != nil {
+				return total, err
+			}
+		}
+
+		n, err := r.Read(w.buf[len(w.buf):w.opts.chunkSize])
+		w.buf = w.buf[:len(w.buf)+n]
+		total += int64(n)
+
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Offset returns the offset reached so far, including buffered data
+func (w *Writer[M]) Offset() int64 {
+	return w.offset + int64(len(w.buf))
+}
+
+// Close sends the remaining data and the final checksum chunk
+func (w *Writer[M]) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if !w.opts.checksum {
+		return nil
+	}
+
+	return w.sender.Send(w.codec.Encode(Chunk{
+		Offset:   w.offset,
+		Checksum: w.hash.Sum(nil),
+	}))
+}
+
+func (w *Writer[M]) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	// The message may keep a reference to the data until it is serialized
+	data := make([]byte, len(w.buf))
+	copy(data, w.buf)
+
+	if err := w.sender.Send(w.codec.Encode(Chunk{Offset: w.offset, Data: data})); err != nil {
+		return err
+	}
+
+	w.hash.Write(data)
+	w.offset += int64(len(data))
+	w.buf = w.buf[:0]
+
+	if w.opts.progress != nil {
+		w.opts.progress(w.offset)
+	}
+	return nil
+}
+
+// Send sends all data from r to the stream and the final checksum chunk.
+// It returns the number of bytes sent.
+func Send[M any](sender Sender[M], codec Codec[M], r io.Reader, opts ...Option) (int64, error) {
+	w := NewWriter(sender, codec, opts...)
+	n, err := w.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	return n, w.Close()
+}